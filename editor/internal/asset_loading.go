@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"Gopher3D/internal/loader"
+	"Gopher3D/internal/renderer"
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// assetLoad is a background load shown in the loading overlay
+type assetLoad struct {
+	name     string
+	progress func() float32
+	done     <-chan struct{}
+}
+
+var activeLoads []assetLoad
+
+// importModelAsync loads an OBJ on the asset pool and adds it to the scene when ready
+func importModelAsync(path, name string) {
+	openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		addModelToScene(path, name)
+		return
+	}
+
+	logToConsole(fmt.Sprintf("Loading model: %s", name), "info")
+	handle := loader.LoadModelAsync(path, true, openglRenderer.Uploads())
+	activeLoads = append(activeLoads, assetLoad{name: name, progress: handle.Progress, done: handle.Done()})
+
+	// Runs on the main thread once parsing finished
+	handle.OnReady(func(model *renderer.Model, err error) {
+		if err != nil {
+			logToConsole(fmt.Sprintf("Failed to load model %s: %v", name, err), "error")
+			return
+		}
+		placeImportedModel(model, name)
+		sceneModified = true
+	})
+}
+
+// renderAssetLoadingStatus shows progress for background loads in the bottom right corner
+func renderAssetLoadingStatus(openglRenderer *renderer.OpenGLRenderer) {
+	remaining := activeLoads[:0]
+	for _, load := range activeLoads {
+		select {
+		case <-load.done:
+		default:
+			remaining = append(remaining, load)
+		}
+	}
+	activeLoads = remaining
+
+	uploads := openglRenderer.PendingUploads()
+	if len(activeLoads) == 0 && uploads == 0 {
+		return
+	}
+
+	imgui.SetNextWindowPosV(imgui.Vec2{X: float32(Eng.Width) - 10, Y: float32(Eng.Height) - 10}, imgui.ConditionAlways, imgui.Vec2{X: 1, Y: 1})
+	imgui.SetNextWindowBgAlpha(0.8)
+	flags := imgui.WindowFlagsNoDecoration | imgui.WindowFlagsAlwaysAutoResize | imgui.WindowFlagsNoSavedSettings |
+		imgui.WindowFlagsNoFocusOnAppearing | imgui.WindowFlagsNoNav
+	if imgui.BeginV("##AssetLoading", nil, flags) {
+		for _, load := range activeLoads {
+			imgui.ProgressBarV(load.progress(), imgui.Vec2{X: 200, Y: 0}, load.name)
+		}
+		if uploads > 0 {
+			imgui.Text(fmt.Sprintf("%d GPU uploads queued", uploads))
+		}
+	}
+	imgui.End()
+}
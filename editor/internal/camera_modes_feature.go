@@ -0,0 +1,210 @@
+package editor
+
+import (
+	"Gopher3D/internal/behaviour"
+	"Gopher3D/internal/renderer"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// sceneCameraController returns a camera's controller settings for saving, or nil for the fly controls
+func sceneCameraController(cam *renderer.Camera) *renderer.CameraControllerSettings {
+	if cam.Controller == nil {
+		return nil
+	}
+	settings := cam.Controller.Settings()
+	return &settings
+}
+
+// loadCameraController gives a loaded scene camera its projection and controller
+func loadCameraController(rend *renderer.OpenGLRenderer, cam *renderer.Camera, data SceneCamera) {
+	cam.ProjectionMode = renderer.ParseProjectionMode(data.Projection)
+	cam.OrthoSize = data.OrthoSize
+	if cam.OrthoSize <= 0 {
+		cam.OrthoSize = renderer.DefaultOrthoSize
+	}
+	if data.Controller != nil {
+		cam.Controller = renderer.NewCameraController(*data.Controller, rend.FindModel(data.Controller.Follow))
+	}
+}
+
+// renderCameraProjection edits a scene camera's projection
+func renderCameraProjection(cam *renderer.Camera) {
+	if imgui.BeginCombo("Projection", cam.ProjectionMode.String()) {
+		for _, mode := range []renderer.ProjectionMode{renderer.ProjectionPerspective, renderer.ProjectionOrthographic} {
+			if imgui.SelectableV(mode.String(), cam.ProjectionMode == mode, 0, imgui.Vec2{}) {
+				cam.SetProjectionMode(mode)
+			}
+		}
+		imgui.EndCombo()
+	}
+	if cam.ProjectionMode == renderer.ProjectionOrthographic {
+		if imgui.DragFloatV("Ortho Size", &cam.OrthoSize, 0.1, 0.1, 10000, "%.1f", 0) {
+			cam.UpdateProjection()
+		}
+	}
+}
+
+// renderCameraController picks and tunes the controller a scene camera uses in the game
+func renderCameraController(rend *renderer.OpenGLRenderer, cam *renderer.Camera) {
+	if !imgui.CollapsingHeaderV("Controller", 0) {
+		return
+	}
+	settings := renderer.CameraControllerSettings{Mode: renderer.CameraModeFly}
+	if cam.Controller != nil {
+		settings = cam.Controller.Settings()
+	}
+	previous := settings.Mode
+	if !editCameraControllerSettings(&settings) {
+		return
+	}
+	if previous == renderer.CameraModeFly && settings.Mode != previous {
+		// Start from the current view instead of jumping
+		const distance = 10
+		focus := cam.Position.Add(cam.Front.Mul(distance))
+		settings.Target = [3]float32{focus.X(), focus.Y(), focus.Z()}
+		settings.Distance = distance
+		settings.Yaw, settings.Pitch = cam.Yaw, cam.Pitch
+		if settings.Mode == renderer.CameraModeRTS {
+			settings.Pitch = max(-cam.Pitch, 5)
+		}
+	}
+	target := rend.FindModel(settings.Follow)
+	cam.Controller = renderer.NewCameraController(settings, target)
+	if cam.Controller != nil {
+		// Show where the controller will start
+		cam.Controller.Update(cam, renderer.CameraInput{}, 0)
+	}
+}
+
+// editCameraControllerSettings draws the controller fields for the chosen mode
+// and reports whether any changed
+func editCameraControllerSettings(s *renderer.CameraControllerSettings) bool {
+	changed := false
+	if imgui.BeginCombo("Mode", string(s.Mode)) {
+		for _, mode := range renderer.CameraModes {
+			if imgui.SelectableV(string(mode), s.Mode == mode, 0, imgui.Vec2{}) && s.Mode != mode {
+				s.Mode = mode
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	switch s.Mode {
+	case renderer.CameraModeFly:
+		imgui.Text("WASD to move, right mouse to look")
+		return changed
+	case renderer.CameraModeOrbit:
+		changed = imgui.DragFloat3V("Target", &s.Target, 0.1, 0, 0, "%.1f", 0) || changed
+		changed = imgui.DragFloatV("Distance", &s.Distance, 0.1, 0.5, 5000, "%.1f", 0) || changed
+	case renderer.CameraModeFollow:
+		changed = imgui.InputText("Follow Model", &s.Follow) || changed
+		changed = imgui.DragFloat3V("Offset", &s.Target, 0.05, 0, 0, "%.2f", 0) || changed
+		changed = imgui.DragFloatV("Distance", &s.Distance, 0.1, 1, 500, "%.1f", 0) || changed
+		changed = imgui.SliderFloatV("Stiffness", &s.Stiffness, 0, 30, "%.1f", 0) || changed
+	case renderer.CameraModeRTS:
+		changed = imgui.DragFloat3V("Focus", &s.Target, 0.1, 0, 0, "%.1f", 0) || changed
+		changed = imgui.DragFloatV("Height", &s.Distance, 0.5, 2, 2000, "%.1f", 0) || changed
+	}
+	changed = imgui.DragFloatV("Yaw##controller", &s.Yaw, 1, -360, 360, "%.0f", 0) || changed
+	if s.Mode == renderer.CameraModeRTS {
+		changed = imgui.SliderFloatV("Tilt", &s.Pitch, 5, 89, "%.0f", 0) || changed
+	} else {
+		changed = imgui.SliderFloatV("Pitch##controller", &s.Pitch, -89, 89, "%.0f", 0) || changed
+	}
+	return changed
+}
+
+// renderCameraComponentMode edits the projection and controller a camera component asks for
+func renderCameraComponentMode(c *behaviour.CameraComponent) {
+	if imgui.BeginCombo("Projection", c.Projection) {
+		for _, mode := range []renderer.ProjectionMode{renderer.ProjectionPerspective, renderer.ProjectionOrthographic} {
+			if imgui.SelectableV(mode.String(), c.Projection == mode.String(), 0, imgui.Vec2{}) {
+				c.Projection = mode.String()
+			}
+		}
+		imgui.EndCombo()
+	}
+	if renderer.ParseProjectionMode(c.Projection) == renderer.ProjectionOrthographic {
+		imgui.DragFloatV("Ortho Size", &c.OrthoSize, 0.1, 0.1, 10000, "%.1f", 0)
+	}
+
+	settings := cameraComponentSettings(c)
+	if editCameraControllerSettings(&settings) {
+		c.Controller = string(settings.Mode)
+		c.Target = settings.Target
+		c.Follow = settings.Follow
+		c.Distance = settings.Distance
+		c.Yaw, c.Pitch = settings.Yaw, settings.Pitch
+		c.Stiffness = settings.Stiffness
+	}
+}
+
+// lensPreviewComponent is the camera component whose lens effects the editor viewport shows, if any
+var lensPreviewComponent *behaviour.CameraComponent
+
+// renderCameraComponentLens edits a camera component's depth of field and motion blur
+func renderCameraComponentLens(c *behaviour.CameraComponent) {
+	if !imgui.CollapsingHeaderV("Lens Effects", 0) {
+		return
+	}
+	imgui.Indent()
+	imgui.Checkbox("Depth of Field", &c.DepthOfField)
+	if c.DepthOfField {
+		imgui.DragFloatV("Focus Distance", &c.FocusDistance, 0.1, 0.1, 10000, "%.1f", 0)
+		imgui.SliderFloatV("F-Stop", &c.FStop, 0.7, 22, "f/%.1f", 0)
+		blades := int32(c.BokehBlades)
+		if imgui.SliderIntV("Bokeh Blades", &blades, 0, 12, "%d", 0) {
+			c.BokehBlades = int(blades)
+		}
+	}
+	imgui.Checkbox("Motion Blur", &c.MotionBlur)
+	if c.MotionBlur {
+		imgui.SliderFloatV("Shutter", &c.Shutter, 0.05, 1, "%.2f", 0)
+	}
+
+	previewing := lensPreviewComponent == c
+	if imgui.Checkbox("Preview in Viewport", &previewing) {
+		if previewing {
+			lensPreviewComponent = c
+		} else {
+			lensPreviewComponent = nil
+			if Eng != nil && Eng.Camera != nil {
+				Eng.Camera.DepthOfField.Enabled = false
+				Eng.Camera.MotionBlur.Enabled = false
+			}
+		}
+	}
+	if previewing && Eng != nil && Eng.Camera != nil {
+		applyCameraComponentLens(Eng.Camera, c)
+	}
+	imgui.Unindent()
+}
+
+// applyCameraComponentLens gives a camera the lens effects a camera component asks for
+func applyCameraComponentLens(cam *renderer.Camera, c *behaviour.CameraComponent) {
+	cam.DepthOfField.Enabled = c.DepthOfField
+	cam.DepthOfField.FocusDistance = c.FocusDistance
+	cam.DepthOfField.FStop = c.FStop
+	cam.DepthOfField.BokehBlades = c.BokehBlades
+	cam.MotionBlur.Enabled = c.MotionBlur
+	cam.MotionBlur.Shutter = c.Shutter
+}
+
+// cameraComponentSettings returns the controller settings a camera component holds
+func cameraComponentSettings(c *behaviour.CameraComponent) renderer.CameraControllerSettings {
+	mode := renderer.CameraMode(c.Controller)
+	if mode == "" {
+		mode = renderer.CameraModeFly
+	}
+	return renderer.CameraControllerSettings{
+		Mode:      mode,
+		Target:    c.Target,
+		Follow:    c.Follow,
+		Distance:  c.Distance,
+		Yaw:       c.Yaw,
+		Pitch:     c.Pitch,
+		Stiffness: c.Stiffness,
+	}
+}
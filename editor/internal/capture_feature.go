@@ -0,0 +1,101 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// captureDir returns where screenshots and recordings are saved
+func captureDir() string {
+	if CurrentProject == nil {
+		return "captures"
+	}
+	return filepath.Join(CurrentProject.Path, "captures")
+}
+
+// executeScreenshotCommand handles "screenshot [scale]"
+func executeScreenshotCommand(args []string) {
+	openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		logToConsole("ERROR: Cannot access renderer", "error")
+		return
+	}
+
+	options := renderer.ScreenshotOptions{Scale: 1, Supersample: 1}
+	if len(args) > 0 {
+		scale, err := strconv.Atoi(args[0])
+		if err != nil || scale < 1 || scale > 8 {
+			logToConsole("Usage: screenshot [scale 1-8]", "warning")
+			return
+		}
+		options.Scale = scale
+		// High-resolution shots are rendered offscreen anyway, so antialias them too
+		if scale > 1 {
+			options.Supersample = 2
+		}
+	}
+
+	path := filepath.Join(captureDir(), fmt.Sprintf("screenshot_%s.png", time.Now().Format("20060102_150405")))
+	openglRenderer.Screenshot(path, options).OnReady(func(path string, err error) {
+		if err != nil {
+			logToConsole(fmt.Sprintf("Screenshot failed: %v", err), "error")
+			return
+		}
+		logToConsole(fmt.Sprintf("Screenshot saved to %s", path), "info")
+	})
+}
+
+// executeRecordCommand handles "record start [fps]" and "record stop"
+func executeRecordCommand(args []string) {
+	openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		logToConsole("ERROR: Cannot access renderer", "error")
+		return
+	}
+	if len(args) == 0 {
+		if openglRenderer.IsRecording() {
+			logToConsole("Recording frames (record stop to finish)", "info")
+		} else {
+			logToConsole("Usage: record start [fps] | record stop", "warning")
+		}
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "start":
+		fps := 30
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 || n > 240 {
+				logToConsole("Usage: record start [fps 1-240]", "warning")
+				return
+			}
+			fps = n
+		}
+		dir := filepath.Join(captureDir(), "recording_"+time.Now().Format("20060102_150405"))
+		if err := openglRenderer.StartRecording(dir, fps); err != nil {
+			logToConsole(fmt.Sprintf("Failed to start recording: %v", err), "error")
+			return
+		}
+		logToConsole(fmt.Sprintf("Recording at a fixed %d FPS to %s", fps, dir), "info")
+
+	case "stop":
+		if !openglRenderer.IsRecording() {
+			logToConsole("Not recording", "warning")
+			return
+		}
+		frames, err := openglRenderer.StopRecording()
+		if err != nil {
+			logToConsole(fmt.Sprintf("Recording stopped after %d frames: %v", frames, err), "error")
+			return
+		}
+		logToConsole(fmt.Sprintf("Recorded %d frames", frames), "info")
+
+	default:
+		logToConsole("Usage: record start [fps] | record stop", "warning")
+	}
+}
@@ -0,0 +1,99 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// debugViewLabels are the menu names of the debug view modes
+var debugViewLabels = map[renderer.DebugViewMode]string{
+	renderer.DebugViewLit:        "Lit",
+	renderer.DebugViewNormals:    "Normals",
+	renderer.DebugViewUVs:        "UV Checker",
+	renderer.DebugViewAlbedo:     "Albedo Only",
+	renderer.DebugViewLighting:   "Lighting Only",
+	renderer.DebugViewDepth:      "Depth",
+	renderer.DebugViewOverdraw:   "Overdraw",
+	renderer.DebugViewInstanceID: "Instance IDs",
+	renderer.DebugViewLOD:        "LOD Level",
+}
+
+// renderDebugViewMenu draws the main menu's view mode and overlay toggles
+func renderDebugViewMenu() {
+	if !imgui.BeginMenu("Debug View") {
+		return
+	}
+	for _, mode := range renderer.DebugViewModes {
+		if imgui.MenuItemV(debugViewLabels[mode], "", renderer.DebugView == mode, true) {
+			setDebugView(mode)
+		}
+	}
+	imgui.Separator()
+	if imgui.MenuItemV("Wireframe", "", renderer.Debug, true) {
+		renderer.Debug = !renderer.Debug
+	}
+	if imgui.MenuItemV("Bounding Spheres", "", renderer.ShowBoundingSpheres, true) {
+		renderer.ShowBoundingSpheres = !renderer.ShowBoundingSpheres
+	}
+	if imgui.MenuItemV("Bounding Boxes", "", renderer.ShowBoundingBoxes, true) {
+		renderer.ShowBoundingBoxes = !renderer.ShowBoundingBoxes
+	}
+	imgui.EndMenu()
+}
+
+// debugViewStatus returns the menu bar note for an active debug view, or ""
+func debugViewStatus() string {
+	if renderer.DebugView == renderer.DebugViewLit {
+		return ""
+	}
+	return fmt.Sprintf("View: %s | ", debugViewLabels[renderer.DebugView])
+}
+
+func setDebugView(mode renderer.DebugViewMode) {
+	renderer.DebugView = mode
+	logToConsole(fmt.Sprintf("Debug view: %s", debugViewLabels[mode]), "info")
+}
+
+// executeDebugViewCommand handles "view [mode]"
+func executeDebugViewCommand(args []string) {
+	if len(args) == 0 {
+		names := make([]string, len(renderer.DebugViewModes))
+		for i, mode := range renderer.DebugViewModes {
+			names[i] = mode.String()
+		}
+		logToConsole(fmt.Sprintf("Debug view: %s", renderer.DebugView), "info")
+		logToConsole("Usage: view ["+strings.Join(names, "|")+"]", "info")
+		return
+	}
+	mode, ok := renderer.ParseDebugViewMode(args[0])
+	if !ok {
+		logToConsole(fmt.Sprintf("Unknown view mode: %s", args[0]), "warning")
+		return
+	}
+	setDebugView(mode)
+}
+
+// executeBoundsCommand handles "bounds [sphere|box|all|off]"
+func executeBoundsCommand(args []string) {
+	if len(args) == 0 {
+		logToConsole("Usage: bounds [sphere|box|all|off]", "warning")
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "sphere", "spheres":
+		renderer.ShowBoundingSpheres = !renderer.ShowBoundingSpheres
+	case "box", "boxes", "aabb":
+		renderer.ShowBoundingBoxes = !renderer.ShowBoundingBoxes
+	case "all", "on":
+		renderer.ShowBoundingSpheres, renderer.ShowBoundingBoxes = true, true
+	case "off":
+		renderer.ShowBoundingSpheres, renderer.ShowBoundingBoxes = false, false
+	default:
+		logToConsole("Usage: bounds [sphere|box|all|off]", "warning")
+		return
+	}
+	logToConsole(fmt.Sprintf("Bounding spheres: %v, boxes: %v", renderer.ShowBoundingSpheres, renderer.ShowBoundingBoxes), "info")
+}
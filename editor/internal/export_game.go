@@ -2,6 +2,8 @@ package editor
 
 import (
 	"Gopher3D/internal/renderer"
+	"Gopher3D/internal/ui"
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -215,6 +217,7 @@ func exportGame(config ExportConfig) error {
 			if err := json.Unmarshal(data, sceneData); err != nil {
 				return fmt.Errorf("failed to parse scene %s: %v", scenePath, err)
 			}
+			migrateScene(sceneData)
 			allSceneData = append(allSceneData, sceneData)
 			if i == 0 {
 				primaryScene = sceneData
@@ -480,9 +483,39 @@ func copySceneAssets(scenePath string, outputDir string, scene *SceneData) error
 			}
 		}
 
+		if sceneModel.MaterialAsset != "" {
+			materialPath, err := exportMaterialAsset(projectAbsolutePath(sceneModel.MaterialAsset), assetsDir)
+			if err != nil {
+				logToConsole(fmt.Sprintf("Warning: Could not export material for %s: %v", sceneModel.Name, err), "warning")
+				exportModel.MaterialAsset = ""
+			} else {
+				exportModel.MaterialAsset = materialPath
+			}
+		}
+
+		// Texture files ship next to the models; render textures are drawn at runtime
+		if _, isRenderTexture := renderer.RenderTextureName(sceneModel.TexturePath); sceneModel.TexturePath != "" && !isRenderTexture {
+			textureName := filepath.Base(sceneModel.TexturePath)
+			if err := copyFile(sceneModel.TexturePath, filepath.Join(assetsDir, textureName)); err != nil {
+				logToConsole(fmt.Sprintf("Warning: Could not copy texture for %s: %v", sceneModel.Name, err), "warning")
+				exportModel.TexturePath = ""
+			} else {
+				exportModel.TexturePath = textureName
+			}
+		}
+
 		exportScene.Models = append(exportScene.Models, exportModel)
 	}
 
+	// Project shaders (custom material shaders and overrides of the built-in ones)
+	if dir := projectShaderDir(); dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			if err := copyDir(dir, filepath.Join(assetsDir, "shaders")); err != nil {
+				logToConsole(fmt.Sprintf("Warning: Could not copy shaders: %v", err), "warning")
+			}
+		}
+	}
+
 	// Serialize water if present
 	if scene.Water != nil && activeWaterSim != nil && activeWaterSim.Model != nil {
 		meshFilename := "water_mesh.gmesh"
@@ -502,6 +535,38 @@ func copySceneAssets(scenePath string, outputDir string, scene *SceneData) error
 		}
 	}
 
+	copyTextFonts(scene.GameObjects, sceneDir, assetsDir)
+
+	exportScene.UILayouts = nil
+	for _, path := range scene.UILayouts {
+		layoutPath, err := exportUILayout(path, assetsDir)
+		if err != nil {
+			logToConsole(fmt.Sprintf("Warning: Could not export UI layout %s: %v", filepath.Base(path), err), "warning")
+			continue
+		}
+		exportScene.UILayouts = append(exportScene.UILayouts, layoutPath)
+	}
+
+	// IES profiles ship in assets/ies, referenced relative to the assets directory
+	exportScene.Lights = append([]SceneLight(nil), scene.Lights...)
+	for i := range exportScene.Lights {
+		light := &exportScene.Lights[i]
+		if light.IESProfile == "" {
+			continue
+		}
+		rel := "ies/" + filepath.Base(light.IESProfile)
+		err := os.MkdirAll(filepath.Join(assetsDir, "ies"), 0755)
+		if err == nil {
+			err = copyFile(light.IESProfile, filepath.Join(assetsDir, filepath.FromSlash(rel)))
+		}
+		if err != nil {
+			logToConsole(fmt.Sprintf("Warning: Could not copy IES profile for %s: %v", light.Name, err), "warning")
+			light.IESProfile = ""
+			continue
+		}
+		light.IESProfile = rel
+	}
+
 	// Write the updated scene file
 	sceneDest := filepath.Join(assetsDir, "scene.json")
 	sceneJSON, err := json.MarshalIndent(exportScene, "", "  ")
@@ -526,6 +591,111 @@ func copySceneAssets(scenePath string, outputDir string, scene *SceneData) error
 	return nil
 }
 
+// exportMaterialAsset writes a .gmat and its textures into assets/materials and
+// returns the material's path relative to the assets directory
+func exportMaterialAsset(path, assetsDir string) (string, error) {
+	// Start from the loaded asset so unsaved inspector edits are exported too
+	material, err := renderer.LoadMaterialAsset(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := material.Encode()
+	if err != nil {
+		return "", err
+	}
+	exported, err := renderer.ParseMaterialAsset(data)
+	if err != nil {
+		return "", err
+	}
+
+	materialsDir := filepath.Join(assetsDir, "materials")
+	if err := os.MkdirAll(filepath.Join(materialsDir, "textures"), 0755); err != nil {
+		return "", err
+	}
+	for i := range exported.Textures {
+		// Render textures are drawn at runtime, not shipped as files
+		if _, ok := renderer.RenderTextureName(exported.Textures[i].Path); ok {
+			continue
+		}
+		src := material.ResolvePath(exported.Textures[i].Path)
+		rel := "textures/" + exportedTextureName(src)
+		if err := copyFile(src, filepath.Join(materialsDir, filepath.FromSlash(rel))); err != nil {
+			logToConsole(fmt.Sprintf("Warning: Could not copy material texture %s: %v", filepath.Base(src), err), "warning")
+		}
+		exported.Textures[i].Path = rel
+	}
+
+	if data, err = exported.Encode(); err != nil {
+		return "", err
+	}
+	name := filepath.Base(material.Path)
+	if err := os.WriteFile(filepath.Join(materialsDir, name), data, 0644); err != nil {
+		return "", err
+	}
+	return "materials/" + name, nil
+}
+
+// exportedTextureName names a copied texture after its source path's hash, so
+// textures sharing a file name in different directories don't overwrite each other
+func exportedTextureName(src string) string {
+	if abs, err := filepath.Abs(src); err == nil {
+		src = abs
+	}
+	sum := sha1.Sum([]byte(filepath.ToSlash(src)))
+	return fmt.Sprintf("%x_%s", sum[:4], filepath.Base(src))
+}
+
+// exportUILayout writes a .gui layout and the images and fonts it uses into
+// assets/ui and returns the layout's path relative to the assets directory
+func exportUILayout(path, assetsDir string) (string, error) {
+	// Start from the layout on screen so unsaved editor changes are exported too
+	layout := findUILayout(path)
+	if layout == nil {
+		loaded, err := ui.LoadLayout(path)
+		if err != nil {
+			return "", err
+		}
+		layout = loaded
+	}
+	data, err := json.Marshal(layout)
+	if err != nil {
+		return "", err
+	}
+	exported, err := ui.ParseLayout(data)
+	if err != nil {
+		return "", err
+	}
+
+	uiDir := filepath.Join(assetsDir, "ui")
+	if err := os.MkdirAll(filepath.Join(uiDir, "files"), 0755); err != nil {
+		return "", err
+	}
+	copyLayoutFile := func(file *string) {
+		if *file == "" {
+			return
+		}
+		src := layout.ResolvePath(*file)
+		rel := "files/" + filepath.Base(src)
+		if err := copyFile(src, filepath.Join(uiDir, filepath.FromSlash(rel))); err != nil {
+			logToConsole(fmt.Sprintf("Warning: Could not copy UI file %s: %v", filepath.Base(src), err), "warning")
+		}
+		*file = rel
+	}
+	exported.Root.Walk(func(w *ui.Widget) {
+		copyLayoutFile(&w.Image)
+		copyLayoutFile(&w.Font)
+	})
+
+	if data, err = json.MarshalIndent(exported, "", "  "); err != nil {
+		return "", err
+	}
+	name := filepath.Base(path)
+	if err := os.WriteFile(filepath.Join(uiDir, name), data, 0644); err != nil {
+		return "", err
+	}
+	return "ui/" + name, nil
+}
+
 // sanitizeFilename removes invalid characters from filename
 func sanitizeFilename(name string) string {
 	invalid := []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|", " "}
@@ -603,6 +773,7 @@ func generateRuntimeCode(scene *SceneData) string {
 		`"Gopher3D/internal/engine"`,
 		`"Gopher3D/internal/loader"`,
 		`"Gopher3D/internal/renderer"`,
+		`"Gopher3D/internal/ui"`,
 		`"encoding/json"`,
 		`"fmt"`,
 		`"os"`,
@@ -647,6 +818,11 @@ func main() {
 		if sceneReady {
 			// Update water animation
 			updateWater(deltaTime)
+
+			// Advance the day/night cycle
+			if timeOfDay != nil {
+				timeOfDay.Advance(float32(deltaTime))
+			}
 `
 	if hasScriptComponents {
 		code += `			// Update scripts
@@ -684,7 +860,22 @@ func loadGame() {
 		return
 	}
 
+	// Project shaders used by custom materials ship next to the scene
+	shaderDir := filepath.Join(filepath.Dir(scenePath), "shaders")
+	if info, err := os.Stat(shaderDir); err == nil && info.IsDir() {
+		renderer.SetShaderDirectory(shaderDir)
+	}
+
+	// Stream textures in while the first frames render
+	if r, ok := gameEngine.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		r.AsyncTextureLoading = true
+	}
 	loadSceneData(&scene, filepath.Dir(scenePath))
+
+	// Build every shader variant the scene uses up front to avoid first-frame hitches
+	if r, ok := gameEngine.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		r.PrewarmShaderVariants()
+	}
 	fmt.Println("Game loaded!")
 }
 
@@ -712,6 +903,9 @@ func setupDefaultScene() {
 }
 
 func loadSceneData(scene *SceneData, assetsDir string) {
+	// Unversioned scenes sampled their textures without sRGB decoding
+	legacyTextures := scene.FormatVersion < 1
+
 	r, ok := gameEngine.GetRenderer().(*renderer.OpenGLRenderer)
 	if !ok {
 		fmt.Println("Could not get renderer")
@@ -777,6 +971,41 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 				model.Material.Alpha = 1.0
 			}
 		}
+
+		// Custom material (.gmat)
+		if m.MaterialAsset != "" {
+			material, err := renderer.LoadMaterialAsset(filepath.Join(assetsDir, m.MaterialAsset))
+			if err != nil {
+				fmt.Printf("Failed to load material for %s: %v\n", m.Name, err)
+			} else {
+				model.CustomMaterial = material
+			}
+		}
+
+		// Texture file, or a render texture drawn by a scene camera
+		if m.TexturePath != "" && model.Material != nil {
+			texturePath := m.TexturePath
+			if _, ok := renderer.RenderTextureName(texturePath); !ok {
+				texturePath = resolveAssetPath(texturePath, assetsDir)
+			}
+			sampler := renderer.SamplerSettings{}
+			if m.TextureSampler != nil {
+				sampler = *m.TextureSampler
+			}
+			if legacyTextures {
+				sampler.ColorSpace = renderer.ColorSpaceLinear
+			}
+			if texturePath != "" {
+				model.Material.TexturePath = texturePath
+				model.Material.Sampler = sampler
+				for i := range model.MaterialGroups {
+					if model.MaterialGroups[i].Material != nil {
+						model.MaterialGroups[i].Material.TexturePath = texturePath
+						model.MaterialGroups[i].Material.Sampler = sampler
+					}
+				}
+			}
+		}
 `
 	if hasScriptComponents {
 		code += `
@@ -802,19 +1031,43 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 	}
 	code += `
 		r.AddModel(model)
+		if m.Reflection != nil {
+			setupReflection(model, m.Reflection, r)
+		}
 		fmt.Printf("Loaded: %s\n", m.Name)
 	}
 
 	// Load lights
 	for i, l := range scene.Lights {
 		var light *renderer.Light
-		if l.Mode == "point" {
+		if l.Mode == "point" || l.Mode == "spot" {
 			light = renderer.CreatePointLight(
 				mgl.Vec3{l.Position[0], l.Position[1], l.Position[2]},
 				mgl.Vec3{l.Color[0], l.Color[1], l.Color[2]},
 				l.Intensity,
 				100.0, // Default range
 			)
+			light.Mode = l.Mode
+			if dir := (mgl.Vec3{l.Direction[0], l.Direction[1], l.Direction[2]}); dir.Len() > 0 {
+				light.Direction = dir
+			}
+			if l.IESProfile != "" {
+				if profile, err := renderer.LoadIESProfile(filepath.Join(assetsDir, filepath.FromSlash(l.IESProfile))); err != nil {
+					fmt.Printf("Warning: Could not load IES profile for %s: %v\n", l.Name, err)
+				} else {
+					light.IES = profile
+				}
+			}
+		} else if l.Mode == "rect" || l.Mode == "disk" {
+			light = renderer.CreateAreaLight(
+				l.Mode,
+				mgl.Vec3{l.Position[0], l.Position[1], l.Position[2]},
+				mgl.Vec3{l.Direction[0], l.Direction[1], l.Direction[2]},
+				mgl.Vec3{l.Color[0], l.Color[1], l.Color[2]},
+				l.Intensity,
+				l.AreaWidth,
+				l.AreaHeight,
+			)
 		} else {
 			light = renderer.CreateDirectionalLight(
 				mgl.Vec3{l.Direction[0], l.Direction[1], l.Direction[2]}.Normalize(),
@@ -863,12 +1116,17 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 		if activeCamera.Far > 0 {
 			gameEngine.Camera.Far = activeCamera.Far
 		}
-		gameEngine.Camera.UpdateProjection()
+		setupCameraMode(gameEngine.Camera, activeCamera, r)
 	} else {
 		gameEngine.Camera.Position = mgl.Vec3{0, 50, 150}
 		gameEngine.Camera.Speed = 100
 		gameEngine.Camera.InvertMouse = false
 	}
+	setupMainCameraComponent(scene.GameObjects, r)
+	setupTextComponents(scene.GameObjects, r, assetsDir)
+	setupUILayouts(scene.UILayouts, assetsDir)
+	setupCameraViews(scene.Cameras, activeCamera)
+	setupRenderTextures(scene.RenderTextures, scene.Cameras, r)
 
 	// If no lights in scene, add a minimal fallback light
 	if len(scene.Lights) == 0 {
@@ -899,6 +1157,8 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 			r.ClearColorG = scene.Skybox.Color[1]
 			r.ClearColorB = scene.Skybox.Color[2]
 			fmt.Printf("Skybox color set to: %.2f, %.2f, %.2f\n", scene.Skybox.Color[0], scene.Skybox.Color[1], scene.Skybox.Color[2])
+		} else if scene.Skybox.Type == "procedural" {
+			loadProceduralSky(scene.Skybox.Procedural, r)
 		} else if scene.Skybox.ImagePath != "" {
 			skyboxPath := resolveAssetPath(scene.Skybox.ImagePath, assetsDir)
 			if skyboxPath != "" {
@@ -921,6 +1181,23 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 		}
 		renderer.FaceCullingEnabled = scene.Rendering.FaceCulling
 		renderer.Debug = scene.Rendering.Wireframe
+		r.RenderPath, _ = renderer.ParseRenderPath(scene.Rendering.RenderPath)
+		if scene.Rendering.SSR != nil {
+			r.SSR = *scene.Rendering.SSR
+		}
+		if fog := scene.Rendering.Fog; fog != nil {
+			r.Fog = renderer.FogSettings{
+				Mode:             renderer.FogMode(fog.Mode),
+				Color:            mgl.Vec3{fog.Color[0], fog.Color[1], fog.Color[2]},
+				Density:          fog.Density,
+				HeightFogEnabled: fog.HeightFog,
+				HeightDensity:    fog.HeightDensity,
+				HeightBase:       fog.HeightBase,
+				HeightFalloff:    fog.HeightFalloff,
+				MaxOpacity:       fog.MaxOpacity,
+				SkyBlend:         fog.SkyBlend,
+			}
+		}
 	}
 
 	// Load water if present - use full water simulation with shaders
@@ -929,6 +1206,259 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 	}
 }
 
+// setupCameraViews draws every scene camera with a viewport alongside the active
+// camera, which keeps the whole window unless it has a viewport of its own
+func setupCameraViews(cameras []SceneCamera, active *SceneCamera) {
+	var views []*renderer.Camera
+	for i := range cameras {
+		data := &cameras[i]
+		rect := renderer.ViewportRect{X: data.Viewport[0], Y: data.Viewport[1], Width: data.Viewport[2], Height: data.Viewport[3]}
+		if data == active {
+			gameEngine.Camera.Viewport = rect
+			gameEngine.Camera.Depth = data.Depth
+			continue
+		}
+		if rect.IsFull() {
+			continue
+		}
+		cam := newSceneCamera(data)
+		cam.Viewport = rect
+		cam.Depth = data.Depth
+		views = append(views, cam)
+	}
+	if len(views) > 0 {
+		gameEngine.Views = append([]*renderer.Camera{gameEngine.Camera}, views...)
+	}
+}
+
+// setupRenderTextures points each render texture at the scene camera that draws it
+func setupRenderTextures(textures []SceneRenderTexture, cameras []SceneCamera, r *renderer.OpenGLRenderer) {
+	for _, entry := range textures {
+		rt := r.RenderTexture(entry.Name)
+		rt.Width, rt.Height = entry.Width, entry.Height
+		rt.UpdateRate = entry.UpdateRate
+		for i := range cameras {
+			if cameras[i].Name == entry.Camera {
+				rt.Camera = newSceneCamera(&cameras[i])
+				break
+			}
+		}
+		if rt.Camera == nil {
+			fmt.Printf("Render texture %s: camera %q not found\n", entry.Name, entry.Camera)
+		}
+	}
+}
+
+// setupReflection makes a model a planar mirror, with the mirror material
+// unless it has its own
+func setupReflection(model *renderer.Model, data *SceneReflection, r *renderer.OpenGLRenderer) {
+	p := renderer.NewPlanarReflection("mirror:"+model.Name, model)
+	if data.Normal != ([3]float32{}) {
+		p.Normal = mgl.Vec3{data.Normal[0], data.Normal[1], data.Normal[2]}
+	}
+	if data.Resolution > 0 {
+		p.Resolution = data.Resolution
+	}
+	p.ClipOffset = data.ClipOffset
+	r.AddPlanarReflection(p)
+	if model.CustomMaterial == nil {
+		model.CustomMaterial = renderer.NewMirrorMaterial(p)
+	}
+}
+
+// setupCameraMode applies a scene camera's projection and controller
+func setupCameraMode(cam *renderer.Camera, data *SceneCamera, r *renderer.OpenGLRenderer) {
+	cam.ProjectionMode = renderer.ParseProjectionMode(data.Projection)
+	if data.OrthoSize > 0 {
+		cam.OrthoSize = data.OrthoSize
+	}
+	if data.Controller != nil {
+		cam.Controller = renderer.NewCameraController(*data.Controller, r.FindModel(data.Controller.Follow))
+	}
+	cam.UpdateProjection()
+}
+
+// setupUILayouts puts the scene's UI layouts on screen for scripts to find by ID
+func setupUILayouts(paths []string, assetsDir string) {
+	for _, path := range paths {
+		layout, err := ui.LoadLayout(filepath.Join(assetsDir, filepath.FromSlash(path)))
+		if err != nil {
+			fmt.Printf("Warning: Could not load UI layout %s: %v\n", path, err)
+			continue
+		}
+		ui.GlobalManager.Add(layout)
+	}
+}
+
+// setupTextComponents draws the screen labels and world text of game objects
+// with a TextComponent
+func setupTextComponents(objects []SceneGameObject, r *renderer.OpenGLRenderer, assetsDir string) {
+	for _, obj := range objects {
+		for _, comp := range obj.Components {
+			p := comp.Properties
+			if comp.Category != "Text" {
+				continue
+			}
+			num := func(key string) float32 {
+				v, _ := p[key].(float64)
+				return float32(v)
+			}
+			str := func(key string) string {
+				v, _ := p[key].(string)
+				return v
+			}
+			vec := func(key string, out []float32) {
+				if v, ok := p[key].([]interface{}); ok && len(v) == len(out) {
+					for i := range out {
+						f, _ := v[i].(float64)
+						out[i] = float32(f)
+					}
+				}
+			}
+
+			text := renderer.NewText(str("text"))
+			text.Name = obj.Name
+			if fontPath := str("font_path"); fontPath != "" {
+				font, err := renderer.LoadFont(resolveAssetPath(fontPath, assetsDir))
+				if err != nil {
+					fmt.Printf("Warning: Could not load font %s, using the default: %v\n", filepath.Base(fontPath), err)
+				} else {
+					text.Font = font
+				}
+			}
+			if size := num("size"); size > 0 {
+				text.Size = size
+			}
+			vec("color", text.Color[:3])
+			if _, ok := p["alpha"]; ok {
+				text.Color[3] = num("alpha")
+			}
+			if align := str("align"); align != "" {
+				text.Align = renderer.TextAlign(align)
+			}
+			text.LineSpacing = num("line_spacing")
+			text.MaxWidth = num("max_width")
+			text.Billboard, _ = p["billboard"].(bool)
+			text.Hidden = !obj.Active
+
+			if str("space") == string(renderer.TextWorld) {
+				text.Space = renderer.TextWorld
+				text.Position = mgl.Vec3(obj.Position)
+				text.Rotation = mgl.AnglesToQuat(
+					mgl.DegToRad(obj.Rotation[2]), mgl.DegToRad(obj.Rotation[1]), mgl.DegToRad(obj.Rotation[0]), mgl.ZYX)
+			} else {
+				var screen [2]float32
+				vec("screen_position", screen[:])
+				text.Position = mgl.Vec3{screen[0], screen[1], 0}
+			}
+			r.AddText(text)
+		}
+	}
+}
+
+// setupMainCameraComponent lets a game object with a main CameraComponent place
+// the camera and choose its projection and controller
+func setupMainCameraComponent(objects []SceneGameObject, r *renderer.OpenGLRenderer) {
+	for _, obj := range objects {
+		for _, comp := range obj.Components {
+			p := comp.Properties
+			if main, _ := p["is_main"].(bool); comp.Category != "Camera" || !main {
+				continue
+			}
+			num := func(key string) float32 {
+				v, _ := p[key].(float64)
+				return float32(v)
+			}
+			str := func(key string) string {
+				v, _ := p[key].(string)
+				return v
+			}
+			var target [3]float32
+			if v, ok := p["target"].([]interface{}); ok && len(v) == 3 {
+				for i := range target {
+					f, _ := v[i].(float64)
+					target[i] = float32(f)
+				}
+			}
+
+			data := &SceneCamera{
+				Name:       obj.Name,
+				Position:   obj.Position,
+				FOV:        num("fov"),
+				Near:       num("near"),
+				Far:        num("far"),
+				Projection: str("projection"),
+				OrthoSize:  num("ortho_size"),
+				Controller: &renderer.CameraControllerSettings{
+					Mode:      renderer.CameraMode(str("controller")),
+					Target:    target,
+					Follow:    str("follow"),
+					Distance:  num("distance"),
+					Yaw:       num("yaw"),
+					Pitch:     num("pitch"),
+					Stiffness: num("stiffness"),
+				},
+			}
+			cam := gameEngine.Camera
+			cam.Position = mgl.Vec3(data.Position)
+			if data.FOV > 0 {
+				cam.Fov = data.FOV
+			}
+			if data.Near > 0 {
+				cam.Near = data.Near
+			}
+			if data.Far > 0 {
+				cam.Far = data.Far
+			}
+			// Lens effects keep the camera's defaults for anything the scene leaves out
+			if v, ok := p["depth_of_field"].(bool); ok {
+				cam.DepthOfField.Enabled = v
+			}
+			if v := num("focus_distance"); v > 0 {
+				cam.DepthOfField.FocusDistance = v
+			}
+			if v := num("f_stop"); v > 0 {
+				cam.DepthOfField.FStop = v
+			}
+			if _, ok := p["bokeh_blades"].(float64); ok {
+				cam.DepthOfField.BokehBlades = int(num("bokeh_blades"))
+			}
+			if v, ok := p["motion_blur"].(bool); ok {
+				cam.MotionBlur.Enabled = v
+			}
+			if v := num("shutter"); v > 0 {
+				cam.MotionBlur.Shutter = v
+			}
+			cam.Controller = nil
+			setupCameraMode(cam, data, r)
+			return
+		}
+	}
+}
+
+// newSceneCamera creates a camera from its scene data
+func newSceneCamera(data *SceneCamera) *renderer.Camera {
+	cam := renderer.NewDefaultCamera(gameEngine.Width, gameEngine.Height)
+	cam.Name = data.Name
+	cam.Position = mgl.Vec3{data.Position[0], data.Position[1], data.Position[2]}
+	if data.FOV > 0 {
+		cam.Fov = data.FOV
+	}
+	if data.Near > 0 {
+		cam.Near = data.Near
+	}
+	if data.Far > 0 {
+		cam.Far = data.Far
+	}
+	cam.ProjectionMode = renderer.ParseProjectionMode(data.Projection)
+	if data.OrthoSize > 0 {
+		cam.OrthoSize = data.OrthoSize
+	}
+	cam.UpdateProjection()
+	cam.SetRotation(data.Rotation[0], data.Rotation[1])
+	return cam
+}
+
 func findAsset(name string) string {
 	exePath, _ := os.Executable()
 	exeDir := filepath.Dir(exePath)
@@ -977,14 +1507,17 @@ func resolveAssetPath(path, assetsDir string) string {
 
 // Scene data structures (must match editor format)
 type SceneData struct {
-	GameObjects []SceneGameObject     ` + "`json:\"game_objects,omitempty\"`" + `
-	Models      []SceneModel          ` + "`json:\"models,omitempty\"`" + `
-	Lights      []SceneLight          ` + "`json:\"lights,omitempty\"`" + `
-	Camera      *SceneCamera          ` + "`json:\"camera,omitempty\"`" + `
-	Cameras     []SceneCamera         ` + "`json:\"cameras,omitempty\"`" + `
-	Water       *SceneWater           ` + "`json:\"water,omitempty\"`" + `
-	Skybox      *SceneSkybox          ` + "`json:\"skybox,omitempty\"`" + `
-	Rendering   *SceneRenderingConfig ` + "`json:\"rendering,omitempty\"`" + `
+	FormatVersion  int                   ` + "`json:\"format_version,omitempty\"`" + `
+	GameObjects    []SceneGameObject     ` + "`json:\"game_objects,omitempty\"`" + `
+	Models         []SceneModel          ` + "`json:\"models,omitempty\"`" + `
+	Lights         []SceneLight          ` + "`json:\"lights,omitempty\"`" + `
+	Camera         *SceneCamera          ` + "`json:\"camera,omitempty\"`" + `
+	Cameras        []SceneCamera         ` + "`json:\"cameras,omitempty\"`" + `
+	RenderTextures []SceneRenderTexture  ` + "`json:\"render_textures,omitempty\"`" + `
+	Water          *SceneWater           ` + "`json:\"water,omitempty\"`" + `
+	Skybox         *SceneSkybox          ` + "`json:\"skybox,omitempty\"`" + `
+	Rendering      *SceneRenderingConfig ` + "`json:\"rendering,omitempty\"`" + `
+	UILayouts      []string              ` + "`json:\"ui_layouts,omitempty\"`" + `
 }
 
 type SceneGameObject struct {
@@ -1007,6 +1540,20 @@ type SceneCamera struct {
 	Far         float32    ` + "`json:\"far,omitempty\"`" + `
 	InvertMouse bool       ` + "`json:\"invert_mouse\"`" + `
 	IsActive    bool       ` + "`json:\"is_active\"`" + `
+	Viewport    [4]float32 ` + "`json:\"viewport,omitzero\"`" + `
+	Depth       int        ` + "`json:\"depth,omitempty\"`" + `
+
+	Projection string                             ` + "`json:\"projection,omitempty\"`" + ` // "perspective" or "orthographic"
+	OrthoSize  float32                            ` + "`json:\"ortho_size,omitempty\"`" + ` // Half the visible height when orthographic
+	Controller *renderer.CameraControllerSettings ` + "`json:\"controller,omitempty\"`" + ` // Fly controls when nil
+}
+
+type SceneRenderTexture struct {
+	Name       string  ` + "`json:\"name\"`" + `
+	Width      int32   ` + "`json:\"width\"`" + `
+	Height     int32   ` + "`json:\"height\"`" + `
+	UpdateRate float32 ` + "`json:\"update_rate,omitempty\"`" + ` // Updates per second; 0 is every frame
+	Camera     string  ` + "`json:\"camera\"`" + `
 }
 
 type SceneModel struct {
@@ -1022,9 +1569,20 @@ type SceneModel struct {
 	Metallic      float32          ` + "`json:\"metallic\"`" + `
 	Roughness     float32          ` + "`json:\"roughness\"`" + `
 	Alpha         float32          ` + "`json:\"alpha\"`" + `
+	TexturePath   string           ` + "`json:\"texture_path,omitempty\"`" + ` // File, or rendertexture:<name>
+	MaterialAsset string           ` + "`json:\"material_asset,omitempty\"`" + `
+	Reflection    *SceneReflection ` + "`json:\"reflection,omitempty\"`" + `
+
+	TextureSampler *renderer.SamplerSettings ` + "`json:\"texture_sampler,omitempty\"`" + `
 	Components    []SceneComponent ` + "`json:\"components,omitempty\"`" + `
 }
 
+type SceneReflection struct {
+	Normal     [3]float32 ` + "`json:\"normal\"`" + ` // In the model's space
+	Resolution float32    ` + "`json:\"resolution\"`" + `
+	ClipOffset float32    ` + "`json:\"clip_offset,omitempty\"`" + `
+}
+
 type SceneComponent struct {
 	Type       string                 ` + "`json:\"type\"`" + `
 	Category   string                 ` + "`json:\"category\"`" + `
@@ -1039,33 +1597,55 @@ type SceneLight struct {
 	Color           [3]float32 ` + "`json:\"color\"`" + `
 	Intensity       float32    ` + "`json:\"intensity\"`" + `
 	AmbientStrength float32    ` + "`json:\"ambient_strength\"`" + `
+	AreaWidth       float32    ` + "`json:\"area_width,omitempty\"`" + `
+	AreaHeight      float32    ` + "`json:\"area_height,omitempty\"`" + `
+	IESProfile      string     ` + "`json:\"ies_profile,omitempty\"`" + `
 }
 
 type SceneWater struct {
-	OceanSize           float32    ` + "`json:\"ocean_size\"`" + `
-	BaseAmplitude       float32    ` + "`json:\"base_amplitude\"`" + `
-	WaterColor          [3]float32 ` + "`json:\"water_color\"`" + `
-	Transparency        float32    ` + "`json:\"transparency\"`" + `
-	WaveSpeedMultiplier float32    ` + "`json:\"wave_speed_multiplier\"`" + `
-	WaveHeight          float32    ` + "`json:\"wave_height\"`" + `
-	WaveRandomness      float32    ` + "`json:\"wave_randomness\"`" + `
-	Position            [3]float32 ` + "`json:\"position\"`" + `
-	FoamEnabled         bool       ` + "`json:\"foam_enabled\"`" + `
-	FoamIntensity       float32    ` + "`json:\"foam_intensity\"`" + `
-	CausticsEnabled     bool       ` + "`json:\"caustics_enabled\"`" + `
-	CausticsIntensity   float32    ` + "`json:\"caustics_intensity\"`" + `
-	CausticsScale       float32    ` + "`json:\"caustics_scale\"`" + `
-	SpecularIntensity   float32    ` + "`json:\"specular_intensity\"`" + `
-	NormalStrength      float32    ` + "`json:\"normal_strength\"`" + `
-	DistortionStrength  float32    ` + "`json:\"distortion_strength\"`" + `
-	ShadowStrength      float32    ` + "`json:\"shadow_strength\"`" + `
-	MeshDataFile        string     ` + "`json:\"mesh_data_file,omitempty\"`" + `
+	OceanSize            float32    ` + "`json:\"ocean_size\"`" + `
+	BaseAmplitude        float32    ` + "`json:\"base_amplitude\"`" + `
+	WaterColor           [3]float32 ` + "`json:\"water_color\"`" + `
+	Transparency         float32    ` + "`json:\"transparency\"`" + `
+	WaveSpeedMultiplier  float32    ` + "`json:\"wave_speed_multiplier\"`" + `
+	WaveHeight           float32    ` + "`json:\"wave_height\"`" + `
+	WaveRandomness       float32    ` + "`json:\"wave_randomness\"`" + `
+	Position             [3]float32 ` + "`json:\"position\"`" + `
+	FoamEnabled          bool       ` + "`json:\"foam_enabled\"`" + `
+	FoamIntensity        float32    ` + "`json:\"foam_intensity\"`" + `
+	CausticsEnabled      bool       ` + "`json:\"caustics_enabled\"`" + `
+	CausticsIntensity    float32    ` + "`json:\"caustics_intensity\"`" + `
+	CausticsScale        float32    ` + "`json:\"caustics_scale\"`" + `
+	SpecularIntensity    float32    ` + "`json:\"specular_intensity\"`" + `
+	NormalStrength       float32    ` + "`json:\"normal_strength\"`" + `
+	DistortionStrength   float32    ` + "`json:\"distortion_strength\"`" + `
+	ReflectionResolution float32    ` + "`json:\"reflection_resolution,omitempty\"`" + `
+	ShadowStrength       float32    ` + "`json:\"shadow_strength\"`" + `
+	MeshDataFile         string     ` + "`json:\"mesh_data_file,omitempty\"`" + `
 }
 
 type SceneSkybox struct {
-	Type      string     ` + "`json:\"type\"`" + `
-	ImagePath string     ` + "`json:\"image_path\"`" + `
-	Color     [3]float32 ` + "`json:\"color\"`" + `
+	Type       string              ` + "`json:\"type\"`" + `
+	ImagePath  string              ` + "`json:\"image_path\"`" + `
+	Color      [3]float32          ` + "`json:\"color\"`" + `
+	Procedural *SceneProceduralSky ` + "`json:\"procedural,omitempty\"`" + `
+}
+
+type SceneProceduralSky struct {
+	TimeOfDay         float32    ` + "`json:\"time_of_day\"`" + `
+	DayLength         float32    ` + "`json:\"day_length\"`" + `
+	Paused            bool       ` + "`json:\"paused\"`" + `
+	SunAzimuth        float32    ` + "`json:\"sun_azimuth\"`" + `
+	SunTilt           float32    ` + "`json:\"sun_tilt\"`" + `
+	DayIntensity      float32    ` + "`json:\"day_intensity\"`" + `
+	NightIntensity    float32    ` + "`json:\"night_intensity\"`" + `
+	DayAmbient        float32    ` + "`json:\"day_ambient\"`" + `
+	NightAmbient      float32    ` + "`json:\"night_ambient\"`" + `
+	SunIntensity      float32    ` + "`json:\"sun_intensity\"`" + `
+	Rayleigh          [3]float32 ` + "`json:\"rayleigh\"`" + `
+	Mie               float32    ` + "`json:\"mie\"`" + `
+	MieDirectionality float32    ` + "`json:\"mie_directionality\"`" + `
+	Exposure          float32    ` + "`json:\"exposure\"`" + `
 }
 
 type SceneRenderingConfig struct {
@@ -1075,6 +1655,66 @@ type SceneRenderingConfig struct {
 	FaceCulling bool       ` + "`json:\"face_culling\"`" + `
 	Wireframe   bool       ` + "`json:\"wireframe\"`" + `
 	SkyboxColor [3]float32 ` + "`json:\"skybox_color\"`" + `
+	Fog         *SceneFog  ` + "`json:\"fog,omitempty\"`" + `
+	RenderPath  string     ` + "`json:\"render_path,omitempty\"`" + `
+
+	SSR *renderer.SSRSettings ` + "`json:\"ssr,omitempty\"`" + `
+}
+
+type SceneFog struct {
+	Mode          string     ` + "`json:\"mode\"`" + `
+	Color         [3]float32 ` + "`json:\"color\"`" + `
+	Density       float32    ` + "`json:\"density\"`" + `
+	HeightFog     bool       ` + "`json:\"height_fog\"`" + `
+	HeightDensity float32    ` + "`json:\"height_density\"`" + `
+	HeightBase    float32    ` + "`json:\"height_base\"`" + `
+	HeightFalloff float32    ` + "`json:\"height_falloff\"`" + `
+	MaxOpacity    float32    ` + "`json:\"max_opacity\"`" + `
+	SkyBlend      float32    ` + "`json:\"sky_blend\"`" + `
+}
+
+// Day/night cycle driving the sun light, present when the scene uses a procedural sky
+var timeOfDay *renderer.TimeOfDay
+
+// loadProceduralSky creates the atmospheric sky and its time-of-day controller
+func loadProceduralSky(p *SceneProceduralSky, r *renderer.OpenGLRenderer) {
+	timeOfDay = renderer.NewTimeOfDay(gameEngine.Light, 10.0)
+	if p != nil {
+		timeOfDay.Hour = p.TimeOfDay
+		timeOfDay.DayLength = p.DayLength
+		timeOfDay.Paused = p.Paused
+		timeOfDay.SunAzimuth = p.SunAzimuth
+		timeOfDay.SunTilt = p.SunTilt
+		timeOfDay.DayIntensity = p.DayIntensity
+		timeOfDay.NightIntensity = p.NightIntensity
+		timeOfDay.DayAmbient = p.DayAmbient
+		timeOfDay.NightAmbient = p.NightAmbient
+		if p.SunIntensity > 0 {
+			timeOfDay.Atmosphere.SunIntensity = p.SunIntensity
+		}
+		if p.Rayleigh != [3]float32{} {
+			timeOfDay.Atmosphere.RayleighCoefficient = mgl.Vec3{p.Rayleigh[0], p.Rayleigh[1], p.Rayleigh[2]}
+		}
+		if p.Mie > 0 {
+			timeOfDay.Atmosphere.MieCoefficient = p.Mie
+		}
+		if p.MieDirectionality > 0 {
+			timeOfDay.Atmosphere.MieDirectionality = p.MieDirectionality
+		}
+		if p.Exposure > 0 {
+			timeOfDay.Atmosphere.Exposure = p.Exposure
+		}
+	}
+
+	sky, err := renderer.CreateProceduralSkybox(timeOfDay.Atmosphere)
+	if err != nil {
+		fmt.Printf("Failed to create procedural sky: %v\n", err)
+		timeOfDay = nil
+		return
+	}
+	r.SetSkybox(sky)
+	timeOfDay.Apply()
+	fmt.Printf("Procedural sky loaded at %.1fh\n", timeOfDay.Hour)
 }
 `
 
@@ -1103,10 +1743,14 @@ func loadWater(w *SceneWater, r *renderer.OpenGLRenderer) {
 	waterSim.SpecularIntensity = w.SpecularIntensity
 	waterSim.NormalStrength = w.NormalStrength
 	waterSim.DistortionStrength = w.DistortionStrength
+	waterSim.ReflectionResolution = w.ReflectionResolution
 	waterSim.ShadowStrength = w.ShadowStrength
 	
 	// Set sky color for reflections
 	waterSim.SetSkyColor(mgl.Vec3{r.ClearColorR, r.ClearColorG, r.ClearColorB})
+	if timeOfDay != nil {
+		timeOfDay.AddSkyColorTarget(waterSim)
+	}
 	
 	// Initialize mesh and add to scene
 	model := waterSim.InitializeMesh()
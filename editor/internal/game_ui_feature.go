@@ -0,0 +1,387 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"Gopher3D/internal/ui"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/sqweek/dialog"
+)
+
+// Game UI editor state
+var (
+	selectedUILayout *ui.Layout
+	selectedUIWidget *ui.Widget
+	newUIWidgetType  = ui.WidgetButton
+)
+
+// projectUIDir returns the default directory for .gui layouts
+func projectUIDir() string {
+	if CurrentProject == nil {
+		return "../resources/ui"
+	}
+	return filepath.Join(CurrentProject.Path, "resources", "ui")
+}
+
+// sceneUILayouts returns the layout files of the scene for saving
+func sceneUILayouts() []string {
+	var paths []string
+	for _, l := range ui.GlobalManager.Layouts() {
+		if l.Path != "" {
+			paths = append(paths, l.Path)
+		}
+	}
+	return paths
+}
+
+// loadSceneUILayouts replaces the layouts on screen with a scene's
+func loadSceneUILayouts(paths []string) {
+	clearUILayouts()
+	for _, path := range paths {
+		l, err := ui.LoadLayout(path)
+		if err != nil {
+			logToConsole(fmt.Sprintf("Failed to load UI layout: %v", err), "error")
+			continue
+		}
+		ui.GlobalManager.Add(l)
+	}
+	if len(paths) > 0 {
+		logToConsole(fmt.Sprintf("Loaded %d UI layouts from scene", len(ui.GlobalManager.Layouts())), "info")
+	}
+}
+
+// findUILayout returns the layout on screen loaded from path, or nil
+func findUILayout(path string) *ui.Layout {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	for _, l := range ui.GlobalManager.Layouts() {
+		if l.Path == path {
+			return l
+		}
+	}
+	return nil
+}
+
+// clearUILayouts takes every layout off screen when a scene is closed
+func clearUILayouts() {
+	ui.GlobalManager.Clear()
+	selectedUILayout, selectedUIWidget = nil, nil
+}
+
+// renderGameUIPanel edits the in-game UI layouts of the scene. Layouts are
+// drawn over the viewport as they will appear in the exported game.
+func renderGameUIPanel() {
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: 380, Y: 560}, imgui.ConditionFirstUseEver)
+	if !imgui.BeginV("Game UI", &ShowGameUI, 0) {
+		imgui.End()
+		return
+	}
+	defer imgui.End()
+
+	if imgui.Button("New Layout...") {
+		newUILayout()
+	}
+	imgui.SameLine()
+	if imgui.Button("Load Layout...") {
+		filename, err := dialog.File().
+			SetStartDir(projectUIDir()).
+			Filter("UI Layouts", strings.TrimPrefix(ui.LayoutExtension, ".")).
+			Title("Load UI Layout").
+			Load()
+		if err == nil && filename != "" {
+			if l, err := ui.LoadLayout(filename); err != nil {
+				logToConsole(fmt.Sprintf("Failed to load UI layout: %v", err), "error")
+			} else {
+				ui.GlobalManager.Add(l)
+				selectedUILayout, selectedUIWidget = l, nil
+				sceneModified = true
+			}
+		}
+	}
+
+	imgui.Separator()
+	layouts := ui.GlobalManager.Layouts()
+	if len(layouts) == 0 {
+		imgui.Text("No layouts in this scene")
+		return
+	}
+	for i, l := range layouts {
+		imgui.PushIDInt(i)
+		shown := !l.Hidden
+		if imgui.Checkbox("##shown", &shown) {
+			l.Hidden = !shown
+		}
+		imgui.SameLine()
+		if imgui.SelectableV(l.Name, l == selectedUILayout, 0, imgui.Vec2{}) {
+			selectedUILayout, selectedUIWidget = l, nil
+		}
+		imgui.PopID()
+	}
+
+	l := selectedUILayout
+	if l == nil {
+		return
+	}
+	imgui.Separator()
+	if imgui.Button("Save##layout") {
+		if err := l.Save(); err != nil {
+			logToConsole(fmt.Sprintf("Failed to save UI layout: %v", err), "error")
+		} else {
+			logToConsole(fmt.Sprintf("Saved UI layout to %s", l.Path), "info")
+		}
+	}
+	imgui.SameLine()
+	if imgui.Button("Reload##layout") {
+		if fresh, err := ui.LoadLayout(l.Path); err != nil {
+			logToConsole(fmt.Sprintf("Failed to reload UI layout: %v", err), "error")
+		} else {
+			ui.GlobalManager.Remove(l)
+			ui.GlobalManager.Add(fresh)
+			selectedUILayout, selectedUIWidget = fresh, nil
+			return
+		}
+	}
+	imgui.SameLine()
+	if imgui.Button("Remove##layout") {
+		ui.GlobalManager.Remove(l)
+		selectedUILayout, selectedUIWidget = nil, nil
+		sceneModified = true
+		return
+	}
+
+	if imgui.CollapsingHeaderV("Widgets", imgui.TreeNodeFlagsDefaultOpen) {
+		renderUIWidgetTree(l.Root)
+	}
+	if w := selectedUIWidget; w != nil && imgui.CollapsingHeaderV("Widget", imgui.TreeNodeFlagsDefaultOpen) {
+		renderUIWidgetActions(w)
+		if selectedUIWidget == w {
+			renderUIWidgetInspector(l, w)
+		}
+	}
+}
+
+// newUILayout creates a .gui file holding an empty full-window panel
+func newUILayout() {
+	dir := projectUIDir()
+	os.MkdirAll(dir, 0755)
+	filename, err := dialog.File().
+		SetStartDir(dir).
+		Filter("UI Layouts", strings.TrimPrefix(ui.LayoutExtension, ".")).
+		Title("New UI Layout").
+		Save()
+	if err != nil || filename == "" {
+		return
+	}
+	if filepath.Ext(filename) != ui.LayoutExtension {
+		filename += ui.LayoutExtension
+	}
+	l := ui.NewLayout(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)))
+	l.Path = filename
+	if err := l.Save(); err != nil {
+		logToConsole(fmt.Sprintf("Failed to create UI layout: %v", err), "error")
+		return
+	}
+	ui.GlobalManager.Add(l)
+	selectedUILayout, selectedUIWidget = l, l.Root
+	sceneModified = true
+	logToConsole(fmt.Sprintf("Created UI layout %s", filepath.Base(filename)), "info")
+}
+
+// renderUIWidgetTree lists a widget and its children for selection
+func renderUIWidgetTree(w *ui.Widget) {
+	flags := imgui.TreeNodeFlagsOpenOnArrow | imgui.TreeNodeFlagsDefaultOpen
+	if len(w.Children) == 0 {
+		flags |= imgui.TreeNodeFlagsLeaf
+	}
+	if w == selectedUIWidget {
+		flags |= imgui.TreeNodeFlagsSelected
+	}
+	label := string(w.Type)
+	if w.ID != "" {
+		label = fmt.Sprintf("%s (%s)", w.ID, w.Type)
+	}
+	open := imgui.TreeNodeV(fmt.Sprintf("%s##%p", label, w), flags)
+	if imgui.IsItemClicked() {
+		selectedUIWidget = w
+	}
+	if !open {
+		return
+	}
+	for _, child := range w.Children {
+		renderUIWidgetTree(child)
+	}
+	imgui.TreePop()
+}
+
+// renderUIWidgetActions adds, moves and deletes widgets around the selected one
+func renderUIWidgetActions(w *ui.Widget) {
+	imgui.PushItemWidth(110)
+	if imgui.BeginCombo("##newWidget", string(newUIWidgetType)) {
+		for _, t := range ui.WidgetTypes {
+			if imgui.SelectableV(string(t), t == newUIWidgetType, 0, imgui.Vec2{}) {
+				newUIWidgetType = t
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.PopItemWidth()
+	imgui.SameLine()
+	if imgui.Button("Add Child") {
+		child := &ui.Widget{Type: newUIWidgetType}
+		switch newUIWidgetType {
+		case ui.WidgetButton:
+			child.Text, child.Size = "Button", [2]float32{160, 36}
+		case ui.WidgetText:
+			child.Text = "Text"
+		case ui.WidgetSlider, ui.WidgetBar:
+			child.Max, child.Value = 1, 0.5
+		}
+		w.AddChild(child)
+		selectedUIWidget = child
+	}
+
+	parent := w.Parent()
+	if parent == nil {
+		return
+	}
+	siblings := parent.Children
+	index := 0
+	for i, sibling := range siblings {
+		if sibling == w {
+			index = i
+		}
+	}
+	imgui.SameLine()
+	if imgui.Button("Up") && index > 0 {
+		siblings[index-1], siblings[index] = siblings[index], siblings[index-1]
+	}
+	imgui.SameLine()
+	if imgui.Button("Down") && index < len(siblings)-1 {
+		siblings[index+1], siblings[index] = siblings[index], siblings[index+1]
+	}
+	imgui.SameLine()
+	if imgui.Button("Delete") {
+		parent.RemoveChild(w)
+		selectedUIWidget = parent
+	}
+}
+
+// renderUIWidgetInspector edits the selected widget's properties
+func renderUIWidgetInspector(l *ui.Layout, w *ui.Widget) {
+	imgui.InputText("ID", &w.ID)
+	if other := l.Find(w.ID); other != nil && other != w {
+		imgui.Text("Another widget already has this ID; the layout will not load")
+	}
+	if imgui.BeginCombo("Type", string(w.Type)) {
+		for _, t := range ui.WidgetTypes {
+			if imgui.SelectableV(string(t), t == w.Type, 0, imgui.Vec2{}) {
+				w.Type = t
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	imgui.Separator()
+	anchor := w.Anchor
+	if anchor == "" {
+		anchor = ui.AnchorTopLeft
+	}
+	if imgui.BeginCombo("Anchor", string(anchor)) {
+		for _, a := range ui.Anchors {
+			if imgui.SelectableV(string(a), a == anchor, 0, imgui.Vec2{}) {
+				w.Anchor = a
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.DragFloat2V("Offset", &w.Offset, 1, 0, 0, "%.0f", 0)
+	imgui.DragFloat2V("Size", &w.Size, 1, 0, 4096, "%.0f", 0)
+	imgui.DragFloatV("Padding", &w.Padding, 0.5, 0, 200, "%.0f", 0)
+	if w.Type == ui.WidgetRow || w.Type == ui.WidgetColumn {
+		imgui.DragFloatV("Spacing", &w.Spacing, 0.5, 0, 200, "%.0f", 0)
+	}
+	if parent := w.Parent(); parent != nil && (parent.Type == ui.WidgetRow || parent.Type == ui.WidgetColumn) {
+		imgui.Checkbox("Grow", &w.Grow)
+	}
+	imgui.Checkbox("Hidden", &w.Hidden)
+	imgui.SameLine()
+	imgui.Checkbox("Disabled", &w.Disabled)
+
+	imgui.Separator()
+	imgui.ColorEdit4("Color", &w.Color)
+	if w.Type == ui.WidgetSlider || w.Type == ui.WidgetBar {
+		imgui.ColorEdit4("Fill Color", &w.FillColor)
+		imgui.DragFloatV("Min", &w.Min, 0.1, 0, 0, "%.2f", 0)
+		imgui.DragFloatV("Max", &w.Max, 0.1, 0, 0, "%.2f", 0)
+		imgui.DragFloatV("Step", &w.Step, 0.01, 0, 0, "%.2f", 0)
+		if imgui.SliderFloatV("Value", &w.Value, w.Min, w.Max, "%.2f", 0) {
+			w.SetValue(w.Value)
+		}
+	}
+	if w.Type == ui.WidgetImage || w.Type == ui.WidgetButton || w.Type == ui.WidgetPanel {
+		renderUILayoutFileField(l, "Image", &w.Image, "Images", "png", "jpg", "jpeg")
+	}
+
+	if w.Type == ui.WidgetText || w.Type == ui.WidgetButton {
+		imgui.Separator()
+		imgui.InputTextMultiline("Text", &w.Text)
+		if renderUILayoutFileField(l, "Font", &w.Font, "Fonts", "ttf", "otf") {
+			if err := l.LoadFonts(); err != nil {
+				logToConsole(fmt.Sprintf("Failed to load font: %v", err), "error")
+				w.Font = ""
+				l.LoadFonts()
+			}
+		}
+		imgui.DragFloatV("Font Size", &w.FontSize, 0.5, 0, 300, "%.1f", 0)
+		imgui.ColorEdit4("Text Color", &w.TextColor)
+		align := w.TextAlign
+		if imgui.BeginCombo("Text Align", orDefault(string(align), "auto")) {
+			if imgui.SelectableV("auto", align == "", 0, imgui.Vec2{}) {
+				w.TextAlign = ""
+			}
+			for _, a := range renderer.TextAligns {
+				if imgui.SelectableV(string(a), a == align, 0, imgui.Vec2{}) {
+					w.TextAlign = a
+				}
+			}
+			imgui.EndCombo()
+		}
+	}
+}
+
+// renderUILayoutFileField picks a file stored relative to the layout, reporting whether it changed
+func renderUILayoutFileField(l *ui.Layout, label string, path *string, filterName string, extensions ...string) bool {
+	name, dir := "none", filepath.Dir(l.Path)
+	if *path != "" {
+		name, dir = filepath.Base(*path), filepath.Dir(l.ResolvePath(*path))
+	}
+	imgui.Text(fmt.Sprintf("%s: %s", label, name))
+	imgui.SameLine()
+	if imgui.Button("...##" + label) {
+		filename, err := dialog.File().
+			SetStartDir(dir).
+			Filter(filterName, extensions...).
+			Title("Select " + label).
+			Load()
+		if err == nil && filename != "" {
+			if rel, err := filepath.Rel(filepath.Dir(l.Path), filename); err == nil {
+				filename = filepath.ToSlash(rel)
+			}
+			*path = filename
+			return true
+		}
+	}
+	if *path != "" {
+		imgui.SameLine()
+		if imgui.Button("Clear##" + label) {
+			*path = ""
+			return true
+		}
+	}
+	return false
+}
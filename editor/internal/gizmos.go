@@ -21,6 +21,7 @@ func RenderGizmos() {
 	if Eng.Camera == nil {
 		return
 	}
+	renderAreaLightGizmos()
 
 	// Get the selected object's position
 	var pos mgl.Vec3
@@ -272,6 +273,42 @@ func applyGizmoMovement(newPos mgl.Vec3) {
 
 // Project world position to screen coordinates
 // Returns Vec3 where X,Y are screen coords and Z is depth (positive = in front)
+// renderAreaLightGizmos outlines the emitting surface of every rect and disk
+// light with a short line along the direction it shines, brighter when selected
+func renderAreaLightGizmos() {
+	openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		return
+	}
+	drawList := imgui.BackgroundDrawList()
+	for i, light := range openglRenderer.GetLights() {
+		if !light.IsArea() {
+			continue
+		}
+		color, thickness := imgui.PackedColor(0xA000D7FF), float32(1.5) // Amber
+		if selectedType == "light" && selectedLightIndex == i {
+			color, thickness = imgui.PackedColor(0xFF40F0FF), 2.5
+		}
+
+		outline := light.AreaOutline(32)
+		for j, p := range outline {
+			drawWorldLine(drawList, p, outline[(j+1)%len(outline)], color, thickness)
+		}
+		right, up := light.AreaAxes()
+		size := max(right.Len(), up.Len())
+		drawWorldLine(drawList, light.Position, light.Position.Add(light.Direction.Normalize().Mul(size)), color, thickness)
+	}
+}
+
+// drawWorldLine draws a world space segment, skipped when either end is behind the camera
+func drawWorldLine(drawList imgui.DrawList, from, to mgl.Vec3, color imgui.PackedColor, thickness float32) {
+	a, b := worldToScreen(from), worldToScreen(to)
+	if a.Z() < 0 || b.Z() < 0 {
+		return
+	}
+	drawList.AddLineV(imgui.Vec2{X: a.X(), Y: a.Y()}, imgui.Vec2{X: b.X(), Y: b.Y()}, color, thickness)
+}
+
 func worldToScreen(worldPos mgl.Vec3) mgl.Vec3 {
 	vp := Eng.Camera.GetViewProjection()
 
@@ -30,6 +30,8 @@ var (
 	ShowAdvancedRender      = false
 	ShowSceneSettings       = false
 	ShowGizmos              = true
+	ShowProfiler            = false
+	ShowGameUI              = false
 
 	ShowAddWater      = false
 	ShowAddVoxel      = false
@@ -68,6 +70,9 @@ var (
 	skyboxColorMode   = true
 	skyboxSolidColor  = [3]float32{0.4, 0.6, 0.9}
 
+	skyboxProceduralMode = false
+	activeTimeOfDay      *renderer.TimeOfDay
+
 	instanceModelOnAdd = false
 	instanceCount      = 1
 
@@ -15,6 +15,17 @@ import (
 	"github.com/inkyblackness/imgui-go/v4"
 )
 
+// colorEditLinear edits a linear lighting color with an sRGB picker, so the
+// swatch looks like the shaded result instead of a washed-out raw value
+func colorEditLinear(label string, color *[3]float32) bool {
+	display := renderer.LinearToSRGB3(*color)
+	if !imgui.ColorEdit3V(label, &display, 0) {
+		return false
+	}
+	*color = renderer.SRGBToLinear3(display)
+	return true
+}
+
 func getFileNameFromPath(path string) string {
 	// Extract filename from path (cross-platform)
 	for i := len(path) - 1; i >= 0; i-- {
@@ -62,10 +73,15 @@ func executeConsoleCommand(cmd string) {
 		logToConsole("  models - List all models in scene", "info")
 		logToConsole("  inspect <name> - Show detailed material info", "info")
 		logToConsole("  wireframe [on/off] - Toggle wireframe mode", "info")
+		logToConsole("  view [mode] - Debug view: lit, normals, uv, albedo, lighting, depth, overdraw, instances, lod", "info")
+		logToConsole("  bounds [sphere|box|all|off] - Toggle bounding volume overlays", "info")
 		logToConsole("  culling [on/off] - Toggle frustum culling", "info")
 		logToConsole("  delete <name> - Delete model by name", "info")
 		logToConsole("  grid [on/off] - Toggle reference grid visibility", "info")
 		logToConsole("  fix-materials - Reset all materials to defaults", "info")
+		logToConsole("  shaders [reload|export|dir|watch|embedded] - Shader files and hot reload", "info")
+		logToConsole("  screenshot [scale] - Save the scene view to a PNG, rendered larger when scale > 1", "info")
+		logToConsole("  record start [fps] | record stop - Save every frame at a fixed timestep", "info")
 		logToConsole("  sh <cmd> - Execute shell command (PowerShell/bash)", "info")
 		logToConsole("  !<cmd> - Shortcut for shell command", "info")
 
@@ -234,6 +250,21 @@ func executeConsoleCommand(cmd string) {
 		}
 		logToConsole(fmt.Sprintf("Fixed %d models with incorrect material values", fixed), "info")
 
+	case "shaders":
+		executeShaderCommand(parts[1:])
+
+	case "view":
+		executeDebugViewCommand(parts[1:])
+
+	case "bounds":
+		executeBoundsCommand(parts[1:])
+
+	case "screenshot":
+		executeScreenshotCommand(parts[1:])
+
+	case "record":
+		executeRecordCommand(parts[1:])
+
 	case "sh", "shell", "exec", "!":
 		// Execute shell command
 		if len(parts) > 1 {
@@ -381,7 +412,11 @@ func loadTextureToSelected(path string) {
 
 	logToConsole(fmt.Sprintf("Loading texture '%s' for model '%s'...", filepath.Base(path), model.Name), "info")
 
-	textureID, err := openglRenderer.LoadTexture(path)
+	var sampler renderer.SamplerSettings
+	if model.Material != nil {
+		sampler = model.Material.Sampler
+	}
+	textureID, err := openglRenderer.LoadTextureWithSampler(path, sampler)
 	if err != nil {
 		logToConsole(fmt.Sprintf("Failed to load texture: %v", err), "error")
 		return
@@ -0,0 +1,325 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/sqweek/dialog"
+)
+
+// projectMaterialDir returns the default directory for .gmat files
+func projectMaterialDir() string {
+	if CurrentProject == nil {
+		return "../resources/materials"
+	}
+	return filepath.Join(CurrentProject.Path, "resources", "materials")
+}
+
+// projectRelativePath returns path relative to the project for saving in
+// scenes, so they survive moving the project. Paths outside it stay absolute.
+func projectRelativePath(path string) string {
+	if CurrentProject == nil || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(CurrentProject.Path, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// projectAbsolutePath resolves a path saved by projectRelativePath
+func projectAbsolutePath(path string) string {
+	if CurrentProject == nil || path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(CurrentProject.Path, filepath.FromSlash(path))
+}
+
+// assignMaterialAsset loads a .gmat file and applies it to a model
+func assignMaterialAsset(model *renderer.Model, path string) bool {
+	material, err := renderer.LoadMaterialAsset(path)
+	if err != nil {
+		logToConsole(fmt.Sprintf("Failed to load material %s: %v", filepath.Base(path), err), "error")
+		return false
+	}
+	if old := model.CustomMaterial; old != nil && old != material {
+		old.Release()
+	}
+	model.CustomMaterial = material
+	logToConsole(fmt.Sprintf("Material '%s' applied to %s", material.Name, model.Name), "info")
+	return true
+}
+
+// renderMaterialAssetInspector draws the custom material section of the model inspector
+func renderMaterialAssetInspector(model *renderer.Model) {
+	if !imgui.CollapsingHeaderV("Custom Material", imgui.TreeNodeFlagsNone) {
+		return
+	}
+
+	material := model.CustomMaterial
+	if material == nil {
+		imgui.Text("Material: Built-in")
+	} else {
+		imgui.Text(fmt.Sprintf("Material: %s (%s)", material.Name, filepath.Base(material.Path)))
+	}
+
+	if imgui.Button("Load Material...") {
+		filename, err := dialog.File().
+			SetStartDir(projectMaterialDir()).
+			Filter("Materials", "gmat").
+			Title("Load Material").
+			Load()
+		if err == nil && filename != "" && assignMaterialAsset(model, filename) {
+			sceneModified = true
+		}
+	}
+	if material == nil {
+		return
+	}
+
+	imgui.SameLine()
+	if imgui.Button("Remove##material") {
+		material.Release()
+		model.CustomMaterial = nil
+		sceneModified = true
+		logToConsole(fmt.Sprintf("Removed custom material from %s", model.Name), "info")
+		return
+	}
+	imgui.SameLine()
+	if imgui.Button("Reload##material") {
+		if err := renderer.ReloadMaterialAsset(material); err != nil {
+			logToConsole(fmt.Sprintf("Failed to reload material: %v", err), "error")
+		} else {
+			logToConsole(fmt.Sprintf("Reloaded material '%s'", material.Name), "info")
+		}
+	}
+	imgui.SameLine()
+	if imgui.Button("Save##material") {
+		if err := material.Save(); err != nil {
+			logToConsole(fmt.Sprintf("Failed to save material: %v", err), "error")
+		} else {
+			logToConsole(fmt.Sprintf("Saved material to %s", material.Path), "info")
+		}
+	}
+
+	shaderLabel := "Shader: model default"
+	if material.Shader.Vertex != "" || material.Shader.Fragment != "" {
+		shaderLabel = fmt.Sprintf("Shader: %s / %s", orDefault(material.Shader.Vertex, "default.vert"), orDefault(material.Shader.Fragment, "default.frag"))
+	}
+	imgui.Text(shaderLabel)
+
+	if len(material.Parameters) > 0 {
+		imgui.Separator()
+		for i := range material.Parameters {
+			renderMaterialParam(&material.Parameters[i])
+		}
+	}
+
+	if len(material.Textures) > 0 {
+		imgui.Separator()
+		for _, tex := range material.Textures {
+			imgui.Text(fmt.Sprintf("%s: %s", tex.Name, filepath.Base(tex.Path)))
+			imgui.SameLine()
+			if imgui.Button("...##tex_" + tex.Name) {
+				filename, err := dialog.File().
+					SetStartDir(filepath.Dir(material.ResolvePath(tex.Path))).
+					Filter("Images", "png", "jpg", "jpeg", "ktx2", "dds").
+					Title("Select Texture for " + tex.Name).
+					Load()
+				if err == nil && filename != "" {
+					if rel, err := filepath.Rel(filepath.Dir(material.Path), filename); err == nil {
+						filename = filepath.ToSlash(rel)
+					}
+					material.SetTexture(tex.Name, filename)
+				}
+			}
+			if openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer); ok {
+				imgui.SameLine()
+				name := tex.Name
+				renderTexturePicker(openglRenderer, "tex_"+name, func(path string) { material.SetTexture(name, path) })
+			}
+		}
+	}
+
+	imgui.Separator()
+	state := &material.RenderState
+	if imgui.BeginCombo("Blend", string(state.Blend)) {
+		for _, mode := range renderer.BlendModes {
+			if imgui.SelectableV(string(mode), state.Blend == mode, 0, imgui.Vec2{}) {
+				state.Blend = mode
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.BeginCombo("Cull", string(state.Cull)) {
+		for _, mode := range renderer.CullModes {
+			if imgui.SelectableV(string(mode), state.Cull == mode, 0, imgui.Vec2{}) {
+				state.Cull = mode
+			}
+		}
+		imgui.EndCombo()
+	}
+	depthWrite := state.WritesDepth()
+	if imgui.Checkbox("Depth Write", &depthWrite) {
+		state.DepthWrite = &depthWrite
+	}
+}
+
+// renderTextureSamplerSettings edits how the model's texture is wrapped and filtered
+func renderTextureSamplerSettings(model *renderer.Model) {
+	if !imgui.TreeNode("Texture Sampling") {
+		return
+	}
+	defer imgui.TreePop()
+
+	sampler := model.Material.Sampler.Resolved()
+	changed := false
+	if imgui.BeginCombo("Wrap", string(sampler.Wrap)) {
+		for _, wrap := range renderer.TextureWraps {
+			if imgui.SelectableV(string(wrap), sampler.Wrap == wrap, 0, imgui.Vec2{}) {
+				sampler.Wrap = wrap
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.BeginCombo("Filter", string(sampler.Filter)) {
+		for _, filter := range renderer.TextureFilters {
+			if imgui.SelectableV(string(filter), sampler.Filter == filter, 0, imgui.Vec2{}) {
+				sampler.Filter = filter
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	colorSpace := sampler.ColorSpace
+	if colorSpace == "" {
+		colorSpace = renderer.ColorSpaceSRGB
+	}
+	if imgui.BeginCombo("Color Space", string(colorSpace)) {
+		for _, space := range renderer.TextureColorSpaces {
+			if imgui.SelectableV(string(space), colorSpace == space, 0, imgui.Vec2{}) {
+				sampler.ColorSpace = space
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	if maxAniso := renderer.MaxTextureAnisotropy(); maxAniso > 1 {
+		// Textures are re-uploaded on release, not on every frame of the drag
+		if imgui.SliderFloatV("Anisotropy", &sampler.Anisotropy, 1, maxAniso, "%.0fx", 0) {
+			model.Material.Sampler.Anisotropy = sampler.Anisotropy
+		}
+		changed = changed || imgui.IsItemDeactivatedAfterEdit()
+	}
+	mipmaps := !sampler.NoMipmaps
+	if imgui.Checkbox("Mipmaps", &mipmaps) {
+		sampler.NoMipmaps = !mipmaps
+		changed = true
+	}
+	if !changed {
+		return
+	}
+
+	openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		return
+	}
+	if err := applyTextureSampler(openglRenderer, model, sampler); err != nil {
+		logToConsole(fmt.Sprintf("Failed to apply texture sampling: %v", err), "error")
+		return
+	}
+	sceneModified = true
+}
+
+// applyTextureSampler reloads the model's textures with new sampler settings
+func applyTextureSampler(openglRenderer *renderer.OpenGLRenderer, model *renderer.Model, sampler renderer.SamplerSettings) error {
+	if sampler.ColorSpace == renderer.ColorSpaceSRGB {
+		sampler.ColorSpace = ""
+	}
+	if sampler == renderer.DefaultSampler {
+		sampler = renderer.SamplerSettings{}
+	}
+	materials := []*renderer.Material{model.Material}
+	for i := range model.MaterialGroups {
+		materials = append(materials, model.MaterialGroups[i].Material)
+	}
+
+	reloaded := make(map[*renderer.Material]bool)
+	for _, material := range materials {
+		if material == nil || reloaded[material] {
+			continue
+		}
+		reloaded[material] = true
+		material.Sampler = sampler
+		if err := openglRenderer.ReloadMaterialTexture(material); err != nil {
+			return err
+		}
+	}
+	model.IsDirty = true
+	return nil
+}
+
+// renderMaterialParam draws the widget matching a parameter's declared type
+func renderMaterialParam(p *renderer.MaterialParam) {
+	v := p.Value
+	switch p.Type {
+	case renderer.ParamFloat:
+		if p.Min < p.Max {
+			imgui.SliderFloatV(p.Name, &v[0], p.Min, p.Max, "%.3f", 0)
+		} else {
+			imgui.DragFloatV(p.Name, &v[0], 0.01, 0, 0, "%.3f", 0)
+		}
+	case renderer.ParamInt:
+		value := int32(v[0])
+		changed := false
+		if p.Min < p.Max {
+			changed = imgui.SliderIntV(p.Name, &value, int32(p.Min), int32(p.Max), "%d", 0)
+		} else {
+			changed = imgui.DragIntV(p.Name, &value, 0.1, 0, 0, "%d", 0)
+		}
+		if changed {
+			v[0] = float32(value)
+		}
+	case renderer.ParamBool:
+		value := v[0] != 0
+		if imgui.Checkbox(p.Name, &value) {
+			v[0] = 0
+			if value {
+				v[0] = 1
+			}
+		}
+	case renderer.ParamVec2:
+		value := [2]float32{v[0], v[1]}
+		if imgui.DragFloat2V(p.Name, &value, 0.01, p.Min, p.Max, "%.3f", 0) {
+			copy(v, value[:])
+		}
+	case renderer.ParamVec3:
+		value := [3]float32{v[0], v[1], v[2]}
+		if imgui.DragFloat3V(p.Name, &value, 0.01, p.Min, p.Max, "%.3f", 0) {
+			copy(v, value[:])
+		}
+	case renderer.ParamColor:
+		value := [3]float32{v[0], v[1], v[2]}
+		if colorEditLinear(p.Name, &value) {
+			copy(v, value[:])
+		}
+	case renderer.ParamVec4:
+		value := [4]float32{v[0], v[1], v[2], v[3]}
+		if imgui.DragFloat4V(p.Name, &value, 0.01, p.Min, p.Max, "%.3f", 0) {
+			copy(v, value[:])
+		}
+	}
+}
+
+// orDefault returns value, or fallback when value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
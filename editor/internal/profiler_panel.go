@@ -0,0 +1,113 @@
+package editor
+
+import (
+	"Gopher3D/internal/profiler"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/sqweek/dialog"
+)
+
+func ms(d time.Duration) float32 {
+	return float32(d) / float32(time.Millisecond)
+}
+
+// renderProfilerPanel shows frame times and per-scope CPU/GPU timings
+func renderProfilerPanel() {
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: 420, Y: 460}, imgui.ConditionFirstUseEver)
+	if !imgui.BeginV("Profiler", &ShowProfiler, 0) {
+		imgui.End()
+		return
+	}
+
+	recording := profiler.Enabled()
+	if imgui.Checkbox("Record", &recording) {
+		profiler.SetEnabled(recording)
+	}
+	imgui.SameLine()
+	if imgui.Button("Clear") {
+		profiler.Default.Reset()
+	}
+	imgui.SameLine()
+	if imgui.Button("Export Trace...") {
+		exportProfilerTrace()
+	}
+
+	frames := profiler.Default.Frames()
+	if len(frames) == 0 {
+		imgui.Text("No frames recorded. Enable Record to start profiling.")
+		imgui.End()
+		return
+	}
+
+	times := make([]float32, len(frames))
+	var total, worst float32
+	for i, f := range frames {
+		times[i] = ms(f.Duration)
+		total += times[i]
+		worst = max(worst, times[i])
+	}
+	avg := total / float32(len(times))
+	overlay := fmt.Sprintf("avg %.2f ms (%.0f FPS), max %.2f ms", avg, 1000/avg, worst)
+	imgui.PlotLinesV("##frametimes", times, 0, overlay, 0, max(worst*1.2, 16.7), imgui.Vec2{X: -1, Y: 80})
+	imgui.Text(fmt.Sprintf("%d frames", len(frames)))
+
+	imgui.Separator()
+	flags := imgui.TableFlagsRowBg | imgui.TableFlagsBordersInnerV
+	if imgui.BeginTableV("##scopes", 4, flags, imgui.Vec2{}, 0) {
+		imgui.TableSetupColumn("Scope")
+		imgui.TableSetupColumn("Avg ms")
+		imgui.TableSetupColumn("Max ms")
+		imgui.TableSetupColumn("Last ms")
+		imgui.TableHeadersRow()
+
+		section := ""
+		for _, st := range profiler.Default.Stats() {
+			label := "CPU"
+			if st.GPU {
+				label = "GPU"
+			}
+			if label != section {
+				section = label
+				imgui.TableNextRow()
+				imgui.TableNextColumn()
+				imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 0.4, Y: 0.8, Z: 1, W: 1})
+				imgui.Text(label)
+				imgui.PopStyleColor()
+			}
+			imgui.TableNextRow()
+			imgui.TableNextColumn()
+			imgui.Text(strings.Repeat("  ", st.Depth+1) + st.Name)
+			imgui.TableNextColumn()
+			imgui.Text(fmt.Sprintf("%.3f", ms(st.Avg)))
+			imgui.TableNextColumn()
+			imgui.Text(fmt.Sprintf("%.3f", ms(st.Max)))
+			imgui.TableNextColumn()
+			imgui.Text(fmt.Sprintf("%.3f", ms(st.Last)))
+		}
+		imgui.EndTable()
+	}
+	imgui.End()
+}
+
+// exportProfilerTrace saves the recorded frames as a Chrome trace
+func exportProfilerTrace() {
+	filename, err := dialog.File().
+		Filter("Chrome trace", "json").
+		Title("Export Profiler Trace").
+		SetStartFile(fmt.Sprintf("trace_%s.json", time.Now().Format("20060102_150405"))).
+		Save()
+	if err != nil || filename == "" {
+		return
+	}
+	if !strings.HasSuffix(strings.ToLower(filename), ".json") {
+		filename += ".json"
+	}
+	if err := profiler.Default.ExportChromeTrace(filename); err != nil {
+		logToConsole(fmt.Sprintf("Failed to export trace: %v", err), "error")
+		return
+	}
+	logToConsole(fmt.Sprintf("Profiler trace exported to %s (open in chrome://tracing or Perfetto)", filename), "info")
+}
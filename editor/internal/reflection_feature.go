@@ -0,0 +1,98 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"fmt"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// addModelReflection turns a model into a planar mirror. Models without a
+// custom material get the mirror material; others can sample the reflection
+// through their own shader.
+func addModelReflection(rend *renderer.OpenGLRenderer, model *renderer.Model, data SceneReflection) *renderer.PlanarReflection {
+	p := renderer.NewPlanarReflection("mirror:"+model.Name, model)
+	if data.Normal != ([3]float32{}) {
+		p.Normal = mgl.Vec3{data.Normal[0], data.Normal[1], data.Normal[2]}
+	}
+	if data.Resolution > 0 {
+		p.Resolution = data.Resolution
+	}
+	p.ClipOffset = data.ClipOffset
+	rend.AddPlanarReflection(p)
+	if model.CustomMaterial == nil {
+		model.CustomMaterial = renderer.NewMirrorMaterial(p)
+	}
+	return p
+}
+
+// removeModelReflection stops a model reflecting, dropping the mirror material it was given
+func removeModelReflection(rend *renderer.OpenGLRenderer, model *renderer.Model, p *renderer.PlanarReflection) {
+	rend.RemovePlanarReflection(p)
+	if m := model.CustomMaterial; m != nil && m.Path == "" && m.Shader.Fragment == "mirror.frag" {
+		m.Release()
+		model.CustomMaterial = nil
+	}
+}
+
+// sceneReflection returns the reflection settings to save with a model, or nil
+func sceneReflection(rend *renderer.OpenGLRenderer, model *renderer.Model) *SceneReflection {
+	p := rend.SurfaceReflection(model)
+	if p == nil {
+		return nil
+	}
+	return &SceneReflection{
+		Normal:     [3]float32{p.Normal.X(), p.Normal.Y(), p.Normal.Z()},
+		Resolution: p.Resolution,
+		ClipOffset: p.ClipOffset,
+	}
+}
+
+// renderModelReflection draws the planar reflection section of the model inspector
+func renderModelReflection(rend *renderer.OpenGLRenderer, model *renderer.Model) {
+	if model.Metadata != nil && model.Metadata["type"] == "water" {
+		return // Water reflections are set up in the water panel
+	}
+	if !imgui.CollapsingHeaderV("Planar Reflection", imgui.TreeNodeFlagsNone) {
+		return
+	}
+
+	p := rend.SurfaceReflection(model)
+	if p == nil {
+		imgui.PushTextWrapPos()
+		imgui.Text("Reflects the scene across the model's local XZ plane, for mirrors and polished floors.")
+		imgui.PopTextWrapPos()
+		if imgui.Button("Make Mirror") {
+			addModelReflection(rend, model, SceneReflection{})
+			sceneModified = true
+			logToConsole(fmt.Sprintf("%s is now a mirror", model.Name), "info")
+		}
+		return
+	}
+
+	if imgui.SliderFloatV("Resolution##reflection", &p.Resolution, 0.1, 1, "%.2f", 0) {
+		sceneModified = true
+	}
+	if imgui.DragFloatV("Clip Offset##reflection", &p.ClipOffset, 0.01, 0, 10, "%.2f", 0) {
+		sceneModified = true
+	}
+
+	imgui.Text("Normal (local):")
+	for _, axis := range []struct {
+		label  string
+		normal mgl.Vec3
+	}{{"X", mgl.Vec3{1, 0, 0}}, {"Y", mgl.Vec3{0, 1, 0}}, {"Z", mgl.Vec3{0, 0, 1}}} {
+		imgui.SameLine()
+		if imgui.RadioButton(axis.label+"##reflectionNormal", p.Normal.ApproxEqual(axis.normal)) {
+			p.Normal = axis.normal
+			sceneModified = true
+		}
+	}
+
+	if imgui.Button("Remove Reflection") {
+		removeModelReflection(rend, model, p)
+		sceneModified = true
+		logToConsole(fmt.Sprintf("Removed reflection from %s", model.Name), "info")
+	}
+}
@@ -0,0 +1,111 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// sceneRenderTextures collects the renderer's render textures for saving
+func sceneRenderTextures(rend *renderer.OpenGLRenderer) []SceneRenderTexture {
+	var out []SceneRenderTexture
+	for _, rt := range rend.RenderTextures() {
+		if rt.Camera == nil {
+			continue // Reflection textures are saved with their surface
+		}
+		entry := SceneRenderTexture{Name: rt.Name, Width: rt.Width, Height: rt.Height, UpdateRate: rt.UpdateRate}
+		entry.Camera = rt.Camera.Name
+		out = append(out, entry)
+	}
+	return out
+}
+
+// loadSceneRenderTextures recreates a scene's render textures once its cameras are loaded
+func loadSceneRenderTextures(rend *renderer.OpenGLRenderer, entries []SceneRenderTexture) {
+	for _, entry := range entries {
+		rt := rend.RenderTexture(entry.Name)
+		rt.Width, rt.Height = entry.Width, entry.Height
+		rt.UpdateRate = entry.UpdateRate
+		rt.Camera = findSceneCamera(entry.Camera)
+		if rt.Camera == nil {
+			logToConsole(fmt.Sprintf("Render texture %s: camera %q not found", entry.Name, entry.Camera), "warning")
+		}
+	}
+}
+
+// clearRenderTextures removes every render texture when a scene is closed
+func clearRenderTextures(rend *renderer.OpenGLRenderer) {
+	for _, rt := range rend.RenderTextures() {
+		rend.RemoveRenderTexture(rt.Name)
+	}
+}
+
+func findSceneCamera(name string) *renderer.Camera {
+	for _, cam := range SceneCameras {
+		if cam.Name == name {
+			return cam
+		}
+	}
+	return nil
+}
+
+// cameraRenderTexture returns the render texture cam draws into, if any
+func cameraRenderTexture(rend *renderer.OpenGLRenderer, cam *renderer.Camera) *renderer.RenderTexture {
+	for _, rt := range rend.RenderTextures() {
+		if rt.Camera == cam {
+			return rt
+		}
+	}
+	return nil
+}
+
+// renderCameraRenderTexture lets a scene camera draw into a render texture
+func renderCameraRenderTexture(rend *renderer.OpenGLRenderer, cam *renderer.Camera) {
+	if !imgui.CollapsingHeaderV("Render Texture", 0) {
+		return
+	}
+	rt := cameraRenderTexture(rend, cam)
+	if rt == nil {
+		imgui.Text("Draws to the game window")
+		if imgui.Button("Render To Texture") {
+			rt = rend.RenderTexture(cam.Name)
+			rt.Camera = cam
+			logToConsole(fmt.Sprintf("Camera %s renders to %s", cam.Name, renderer.RenderTexturePath(rt.Name)), "info")
+		}
+		return
+	}
+
+	imgui.Text("Texture: " + renderer.RenderTexturePath(rt.Name))
+	size := [2]int32{rt.Width, rt.Height}
+	if imgui.InputInt("Width", &size[0]) {
+		rt.Width = min(max(size[0], 16), 4096)
+	}
+	if imgui.InputInt("Height", &size[1]) {
+		rt.Height = min(max(size[1], 16), 4096)
+	}
+	imgui.SliderFloatV("Updates/sec", &rt.UpdateRate, 0, 60, "%.0f (0 = every frame)", 0)
+	if imgui.Button("Stop Rendering To Texture") {
+		rend.RemoveRenderTexture(rt.Name)
+	}
+}
+
+// renderTexturePicker shows a button listing render textures; picking one calls
+// apply with its texture path
+func renderTexturePicker(rend *renderer.OpenGLRenderer, id string, apply func(path string)) {
+	if imgui.Button("Render Texture...##" + id) {
+		imgui.OpenPopup("rtpick_" + id)
+	}
+	if imgui.BeginPopup("rtpick_" + id) {
+		textures := rend.RenderTextures()
+		if len(textures) == 0 {
+			imgui.Text("No render textures. Enable one on a camera.")
+		}
+		for _, rt := range textures {
+			if imgui.Selectable(rt.Name) {
+				apply(renderer.RenderTexturePath(rt.Name))
+			}
+		}
+		imgui.EndPopup()
+	}
+}
@@ -16,18 +16,54 @@ import (
 	"github.com/sqweek/dialog"
 )
 
+// sceneFormatVersion is written to saved scenes. Version 1 decodes textures
+// as sRGB unless they are tagged linear.
+const sceneFormatVersion = 1
+
 type SceneData struct {
+	FormatVersion int `json:"format_version,omitempty"`
+
 	// New unified GameObject system
 	GameObjects []SceneGameObject `json:"game_objects,omitempty"`
 
 	// Legacy data (for backward compatibility)
-	Models    []SceneModel          `json:"models,omitempty"`
-	Lights    []SceneLight          `json:"lights,omitempty"`
-	Camera    *SceneCamera          `json:"camera,omitempty"`
-	Cameras   []SceneCamera         `json:"cameras,omitempty"`
-	Water     *SceneWater           `json:"water,omitempty"`
-	Skybox    *SceneSkybox          `json:"skybox,omitempty"`
-	Rendering *SceneRenderingConfig `json:"rendering,omitempty"`
+	Models         []SceneModel          `json:"models,omitempty"`
+	Lights         []SceneLight          `json:"lights,omitempty"`
+	Camera         *SceneCamera          `json:"camera,omitempty"`
+	Cameras        []SceneCamera         `json:"cameras,omitempty"`
+	RenderTextures []SceneRenderTexture  `json:"render_textures,omitempty"`
+	Water          *SceneWater           `json:"water,omitempty"`
+	Skybox         *SceneSkybox          `json:"skybox,omitempty"`
+	Rendering      *SceneRenderingConfig `json:"rendering,omitempty"`
+	UILayouts      []string              `json:"ui_layouts,omitempty"` // .gui files drawn over the game
+}
+
+// migrateScene upgrades scenes saved by older editors in place. Textures of
+// unversioned scenes were sampled without sRGB decoding, so they are tagged
+// linear to keep their look.
+func migrateScene(sceneData *SceneData) bool {
+	if sceneData.FormatVersion >= sceneFormatVersion {
+		return false
+	}
+	// Skyboxes need nothing: they are sampled as sRGB and encoded again for
+	// display, so they look as before. Old .gmat files migrate when parsed.
+	for i := range sceneData.Models {
+		m := &sceneData.Models[i]
+		if v := m.VoxelConfig; v != nil && v.BlockTextures != ([6][3]string{}) {
+			v.BlockTextureColorSpace = renderer.ColorSpaceLinear
+		}
+		if _, isRenderTexture := renderer.RenderTextureName(m.TexturePath); m.TexturePath == "" || isRenderTexture {
+			continue
+		}
+		sampler := renderer.SamplerSettings{}
+		if m.TextureSampler != nil {
+			sampler = *m.TextureSampler
+		}
+		sampler.ColorSpace = renderer.ColorSpaceLinear
+		m.TextureSampler = &sampler
+	}
+	sceneData.FormatVersion = sceneFormatVersion
+	return true
 }
 
 type SceneRenderingConfig struct {
@@ -37,6 +73,23 @@ type SceneRenderingConfig struct {
 	FaceCulling bool       `json:"face_culling"`
 	Wireframe   bool       `json:"wireframe"`
 	SkyboxColor [3]float32 `json:"skybox_color"`
+	Fog         *SceneFog  `json:"fog,omitempty"`
+	RenderPath  string     `json:"render_path,omitempty"` // "forward" or "deferred"; empty is forward
+
+	SSR *renderer.SSRSettings `json:"ssr,omitempty"` // Screen-space reflections; off when missing
+}
+
+// SceneFog stores distance and height fog. Mode is "none", "exp" or "exp2".
+type SceneFog struct {
+	Mode          string     `json:"mode"`
+	Color         [3]float32 `json:"color"`
+	Density       float32    `json:"density"`
+	HeightFog     bool       `json:"height_fog"`
+	HeightDensity float32    `json:"height_density"`
+	HeightBase    float32    `json:"height_base"`
+	HeightFalloff float32    `json:"height_falloff"`
+	MaxOpacity    float32    `json:"max_opacity"`
+	SkyBlend      float32    `json:"sky_blend"`
 }
 
 type SceneCamera struct {
@@ -49,6 +102,21 @@ type SceneCamera struct {
 	Far         float32    `json:"far,omitempty"`
 	InvertMouse bool       `json:"invert_mouse"`
 	IsActive    bool       `json:"is_active"`
+	Viewport    [4]float32 `json:"viewport,omitzero"` // X, Y, width, height as window fractions from the top-left
+	Depth       int        `json:"depth,omitempty"`   // Higher depths draw on top
+
+	Projection string                             `json:"projection,omitempty"` // "perspective" or "orthographic"
+	OrthoSize  float32                            `json:"ortho_size,omitempty"` // Half the visible height when orthographic
+	Controller *renderer.CameraControllerSettings `json:"controller,omitempty"` // Fly controls when nil
+}
+
+// SceneRenderTexture is a texture a scene camera renders into, usable by any material
+type SceneRenderTexture struct {
+	Name       string  `json:"name"`
+	Width      int32   `json:"width"`
+	Height     int32   `json:"height"`
+	UpdateRate float32 `json:"update_rate,omitempty"` // Updates per second; 0 is every frame
+	Camera     string  `json:"camera"`                // Name of the scene camera drawing it
 }
 
 type SceneModel struct {
@@ -67,6 +135,10 @@ type SceneModel struct {
 	Exposure      float32    `json:"exposure"`
 	Alpha         float32    `json:"alpha"`
 	TexturePath   string     `json:"texture_path,omitempty"`
+	MaterialAsset string     `json:"material_asset,omitempty"` // Custom material (.gmat)
+
+	TextureSampler *renderer.SamplerSettings `json:"texture_sampler,omitempty"`
+	Reflection     *SceneReflection          `json:"reflection,omitempty"` // Planar mirror on the model
 
 	// Serialized mesh data (for procedural/voxel models)
 	MeshDataFile string `json:"mesh_data_file,omitempty"`
@@ -78,6 +150,13 @@ type SceneModel struct {
 	Components []SceneComponent `json:"components,omitempty"`
 }
 
+// SceneReflection makes a model a planar mirror
+type SceneReflection struct {
+	Normal     [3]float32 `json:"normal"` // In the model's space
+	Resolution float32    `json:"resolution"`
+	ClipOffset float32    `json:"clip_offset,omitempty"`
+}
+
 type SceneComponent struct {
 	Type       string                 `json:"type"`
 	Category   string                 `json:"category"` // "Script", "Mesh", "Water", "Voxel", etc.
@@ -97,7 +176,7 @@ type SceneGameObject struct {
 
 type SceneLight struct {
 	Name            string     `json:"name"`
-	Mode            string     `json:"mode"` // "directional" or "point"
+	Mode            string     `json:"mode"` // "directional", "point", "spot", "rect" or "disk"
 	Position        [3]float32 `json:"position"`
 	Direction       [3]float32 `json:"direction"`
 	Color           [3]float32 `json:"color"`
@@ -107,33 +186,56 @@ type SceneLight struct {
 	ConstantAtten   float32    `json:"constant_atten"`
 	LinearAtten     float32    `json:"linear_atten"`
 	QuadraticAtten  float32    `json:"quadratic_atten"`
+	AreaWidth       float32    `json:"area_width,omitempty"`
+	AreaHeight      float32    `json:"area_height,omitempty"`
+	IESProfile      string     `json:"ies_profile,omitempty"` // Path of the IES file shaping a point or spot light
 }
 
 type SceneWater struct {
-	OceanSize           float32    `json:"ocean_size"`
-	BaseAmplitude       float32    `json:"base_amplitude"`
-	WaterColor          [3]float32 `json:"water_color"`
-	Transparency        float32    `json:"transparency"`
-	WaveSpeedMultiplier float32    `json:"wave_speed_multiplier"`
-	WaveHeight          float32    `json:"wave_height"`
-	WaveRandomness      float32    `json:"wave_randomness"`
-	Position            [3]float32 `json:"position"`
-	FoamEnabled         bool       `json:"foam_enabled"`
-	FoamIntensity       float32    `json:"foam_intensity"`
-	CausticsEnabled     bool       `json:"caustics_enabled"`
-	CausticsIntensity   float32    `json:"caustics_intensity"`
-	CausticsScale       float32    `json:"caustics_scale"`
-	SpecularIntensity   float32    `json:"specular_intensity"`
-	NormalStrength      float32    `json:"normal_strength"`
-	DistortionStrength  float32    `json:"distortion_strength"`
-	ShadowStrength      float32    `json:"shadow_strength"`
-	MeshDataFile        string     `json:"mesh_data_file,omitempty"`
+	OceanSize            float32    `json:"ocean_size"`
+	BaseAmplitude        float32    `json:"base_amplitude"`
+	WaterColor           [3]float32 `json:"water_color"`
+	Transparency         float32    `json:"transparency"`
+	WaveSpeedMultiplier  float32    `json:"wave_speed_multiplier"`
+	WaveHeight           float32    `json:"wave_height"`
+	WaveRandomness       float32    `json:"wave_randomness"`
+	Position             [3]float32 `json:"position"`
+	FoamEnabled          bool       `json:"foam_enabled"`
+	FoamIntensity        float32    `json:"foam_intensity"`
+	CausticsEnabled      bool       `json:"caustics_enabled"`
+	CausticsIntensity    float32    `json:"caustics_intensity"`
+	CausticsScale        float32    `json:"caustics_scale"`
+	SpecularIntensity    float32    `json:"specular_intensity"`
+	NormalStrength       float32    `json:"normal_strength"`
+	DistortionStrength   float32    `json:"distortion_strength"`
+	ReflectionResolution float32    `json:"reflection_resolution,omitempty"`
+	ShadowStrength       float32    `json:"shadow_strength"`
+	MeshDataFile         string     `json:"mesh_data_file,omitempty"`
 }
 
 type SceneSkybox struct {
-	Type      string     `json:"type"`       // "image" or "color"
-	ImagePath string     `json:"image_path"` // Path to skybox texture (for image type)
-	Color     [3]float32 `json:"color"`      // RGB color (for color type)
+	Type       string              `json:"type"`                 // "image", "color" or "procedural"
+	ImagePath  string              `json:"image_path"`           // Path to skybox texture (for image type)
+	Color      [3]float32          `json:"color"`                // RGB color (for color type)
+	Procedural *SceneProceduralSky `json:"procedural,omitempty"` // Atmosphere and time of day (for procedural type)
+}
+
+// SceneProceduralSky stores the atmospheric sky and its time-of-day controller
+type SceneProceduralSky struct {
+	TimeOfDay         float32    `json:"time_of_day"` // Hours (0-24)
+	DayLength         float32    `json:"day_length"`  // Real seconds per day, 0 = static
+	Paused            bool       `json:"paused"`
+	SunAzimuth        float32    `json:"sun_azimuth"`
+	SunTilt           float32    `json:"sun_tilt"`
+	DayIntensity      float32    `json:"day_intensity"`
+	NightIntensity    float32    `json:"night_intensity"`
+	DayAmbient        float32    `json:"day_ambient"`
+	NightAmbient      float32    `json:"night_ambient"`
+	SunIntensity      float32    `json:"sun_intensity"`
+	Rayleigh          [3]float32 `json:"rayleigh"`
+	Mie               float32    `json:"mie"`
+	MieDirectionality float32    `json:"mie_directionality"`
+	Exposure          float32    `json:"exposure"`
 }
 
 func newScene() {
@@ -180,7 +282,13 @@ func newScene() {
 	openglRenderer.AddLight(defaultLight)
 	Eng.Light = defaultLight
 
+	// Stream textures in the background so large scenes don't freeze the editor
+	if openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		openglRenderer.AsyncTextureLoading = true
+	}
+
 	// Reset skybox state - both renderer clear color and editor state
+	disableProceduralSky()
 	openglRenderer.ClearColorR = 0.4
 	openglRenderer.ClearColorG = 0.6
 	openglRenderer.ClearColorB = 0.9
@@ -188,14 +296,21 @@ func newScene() {
 	skyboxTexturePath = ""
 	skyboxSolidColor = [3]float32{0.4, 0.6, 0.9}
 
+	// Reset fog
+	openglRenderer.Fog = renderer.DefaultFogSettings()
+	openglRenderer.RenderPath = renderer.RenderPathForward
+	openglRenderer.SSR = renderer.DefaultSSRSettings()
+
 	// Reset Water - ensure it's fully cleared
 	activeWaterSim = nil
 
 	// Reset voxel colors to defaults
 	loader.ClearCustomVoxelColors()
 
-	// Reset Cameras
+	// Reset Cameras and the render textures they draw
+	clearRenderTextures(openglRenderer)
 	SceneCameras = nil
+	clearUILayouts()
 
 	// Reset selection state
 	currentScenePath = ""
@@ -243,8 +358,9 @@ func saveScene() {
 
 	// Collect scene data
 	sceneData := SceneData{
-		Models: []SceneModel{},
-		Lights: []SceneLight{},
+		FormatVersion: sceneFormatVersion,
+		Models:        []SceneModel{},
+		Lights:        []SceneLight{},
 	}
 
 	// Save models with complete material properties
@@ -277,7 +393,14 @@ func saveScene() {
 			sceneModel.Exposure = model.Material.Exposure
 			sceneModel.Alpha = model.Material.Alpha
 			sceneModel.TexturePath = model.Material.TexturePath
+			if sampler := model.Material.Sampler; sampler != (renderer.SamplerSettings{}) {
+				sceneModel.TextureSampler = &sampler
+			}
+		}
+		if model.CustomMaterial != nil {
+			sceneModel.MaterialAsset = projectRelativePath(model.CustomMaterial.Path)
 		}
+		sceneModel.Reflection = sceneReflection(openglRenderer, model)
 
 		// Check for voxel configuration
 		if model.Metadata != nil {
@@ -330,29 +453,37 @@ func saveScene() {
 			LinearAtten:     light.LinearAtten,
 			QuadraticAtten:  light.QuadraticAtten,
 		}
+		if light.IsArea() {
+			sceneLight.AreaWidth = light.AreaWidth
+			sceneLight.AreaHeight = light.AreaHeight
+		}
+		if light.IES != nil {
+			sceneLight.IESProfile = light.IES.Path
+		}
 		sceneData.Lights = append(sceneData.Lights, sceneLight)
 	}
 
 	// Save water if it exists
 	if activeWaterSim != nil && activeWaterSim.Model != nil {
 		sceneData.Water = &SceneWater{
-			OceanSize:           activeWaterSim.OceanSize,
-			BaseAmplitude:       activeWaterSim.BaseAmplitude,
-			WaterColor:          [3]float32{activeWaterSim.WaterColor.X(), activeWaterSim.WaterColor.Y(), activeWaterSim.WaterColor.Z()},
-			Transparency:        activeWaterSim.Transparency,
-			WaveSpeedMultiplier: activeWaterSim.WaveSpeedMultiplier,
-			WaveHeight:          activeWaterSim.WaveHeight,
-			WaveRandomness:      activeWaterSim.WaveRandomness,
-			Position:            [3]float32{activeWaterSim.Model.Position.X(), activeWaterSim.Model.Position.Y(), activeWaterSim.Model.Position.Z()},
-			FoamEnabled:         activeWaterSim.FoamEnabled,
-			FoamIntensity:       activeWaterSim.FoamIntensity,
-			CausticsEnabled:     activeWaterSim.CausticsEnabled,
-			CausticsIntensity:   activeWaterSim.CausticsIntensity,
-			CausticsScale:       activeWaterSim.CausticsScale,
-			SpecularIntensity:   activeWaterSim.SpecularIntensity,
-			NormalStrength:      activeWaterSim.NormalStrength,
-			DistortionStrength:  activeWaterSim.DistortionStrength,
-			ShadowStrength:      activeWaterSim.ShadowStrength,
+			OceanSize:            activeWaterSim.OceanSize,
+			BaseAmplitude:        activeWaterSim.BaseAmplitude,
+			WaterColor:           [3]float32{activeWaterSim.WaterColor.X(), activeWaterSim.WaterColor.Y(), activeWaterSim.WaterColor.Z()},
+			Transparency:         activeWaterSim.Transparency,
+			WaveSpeedMultiplier:  activeWaterSim.WaveSpeedMultiplier,
+			WaveHeight:           activeWaterSim.WaveHeight,
+			WaveRandomness:       activeWaterSim.WaveRandomness,
+			Position:             [3]float32{activeWaterSim.Model.Position.X(), activeWaterSim.Model.Position.Y(), activeWaterSim.Model.Position.Z()},
+			FoamEnabled:          activeWaterSim.FoamEnabled,
+			FoamIntensity:        activeWaterSim.FoamIntensity,
+			CausticsEnabled:      activeWaterSim.CausticsEnabled,
+			CausticsIntensity:    activeWaterSim.CausticsIntensity,
+			CausticsScale:        activeWaterSim.CausticsScale,
+			SpecularIntensity:    activeWaterSim.SpecularIntensity,
+			NormalStrength:       activeWaterSim.NormalStrength,
+			DistortionStrength:   activeWaterSim.DistortionStrength,
+			ReflectionResolution: activeWaterSim.ReflectionResolution,
+			ShadowStrength:       activeWaterSim.ShadowStrength,
 		}
 	}
 
@@ -386,13 +517,26 @@ func saveScene() {
 				Far:         cam.Far,
 				InvertMouse: cam.InvertMouse,
 				IsActive:    cam.IsActive,
+				Viewport:    [4]float32{cam.Viewport.X, cam.Viewport.Y, cam.Viewport.Width, cam.Viewport.Height},
+				Depth:       cam.Depth,
+				Projection:  cam.ProjectionMode.String(),
+				OrthoSize:   cam.OrthoSize,
+				Controller:  sceneCameraController(cam),
 			}
 		}
 	}
+	sceneData.RenderTextures = sceneRenderTextures(openglRenderer)
+	sceneData.UILayouts = sceneUILayouts()
 
 	// Save skybox - use actual renderer clear color for consistency
 	actualSkyboxColor := [3]float32{openglRenderer.ClearColorR, openglRenderer.ClearColorG, openglRenderer.ClearColorB}
-	if skyboxColorMode {
+	if skyboxProceduralMode && activeTimeOfDay != nil {
+		sceneData.Skybox = &SceneSkybox{
+			Type:       "procedural",
+			Color:      actualSkyboxColor,
+			Procedural: sceneProceduralSkyFromController(activeTimeOfDay),
+		}
+	} else if skyboxColorMode {
 		sceneData.Skybox = &SceneSkybox{
 			Type:  "color",
 			Color: actualSkyboxColor,
@@ -405,6 +549,7 @@ func saveScene() {
 	}
 
 	// Save rendering configuration
+	ssr := openglRenderer.SSR
 	sceneData.Rendering = &SceneRenderingConfig{
 		Bloom:       openglRenderer.EnableBloom,
 		FXAA:        openglRenderer.EnableFXAA,
@@ -412,6 +557,9 @@ func saveScene() {
 		FaceCulling: renderer.FaceCullingEnabled,
 		Wireframe:   renderer.Debug,
 		SkyboxColor: actualSkyboxColor,
+		Fog:         sceneFogFromSettings(openglRenderer.Fog),
+		RenderPath:  openglRenderer.RenderPath.String(),
+		SSR:         &ssr,
 	}
 
 	// Write to file
@@ -457,6 +605,9 @@ func loadScene() {
 		logToConsole(fmt.Sprintf("Failed to parse scene: %v", err), "error")
 		return
 	}
+	if migrateScene(&sceneData) {
+		logToConsole("Scene is from an older version: its textures are kept linear, save to upgrade it", "info")
+	}
 
 	// Clear current scene first (this resets selection)
 	// But save the selection reset for AFTER we load, so UI updates correctly
@@ -523,6 +674,8 @@ func loadScene() {
 			if !isColorZero(sceneModel.VoxelConfig.ColorLeaves) {
 				voxelComp.LeavesColor = sceneModel.VoxelConfig.ColorLeaves
 			}
+			voxelComp.BlockTextures = sceneModel.VoxelConfig.BlockTextures
+			voxelComp.BlockTextureColorSpace = string(sceneModel.VoxelConfig.BlockTextureColorSpace)
 
 			// Generate terrain from component
 			model = generateVoxelTerrainFromComponent(voxelComp)
@@ -542,9 +695,19 @@ func loadScene() {
 				}
 				model.Material.TexturePath = sceneModel.TexturePath
 				model.Material.TextureID = 0
+				if sceneModel.TextureSampler != nil {
+					model.Material.Sampler = *sceneModel.TextureSampler
+				}
 				logToConsole(fmt.Sprintf("Voxel texture will be loaded: %s", filepath.Base(sceneModel.TexturePath)), "info")
 			}
 
+			if sceneModel.MaterialAsset != "" {
+				assignMaterialAsset(model, projectAbsolutePath(sceneModel.MaterialAsset))
+			}
+			if sceneModel.Reflection != nil {
+				addModelReflection(openglRenderer, model, *sceneModel.Reflection)
+			}
+
 			// Add model to engine
 			Eng.AddModel(model)
 
@@ -617,6 +780,7 @@ func loadScene() {
 				Alpha:         alpha,
 				TextureID:     0,
 				TexturePath:   originalMainMaterial.TexturePath,
+				Sampler:       originalMainMaterial.Sampler,
 			}
 		}
 
@@ -650,6 +814,7 @@ func loadScene() {
 					Alpha:         alpha,
 					TextureID:     0,
 					TexturePath:   originalMat.TexturePath,
+					Sampler:       originalMat.Sampler,
 				}
 			}
 		}
@@ -723,19 +888,32 @@ func loadScene() {
 
 		// SECOND: Set texture paths BEFORE AddModel so textures load correctly
 		if sceneModel.TexturePath != "" {
+			var sampler renderer.SamplerSettings
+			if sceneModel.TextureSampler != nil {
+				sampler = *sceneModel.TextureSampler
+			}
 			if model.Material != nil {
 				model.Material.TexturePath = sceneModel.TexturePath
 				model.Material.TextureID = 0 // Clear so loadModelTextures will load it
+				model.Material.Sampler = sampler
 			}
 			// Also set for material groups so they load correctly
 			for i := range model.MaterialGroups {
 				if model.MaterialGroups[i].Material != nil {
 					model.MaterialGroups[i].Material.TexturePath = sceneModel.TexturePath
 					model.MaterialGroups[i].Material.TextureID = 0
+					model.MaterialGroups[i].Material.Sampler = sampler
 				}
 			}
 		}
 
+		if sceneModel.MaterialAsset != "" {
+			assignMaterialAsset(model, projectAbsolutePath(sceneModel.MaterialAsset))
+		}
+		if sceneModel.Reflection != nil {
+			addModelReflection(openglRenderer, model, *sceneModel.Reflection)
+		}
+
 		// Mark model as dirty to ensure uniforms are updated on next render
 		model.IsDirty = true
 
@@ -766,13 +944,34 @@ func loadScene() {
 				mgl.Vec3{sceneLight.Color[0], sceneLight.Color[1], sceneLight.Color[2]},
 				sceneLight.Intensity,
 			)
-		} else if sceneLight.Mode == "point" {
+		} else if sceneLight.Mode == "point" || sceneLight.Mode == "spot" {
 			light = renderer.CreatePointLight(
 				mgl.Vec3{sceneLight.Position[0], sceneLight.Position[1], sceneLight.Position[2]},
 				mgl.Vec3{sceneLight.Color[0], sceneLight.Color[1], sceneLight.Color[2]},
 				sceneLight.Intensity,
 				100.0, // Default range
 			)
+			light.Mode = sceneLight.Mode
+			if dir := (mgl.Vec3{sceneLight.Direction[0], sceneLight.Direction[1], sceneLight.Direction[2]}); dir.Len() > 0 {
+				light.Direction = dir // Aims IES profiles
+			}
+			if sceneLight.IESProfile != "" {
+				if profile, err := renderer.LoadIESProfile(sceneLight.IESProfile); err != nil {
+					logToConsole(fmt.Sprintf("Could not load IES profile for %s: %v", sceneLight.Name, err), "warning")
+				} else {
+					light.IES = profile
+				}
+			}
+		} else if sceneLight.Mode == "rect" || sceneLight.Mode == "disk" {
+			light = renderer.CreateAreaLight(
+				sceneLight.Mode,
+				mgl.Vec3{sceneLight.Position[0], sceneLight.Position[1], sceneLight.Position[2]},
+				mgl.Vec3{sceneLight.Direction[0], sceneLight.Direction[1], sceneLight.Direction[2]},
+				mgl.Vec3{sceneLight.Color[0], sceneLight.Color[1], sceneLight.Color[2]},
+				sceneLight.Intensity,
+				sceneLight.AreaWidth,
+				sceneLight.AreaHeight,
+			)
 		}
 		if light != nil {
 			light.Name = sceneLight.Name
@@ -824,6 +1023,7 @@ func loadScene() {
 		waterComp.SpecularIntensity = sceneData.Water.SpecularIntensity
 		waterComp.NormalStrength = sceneData.Water.NormalStrength
 		waterComp.DistortionStrength = sceneData.Water.DistortionStrength
+		waterComp.ReflectionResolution = sceneData.Water.ReflectionResolution
 		waterComp.ShadowStrength = sceneData.Water.ShadowStrength
 
 		// Create GameObject
@@ -845,6 +1045,7 @@ func loadScene() {
 		ws.SpecularIntensity = waterComp.SpecularIntensity
 		ws.NormalStrength = waterComp.NormalStrength
 		ws.DistortionStrength = waterComp.DistortionStrength
+		ws.ReflectionResolution = waterComp.ReflectionResolution
 		ws.ShadowStrength = waterComp.ShadowStrength
 
 		// Initialize mesh and add to engine
@@ -902,10 +1103,15 @@ func loadScene() {
 				Far:         camData.Far,
 				InvertMouse: camData.InvertMouse,
 				IsActive:    camData.IsActive,
+				Viewport:    renderer.ViewportRect{X: camData.Viewport[0], Y: camData.Viewport[1], Width: camData.Viewport[2], Height: camData.Viewport[3]},
+				Depth:       camData.Depth,
 				WorldUp:     mgl.Vec3{0, 1, 0},
 				Front:       mgl.Vec3{0, 0, -1},
 				Up:          mgl.Vec3{0, 1, 0},
 				Sensitivity: 0.1,
+
+				DepthOfField: renderer.DefaultDepthOfFieldSettings(),
+				MotionBlur:   renderer.DefaultMotionBlurSettings(),
 			}
 			if cam.Near == 0 {
 				cam.Near = 0.1
@@ -919,15 +1125,21 @@ func loadScene() {
 			if cam.Speed == 0 {
 				cam.Speed = 70.0
 			}
+			loadCameraController(openglRenderer, cam, camData)
 			cam.UpdateProjection()
 			SceneCameras[i] = cam
 		}
 		logToConsole(fmt.Sprintf("Loaded %d cameras from scene", len(SceneCameras)), "info")
 	}
+	loadSceneRenderTextures(openglRenderer, sceneData.RenderTextures)
+	loadSceneUILayouts(sceneData.UILayouts)
 
 	// Load skybox if it exists in scene
 	if sceneData.Skybox != nil {
-		if sceneData.Skybox.Type == "color" {
+		if sceneData.Skybox.Type == "procedural" {
+			enableProceduralSky(sceneData.Skybox.Procedural)
+			logToConsole("Procedural sky loaded from scene", "info")
+		} else if sceneData.Skybox.Type == "color" {
 			skyboxColorMode = true
 			skyboxSolidColor = sceneData.Skybox.Color
 			openglRenderer.ClearColorR = sceneData.Skybox.Color[0]
@@ -963,9 +1175,21 @@ func loadScene() {
 		renderer.DepthTestEnabled = sceneData.Rendering.DepthTest
 		renderer.FaceCullingEnabled = sceneData.Rendering.FaceCulling
 		renderer.Debug = sceneData.Rendering.Wireframe
+		openglRenderer.RenderPath, _ = renderer.ParseRenderPath(sceneData.Rendering.RenderPath)
+		openglRenderer.SSR = renderer.DefaultSSRSettings()
+		if sceneData.Rendering.SSR != nil {
+			openglRenderer.SSR = *sceneData.Rendering.SSR
+		}
+		if sceneData.Rendering.Fog != nil {
+			openglRenderer.Fog = sceneData.Rendering.Fog.toSettings()
+		}
 		logToConsole("Rendering configuration loaded from scene", "info")
 	}
 
+	if built := openglRenderer.PrewarmShaderVariants(); built > 0 {
+		logToConsole(fmt.Sprintf("Compiled %d shader variants", built), "info")
+	}
+
 	currentScenePath = filename
 	sceneModified = false
 	logToConsole(fmt.Sprintf("Scene loaded: %s (%d models, %d lights)", filepath.Base(filename), len(sceneData.Models), len(sceneData.Lights)), "info")
@@ -982,6 +1206,12 @@ func addModelToScene(path string, name string) *renderer.Model {
 		return nil
 	}
 
+	placeImportedModel(model, name)
+	return model
+}
+
+// placeImportedModel applies editor defaults to a freshly loaded model and adds it to the scene
+func placeImportedModel(model *renderer.Model, name string) {
 	model.Name = name
 
 	// Position new models slightly offset so they don't overlap
@@ -1050,8 +1280,6 @@ func addModelToScene(path string, name string) *renderer.Model {
 	Eng.AddModel(model)
 
 	createGameObjectForModel(model)
-
-	return model
 }
 
 func createGameObjectForModel(model *renderer.Model) *behaviour.GameObject {
@@ -1142,6 +1370,10 @@ func AddSceneCamera(name string) *renderer.Camera {
 		Far:         10000,
 		InvertMouse: false,
 		IsActive:    len(SceneCameras) == 0, // First camera is active by default
+		OrthoSize:   renderer.DefaultOrthoSize,
+
+		DepthOfField: renderer.DefaultDepthOfFieldSettings(),
+		MotionBlur:   renderer.DefaultMotionBlurSettings(),
 	}
 	cam.UpdateProjection()
 
@@ -1157,6 +1389,11 @@ func RemoveSceneCamera(index int) {
 	}
 
 	name := SceneCameras[index].Name
+	if rend, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		if rt := cameraRenderTexture(rend, SceneCameras[index]); rt != nil {
+			rend.RemoveRenderTexture(rt.Name)
+		}
+	}
 	SceneCameras = append(SceneCameras[:index], SceneCameras[index+1:]...)
 	logToConsole(fmt.Sprintf("Removed camera: %s", name), "info")
 }
@@ -1208,6 +1445,8 @@ func SetupEditorScene() {
 
 	// Add initial console message
 	logToConsole("Editor initialized - Type 'help' for available commands", "info")
+
+	initProjectShaders()
 }
 
 // serializeComponents converts components to serializable format
@@ -1272,6 +1511,34 @@ func serializeComponents(components []behaviour.Component) []SceneComponent {
 			sceneComp.Properties["near"] = c.Near
 			sceneComp.Properties["far"] = c.Far
 			sceneComp.Properties["is_main"] = c.IsMain
+			sceneComp.Properties["projection"] = c.Projection
+			sceneComp.Properties["ortho_size"] = c.OrthoSize
+			sceneComp.Properties["controller"] = c.Controller
+			sceneComp.Properties["target"] = c.Target
+			sceneComp.Properties["follow"] = c.Follow
+			sceneComp.Properties["distance"] = c.Distance
+			sceneComp.Properties["yaw"] = c.Yaw
+			sceneComp.Properties["pitch"] = c.Pitch
+			sceneComp.Properties["stiffness"] = c.Stiffness
+			sceneComp.Properties["depth_of_field"] = c.DepthOfField
+			sceneComp.Properties["focus_distance"] = c.FocusDistance
+			sceneComp.Properties["f_stop"] = c.FStop
+			sceneComp.Properties["bokeh_blades"] = c.BokehBlades
+			sceneComp.Properties["motion_blur"] = c.MotionBlur
+			sceneComp.Properties["shutter"] = c.Shutter
+
+		case *behaviour.TextComponent:
+			sceneComp.Properties["text"] = c.Text
+			sceneComp.Properties["font_path"] = c.FontPath
+			sceneComp.Properties["size"] = c.Size
+			sceneComp.Properties["color"] = c.Color
+			sceneComp.Properties["alpha"] = c.Alpha
+			sceneComp.Properties["align"] = c.Align
+			sceneComp.Properties["space"] = c.Space
+			sceneComp.Properties["billboard"] = c.Billboard
+			sceneComp.Properties["screen_position"] = c.ScreenPosition
+			sceneComp.Properties["max_width"] = c.MaxWidth
+			sceneComp.Properties["line_spacing"] = c.LineSpacing
 
 		case *behaviour.ScriptComponent:
 			sceneComp.Properties["script_name"] = c.ScriptName
@@ -1393,6 +1660,100 @@ func deserializeComponents(sceneComps []SceneComponent) []behaviour.Component {
 			if v, ok := sc.Properties["is_main"].(bool); ok {
 				c.IsMain = v
 			}
+			if v, ok := sc.Properties["projection"].(string); ok {
+				c.Projection = v
+			}
+			if v, ok := sc.Properties["ortho_size"].(float64); ok {
+				c.OrthoSize = float32(v)
+			}
+			if v, ok := sc.Properties["controller"].(string); ok {
+				c.Controller = v
+			}
+			if v, ok := sc.Properties["target"].([]interface{}); ok && len(v) == 3 {
+				for i := range c.Target {
+					if f, ok := v[i].(float64); ok {
+						c.Target[i] = float32(f)
+					}
+				}
+			}
+			if v, ok := sc.Properties["follow"].(string); ok {
+				c.Follow = v
+			}
+			if v, ok := sc.Properties["distance"].(float64); ok {
+				c.Distance = float32(v)
+			}
+			if v, ok := sc.Properties["yaw"].(float64); ok {
+				c.Yaw = float32(v)
+			}
+			if v, ok := sc.Properties["pitch"].(float64); ok {
+				c.Pitch = float32(v)
+			}
+			if v, ok := sc.Properties["stiffness"].(float64); ok {
+				c.Stiffness = float32(v)
+			}
+			if v, ok := sc.Properties["depth_of_field"].(bool); ok {
+				c.DepthOfField = v
+			}
+			if v, ok := sc.Properties["focus_distance"].(float64); ok {
+				c.FocusDistance = float32(v)
+			}
+			if v, ok := sc.Properties["f_stop"].(float64); ok {
+				c.FStop = float32(v)
+			}
+			if v, ok := sc.Properties["bokeh_blades"].(float64); ok {
+				c.BokehBlades = int(v)
+			}
+			if v, ok := sc.Properties["motion_blur"].(bool); ok {
+				c.MotionBlur = v
+			}
+			if v, ok := sc.Properties["shutter"].(float64); ok {
+				c.Shutter = float32(v)
+			}
+			comp = c
+
+		case string(behaviour.ComponentTypeText):
+			c := behaviour.NewTextComponent()
+			if v, ok := sc.Properties["text"].(string); ok {
+				c.Text = v
+			}
+			if v, ok := sc.Properties["font_path"].(string); ok {
+				c.FontPath = v
+			}
+			if v, ok := sc.Properties["size"].(float64); ok {
+				c.Size = float32(v)
+			}
+			if v, ok := sc.Properties["color"].([]interface{}); ok && len(v) == 3 {
+				for i := range c.Color {
+					if f, ok := v[i].(float64); ok {
+						c.Color[i] = float32(f)
+					}
+				}
+			}
+			if v, ok := sc.Properties["alpha"].(float64); ok {
+				c.Alpha = float32(v)
+			}
+			if v, ok := sc.Properties["align"].(string); ok {
+				c.Align = v
+			}
+			if v, ok := sc.Properties["space"].(string); ok {
+				c.Space = v
+			}
+			if v, ok := sc.Properties["billboard"].(bool); ok {
+				c.Billboard = v
+			}
+			if v, ok := sc.Properties["screen_position"].([]interface{}); ok && len(v) == 2 {
+				for i := range c.ScreenPosition {
+					if f, ok := v[i].(float64); ok {
+						c.ScreenPosition[i] = float32(f)
+					}
+				}
+			}
+			if v, ok := sc.Properties["max_width"].(float64); ok {
+				c.MaxWidth = float32(v)
+			}
+			if v, ok := sc.Properties["line_spacing"].(float64); ok {
+				c.LineSpacing = float32(v)
+			}
 			comp = c
 
 		case string(behaviour.ComponentTypeScript):
@@ -1421,6 +1782,36 @@ func deserializeComponents(sceneComps []SceneComponent) []behaviour.Component {
 	return result
 }
 
+// sceneFogFromSettings converts renderer fog settings to their scene representation
+func sceneFogFromSettings(fog renderer.FogSettings) *SceneFog {
+	return &SceneFog{
+		Mode:          string(fog.Mode),
+		Color:         [3]float32{fog.Color[0], fog.Color[1], fog.Color[2]},
+		Density:       fog.Density,
+		HeightFog:     fog.HeightFogEnabled,
+		HeightDensity: fog.HeightDensity,
+		HeightBase:    fog.HeightBase,
+		HeightFalloff: fog.HeightFalloff,
+		MaxOpacity:    fog.MaxOpacity,
+		SkyBlend:      fog.SkyBlend,
+	}
+}
+
+// toSettings converts saved scene fog back to renderer fog settings
+func (f *SceneFog) toSettings() renderer.FogSettings {
+	return renderer.FogSettings{
+		Mode:             renderer.FogMode(f.Mode),
+		Color:            mgl.Vec3{f.Color[0], f.Color[1], f.Color[2]},
+		Density:          f.Density,
+		HeightFogEnabled: f.HeightFog,
+		HeightDensity:    f.HeightDensity,
+		HeightBase:       f.HeightBase,
+		HeightFalloff:    f.HeightFalloff,
+		MaxOpacity:       f.MaxOpacity,
+		SkyBlend:         f.SkyBlend,
+	}
+}
+
 // quatToEulerArray converts quaternion to euler angles array
 func quatToEulerArray(q mgl.Quat) [3]float32 {
 	euler := quatToEuler(q)
@@ -0,0 +1,121 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectShaderDir returns the project's shader override directory
+func projectShaderDir() string {
+	if CurrentProject == nil {
+		return ""
+	}
+	return filepath.Join(CurrentProject.Path, "resources", "shaders")
+}
+
+// initProjectShaders routes shader errors to the console and, if the project has a
+// resources/shaders directory, loads and watches shader files from it
+func initProjectShaders() {
+	renderer.OnShaderReload = logShaderResult
+
+	dir := projectShaderDir()
+	if dir == "" {
+		return
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		renderer.SetShaderDirectory(dir)
+		renderer.SetShaderHotReload(true)
+		logToConsole(fmt.Sprintf("Loading shaders from %s (hot reload on)", dir), "info")
+	}
+}
+
+// logShaderResult reports shader rebuilds and compile errors in the console
+func logShaderResult(shaderName string, err error) {
+	if err == nil {
+		logToConsole(fmt.Sprintf("Shader reloaded: %s", shaderName), "info")
+		return
+	}
+
+	var shaderErr *renderer.ShaderError
+	if !errors.As(err, &shaderErr) {
+		logToConsole(fmt.Sprintf("Shader %s: %v", shaderName, err), "error")
+		return
+	}
+	logToConsole(fmt.Sprintf("Shader %s: %s failed", shaderErr.Shader, shaderErr.Stage), "error")
+	for _, line := range strings.Split(shaderErr.Log, "\n") {
+		if strings.TrimSpace(line) != "" {
+			logToConsole("  "+line, "error")
+		}
+	}
+}
+
+// executeShaderCommand handles the "shaders" console command
+func executeShaderCommand(args []string) {
+	if len(args) == 0 {
+		dir := renderer.GetShaderDirectory()
+		if dir == "" {
+			logToConsole("Shaders: embedded sources only", "info")
+		} else {
+			logToConsole(fmt.Sprintf("Shaders: %s (hot reload: %v)", dir, renderer.IsShaderHotReloadEnabled()), "info")
+		}
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "reload":
+		renderer.ReloadShaders()
+		logToConsole("Rebuilding all shaders", "info")
+
+	case "export":
+		dir := projectShaderDir()
+		if len(args) > 1 {
+			dir = strings.Join(args[1:], " ")
+		}
+		if dir == "" {
+			logToConsole("Usage: shaders export <dir> (no project open)", "warning")
+			return
+		}
+		written, err := renderer.ExportEmbeddedShaders(dir)
+		if err != nil {
+			logToConsole(fmt.Sprintf("Failed to export shaders: %v", err), "error")
+			return
+		}
+		logToConsole(fmt.Sprintf("Exported %d shader files to %s", written, dir), "info")
+		renderer.SetShaderDirectory(dir)
+		renderer.SetShaderHotReload(true)
+
+	case "dir":
+		if len(args) < 2 {
+			logToConsole("Usage: shaders dir <path>", "warning")
+			return
+		}
+		dir := strings.Join(args[1:], " ")
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			logToConsole(fmt.Sprintf("Not a directory: %s", dir), "error")
+			return
+		}
+		renderer.SetShaderDirectory(dir)
+		renderer.SetShaderHotReload(true)
+		logToConsole(fmt.Sprintf("Loading shaders from %s", dir), "info")
+
+	case "watch":
+		enabled := !renderer.IsShaderHotReloadEnabled()
+		if len(args) > 1 {
+			enabled = args[1] == "on"
+		}
+		renderer.SetShaderHotReload(enabled)
+		logToConsole(fmt.Sprintf("Shader hot reload: %v", enabled), "info")
+
+	case "embedded":
+		renderer.SetShaderHotReload(false)
+		renderer.SetShaderDirectory("")
+		logToConsole("Using embedded shaders", "info")
+
+	default:
+		logToConsole("Usage: shaders [reload|export [dir]|dir <path>|watch [on/off]|embedded]", "warning")
+	}
+}
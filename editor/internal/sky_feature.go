@@ -0,0 +1,193 @@
+package editor
+
+import (
+	"Gopher3D/internal/behaviour"
+	"Gopher3D/internal/renderer"
+	"fmt"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// enableProceduralSky switches the background to the atmospheric sky and starts a
+// time-of-day controller driving the primary light. saved may be nil for defaults.
+func enableProceduralSky(saved *SceneProceduralSky) {
+	openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		return
+	}
+
+	disableProceduralSky()
+
+	tod := renderer.NewTimeOfDay(Eng.Light, 10.0)
+	if saved != nil {
+		applySceneProceduralSky(saved, tod)
+	}
+
+	sky, err := renderer.CreateProceduralSkybox(tod.Atmosphere)
+	if err != nil {
+		logToConsole(fmt.Sprintf("Failed to create procedural sky: %v", err), "error")
+		return
+	}
+	openglRenderer.SetSkybox(sky)
+
+	if activeWaterSim != nil {
+		tod.AddSkyColorTarget(activeWaterSim)
+	}
+	tod.Apply()
+	behaviour.GlobalBehaviourManager.Add(tod)
+
+	activeTimeOfDay = tod
+	skyboxProceduralMode = true
+	skyboxColorMode = false
+	logToConsole("Procedural sky enabled", "info")
+}
+
+// disableProceduralSky removes the procedural sky and stops its time-of-day controller
+func disableProceduralSky() {
+	if activeTimeOfDay != nil {
+		behaviour.GlobalBehaviourManager.Remove(activeTimeOfDay)
+		activeTimeOfDay = nil
+	}
+	skyboxProceduralMode = false
+
+	if openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		if sky := openglRenderer.GetSkybox(); sky != nil && sky.IsProcedural() {
+			openglRenderer.SetSkybox(nil)
+			sky.Cleanup()
+		}
+	}
+}
+
+// renderProceduralSkySettings draws the time-of-day and atmosphere controls
+func renderProceduralSkySettings() {
+	tod := activeTimeOfDay
+	if tod == nil {
+		return
+	}
+
+	// Keep following the scene's primary light if it was replaced
+	if Eng.Light != nil && tod.Light != Eng.Light {
+		tod.Light = Eng.Light
+	}
+
+	hour := tod.Hour
+	if imgui.SliderFloatV("Time of Day", &hour, 0.0, 24.0, fmt.Sprintf("%02d:%02d", int(hour), int((hour-float32(int(hour)))*60)), 0) {
+		tod.SetHour(hour)
+		sceneModified = true
+	}
+	imgui.Checkbox("Pause Cycle", &tod.Paused)
+	if imgui.DragFloatV("Day Length (s)", &tod.DayLength, 1.0, 0, 86400, "%.0f", 0) {
+		sceneModified = true
+	}
+	if imgui.SliderFloatV("Sun Azimuth", &tod.SunAzimuth, -180.0, 180.0, "%.0f°", 0) {
+		tod.Apply()
+		sceneModified = true
+	}
+	if imgui.SliderFloatV("Sun Tilt", &tod.SunTilt, 0.0, 80.0, "%.0f°", 0) {
+		tod.Apply()
+		sceneModified = true
+	}
+
+	if imgui.TreeNode("Light Response") {
+		imgui.SliderFloatV("Day Intensity", &tod.DayIntensity, 0.0, 5.0, "%.2f", 0)
+		imgui.SliderFloatV("Night Intensity", &tod.NightIntensity, 0.0, 1.0, "%.2f", 0)
+		imgui.SliderFloatV("Day Ambient", &tod.DayAmbient, 0.0, 1.0, "%.2f", 0)
+		imgui.SliderFloatV("Night Ambient", &tod.NightAmbient, 0.0, 1.0, "%.2f", 0)
+		imgui.TreePop()
+	}
+
+	if imgui.TreeNode("Atmosphere") {
+		changed := false
+		if imgui.SliderFloatV("Sun Intensity", &tod.Atmosphere.SunIntensity, 1.0, 60.0, "%.1f", 0) {
+			changed = true
+		}
+		// Show scattering in 1e-6/m units so the sliders are usable
+		rayleigh := [3]float32{
+			tod.Atmosphere.RayleighCoefficient[0] * 1e6,
+			tod.Atmosphere.RayleighCoefficient[1] * 1e6,
+			tod.Atmosphere.RayleighCoefficient[2] * 1e6,
+		}
+		if imgui.DragFloat3V("Rayleigh (1e-6)", &rayleigh, 0.1, 0, 100, "%.1f", 0) {
+			tod.Atmosphere.RayleighCoefficient = mgl.Vec3{rayleigh[0] * 1e-6, rayleigh[1] * 1e-6, rayleigh[2] * 1e-6}
+			changed = true
+		}
+		mie := tod.Atmosphere.MieCoefficient * 1e6
+		if imgui.SliderFloatV("Mie (1e-6)", &mie, 0.0, 200.0, "%.1f", 0) {
+			tod.Atmosphere.MieCoefficient = mie * 1e-6
+			changed = true
+		}
+		if imgui.SliderFloatV("Mie Directionality", &tod.Atmosphere.MieDirectionality, 0.0, 0.99, "%.3f", 0) {
+			changed = true
+		}
+		if imgui.SliderFloatV("Exposure##sky", &tod.Atmosphere.Exposure, 0.1, 5.0, "%.2f", 0) {
+			changed = true
+		}
+		if changed {
+			syncProceduralSkyAtmosphere()
+			sceneModified = true
+		}
+		imgui.TreePop()
+	}
+}
+
+// syncProceduralSkyAtmosphere copies the controller's atmosphere to the rendered sky
+func syncProceduralSkyAtmosphere() {
+	if activeTimeOfDay == nil {
+		return
+	}
+	if openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		if sky := openglRenderer.GetSkybox(); sky != nil && sky.IsProcedural() {
+			*sky.Atmosphere = activeTimeOfDay.Atmosphere
+		}
+	}
+	activeTimeOfDay.Apply()
+}
+
+// sceneProceduralSkyFromController captures the sky and time of day for saving
+func sceneProceduralSkyFromController(tod *renderer.TimeOfDay) *SceneProceduralSky {
+	return &SceneProceduralSky{
+		TimeOfDay:         tod.Hour,
+		DayLength:         tod.DayLength,
+		Paused:            tod.Paused,
+		SunAzimuth:        tod.SunAzimuth,
+		SunTilt:           tod.SunTilt,
+		DayIntensity:      tod.DayIntensity,
+		NightIntensity:    tod.NightIntensity,
+		DayAmbient:        tod.DayAmbient,
+		NightAmbient:      tod.NightAmbient,
+		SunIntensity:      tod.Atmosphere.SunIntensity,
+		Rayleigh:          [3]float32{tod.Atmosphere.RayleighCoefficient[0], tod.Atmosphere.RayleighCoefficient[1], tod.Atmosphere.RayleighCoefficient[2]},
+		Mie:               tod.Atmosphere.MieCoefficient,
+		MieDirectionality: tod.Atmosphere.MieDirectionality,
+		Exposure:          tod.Atmosphere.Exposure,
+	}
+}
+
+// applySceneProceduralSky restores saved sky settings onto a controller
+func applySceneProceduralSky(saved *SceneProceduralSky, tod *renderer.TimeOfDay) {
+	tod.Hour = saved.TimeOfDay
+	tod.DayLength = saved.DayLength
+	tod.Paused = saved.Paused
+	tod.SunAzimuth = saved.SunAzimuth
+	tod.SunTilt = saved.SunTilt
+	tod.DayIntensity = saved.DayIntensity
+	tod.NightIntensity = saved.NightIntensity
+	tod.DayAmbient = saved.DayAmbient
+	tod.NightAmbient = saved.NightAmbient
+	if saved.SunIntensity > 0 {
+		tod.Atmosphere.SunIntensity = saved.SunIntensity
+	}
+	if saved.Rayleigh != [3]float32{} {
+		tod.Atmosphere.RayleighCoefficient = mgl.Vec3{saved.Rayleigh[0], saved.Rayleigh[1], saved.Rayleigh[2]}
+	}
+	if saved.Mie > 0 {
+		tod.Atmosphere.MieCoefficient = saved.Mie
+	}
+	if saved.MieDirectionality > 0 {
+		tod.Atmosphere.MieDirectionality = saved.MieDirectionality
+	}
+	if saved.Exposure > 0 {
+		tod.Atmosphere.Exposure = saved.Exposure
+	}
+}
@@ -0,0 +1,177 @@
+package editor
+
+import (
+	"Gopher3D/internal/behaviour"
+	"Gopher3D/internal/renderer"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/sqweek/dialog"
+)
+
+// componentTexts maps each text component to the renderer text drawing it
+var componentTexts = make(map[*behaviour.TextComponent]*renderer.Text)
+
+// syncTextComponents keeps one renderer text per text component, following its
+// game object's transform, and drops texts whose component went away
+func syncTextComponents(openglRenderer *renderer.OpenGLRenderer) {
+	seen := make(map[*behaviour.TextComponent]bool, len(componentTexts))
+	for _, obj := range behaviour.GlobalComponentManager.GetAllGameObjects() {
+		for _, comp := range obj.Components {
+			c, ok := comp.(*behaviour.TextComponent)
+			if !ok {
+				continue
+			}
+			seen[c] = true
+			text := componentTexts[c]
+			if text == nil {
+				text = renderer.NewText(c.Text)
+				openglRenderer.AddText(text)
+				componentTexts[c] = text
+				c.TextData = text
+			}
+			applyTextComponent(text, c, obj)
+		}
+	}
+	for c, text := range componentTexts {
+		if !seen[c] {
+			openglRenderer.RemoveText(text)
+			delete(componentTexts, c)
+			c.TextData = nil
+		}
+	}
+}
+
+// applyTextComponent copies a component's settings onto the text drawing it
+func applyTextComponent(text *renderer.Text, c *behaviour.TextComponent, obj *behaviour.GameObject) {
+	text.Name = obj.Name
+	text.Content = c.Text
+	text.Font = textComponentFont(c.FontPath)
+	text.Size = c.Size
+	text.Color = [4]float32{c.Color[0], c.Color[1], c.Color[2], c.Alpha}
+	text.Align = renderer.TextAlign(c.Align)
+	text.LineSpacing = c.LineSpacing
+	text.MaxWidth = c.MaxWidth
+	text.Space = renderer.TextSpace(c.Space)
+	text.Billboard = c.Billboard
+	text.Hidden = !obj.Active || !c.GetEnabled()
+	if text.Space == renderer.TextWorld && obj.Transform != nil {
+		text.Position = obj.Transform.Position
+		text.Rotation = obj.Transform.Rotation
+	} else {
+		text.Position = [3]float32{c.ScreenPosition[0], c.ScreenPosition[1], 0}
+	}
+}
+
+// failedFonts remembers font paths that did not load so they are reported once
+var failedFonts = make(map[string]bool)
+
+// textComponentFont loads a component's font, falling back to the built-in one
+func textComponentFont(path string) *renderer.Font {
+	if path == "" {
+		return nil
+	}
+	font, err := renderer.LoadFont(path)
+	if err != nil {
+		if !failedFonts[path] {
+			failedFonts[path] = true
+			logToConsole(fmt.Sprintf("Failed to load font %s: %v", filepath.Base(path), err), "warning")
+		}
+		return nil
+	}
+	return font
+}
+
+// projectFontDir returns the default directory for font files
+func projectFontDir() string {
+	if CurrentProject == nil {
+		return "../resources/fonts"
+	}
+	return filepath.Join(CurrentProject.Path, "resources", "fonts")
+}
+
+func renderTextComponentInspector(c *behaviour.TextComponent) {
+	imgui.InputTextMultiline("Text", &c.Text)
+
+	font := "Built-in"
+	if c.FontPath != "" {
+		font = filepath.Base(c.FontPath)
+	}
+	imgui.Text("Font: " + font)
+	imgui.SameLine()
+	if imgui.Button("...##font") {
+		filename, err := dialog.File().
+			SetStartDir(projectFontDir()).
+			Filter("Fonts", "ttf", "otf").
+			Title("Select Font").
+			Load()
+		if err == nil && filename != "" {
+			c.FontPath = filename
+			delete(failedFonts, filename)
+		}
+	}
+	if c.FontPath != "" {
+		imgui.SameLine()
+		if imgui.Button("Default##font") {
+			c.FontPath = ""
+		}
+	}
+
+	imgui.DragFloatV("Size", &c.Size, 0.5, 1, 1000, "%.1f", 0)
+	color := c.Color
+	if imgui.ColorEdit3("Color", &color) {
+		c.Color = color
+	}
+	imgui.SliderFloatV("Alpha", &c.Alpha, 0, 1, "%.2f", 0)
+
+	if imgui.BeginCombo("Align", c.Align) {
+		for _, align := range renderer.TextAligns {
+			if imgui.SelectableV(string(align), c.Align == string(align), 0, imgui.Vec2{}) {
+				c.Align = string(align)
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.DragFloatV("Max Width", &c.MaxWidth, 1, 0, 10000, "%.0f", 0)
+	imgui.SliderFloatV("Line Spacing", &c.LineSpacing, 0.5, 3, "%.2f", 0)
+
+	if imgui.BeginCombo("Space", c.Space) {
+		for _, space := range []renderer.TextSpace{renderer.TextScreen, renderer.TextWorld} {
+			if imgui.SelectableV(string(space), c.Space == string(space), 0, imgui.Vec2{}) {
+				c.Space = string(space)
+			}
+		}
+		imgui.EndCombo()
+	}
+	if c.Space == string(renderer.TextWorld) {
+		imgui.Checkbox("Billboard", &c.Billboard)
+		imgui.Text("Placed at the object's transform")
+	} else {
+		imgui.DragFloat2V("Screen Position", &c.ScreenPosition, 1, 0, 0, "%.0f", 0)
+	}
+}
+
+// copyTextFonts copies the fonts text components use into the export's assets
+func copyTextFonts(objects []SceneGameObject, sceneDir, assetsDir string) {
+	copied := make(map[string]bool)
+	for _, obj := range objects {
+		for _, comp := range obj.Components {
+			path, _ := comp.Properties["font_path"].(string)
+			if comp.Category != string(behaviour.ComponentTypeText) || path == "" || copied[path] {
+				continue
+			}
+			copied[path] = true
+			src := path
+			if !filepath.IsAbs(src) {
+				if _, err := os.Stat(src); err != nil {
+					src = filepath.Join(sceneDir, src)
+				}
+			}
+			if err := copyFile(src, filepath.Join(assetsDir, filepath.Base(path))); err != nil {
+				logToConsole(fmt.Sprintf("Warning: Could not copy font %s: %v", filepath.Base(path), err), "warning")
+			}
+		}
+	}
+}
@@ -14,10 +14,11 @@ var (
 	addModelScale = [3]float32{1, 1, 1}
 	addModelPos   = [3]float32{0, 0, 0}
 
-	addLightType      = 0 // 0=Directional, 1=Point
+	addLightType      = 0 // 0=Directional, 1=Point, 2=Rectangle, 3=Disk
 	addLightColor     = [3]float32{1, 1, 1}
 	addLightIntensity = float32(1.0)
-	addLightRange     = float32(100.0) // For point light
+	addLightRange     = float32(100.0)   // For point light
+	addLightSize      = [2]float32{2, 1} // For area lights
 
 	addWaterSize      = float32(1000.0) // Reduced default size for better editor usability
 	addWaterAmplitude = float32(5.0)    // Reduced amplitude for scale
@@ -128,14 +129,20 @@ func renderAddLightDialog() {
 		imgui.RadioButtonInt("Directional Light", &addLightType, 0)
 		imgui.SameLine()
 		imgui.RadioButtonInt("Point Light", &addLightType, 1)
+		imgui.RadioButtonInt("Rectangle Light", &addLightType, 2)
+		imgui.SameLine()
+		imgui.RadioButtonInt("Disk Light", &addLightType, 3)
 
 		imgui.Spacing()
-		imgui.ColorEdit3("Color", &addLightColor)
+		colorEditLinear("Color", &addLightColor)
 		imgui.DragFloatV("Intensity", &addLightIntensity, 0.1, 0.0, 100.0, "%.1f", 1.0)
 
 		if addLightType == 1 {
 			imgui.DragFloatV("Range", &addLightRange, 1.0, 0.0, 10000.0, "%.1f", 1.0)
 		}
+		if addLightType >= 2 {
+			imgui.DragFloat2V("Size", &addLightSize, 0.05, 0.01, 1000.0, "%.2f", 0)
+		}
 
 		imgui.Separator()
 		imgui.Spacing()
@@ -152,6 +159,22 @@ func renderAddLightDialog() {
 					addLightIntensity,
 				)
 				light.Name = fmt.Sprintf("Directional Light %d", len(Eng.GetRenderer().(*renderer.OpenGLRenderer).GetLights())+1)
+			} else if addLightType >= 2 {
+				// Area, facing where the camera looks
+				mode, label := "rect", "Rectangle Light"
+				if addLightType == 3 {
+					mode, label = "disk", "Disk Light"
+				}
+				light = renderer.CreateAreaLight(
+					mode,
+					Eng.Camera.Position,
+					Eng.Camera.Front,
+					colorVec,
+					addLightIntensity,
+					addLightSize[0],
+					addLightSize[1],
+				)
+				light.Name = fmt.Sprintf("%s %d", label, len(Eng.GetRenderer().(*renderer.OpenGLRenderer).GetLights())+1)
 			} else {
 				// Point
 				light = renderer.CreatePointLight(
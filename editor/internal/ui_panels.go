@@ -220,6 +220,7 @@ func RenderEditorUI() {
 
 	// Initialize panel layouts if not done
 	initializePanelLayouts()
+	syncTextComponents(openglRenderer)
 
 	models := openglRenderer.GetModels()
 
@@ -251,7 +252,7 @@ func RenderEditorUI() {
 				if err == nil && filename != "" {
 					// Extract name from path
 					name := getFileNameFromPath(filename)
-					addModelToScene(filename, name)
+					importModelAsync(filename, name)
 				}
 			}
 			if imgui.MenuItem("Import Texture...") {
@@ -267,7 +268,7 @@ func RenderEditorUI() {
 
 						filename, err := dialog.File().
 							SetStartDir(startDir).
-							Filter("Images", "png", "jpg", "jpeg").
+							Filter("Images", "png", "jpg", "jpeg", "ktx2", "dds").
 							Title("Import Texture").
 							Load()
 						if err == nil && filename != "" {
@@ -344,12 +345,27 @@ func RenderEditorUI() {
 				ShowAdvancedRender = !ShowAdvancedRender
 				SaveConfig()
 			}
+			if imgui.MenuItemV("Profiler", "", ShowProfiler, true) {
+				ShowProfiler = !ShowProfiler
+			}
+			if imgui.MenuItemV("Game UI", "", ShowGameUI, true) {
+				ShowGameUI = !ShowGameUI
+			}
+			if imgui.BeginMenu("Camera Views") {
+				for i, name := range viewLayoutNames {
+					if imgui.MenuItemV(name, "", editorViewLayout == i, true) {
+						setEditorViewLayout(i)
+					}
+				}
+				imgui.EndMenu()
+			}
 			imgui.Separator()
 			if imgui.MenuItemV("Show Gizmos", "", ShowGizmos, true) {
 				ShowGizmos = !ShowGizmos
 			}
 			imgui.EndMenu()
 		}
+		renderDebugViewMenu()
 		if imgui.BeginMenu("Experimental") {
 			if imgui.MenuItemV("Panel Snapping", "", snapEnabled, true) {
 				snapEnabled = !snapEnabled
@@ -360,7 +376,7 @@ func RenderEditorUI() {
 		// FPS Display in menu bar (right side)
 		updateFPS()
 		menuBarSize := imgui.WindowSize()
-		fpsText := fmt.Sprintf("FPS: %.0f", fps)
+		fpsText := debugViewStatus() + fmt.Sprintf("FPS: %.0f", fps)
 		fpsTextSize := imgui.CalcTextSize(fpsText, false, 0)
 		imgui.SetCursorPos(imgui.Vec2{X: menuBarSize.X - fpsTextSize.X - 10, Y: imgui.CursorPosY()})
 		imgui.Text(fpsText)
@@ -403,6 +419,16 @@ func RenderEditorUI() {
 		renderRebuildModal()
 	}
 
+	renderAssetLoadingStatus(openglRenderer)
+	updateEditorViews()
+
+	if ShowProfiler {
+		renderProfilerPanel()
+	}
+	if ShowGameUI {
+		renderGameUIPanel()
+	}
+
 	// File Explorer (Bottom Left)
 	if ShowFileExplorer {
 		posCondition := getPanelPosCondition("Project")
@@ -653,16 +679,24 @@ func RenderEditorUI() {
 
 			if imgui.CollapsingHeaderV("Skybox / Background", imgui.TreeNodeFlagsDefaultOpen) {
 				imgui.Text("Background Mode:")
-				if imgui.RadioButton("Solid Color", skyboxColorMode) {
+				if imgui.RadioButton("Solid Color", skyboxColorMode && !skyboxProceduralMode) {
 					skyboxColorMode = true
+					disableProceduralSky()
 					SaveConfig()
 				}
 				imgui.SameLine()
-				if imgui.RadioButton("Skybox Image", !skyboxColorMode) {
+				if imgui.RadioButton("Skybox Image", !skyboxColorMode && !skyboxProceduralMode) {
 					skyboxColorMode = false
+					disableProceduralSky()
 					SaveConfig()
 				}
-				if skyboxColorMode {
+				imgui.SameLine()
+				if imgui.RadioButton("Procedural Sky", skyboxProceduralMode) && !skyboxProceduralMode {
+					enableProceduralSky(nil)
+				}
+				if skyboxProceduralMode {
+					renderProceduralSkySettings()
+				} else if skyboxColorMode {
 					imgui.ColorEdit3V("##skycolor", &skyboxSolidColor, 0)
 					if imgui.Button("Apply") {
 						// Explicitly set the renderer clear color
@@ -693,6 +727,10 @@ func RenderEditorUI() {
 				}
 			}
 
+			if imgui.CollapsingHeaderV("Fog", imgui.TreeNodeFlagsNone) {
+				renderFogSettings(openglRenderer)
+			}
+
 			if imgui.CollapsingHeaderV("Window", imgui.TreeNodeFlagsNone) {
 				window := Eng.GetWindow()
 				if window != nil {
@@ -892,6 +930,9 @@ func RenderEditorUI() {
 					// If water, clean up the simulation and remove from behavior manager
 					if isWater && activeWaterSim != nil {
 						behaviour.GlobalBehaviourManager.Remove(activeWaterSim)
+						if activeTimeOfDay != nil {
+							activeTimeOfDay.RemoveSkyColorTarget(activeWaterSim)
+						}
 						activeWaterSim = nil
 						logToConsole("Water simulation removed", "info")
 					}
@@ -970,7 +1011,7 @@ func RenderEditorUI() {
 					imgui.Separator()
 					if imgui.CollapsingHeaderV("Material Properties", imgui.TreeNodeFlagsDefaultOpen) {
 						diffuse := [3]float32{model.Material.DiffuseColor[0], model.Material.DiffuseColor[1], model.Material.DiffuseColor[2]}
-						if imgui.ColorEdit3V("Diffuse Color", &diffuse, 0) {
+						if colorEditLinear("Diffuse Color", &diffuse) {
 							model.SetDiffuseColor(diffuse[0], diffuse[1], diffuse[2])
 							model.IsDirty = true
 						}
@@ -1003,13 +1044,15 @@ func RenderEditorUI() {
 
 							filename, err := dialog.File().
 								SetStartDir(startDir).
-								Filter("Images", "png", "jpg", "jpeg").
+								Filter("Images", "png", "jpg", "jpeg", "ktx2", "dds").
 								Title("Load Texture for Model").
 								Load()
 							if err == nil && filename != "" {
 								loadTextureToSelected(filename)
 							}
 						}
+						imgui.SameLine()
+						renderTexturePicker(openglRenderer, "model", loadTextureToSelected)
 
 						if model.Material.TexturePath != "" {
 							imgui.SameLine()
@@ -1026,10 +1069,15 @@ func RenderEditorUI() {
 								model.IsDirty = true
 								logToConsole(fmt.Sprintf("Removed texture from %s", model.Name), "info")
 							}
+							renderTextureSamplerSettings(model)
 						}
 					}
 				}
 
+				imgui.Separator()
+				renderMaterialAssetInspector(model)
+				renderModelReflection(openglRenderer, model)
+
 				// Scripts Section (Unity-style)
 				imgui.Spacing()
 				if imgui.CollapsingHeaderV("Scripts", imgui.TreeNodeFlagsDefaultOpen) {
@@ -1190,6 +1238,11 @@ func RenderEditorUI() {
 								sim.ApplyChanges()
 							}
 
+							reflection := sim.ReflectionResolution
+							if imgui.SliderFloatV("Reflection Resolution", &reflection, 0.0, 1.0, "%.2f (0 = sky only)", 1.0) {
+								sim.ReflectionResolution = reflection
+							}
+
 							// Shadows
 							imgui.Separator()
 							shadow := sim.ShadowStrength
@@ -1270,7 +1323,7 @@ func RenderEditorUI() {
 
 					// Direction (Always show for directional/spot, or if user wants to see it)
 					// Forcing visibility for now to ensure user sees it
-					if light.Mode == "directional" || light.Mode == "spot" {
+					if light.Mode == "directional" || light.Mode == "spot" || light.IsArea() {
 						imgui.Text("Direction")
 						dirX, dirY, dirZ := light.Direction.X(), light.Direction.Y(), light.Direction.Z()
 						imgui.PushItemWidth(w / 3.3)
@@ -1296,7 +1349,7 @@ func RenderEditorUI() {
 
 					// Color
 					color := [3]float32{light.Color.X(), light.Color.Y(), light.Color.Z()}
-					if imgui.ColorEdit3V("Color", &color, 0) {
+					if colorEditLinear("Color", &color) {
 						light.Color = mgl.Vec3{color[0], color[1], color[2]}
 					}
 
@@ -1318,6 +1371,22 @@ func RenderEditorUI() {
 						light.Temperature = temp
 					}
 
+					if light.IsArea() {
+						imgui.Separator()
+						imgui.Text("Emitting Surface")
+						width, height := light.AreaWidth, light.AreaHeight
+						widthLabel, heightLabel := "Width", "Height"
+						if light.Mode == "disk" {
+							widthLabel, heightLabel = "Diameter X", "Diameter Y"
+						}
+						if imgui.DragFloatV(widthLabel, &width, 0.05, 0.01, 1000, "%.2f", 0) {
+							light.AreaWidth = width
+						}
+						if imgui.DragFloatV(heightLabel, &height, 0.05, 0.01, 1000, "%.2f", 0) {
+							light.AreaHeight = height
+						}
+					}
+
 					if light.Mode == "point" || light.Mode == "spot" {
 						imgui.Separator()
 						imgui.Text("Attenuation")
@@ -1336,6 +1405,10 @@ func RenderEditorUI() {
 							light.QuadraticAtten = quad
 						}
 					}
+
+					if light.Mode == "point" || light.Mode == "spot" {
+						renderLightIESProfile(light)
+					}
 				}
 			} else if selectedType == "gameobject" && selectedGameObjectIndex >= 0 {
 				// GameObject inspector (all GameObjects)
@@ -1362,6 +1435,9 @@ func RenderEditorUI() {
 							if waterComp, ok := comp.(*behaviour.WaterComponent); ok {
 								if ws, ok := waterComp.Simulation.(*WaterSimulation); ok {
 									behaviour.GlobalBehaviourManager.Remove(ws)
+									if activeTimeOfDay != nil {
+										activeTimeOfDay.RemoveSkyColorTarget(ws)
+									}
 									if activeWaterSim == ws {
 										activeWaterSim = nil
 									}
@@ -1467,6 +1543,8 @@ func RenderEditorUI() {
 								categoryIcon = "[L]"
 							case behaviour.ComponentTypeCamera:
 								categoryIcon = "[Cam]"
+							case behaviour.ComponentTypeText:
+								categoryIcon = "[T]"
 							}
 
 							expanded := imgui.TreeNodeV(categoryIcon+" "+typeName, 0)
@@ -1601,9 +1679,16 @@ func RenderEditorUI() {
 						cam.UpdateProjection()
 					}
 
+					imgui.Separator()
+					renderCameraProjection(cam)
+
 					imgui.PopItemWidth()
 				}
 
+				renderCameraController(openglRenderer, cam)
+				renderCameraViewport(cam)
+				renderCameraRenderTexture(openglRenderer, cam)
+
 				// Copy from Editor Camera button
 				imgui.Separator()
 				if imgui.Button("Copy from Editor Camera") {
@@ -1697,7 +1782,7 @@ func renderFileExplorerContent() {
 				selectedFilePath = filepath.Join(currentDirectory, entry.Name())
 				if imgui.IsItemHovered() && imgui.IsMouseDoubleClicked(0) {
 					if ext == ".obj" {
-						addModelToScene(selectedFilePath, getFileNameFromPath(selectedFilePath))
+						importModelAsync(selectedFilePath, getFileNameFromPath(selectedFilePath))
 					}
 				}
 			}
@@ -1822,7 +1907,7 @@ func renderAdvancedRenderingPBR() {
 	if config.EnableSheen {
 		imgui.Indent()
 		sheenColor := [3]float32{config.SheenColor.X(), config.SheenColor.Y(), config.SheenColor.Z()}
-		if imgui.ColorEdit3V("Color##sheen", &sheenColor, 0) {
+		if colorEditLinear("Color##sheen", &sheenColor) {
 			config.SheenColor = mgl.Vec3{sheenColor[0], sheenColor[1], sheenColor[2]}
 			changed = true
 		}
@@ -1871,6 +1956,50 @@ func renderAdvancedRenderingPBR() {
 	}
 }
 
+// renderFogSettings edits the scene-wide distance and height fog
+func renderFogSettings(openglRenderer *renderer.OpenGLRenderer) {
+	fog := &openglRenderer.Fog
+
+	fogModes := []renderer.FogMode{renderer.FogNone, renderer.FogExponential, renderer.FogExponentialSquared}
+	fogModeNames := []string{"None", "Exponential", "Exponential Squared"}
+	currentIdx := 0
+	for i, m := range fogModes {
+		if m == fog.Mode {
+			currentIdx = i
+			break
+		}
+	}
+	if imgui.BeginCombo("Distance Fog", fogModeNames[currentIdx]) {
+		for i, name := range fogModeNames {
+			if imgui.SelectableV(name, i == currentIdx, 0, imgui.Vec2{}) {
+				fog.Mode = fogModes[i]
+				logToConsole(fmt.Sprintf("Fog mode: %s", name), "info")
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	color := [3]float32{fog.Color[0], fog.Color[1], fog.Color[2]}
+	if imgui.ColorEdit3V("Fog Color", &color, 0) {
+		fog.Color = mgl.Vec3{color[0], color[1], color[2]}
+	}
+	if fog.Mode != renderer.FogNone {
+		imgui.SliderFloatV("Density##fog", &fog.Density, 0.0, 0.02, "%.5f", 1.0)
+	}
+
+	imgui.Checkbox("Height Fog", &fog.HeightFogEnabled)
+	if fog.HeightFogEnabled {
+		imgui.Indent()
+		imgui.SliderFloatV("Density##heightfog", &fog.HeightDensity, 0.0, 0.2, "%.4f", 1.0)
+		imgui.DragFloatV("Base Height##heightfog", &fog.HeightBase, 0.5, 0, 0, "%.1f", 0)
+		imgui.SliderFloatV("Falloff##heightfog", &fog.HeightFalloff, 0.001, 1.0, "%.3f", 1.0)
+		imgui.Unindent()
+	}
+
+	imgui.SliderFloatV("Max Opacity##fog", &fog.MaxOpacity, 0.0, 1.0, "%.2f", 1.0)
+	imgui.SliderFloatV("Sky Blend##fog", &fog.SkyBlend, 0.0, 1.0, "%.2f", 1.0)
+}
+
 func renderAdvancedRenderingLighting() {
 	if Eng == nil || Eng.GetRenderer() == nil {
 		imgui.Text("No renderer available")
@@ -1987,7 +2116,7 @@ func renderAdvancedRenderingLighting() {
 			changed = true
 		}
 		scatterColor := [3]float32{config.ScatteringColor.X(), config.ScatteringColor.Y(), config.ScatteringColor.Z()}
-		if imgui.ColorEdit3V("Color##sss", &scatterColor, 0) {
+		if colorEditLinear("Color##sss", &scatterColor) {
 			config.ScatteringColor = mgl.Vec3{scatterColor[0], scatterColor[1], scatterColor[2]}
 			changed = true
 		}
@@ -2010,6 +2139,8 @@ func renderAdvancedRenderingPostProcess() {
 		return
 	}
 
+	renderReflectionSettings(openglRenderer)
+
 	models := openglRenderer.GetModels()
 	if len(models) == 0 {
 		imgui.Text("No models to configure")
@@ -2061,6 +2192,49 @@ func renderAdvancedRenderingPostProcess() {
 	}
 }
 
+// renderReflectionSettings edits the renderer's screen-space reflections, saved with the scene
+func renderReflectionSettings(openglRenderer *renderer.OpenGLRenderer) {
+	if !imgui.CollapsingHeaderV("Screen-Space Reflections", imgui.TreeNodeFlagsDefaultOpen) {
+		return
+	}
+	ssr := &openglRenderer.SSR
+	if imgui.Checkbox("Enable Reflections", &ssr.Enabled) {
+		sceneModified = true
+		if ssr.Enabled {
+			logToConsole("Screen-space reflections enabled", "info")
+		} else {
+			logToConsole("Screen-space reflections disabled", "info")
+		}
+	}
+	if !ssr.Enabled {
+		return
+	}
+	imgui.Indent()
+	if imgui.BeginCombo("Quality##ssr", string(ssr.Quality)) {
+		for _, quality := range []renderer.SSRQuality{renderer.SSRQualityLow, renderer.SSRQualityMedium, renderer.SSRQualityHigh} {
+			if imgui.SelectableV(string(quality), ssr.Quality == quality, 0, imgui.Vec2{}) && ssr.Quality != quality {
+				ssr.Quality = quality
+				sceneModified = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.SliderFloatV("Max Distance##ssr", &ssr.MaxDistance, 1.0, 500.0, "%.0f", 1.0) {
+		sceneModified = true
+	}
+	if imgui.SliderFloatV("Thickness##ssr", &ssr.Thickness, 0.01, 5.0, "%.2f", 1.0) {
+		sceneModified = true
+	}
+	if imgui.SliderFloatV("Intensity##ssr", &ssr.Intensity, 0.0, 2.0, "%.2f", 1.0) {
+		sceneModified = true
+	}
+	if imgui.SliderFloatV("Max Roughness##ssr", &ssr.MaxRoughness, 0.05, 1.0, "%.2f", 1.0) {
+		sceneModified = true
+	}
+	imgui.Text("Reflects opaque default-shaded models; misses show the sky.")
+	imgui.Unindent()
+}
+
 func renderAdvancedRenderingPerformance() {
 	if Eng == nil || Eng.GetRenderer() == nil {
 		imgui.Text("No renderer available")
@@ -2072,6 +2246,21 @@ func renderAdvancedRenderingPerformance() {
 		return
 	}
 
+	if imgui.CollapsingHeaderV("Render Path", imgui.TreeNodeFlagsDefaultOpen) {
+		if imgui.BeginCombo("Path", openglRenderer.RenderPath.String()) {
+			for _, path := range renderer.RenderPaths {
+				if imgui.SelectableV(path.String(), openglRenderer.RenderPath == path, 0, imgui.Vec2{}) && openglRenderer.RenderPath != path {
+					openglRenderer.RenderPath = path
+					sceneModified = true
+					logToConsole(fmt.Sprintf("Render path: %s", path), "info")
+				}
+			}
+			imgui.EndCombo()
+		}
+		imgui.Text("Deferred lights opaque models with every scene light.")
+		imgui.Text("Transparent, water and custom-material models stay forward.")
+	}
+
 	models := openglRenderer.GetModels()
 	if len(models) == 0 {
 		imgui.Text("No models to configure")
@@ -2156,46 +2345,26 @@ func getAdvancedConfigFromModel(model *renderer.Model) renderer.AdvancedRenderin
 		return config
 	}
 
-	// Extract values from custom uniforms
-	if val, ok := model.CustomUniforms["enableClearcoat"].(bool); ok {
-		config.EnableClearcoat = val
-	}
+	// Feature toggles live in the shader variant, tunables in custom uniforms
+	config.SetShaderFeatures(model.ShaderFeatures)
 	if val, ok := model.CustomUniforms["clearcoatRoughness"].(float32); ok {
 		config.ClearcoatRoughness = val
 	}
 	if val, ok := model.CustomUniforms["clearcoatIntensity"].(float32); ok {
 		config.ClearcoatIntensity = val
 	}
-	if val, ok := model.CustomUniforms["enableSheen"].(bool); ok {
-		config.EnableSheen = val
-	}
 	if val, ok := model.CustomUniforms["sheenColor"].(mgl.Vec3); ok {
 		config.SheenColor = val
 	}
 	if val, ok := model.CustomUniforms["sheenRoughness"].(float32); ok {
 		config.SheenRoughness = val
 	}
-	if val, ok := model.CustomUniforms["enableTransmission"].(bool); ok {
-		config.EnableTransmission = val
-	}
 	if val, ok := model.CustomUniforms["transmissionFactor"].(float32); ok {
 		config.TransmissionFactor = val
 	}
-	if val, ok := model.CustomUniforms["enableMultipleScattering"].(bool); ok {
-		config.EnableMultipleScattering = val
-	}
-	if val, ok := model.CustomUniforms["enableEnergyConservation"].(bool); ok {
-		config.EnableEnergyConservation = val
-	}
-	if val, ok := model.CustomUniforms["enableImageBasedLighting"].(bool); ok {
-		config.EnableImageBasedLighting = val
-	}
 	if val, ok := model.CustomUniforms["iblIntensity"].(float32); ok {
 		config.IBLIntensity = val
 	}
-	if val, ok := model.CustomUniforms["enableSSAO"].(bool); ok {
-		config.EnableSSAO = val
-	}
 	if val, ok := model.CustomUniforms["ssaoIntensity"].(float32); ok {
 		config.SSAOIntensity = val
 	}
@@ -2208,9 +2377,6 @@ func getAdvancedConfigFromModel(model *renderer.Model) renderer.AdvancedRenderin
 	if val, ok := model.CustomUniforms["ssaoSampleCount"].(int32); ok {
 		config.SSAOSampleCount = int(val)
 	}
-	if val, ok := model.CustomUniforms["enableVolumetricLighting"].(bool); ok {
-		config.EnableVolumetricLighting = val
-	}
 	if val, ok := model.CustomUniforms["volumetricIntensity"].(float32); ok {
 		config.VolumetricIntensity = val
 	}
@@ -2220,18 +2386,12 @@ func getAdvancedConfigFromModel(model *renderer.Model) renderer.AdvancedRenderin
 	if val, ok := model.CustomUniforms["volumetricScattering"].(float32); ok {
 		config.VolumetricScattering = val
 	}
-	if val, ok := model.CustomUniforms["enableGlobalIllumination"].(bool); ok {
-		config.EnableGlobalIllumination = val
-	}
 	if val, ok := model.CustomUniforms["giIntensity"].(float32); ok {
 		config.GIIntensity = val
 	}
 	if val, ok := model.CustomUniforms["giBounces"].(int32); ok {
 		config.GIBounces = int(val)
 	}
-	if val, ok := model.CustomUniforms["enableBloom"].(bool); ok {
-		config.EnableBloom = val
-	}
 	if val, ok := model.CustomUniforms["bloomThreshold"].(float32); ok {
 		config.BloomThreshold = val
 	}
@@ -2241,9 +2401,6 @@ func getAdvancedConfigFromModel(model *renderer.Model) renderer.AdvancedRenderin
 	if val, ok := model.CustomUniforms["bloomRadius"].(float32); ok {
 		config.BloomRadius = val
 	}
-	if val, ok := model.CustomUniforms["enablePerlinNoise"].(bool); ok {
-		config.EnablePerlinNoise = val
-	}
 	if val, ok := model.CustomUniforms["noiseScale"].(float32); ok {
 		config.NoiseScale = val
 	}
@@ -2253,9 +2410,6 @@ func getAdvancedConfigFromModel(model *renderer.Model) renderer.AdvancedRenderin
 	if val, ok := model.CustomUniforms["noiseIntensity"].(float32); ok {
 		config.NoiseIntensity = val
 	}
-	if val, ok := model.CustomUniforms["enableShadows"].(bool); ok {
-		config.EnableAdvancedShadows = val
-	}
 	if val, ok := model.CustomUniforms["shadowIntensity"].(float32); ok {
 		config.ShadowIntensity = val
 	}
@@ -2274,9 +2428,6 @@ func getAdvancedConfigFromModel(model *renderer.Model) renderer.AdvancedRenderin
 	if val, ok := model.CustomUniforms["scatteringColor"].(mgl.Vec3); ok {
 		config.ScatteringColor = val
 	}
-	if val, ok := model.CustomUniforms["enableHighQualityFiltering"].(bool); ok {
-		config.EnableHighQualityFiltering = val
-	}
 	if val, ok := model.CustomUniforms["filteringQuality"].(int32); ok {
 		config.FilteringQuality = int(val)
 	}
@@ -2327,6 +2478,11 @@ func applyRenderingPreset(presetName string) {
 	}
 
 	applyAdvancedConfigToAllModels(config)
+	if Eng != nil {
+		if openglRenderer, ok := Eng.GetRenderer().(*renderer.OpenGLRenderer); ok {
+			openglRenderer.SSR = config.SSRSettings()
+		}
+	}
 	globalAdvancedRenderingEnabled = true
 }
 
@@ -2343,6 +2499,8 @@ func renderComponentInspector(comp behaviour.Component, obj *behaviour.GameObjec
 		renderLightComponentInspector(c)
 	case *behaviour.CameraComponent:
 		renderCameraComponentInspector(c)
+	case *behaviour.TextComponent:
+		renderTextComponentInspector(c)
 	case *behaviour.ScriptComponent:
 		imgui.Text("Script: " + c.ScriptName)
 	default:
@@ -2367,13 +2525,13 @@ func renderMeshComponentInspector(c *behaviour.MeshComponent) {
 	imgui.Text("Material:")
 
 	diffuse := c.DiffuseColor
-	if imgui.ColorEdit3V("Diffuse", &diffuse, 0) {
+	if colorEditLinear("Diffuse", &diffuse) {
 		c.DiffuseColor = diffuse
 		applyMeshMaterial(c)
 	}
 
 	specular := c.SpecularColor
-	if imgui.ColorEdit3V("Specular", &specular, 0) {
+	if colorEditLinear("Specular", &specular) {
 		c.SpecularColor = specular
 		applyMeshMaterial(c)
 	}
@@ -2490,6 +2648,9 @@ func renderWaterComponentInspector(c *behaviour.WaterComponent) {
 	if imgui.SliderFloatV("Distortion", &c.DistortionStrength, 0, 0.5, "%.2f", 0) {
 		changed = true
 	}
+	if imgui.SliderFloatV("Reflection Resolution", &c.ReflectionResolution, 0, 1, "%.2f (0 = sky only)", 0) {
+		changed = true
+	}
 
 	// Real-time sync to simulation for all properties except size
 	if changed && c.Generated {
@@ -2531,27 +2692,27 @@ func renderVoxelComponentInspector(c *behaviour.VoxelTerrainComponent, obj *beha
 	imgui.Separator()
 	imgui.Text("Colors:")
 	grass := c.GrassColor
-	if imgui.ColorEdit3V("Grass", &grass, 0) {
+	if colorEditLinear("Grass", &grass) {
 		c.GrassColor = grass
 	}
 	dirt := c.DirtColor
-	if imgui.ColorEdit3V("Dirt", &dirt, 0) {
+	if colorEditLinear("Dirt", &dirt) {
 		c.DirtColor = dirt
 	}
 	stone := c.StoneColor
-	if imgui.ColorEdit3V("Stone", &stone, 0) {
+	if colorEditLinear("Stone", &stone) {
 		c.StoneColor = stone
 	}
 	sand := c.SandColor
-	if imgui.ColorEdit3V("Sand", &sand, 0) {
+	if colorEditLinear("Sand", &sand) {
 		c.SandColor = sand
 	}
 	wood := c.WoodColor
-	if imgui.ColorEdit3V("Wood (Trunk)", &wood, 0) {
+	if colorEditLinear("Wood (Trunk)", &wood) {
 		c.WoodColor = wood
 	}
 	leaves := c.LeavesColor
-	if imgui.ColorEdit3V("Leaves", &leaves, 0) {
+	if colorEditLinear("Leaves", &leaves) {
 		c.LeavesColor = leaves
 	}
 
@@ -2568,6 +2729,49 @@ func renderVoxelComponentInspector(c *behaviour.VoxelTerrainComponent, obj *beha
 	}
 }
 
+// renderLightIESProfile picks the photometric profile of a point or spot light.
+// The profile is aimed along the light's Direction.
+func renderLightIESProfile(light *renderer.Light) {
+	imgui.Separator()
+	imgui.Text("IES Profile")
+	if light.IES == nil {
+		imgui.Text("None (uniform)")
+	} else {
+		imgui.Text(filepath.Base(light.IES.Path))
+		imgui.Text(fmt.Sprintf("Peak %.0f cd, %d x %d angles", light.IES.MaxCandela,
+			len(light.IES.VerticalAngles), len(light.IES.HorizontalAngles)))
+	}
+
+	if imgui.Button("Load IES...") {
+		startDir := "../resources"
+		if CurrentProject != nil {
+			startDir = filepath.Join(CurrentProject.Path, "resources")
+		}
+		filename, err := dialog.File().
+			SetStartDir(startDir).
+			Filter("IES Profiles", "ies").
+			Title("Load IES Profile").
+			Load()
+		if err == nil && filename != "" {
+			profile, err := renderer.LoadIESProfile(filename)
+			if err != nil {
+				logToConsole(fmt.Sprintf("Failed to load IES profile: %v", err), "error")
+			} else {
+				light.IES = profile
+				sceneModified = true
+				logToConsole(fmt.Sprintf("Applied IES profile %s to %s", filepath.Base(filename), light.Name), "info")
+			}
+		}
+	}
+	if light.IES != nil {
+		imgui.SameLine()
+		if imgui.Button("Clear##ies") {
+			light.IES = nil
+			sceneModified = true
+		}
+	}
+}
+
 func renderLightComponentInspector(c *behaviour.LightComponent) {
 	lightModes := []string{"directional", "point", "spot"}
 	currentIdx := 0
@@ -2587,7 +2791,7 @@ func renderLightComponentInspector(c *behaviour.LightComponent) {
 	}
 
 	color := c.Color
-	if imgui.ColorEdit3V("Color", &color, 0) {
+	if colorEditLinear("Color", &color) {
 		c.Color = color
 	}
 
@@ -2604,6 +2808,8 @@ func renderCameraComponentInspector(c *behaviour.CameraComponent) {
 	imgui.DragFloatV("Near", &c.Near, 0.01, 0.01, 100, "%.2f", 0)
 	imgui.DragFloatV("Far", &c.Far, 10, 100, 100000, "%.0f", 0)
 	imgui.Checkbox("Main Camera", &c.IsMain)
+	renderCameraComponentMode(c)
+	renderCameraComponentLens(c)
 }
 
 // Helper functions for hierarchy display
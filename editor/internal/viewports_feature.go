@@ -0,0 +1,148 @@
+package editor
+
+import (
+	"Gopher3D/internal/renderer"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// Editor view layouts
+const (
+	viewLayoutSingle = iota // Editor camera only
+	viewLayoutGame          // Scene cameras as the game will show them
+	viewLayoutQuad          // Editor camera with top, front and right views
+)
+
+var viewLayoutNames = []string{"Editor Camera", "Game Views", "Quad View"}
+
+var (
+	editorViewLayout = viewLayoutSingle
+	quadViewCameras  []*renderer.Camera
+)
+
+// quadViewDistance is how far the axis views sit from the editor camera's focus
+const quadViewDistance = 150
+
+// gameViews returns the cameras a game draws: the active camera fills the window
+// unless it has its own viewport, and every other camera with a viewport is drawn on top
+func gameViews(cameras []*renderer.Camera) []*renderer.Camera {
+	var views []*renderer.Camera
+	for _, cam := range cameras {
+		if cam.IsActive || !cam.Viewport.IsFull() {
+			cam.SetRotation(cam.Yaw, cam.Pitch)
+			views = append(views, cam)
+		}
+	}
+	return views
+}
+
+// updateEditorViews points the engine at the cameras of the current view layout
+func updateEditorViews() {
+	switch editorViewLayout {
+	case viewLayoutGame:
+		Eng.Views = gameViews(SceneCameras)
+	case viewLayoutQuad:
+		Eng.Views = updateQuadViews()
+	default:
+		Eng.Views = nil
+	}
+}
+
+// updateQuadViews keeps the axis views looking at the point in front of the editor camera
+func updateQuadViews() []*renderer.Camera {
+	if Eng.Camera == nil {
+		return nil
+	}
+	if quadViewCameras == nil {
+		rects := renderer.SplitViewports(4)
+		quadViewCameras = []*renderer.Camera{Eng.Camera}
+		for i, name := range []string{"Top", "Front", "Right"} {
+			cam := renderer.NewDefaultCamera(Eng.Width, Eng.Height)
+			cam.Name = name
+			cam.Viewport = rects[i+1]
+			quadViewCameras = append(quadViewCameras, cam)
+		}
+	}
+	quadViewCameras[0] = Eng.Camera
+	Eng.Camera.Viewport = renderer.SplitViewports(4)[0]
+
+	focus := Eng.Camera.Position.Add(Eng.Camera.Front.Mul(quadViewDistance))
+	axes := []struct {
+		offset     mgl.Vec3
+		yaw, pitch float32
+	}{
+		{mgl.Vec3{0, quadViewDistance, 0}, -90, -89.9}, // Top, looking down
+		{mgl.Vec3{0, 0, quadViewDistance}, -90, 0},     // Front, looking along -Z
+		{mgl.Vec3{quadViewDistance, 0, 0}, 180, 0},     // Right, looking along -X
+	}
+	for i, axis := range axes {
+		cam := quadViewCameras[i+1]
+		cam.Position = focus.Add(axis.offset)
+		cam.Near, cam.Far = Eng.Camera.Near, Eng.Camera.Far
+		cam.SetRotation(axis.yaw, axis.pitch)
+	}
+	return quadViewCameras
+}
+
+// setEditorViewLayout switches layouts, giving the editor camera the whole window back
+func setEditorViewLayout(layout int) {
+	editorViewLayout = layout
+	if Eng.Camera != nil && layout != viewLayoutQuad {
+		Eng.Camera.Viewport = renderer.ViewportRect{}
+	}
+	updateEditorViews()
+}
+
+// viewportPresets are the quick layouts offered in the camera inspector
+var viewportPresets = []struct {
+	name string
+	rect renderer.ViewportRect
+}{
+	{"Full", renderer.FullViewport},
+	{"Left", renderer.ViewportRect{X: 0, Y: 0, Width: 0.5, Height: 1}},
+	{"Right", renderer.ViewportRect{X: 0.5, Y: 0, Width: 0.5, Height: 1}},
+	{"Top", renderer.ViewportRect{X: 0, Y: 0, Width: 1, Height: 0.5}},
+	{"Bottom", renderer.ViewportRect{X: 0, Y: 0.5, Width: 1, Height: 0.5}},
+	{"Minimap", renderer.ViewportRect{X: 0.74, Y: 0.02, Width: 0.24, Height: 0.24}},
+}
+
+// renderCameraViewport edits where a scene camera draws in the game window
+func renderCameraViewport(cam *renderer.Camera) {
+	if !imgui.CollapsingHeaderV("Viewport", 0) {
+		return
+	}
+	for i, preset := range viewportPresets {
+		if i > 0 {
+			imgui.SameLine()
+		}
+		if imgui.Button(preset.name) {
+			cam.Viewport = preset.rect
+		}
+	}
+
+	rect := cam.Viewport
+	if rect.Width <= 0 || rect.Height <= 0 {
+		rect = renderer.FullViewport
+	}
+	w := imgui.ContentRegionAvail().X
+	imgui.PushItemWidth(w / 2.1)
+	changed := imgui.DragFloatV("##vpX", &rect.X, 0.005, 0, 1, "X: %.3f", 0)
+	imgui.SameLine()
+	changed = imgui.DragFloatV("##vpY", &rect.Y, 0.005, 0, 1, "Y: %.3f", 0) || changed
+	changed = imgui.DragFloatV("##vpW", &rect.Width, 0.005, 0.01, 1, "Width: %.3f", 0) || changed
+	imgui.SameLine()
+	changed = imgui.DragFloatV("##vpH", &rect.Height, 0.005, 0.01, 1, "Height: %.3f", 0) || changed
+	imgui.PopItemWidth()
+	if changed {
+		cam.Viewport = rect
+	}
+
+	depth := int32(cam.Depth)
+	if imgui.InputInt("Depth", &depth) {
+		cam.Depth = int(depth)
+	}
+	imgui.PushTextWrapPos()
+	imgui.Text("Higher depths draw on top. Cameras with a viewport are drawn alongside the active camera in the game.")
+	imgui.PopTextWrapPos()
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/inkyblackness/imgui-go/v4"
+	"github.com/sqweek/dialog"
+	"path/filepath"
 	"time"
 )
 
@@ -27,8 +29,15 @@ var (
 	voxelColorSand   = [3]float32{0.9, 0.8, 0.5}
 	voxelColorWood   = [3]float32{0.4, 0.25, 0.1}
 	voxelColorLeaves = [3]float32{0.2, 0.6, 0.2}
+
+	// Top, side and bottom texture per tile, indexed by voxel ID - 1
+	voxelBlockTextures [6][3]string
+	// Color space of voxelBlockTextures; scenes saved before color spaces keep them linear
+	voxelBlockTextureColorSpace renderer.TextureColorSpace
 )
 
+var voxelBlockNames = [6]string{"Grass", "Dirt", "Stone", "Sand", "Wood", "Leaves"}
+
 type VoxelConfig struct {
 	// Legacy fields
 	Scale       float32    `json:"scale"`
@@ -47,6 +56,9 @@ type VoxelConfig struct {
 	ColorWood   [3]float32 `json:"color_wood"`
 	ColorLeaves [3]float32 `json:"color_leaves"`
 
+	BlockTextures          [6][3]string               `json:"block_textures,omitzero"`
+	BlockTextureColorSpace renderer.TextureColorSpace `json:"block_texture_color_space,omitempty"`
+
 	// New component-based fields
 	WorldSizeX  int     `json:"world_size_x,omitempty"`
 	WorldSizeY  int     `json:"world_size_y,omitempty"`
@@ -124,12 +136,12 @@ func renderAddVoxelDialog() {
 		imgui.Spacing()
 
 		if imgui.CollapsingHeaderV("Tile Colors", imgui.TreeNodeFlagsNone) {
-			imgui.ColorEdit3V("Grass", &voxelColorGrass, 0)
-			imgui.ColorEdit3V("Dirt", &voxelColorDirt, 0)
-			imgui.ColorEdit3V("Stone", &voxelColorStone, 0)
-			imgui.ColorEdit3V("Sand", &voxelColorSand, 0)
-			imgui.ColorEdit3V("Wood (Trunk)", &voxelColorWood, 0)
-			imgui.ColorEdit3V("Leaves", &voxelColorLeaves, 0)
+			colorEditLinear("Grass", &voxelColorGrass)
+			colorEditLinear("Dirt", &voxelColorDirt)
+			colorEditLinear("Stone", &voxelColorStone)
+			colorEditLinear("Sand", &voxelColorSand)
+			colorEditLinear("Wood (Trunk)", &voxelColorWood)
+			colorEditLinear("Leaves", &voxelColorLeaves)
 
 			imgui.Spacing()
 			if imgui.Button("Reset Colors") {
@@ -141,11 +153,10 @@ func renderAddVoxelDialog() {
 				voxelColorLeaves = [3]float32{0.2, 0.6, 0.2}
 			}
 
-			imgui.Spacing()
-			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 0.6, Y: 0.6, Z: 0.6, W: 1})
-			imgui.Text("Note: Texture support planned.")
-			imgui.Text("Currently only solid colors.")
-			imgui.PopStyleColor()
+		}
+
+		if imgui.CollapsingHeaderV("Tile Textures", imgui.TreeNodeFlagsNone) {
+			renderVoxelBlockTextures()
 		}
 
 		imgui.Spacing()
@@ -189,6 +200,8 @@ func createVoxelTerrainGameObject() *behaviour.GameObject {
 	voxelComp.SandColor = voxelColorSand
 	voxelComp.WoodColor = voxelColorWood
 	voxelComp.LeavesColor = voxelColorLeaves
+	voxelComp.BlockTextures = voxelBlockTextures
+	voxelComp.BlockTextureColorSpace = string(voxelBlockTextureColorSpace)
 
 	// Create GameObject
 	obj := behaviour.NewGameObject(fmt.Sprintf("Voxel Terrain (%dx%d)", voxelWorldSize, voxelWorldSize))
@@ -225,6 +238,7 @@ func generateVoxelTerrainFromComponent(comp *behaviour.VoxelTerrainComponent) *r
 	loader.SetVoxelColor(4, mgl32.Vec3{comp.SandColor[0], comp.SandColor[1], comp.SandColor[2]})
 	loader.SetVoxelColor(5, mgl32.Vec3{comp.WoodColor[0], comp.WoodColor[1], comp.WoodColor[2]})
 	loader.SetVoxelColor(6, mgl32.Vec3{comp.LeavesColor[0], comp.LeavesColor[1], comp.LeavesColor[2]})
+	registerVoxelBlocks(comp.BlockTextures, renderer.TextureColorSpace(comp.BlockTextureColorSpace))
 
 	noise := renderer.NewImprovedPerlinNoise(int64(comp.Seed))
 	world := loader.NewVoxelWorld(chunkSize, worldSize, worldSize, 64, voxelSizeVal, loader.CreateCubeGeometry(voxelSizeVal), loader.InstancedMode)
@@ -385,6 +399,9 @@ func generateVoxelTerrainFromComponent(comp *behaviour.VoxelTerrainComponent) *r
 		ColorSand:   comp.SandColor,
 		ColorWood:   comp.WoodColor,
 		ColorLeaves: comp.LeavesColor,
+
+		BlockTextures:          comp.BlockTextures,
+		BlockTextureColorSpace: renderer.TextureColorSpace(comp.BlockTextureColorSpace),
 	}
 
 	return model
@@ -435,6 +452,7 @@ func generateVoxelTerrain() *renderer.Model {
 	loader.SetVoxelColor(4, mgl32.Vec3{voxelColorSand[0], voxelColorSand[1], voxelColorSand[2]})
 	loader.SetVoxelColor(5, mgl32.Vec3{voxelColorWood[0], voxelColorWood[1], voxelColorWood[2]})
 	loader.SetVoxelColor(6, mgl32.Vec3{voxelColorLeaves[0], voxelColorLeaves[1], voxelColorLeaves[2]})
+	registerVoxelBlocks(voxelBlockTextures, voxelBlockTextureColorSpace)
 
 	noise := renderer.NewImprovedPerlinNoise(int64(voxelSeed))
 	world := loader.NewVoxelWorld(chunkSize, worldSize, worldSize, 64, voxelSizeVal, loader.CreateCubeGeometry(voxelSizeVal), loader.InstancedMode)
@@ -597,6 +615,9 @@ func generateVoxelTerrain() *renderer.Model {
 		ColorSand:   voxelColorSand,
 		ColorWood:   voxelColorWood,
 		ColorLeaves: voxelColorLeaves,
+
+		BlockTextures:          voxelBlockTextures,
+		BlockTextureColorSpace: voxelBlockTextureColorSpace,
 	}
 
 	// Ensure material has correct exposure and lighting properties (fixes dark voxels after deletion)
@@ -743,6 +764,8 @@ func regenerateVoxelTerrain(config VoxelConfig) *renderer.Model {
 	voxelWorldSize = config.WorldSize
 	voxelBiome = config.Biome
 	voxelTreeDensity = config.TreeDensity
+	voxelBlockTextures = config.BlockTextures
+	voxelBlockTextureColorSpace = config.BlockTextureColorSpace
 
 	biomeNames := []string{"Plains", "Mountains", "Desert", "Islands", "Caves"}
 	biomeName := "Unknown"
@@ -810,6 +833,8 @@ func generateVoxelTerrainNew(config *VoxelConfig, name string) *renderer.Model {
 		seed = voxelSeed
 	}
 
+	registerVoxelBlocks(config.BlockTextures, config.BlockTextureColorSpace)
+
 	// Create voxel world using the existing API
 	geometry := loader.CreateCubeGeometry(voxelSize)
 	world := loader.NewVoxelWorld(chunkSize, worldSizeX, worldSizeZ, maxHeight, voxelSize, geometry, loader.InstancedMode)
@@ -860,3 +885,66 @@ func generateVoxelTerrainNew(config *VoxelConfig, name string) *renderer.Model {
 
 	return model
 }
+
+// renderVoxelBlockTextures edits the per-face textures of each tile type
+func renderVoxelBlockTextures() {
+	faces := [3]string{"Top", "Side", "Bottom"}
+	for i, name := range voxelBlockNames {
+		if !imgui.TreeNode(name + "##blocktex") {
+			continue
+		}
+		for f, face := range faces {
+			path := &voxelBlockTextures[i][f]
+			label := "(color only)"
+			if *path != "" {
+				label = filepath.Base(*path)
+			}
+			imgui.Text(fmt.Sprintf("%s: %s", face, label))
+			imgui.SameLine()
+			if imgui.Button(fmt.Sprintf("...##blocktex_%d_%d", i, f)) {
+				filename, err := dialog.File().
+					Filter("Images", "png", "jpg", "jpeg").
+					Title(fmt.Sprintf("Select %s %s Texture", name, face)).
+					Load()
+				if err == nil && filename != "" {
+					*path = filename
+				}
+			}
+			if *path != "" {
+				imgui.SameLine()
+				if imgui.Button(fmt.Sprintf("X##blocktex_clear_%d_%d", i, f)) {
+					*path = ""
+				}
+			}
+		}
+		imgui.TreePop()
+	}
+
+	imgui.Spacing()
+	imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 0.6, Y: 0.6, Z: 0.6, W: 1})
+	imgui.Text("Textured tiles ignore their tile color.")
+	imgui.PopStyleColor()
+}
+
+// registerVoxelBlocks rebuilds the block registry for the built-in tile types
+func registerVoxelBlocks(textures [6][3]string, colorSpace renderer.TextureColorSpace) {
+	loader.Blocks.Clear()
+	loader.Blocks.ColorSpace = colorSpace
+	for i, name := range voxelBlockNames {
+		block := loader.BlockType{
+			ID:     loader.VoxelID(i + 1),
+			Name:   name,
+			Top:    textures[i][0],
+			Side:   textures[i][1],
+			Bottom: textures[i][2],
+		}
+		if err := loader.Blocks.Register(block); err != nil {
+			logToConsole(fmt.Sprintf("Failed to register voxel block %s: %v", name, err), "error")
+			continue
+		}
+		if block.Top != "" || block.Side != "" || block.Bottom != "" {
+			// Show the texture as authored instead of tinting it with the tile color
+			loader.SetVoxelColor(block.ID, mgl32.Vec3{1, 1, 1})
+		}
+	}
+}
@@ -55,6 +55,7 @@ func createWaterGameObject() *behaviour.GameObject {
 	ws.SpecularIntensity = waterComp.SpecularIntensity
 	ws.NormalStrength = waterComp.NormalStrength
 	ws.DistortionStrength = waterComp.DistortionStrength
+	ws.ReflectionResolution = waterComp.ReflectionResolution
 	ws.ShadowStrength = waterComp.ShadowStrength
 
 	// Set sky color from editor
@@ -81,6 +82,9 @@ func createWaterGameObject() *behaviour.GameObject {
 	// Add to behaviour manager for updates
 	behaviour.GlobalBehaviourManager.Add(ws)
 	activeWaterSim = ws
+	if activeTimeOfDay != nil {
+		activeTimeOfDay.AddSkyColorTarget(ws)
+	}
 
 	// Register the GameObject
 	behaviour.GlobalComponentManager.RegisterGameObject(obj)
@@ -114,6 +118,7 @@ func SyncWaterComponentToSimulation(comp *behaviour.WaterComponent) {
 	ws.SpecularIntensity = comp.SpecularIntensity
 	ws.NormalStrength = comp.NormalStrength
 	ws.DistortionStrength = comp.DistortionStrength
+	ws.ReflectionResolution = comp.ReflectionResolution
 	ws.ShadowStrength = comp.ShadowStrength
 
 	// Apply changes immediately
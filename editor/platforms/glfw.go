@@ -26,6 +26,7 @@ type GLFW struct {
 
 	time             float64
 	mouseJustPressed [3]bool
+	previousScroll   glfw.ScrollCallback // Callback installed before ImGui's, fed scrolls ImGui doesn't use
 }
 
 // NewGLFW attempts to initialize a GLFW context.
@@ -171,7 +172,7 @@ func (platform *GLFW) setKeyMapping() {
 
 func (platform *GLFW) installCallbacks() {
 	platform.window.SetMouseButtonCallback(platform.mouseButtonChange)
-	platform.window.SetScrollCallback(platform.mouseScrollChange)
+	platform.previousScroll = platform.window.SetScrollCallback(platform.mouseScrollChange)
 	platform.window.SetKeyCallback(platform.keyChange)
 	platform.window.SetCharCallback(platform.charChange)
 }
@@ -198,6 +199,9 @@ func (platform *GLFW) mouseButtonChange(window *glfw.Window, rawButton glfw.Mous
 
 func (platform *GLFW) mouseScrollChange(window *glfw.Window, x, y float64) {
 	platform.imguiIO.AddMouseWheelDelta(float32(x), float32(y))
+	if platform.previousScroll != nil && !platform.imguiIO.WantCaptureMouse() {
+		platform.previousScroll(window, x, y)
+	}
 }
 
 func (platform *GLFW) keyChange(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
@@ -16,6 +16,7 @@ require (
 	github.com/vulkan-go/vulkan v0.0.0-20221209234627-c0a353ae26c8
 	github.com/xlab/linmath v0.0.0-20220922225318-40b6290c3b40
 	go.uber.org/zap v1.17.0
+	golang.org/x/image v0.24.0
 )
 
 require (
@@ -23,6 +24,6 @@ require (
 	github.com/stretchr/testify v1.11.1 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	golang.org/x/image v0.24.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
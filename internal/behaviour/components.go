@@ -18,6 +18,7 @@ const (
 	ComponentTypeCamera    ComponentType = "Camera"
 	ComponentTypeWater     ComponentType = "Water"
 	ComponentTypeVoxel     ComponentType = "Voxel"
+	ComponentTypeText      ComponentType = "Text"
 	ComponentTypeCustom    ComponentType = "Custom"
 )
 
@@ -81,22 +82,23 @@ func (m *MeshComponent) GetMesh() interface{} {
 // WaterComponent holds water simulation data
 type WaterComponent struct {
 	BaseComponent
-	OceanSize           float32    `json:"ocean_size"`
-	BaseAmplitude       float32    `json:"base_amplitude"`
-	WaterColor          [3]float32 `json:"water_color"`
-	Transparency        float32    `json:"transparency"`
-	WaveSpeedMultiplier float32    `json:"wave_speed_multiplier"`
-	WaveHeight          float32    `json:"wave_height"`     // Wave amplitude multiplier
-	WaveRandomness      float32    `json:"wave_randomness"` // Adds randomness/choppiness to waves
-	FoamEnabled         bool       `json:"foam_enabled"`
-	FoamIntensity       float32    `json:"foam_intensity"`
-	CausticsEnabled     bool       `json:"caustics_enabled"`
-	CausticsIntensity   float32    `json:"caustics_intensity"`
-	CausticsScale       float32    `json:"caustics_scale"`
-	SpecularIntensity   float32    `json:"specular_intensity"`
-	NormalStrength      float32    `json:"normal_strength"`
-	DistortionStrength  float32    `json:"distortion_strength"`
-	ShadowStrength      float32    `json:"shadow_strength"`
+	OceanSize            float32    `json:"ocean_size"`
+	BaseAmplitude        float32    `json:"base_amplitude"`
+	WaterColor           [3]float32 `json:"water_color"`
+	Transparency         float32    `json:"transparency"`
+	WaveSpeedMultiplier  float32    `json:"wave_speed_multiplier"`
+	WaveHeight           float32    `json:"wave_height"`     // Wave amplitude multiplier
+	WaveRandomness       float32    `json:"wave_randomness"` // Adds randomness/choppiness to waves
+	FoamEnabled          bool       `json:"foam_enabled"`
+	FoamIntensity        float32    `json:"foam_intensity"`
+	CausticsEnabled      bool       `json:"caustics_enabled"`
+	CausticsIntensity    float32    `json:"caustics_intensity"`
+	CausticsScale        float32    `json:"caustics_scale"`
+	SpecularIntensity    float32    `json:"specular_intensity"`
+	NormalStrength       float32    `json:"normal_strength"`
+	DistortionStrength   float32    `json:"distortion_strength"`
+	ReflectionResolution float32    `json:"reflection_resolution,omitempty"` // Planar reflection size as a fraction of the view; 0 turns it off
+	ShadowStrength       float32    `json:"shadow_strength"`
 
 	// Runtime references
 	Simulation interface{} `json:"-"` // The water simulation behaviour
@@ -106,23 +108,24 @@ type WaterComponent struct {
 
 func NewWaterComponent() *WaterComponent {
 	return &WaterComponent{
-		OceanSize:           1000,
-		BaseAmplitude:       2.0,
-		WaterColor:          [3]float32{0.0, 0.3, 0.5},
-		Transparency:        0.7,
-		WaveSpeedMultiplier: 1.0,
-		WaveHeight:          1.0,
-		WaveRandomness:      0.0,
-		FoamEnabled:         true,
-		FoamIntensity:       0.5,
-		CausticsEnabled:     true,
-		CausticsIntensity:   0.3,
-		CausticsScale:       1.0,
-		SpecularIntensity:   1.0,
-		NormalStrength:      1.0,
-		DistortionStrength:  0.1,
-		ShadowStrength:      0.5,
-		Generated:           false,
+		OceanSize:            1000,
+		BaseAmplitude:        2.0,
+		WaterColor:           [3]float32{0.0, 0.3, 0.5},
+		Transparency:         0.7,
+		WaveSpeedMultiplier:  1.0,
+		WaveHeight:           1.0,
+		WaveRandomness:       0.0,
+		FoamEnabled:          true,
+		FoamIntensity:        0.5,
+		CausticsEnabled:      true,
+		CausticsIntensity:    0.3,
+		CausticsScale:        1.0,
+		SpecularIntensity:    1.0,
+		NormalStrength:       1.0,
+		DistortionStrength:   0.1,
+		ReflectionResolution: 0.5,
+		ShadowStrength:       0.5,
+		Generated:            false,
 	}
 }
 
@@ -157,6 +160,11 @@ type VoxelTerrainComponent struct {
 	WoodColor   [3]float32 `json:"wood_color"`
 	LeavesColor [3]float32 `json:"leaves_color"`
 
+	// Top, side and bottom texture per block type, indexed by voxel ID - 1
+	BlockTextures [6][3]string `json:"block_textures,omitzero"`
+	// Color space of the block textures; empty is sRGB
+	BlockTextureColorSpace string `json:"block_texture_color_space,omitempty"`
+
 	// Runtime references
 	VoxelWorld interface{} `json:"-"` // The actual voxel world
 	Model      interface{} `json:"-"` // The rendered model
@@ -233,16 +241,43 @@ type CameraComponent struct {
 	Far    float32
 	IsMain bool // Is this the main game camera?
 
+	// Projection and controller, by the names scenes save them under
+	Projection string     // "perspective" or "orthographic"
+	OrthoSize  float32    // Half the visible height in world units when orthographic
+	Controller string     // "fly", "orbit", "follow" or "rts"
+	Target     [3]float32 // Orbit target, RTS focus point, or offset from the followed model
+	Follow     string     // Model the follow controller trails
+	Distance   float32    // Orbit and follow arm length, RTS height
+	Yaw        float32    // Controller view direction in degrees
+	Pitch      float32
+	Stiffness  float32 // How fast the follow camera catches up; 0 snaps
+
+	// Lens effects, by the names scenes save them under
+	DepthOfField  bool
+	FocusDistance float32 // Distance to the sharp plane in world units
+	FStop         float32 // Aperture; lower blurs more
+	BokehBlades   int     // Aperture blades; below 3 is round
+	MotionBlur    bool
+	Shutter       float32 // Fraction of the frame the shutter stays open; 0.5 is a 180° shutter
+
 	// Runtime reference
 	CameraData interface{}
 }
 
 func NewCameraComponent() *CameraComponent {
 	return &CameraComponent{
-		FOV:    45.0,
-		Near:   0.1,
-		Far:    10000.0,
-		IsMain: false,
+		FOV:        45.0,
+		Near:       0.1,
+		Far:        10000.0,
+		IsMain:     false,
+		Projection: "perspective",
+		OrthoSize:  10,
+		Controller: "fly",
+
+		FocusDistance: 10.0,
+		FStop:         2.8,
+		BokehBlades:   6,
+		Shutter:       0.5,
 	}
 }
 
@@ -254,6 +289,46 @@ func (c *CameraComponent) GetTypeName() string {
 	return "CameraComponent"
 }
 
+// TextComponent holds a screen label or a piece of world text
+type TextComponent struct {
+	BaseComponent
+	Text           string
+	FontPath       string // TTF or OTF file; empty uses the built-in font
+	Size           float32
+	Color          [3]float32
+	Alpha          float32
+	Align          string     // "left", "center" or "right"
+	Space          string     // "screen" or "world"
+	Billboard      bool       // World text turns to face the camera
+	ScreenPosition [2]float32 // Pixels from the viewport's top-left for screen text
+	MaxWidth       float32    // Wrap width; 0 never wraps
+	LineSpacing    float32    // Line height multiplier; 0 means 1
+
+	// Runtime reference
+	TextData interface{}
+}
+
+func NewTextComponent() *TextComponent {
+	return &TextComponent{
+		Text:           "Text",
+		Size:           32,
+		Color:          [3]float32{1, 1, 1},
+		Alpha:          1,
+		Align:          "left",
+		Space:          "screen",
+		ScreenPosition: [2]float32{20, 20},
+		LineSpacing:    1,
+	}
+}
+
+func (t *TextComponent) GetComponentType() ComponentType {
+	return ComponentTypeText
+}
+
+func (t *TextComponent) GetTypeName() string {
+	return "TextComponent"
+}
+
 // ScriptComponent is a wrapper for user scripts to identify them as scripts
 type ScriptComponent struct {
 	BaseComponent
@@ -331,6 +406,7 @@ func BuiltInComponents() []string {
 		"VoxelTerrainComponent",
 		"LightComponent",
 		"CameraComponent",
+		"TextComponent",
 	}
 }
 
@@ -347,6 +423,8 @@ func CreateBuiltInComponent(name string) Component {
 		return NewLightComponent()
 	case "CameraComponent":
 		return NewCameraComponent()
+	case "TextComponent":
+		return NewTextComponent()
 	default:
 		return nil
 	}
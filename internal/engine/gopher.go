@@ -3,7 +3,9 @@ package engine
 import (
 	behaviour "Gopher3D/internal/behaviour"
 	"Gopher3D/internal/logger"
+	"Gopher3D/internal/profiler"
 	"Gopher3D/internal/renderer"
+	"Gopher3D/internal/ui"
 	"runtime"
 	"time"
 
@@ -42,11 +44,13 @@ type Gopher struct {
 	skybox            *renderer.Skybox
 	skyboxPath        string // Store path until OpenGL is ready
 	Camera            *renderer.Camera
+	Views             []*renderer.Camera // Cameras drawn into their viewports instead of Camera alone, when set
 	frameTrackId      int
 	onRenderCallback  func(deltaTime float64) // Optional callback for custom rendering (e.g., editor UI)
 	EnableCameraInput bool                    // Control whether camera processes keyboard/mouse input (for editor)
 	MSAASamples       int                     // MSAA samples (0=off, 2, 4, 8, 16)
 	WindowDecorated   bool                    // Window decoration (border/title bar)
+	scroll            float64                 // Wheel steps since the camera controller last read them
 }
 
 func NewGopher(rendererAPI rendAPI) *Gopher {
@@ -156,18 +160,20 @@ func (gopher *Gopher) Render(x, y int) {
 
 	// DON'T set CursorPosCallback - it conflicts with ImGui's callbacks
 	// Mouse movement for camera is now handled via polling in RenderLoop
+	gopher.window.SetScrollCallback(func(_ *glfw.Window, _, y float64) {
+		gopher.AddCameraScroll(y)
+	})
 
 	gopher.RenderLoop()
 }
 
 func (gopher *Gopher) RenderLoop() {
-	var lastTime = glfw.GetTime()
 	var lastWidth, lastHeight int32 = gopher.Width, gopher.Height
 
 	for !gopher.window.ShouldClose() {
-		currentTime := glfw.GetTime()
-		deltaTime := currentTime - lastTime
-		lastTime = currentTime
+		profiler.BeginFrame()
+		// Fixed while recording a capture, wall time otherwise
+		deltaTime := renderer.Clock.Tick().Seconds()
 
 		// Check actual window size and update if it changed
 		actualWidth, actualHeight := gopher.window.GetSize()
@@ -183,7 +189,7 @@ func (gopher *Gopher) RenderLoop() {
 		}
 
 		// Only process camera input if enabled (can be disabled by editor when UI wants keyboard)
-		if gopher.EnableCameraInput {
+		if gopher.EnableCameraInput && gopher.Camera.Controller == nil {
 			gopher.Camera.ProcessKeyboard(gopher.window, float32(deltaTime))
 
 			// Process mouse movement for camera (polling instead of callback to avoid ImGui conflicts)
@@ -197,41 +203,63 @@ func (gopher *Gopher) RenderLoop() {
 		}
 
 		//TODO: Rignt now it's fixed but maybe in the future we can make it confgigurable?
+		endBehaviours := profiler.Begin("Behaviours")
 		if gopher.frameTrackId >= 2 {
 			behaviour.GlobalBehaviourManager.UpdateAllFixed()
 			gopher.frameTrackId = 0
 		}
 		behaviour.GlobalBehaviourManager.UpdateAll()
+		endBehaviours()
+
+		// Controllers run after behaviours so a followed model has already moved
+		if controller := gopher.Camera.Controller; controller != nil {
+			// Follow cameras keep their arm out of the renderer's models
+			if follow, ok := controller.(*renderer.FollowController); ok && follow.Obstacles == nil {
+				if scene, ok := gopher.rendererAPI.(interface{ GetModels() []*renderer.Model }); ok {
+					follow.Obstacles = scene.GetModels
+				}
+			}
+			var input renderer.CameraInput
+			if gopher.EnableCameraInput {
+				input = gopher.cameraInput()
+			}
+			controller.Update(gopher.Camera, input, float32(deltaTime))
+		}
 
 		// Check if a skybox needs to be created (can happen dynamically from behaviors)
 		if gopher.skyboxPath != "" && gopher.skybox == nil {
-			skybox, err := renderer.CreateSkybox(gopher.skyboxPath)
-			if err != nil {
-				logger.Log.Error("Failed to create skybox", zap.String("path", gopher.skyboxPath), zap.Error(err))
-				// Clear the path to prevent infinite retry loop
-				gopher.skyboxPath = ""
-			} else {
-				gopher.skybox = skybox
-				gopher.rendererAPI.SetSkybox(skybox)
-				logger.Log.Info("Skybox created and set", zap.String("path", gopher.skyboxPath))
-				// Clear the path after successful creation
-				gopher.skyboxPath = ""
-			}
+			gopher.loadSkybox()
 		}
 
-		gopher.rendererAPI.Render(*gopher.Camera, gopher.Light)
+		endRender := profiler.Begin("Render")
+		if multi, ok := gopher.rendererAPI.(renderer.MultiViewRenderer); ok && len(gopher.Views) > 0 {
+			multi.RenderViews(gopher.Views, gopher.Light)
+		} else {
+			gopher.rendererAPI.Render(*gopher.Camera, gopher.Light)
+		}
+		endRender()
+
+		// Game UI goes over the finished frame, under the editor's
+		endUI := profiler.Begin("UI")
+		gopher.updateUI()
+		endUI()
 
 		// Call custom render callback if set (for editor UI, etc.)
 		if gopher.onRenderCallback != nil {
+			endCallback := profiler.Begin("Render Callback")
 			gopher.onRenderCallback(deltaTime)
+			endCallback()
 		}
 
+		endSwap := profiler.Begin("Swap Buffers")
 		switch gopher.rendererAPI.(type) {
 		case *renderer.OpenGLRenderer:
 			gopher.window.SwapBuffers()
 		}
+		endSwap()
 		gopher.frameTrackId++
 		glfw.PollEvents()
+		profiler.EndFrame()
 	}
 	gopher.rendererAPI.Cleanup()
 }
@@ -254,6 +282,29 @@ func (gopher *Gopher) SetFaceCulling(enabled bool) {
 	renderer.FaceCullingEnabled = enabled
 }
 
+// loadSkybox creates the pending skybox, decoding its image in the background on OpenGL
+func (gopher *Gopher) loadSkybox() {
+	path := gopher.skyboxPath
+	// Clear the path so a failed load isn't retried every frame
+	gopher.skyboxPath = ""
+
+	setSkybox := func(skybox *renderer.Skybox, err error) {
+		if err != nil {
+			logger.Log.Error("Failed to create skybox", zap.String("path", path), zap.Error(err))
+			return
+		}
+		gopher.skybox = skybox
+		gopher.rendererAPI.SetSkybox(skybox)
+		logger.Log.Info("Skybox created and set", zap.String("path", path))
+	}
+
+	if openglRenderer, ok := gopher.rendererAPI.(*renderer.OpenGLRenderer); ok {
+		renderer.CreateSkyboxAsync(path, openglRenderer.Uploads()).OnReady(setSkybox)
+		return
+	}
+	setSkybox(renderer.CreateSkybox(path))
+}
+
 // SetSkybox sets a skybox for the engine
 func (g *Gopher) SetSkybox(texturePath string) error {
 	g.skyboxPath = texturePath
@@ -327,6 +378,95 @@ func (gopher *Gopher) GetRenderer() renderer.Render {
 	return gopher.rendererAPI
 }
 
+// AddCameraScroll feeds scroll wheel steps to the camera controller. Hosts that
+// replace the window's scroll callback, like the editor UI, forward them here.
+func (gopher *Gopher) AddCameraScroll(steps float64) {
+	gopher.scroll += steps
+}
+
+// cameraInput reads the keyboard and mouse for a camera controller: WASD moves,
+// Q/E lower and raise, the right mouse button looks, the middle one pans and
+// the wheel zooms
+func (gopher *Gopher) cameraInput() renderer.CameraInput {
+	window := gopher.window
+	axis := func(positive, negative glfw.Key) float32 {
+		var v float32
+		if window.GetKey(positive) == glfw.Press {
+			v++
+		}
+		if window.GetKey(negative) == glfw.Press {
+			v--
+		}
+		return v
+	}
+	input := renderer.CameraInput{
+		Move: mgl.Vec3{axis(glfw.KeyD, glfw.KeyA), axis(glfw.KeyE, glfw.KeyQ), axis(glfw.KeyW, glfw.KeyS)},
+		Zoom: float32(gopher.scroll),
+		Fast: window.GetKey(glfw.KeyLeftShift) == glfw.Press || window.GetKey(glfw.KeyRightShift) == glfw.Press,
+	}
+	gopher.scroll = 0
+
+	looking := window.GetMouseButton(glfw.MouseButtonRight) == glfw.Press
+	panning := window.GetMouseButton(glfw.MouseButtonMiddle) == glfw.Press
+	if !looking && !panning {
+		firstMouse = true
+		return input
+	}
+	xpos, ypos := window.GetCursorPos()
+	if firstMouse {
+		lastX, lastY = xpos, ypos
+		firstMouse = false
+	}
+	delta := mgl.Vec2{float32(xpos - lastX), float32(lastY - ypos)}
+	lastX, lastY = xpos, ypos
+	if looking {
+		input.Look = delta
+	} else {
+		input.Pan = delta
+	}
+	return input
+}
+
+// updateUI applies the frame's input to the game UI and draws it
+func (gopher *Gopher) updateUI() {
+	rend, ok := gopher.rendererAPI.(*renderer.OpenGLRenderer)
+	if !ok {
+		return
+	}
+	manager := ui.GlobalManager
+	if len(manager.Layouts()) > 0 {
+		manager.Update(gopher.uiInput(), float32(gopher.Width), float32(gopher.Height))
+	}
+	rend.DrawUI(manager.DrawList(), gopher.Width, gopher.Height)
+}
+
+// uiInput reads the mouse and the UI navigation keys: Tab and Shift+Tab or the
+// up and down arrows move focus, left and right step sliders, Enter and Space click
+func (gopher *Gopher) uiInput() ui.Input {
+	window := gopher.window
+	down := func(keys ...glfw.Key) bool {
+		for _, key := range keys {
+			if window.GetKey(key) == glfw.Press {
+				return true
+			}
+		}
+		return false
+	}
+	x, y := window.GetCursorPos()
+	tab := down(glfw.KeyTab)
+	shift := down(glfw.KeyLeftShift, glfw.KeyRightShift)
+	return ui.Input{
+		MouseX:    float32(x),
+		MouseY:    float32(y),
+		MouseDown: window.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press,
+		Next:      (tab && !shift) || down(glfw.KeyDown),
+		Previous:  (tab && shift) || down(glfw.KeyUp),
+		Increase:  down(glfw.KeyRight),
+		Decrease:  down(glfw.KeyLeft),
+		Submit:    down(glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeySpace),
+	}
+}
+
 // Mouse callback function
 // processCameraMouseMovement handles camera rotation via mouse (called from polling, not callback)
 func (gopher *Gopher) processCameraMouseMovement(xpos, ypos float64) {
@@ -0,0 +1,45 @@
+package loader
+
+import (
+	"Gopher3D/internal/renderer"
+	"io"
+)
+
+// Share of the progress bar given to parsing; the rest covers the GPU upload
+const parseProgressShare = 0.9
+
+// LoadModelAsync parses an OBJ file on the asset pool. The handle resolves on the main
+// thread (during uploads.Process), where the caller adds the model to the renderer.
+func LoadModelAsync(filename string, recalculateNormals bool, uploads *renderer.UploadQueue) *renderer.AssetHandle[*renderer.Model] {
+	handle := renderer.NewAssetHandle[*renderer.Model](filename, uploads)
+	renderer.AssetPool().Submit(func() {
+		model, err := LoadModelWithProgress(filename, recalculateNormals, func(p float32) {
+			handle.SetProgress(p * parseProgressShare)
+		})
+		if err != nil {
+			handle.Fail(err)
+			return
+		}
+		uploads.Push(func() { handle.Complete(model, nil) })
+	})
+	return handle
+}
+
+// progressReader reports the fraction of a file consumed, in roughly 1% steps
+type progressReader struct {
+	reader   io.Reader
+	total    int64
+	read     int64
+	reported int64
+	report   func(float32)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read-r.reported >= r.total/100 || err == io.EOF {
+		r.reported = r.read
+		r.report(float32(r.read) / float32(r.total))
+	}
+	return n, err
+}
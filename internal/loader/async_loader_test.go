@@ -0,0 +1,34 @@
+package loader
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestProgressReader(t *testing.T) {
+	data := strings.Repeat("v 0 0 0\n", 1000)
+	var reports []float32
+	r := &progressReader{reader: strings.NewReader(data), total: int64(len(data)), report: func(p float32) {
+		reports = append(reports, p)
+	}}
+
+	buf := make([]byte, 64)
+	for {
+		if _, err := r.Read(buf); err == io.EOF {
+			break
+		}
+	}
+
+	if len(reports) == 0 || reports[len(reports)-1] != 1 {
+		t.Fatalf("Expected progress to end at 1, got %v", reports)
+	}
+	if len(reports) > 110 {
+		t.Errorf("Expected roughly 1%% steps, got %d reports", len(reports))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i] < reports[i-1] {
+			t.Fatalf("Progress went backwards: %v", reports)
+		}
+	}
+}
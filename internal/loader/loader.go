@@ -8,6 +8,7 @@ import (
 	"fmt"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -155,11 +156,21 @@ func LoadObject() *renderer.Model {
 }
 
 func LoadModel(filename string, recalculateNormals bool) (*renderer.Model, error) {
+	return LoadModelWithProgress(filename, recalculateNormals, nil)
+}
+
+// LoadModelWithProgress is LoadModel reporting how much of the OBJ file has been read (0-1)
+func LoadModelWithProgress(filename string, recalculateNormals bool, progress func(float32)) (*renderer.Model, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
+
+	var reader io.Reader = file
+	if info, err := file.Stat(); err == nil && progress != nil && info.Size() > 0 {
+		reader = &progressReader{reader: file, total: info.Size(), report: progress}
+	}
 	var modelMaterials map[string]*renderer.Material
 	var model *renderer.Model
 	var vertices []float32
@@ -190,7 +201,7 @@ func LoadModel(filename string, recalculateNormals bool) (*renderer.Model, error
 	model.Material = renderer.DefaultMaterial
 	uniqueMaterial := *model.Material
 	model.Material = &uniqueMaterial
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.Fields(line)
@@ -0,0 +1,141 @@
+package loader
+
+import (
+	"Gopher3D/internal/renderer"
+	"fmt"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// BlockType describes how a voxel type is drawn. Faces without a texture use the
+// model's material texture; Color tints every face.
+type BlockType struct {
+	ID        VoxelID
+	Name      string
+	Color     mgl32.Vec3 // Zero uses white for textured blocks, the default palette otherwise
+	Top       string     // Texture paths for each face
+	Side      string
+	Bottom    string
+	Metallic  float32 // Zero keeps the voxel material's value
+	Roughness float32 // Zero keeps the voxel material's value
+}
+
+// BlockRegistry maps voxel IDs to block types and assigns every distinct face
+// texture a layer in one shared texture array
+type BlockRegistry struct {
+	ColorSpace renderer.TextureColorSpace // Color space of every face texture; unset is sRGB
+
+	blocks map[VoxelID]BlockType
+	layers map[string]int
+	paths  []string
+}
+
+// NewBlockRegistry creates an empty registry
+func NewBlockRegistry() *BlockRegistry {
+	return &BlockRegistry{blocks: make(map[VoxelID]BlockType), layers: make(map[string]int)}
+}
+
+// Blocks is the registry used by voxel worlds when building models
+var Blocks = NewBlockRegistry()
+
+// Register adds or replaces a block type. Textures shared between blocks or faces
+// occupy a single layer.
+func (r *BlockRegistry) Register(block BlockType) error {
+	if block.ID == 0 {
+		return fmt.Errorf("voxel ID 0 is air and can't be registered as a block")
+	}
+	if block.Metallic < 0 || block.Metallic > 1 || block.Roughness < 0 || block.Roughness > 1 {
+		return fmt.Errorf("block %q: metallic and roughness must be between 0 and 1", block.Name)
+	}
+
+	r.blocks[block.ID] = block
+	r.rebuildLayers()
+	return nil
+}
+
+// Remove unregisters a block type
+func (r *BlockRegistry) Remove(id VoxelID) {
+	delete(r.blocks, id)
+	r.rebuildLayers()
+}
+
+// Clear removes every block type
+func (r *BlockRegistry) Clear() {
+	r.blocks = make(map[VoxelID]BlockType)
+	r.rebuildLayers()
+}
+
+// Get returns the block type registered for id
+func (r *BlockRegistry) Get(id VoxelID) (BlockType, bool) {
+	block, ok := r.blocks[id]
+	return block, ok
+}
+
+// TexturePaths returns the face textures in layer order
+func (r *BlockRegistry) TexturePaths() []string {
+	return append([]string(nil), r.paths...)
+}
+
+// HasTextures returns true if any registered block has a face texture
+func (r *BlockRegistry) HasTextures() bool {
+	return len(r.paths) > 0
+}
+
+// Attributes returns the texture layers and surface values drawn for id
+func (r *BlockRegistry) Attributes(id VoxelID) renderer.BlockAttributes {
+	attrs := renderer.NoBlockAttributes
+	block, ok := r.blocks[id]
+	if !ok {
+		return attrs
+	}
+	for i, path := range []string{block.Top, block.Side, block.Bottom} {
+		if layer, ok := r.layers[path]; ok && path != "" {
+			attrs.Layers[i] = float32(layer)
+		}
+	}
+	if block.Metallic > 0 {
+		attrs.Metallic = block.Metallic
+	}
+	if block.Roughness > 0 {
+		attrs.Roughness = block.Roughness
+	}
+	return attrs
+}
+
+// rebuildLayers assigns layers in block ID order so the array is stable between runs
+func (r *BlockRegistry) rebuildLayers() {
+	ids := make([]int, 0, len(r.blocks))
+	for id := range r.blocks {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	r.layers = make(map[string]int)
+	r.paths = nil
+	for _, id := range ids {
+		block := r.blocks[VoxelID(id)]
+		for _, path := range []string{block.Top, block.Side, block.Bottom} {
+			if _, seen := r.layers[path]; path == "" || seen {
+				continue
+			}
+			r.layers[path] = len(r.paths)
+			r.paths = append(r.paths, path)
+		}
+	}
+}
+
+// textured returns true if the block has at least one face texture
+func (b BlockType) textured() bool {
+	return b.Top != "" || b.Side != "" || b.Bottom != ""
+}
+
+// applyBlockTextures attaches the registry's texture array to a voxel model
+func applyBlockTextures(model *renderer.Model, attrs []renderer.BlockAttributes) {
+	if !Blocks.HasTextures() {
+		return
+	}
+	model.BlockAttributes = attrs
+	model.BlockTexturePaths = Blocks.TexturePaths()
+	model.BlockTextureColorSpace = Blocks.ColorSpace
+}
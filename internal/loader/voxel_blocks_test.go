@@ -0,0 +1,146 @@
+package loader
+
+import (
+	"Gopher3D/internal/renderer"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBlockRegistryLayers(t *testing.T) {
+	r := NewBlockRegistry()
+	if err := r.Register(BlockType{ID: 0, Name: "air"}); err == nil {
+		t.Error("Registering voxel ID 0 should fail")
+	}
+	if err := r.Register(BlockType{ID: 2, Name: "dirt", Top: "dirt.png", Side: "dirt.png", Bottom: "dirt.png"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(BlockType{ID: 1, Name: "grass", Top: "grass_top.png", Side: "grass_side.png", Bottom: "dirt.png", Roughness: 0.7}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Layers follow block ID order and shared textures get one layer
+	paths := r.TexturePaths()
+	want := []string{"grass_top.png", "grass_side.png", "dirt.png"}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Layer %d: expected %s, got %s", i, want[i], paths[i])
+		}
+	}
+
+	grass := r.Attributes(1)
+	if grass.Layers != [3]float32{0, 1, 2} || grass.Roughness != 0.7 || grass.Metallic != -1 {
+		t.Errorf("Unexpected grass attributes: %+v", grass)
+	}
+	if dirt := r.Attributes(2); dirt.Layers != [3]float32{2, 2, 2} {
+		t.Errorf("Expected dirt on layer 2 for every face, got %v", dirt.Layers)
+	}
+	if r.Attributes(9) != renderer.NoBlockAttributes {
+		t.Error("Unregistered blocks should keep the model material")
+	}
+
+	r.Remove(1)
+	if len(r.TexturePaths()) != 1 || r.Attributes(2).Layers[0] != 0 {
+		t.Errorf("Removing a block should compact the layers, got %v", r.TexturePaths())
+	}
+}
+
+func TestGetVoxelColorUsesRegistry(t *testing.T) {
+	defer Blocks.Clear()
+	defer ClearCustomVoxelColors()
+
+	Blocks.Register(BlockType{ID: 3, Name: "stone", Side: "stone.png"})
+	if c := GetVoxelColor(3); c != (mgl32.Vec3{1, 1, 1}) {
+		t.Errorf("Textured blocks without a color should be white, got %v", c)
+	}
+	Blocks.Register(BlockType{ID: 4, Name: "sand", Color: mgl32.Vec3{1, 0, 0}})
+	if c := GetVoxelColor(4); c != (mgl32.Vec3{1, 0, 0}) {
+		t.Errorf("Expected the registered color, got %v", c)
+	}
+	SetVoxelColor(4, mgl32.Vec3{0, 0, 1})
+	if c := GetVoxelColor(4); c != (mgl32.Vec3{0, 0, 1}) {
+		t.Errorf("Custom colors should take priority, got %v", c)
+	}
+}
+
+func TestInstancedModelBlockAttributes(t *testing.T) {
+	defer Blocks.Clear()
+
+	world := NewVoxelWorld(4, 1, 1, 4, 1.0, CreateCubeGeometry(1.0), InstancedMode)
+	world.SetVoxel(0, 0, 0, 1)
+	world.SetVoxel(1, 0, 0, 2)
+
+	model, err := world.CreateInstancedModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.BlockAttributes != nil || model.BlockTexturePaths != nil {
+		t.Error("Worlds without block textures should not get block attributes")
+	}
+
+	Blocks.Register(BlockType{ID: 2, Name: "dirt", Top: "dirt.png"})
+	model, err = world.CreateInstancedModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(model.BlockAttributes) != model.InstanceCount || len(model.BlockTexturePaths) != 1 {
+		t.Fatalf("Expected %d block attributes and 1 layer, got %d and %d",
+			model.InstanceCount, len(model.BlockAttributes), len(model.BlockTexturePaths))
+	}
+	textured := 0
+	for _, attrs := range model.BlockAttributes {
+		if attrs.Layers[0] == 0 {
+			textured++
+		}
+	}
+	if textured != 1 {
+		t.Errorf("Expected one instance with the dirt texture, got %d", textured)
+	}
+}
+
+func TestSurfaceNetsFaceProjection(t *testing.T) {
+	defer Blocks.Clear()
+	Blocks.Register(BlockType{ID: 1, Name: "stone", Top: "stone_top.png", Side: "stone_side.png"})
+
+	// A flat floor at height 0 with a 7 voxel cliff from x = 4
+	world := NewVoxelWorld(8, 1, 1, 16, 1.0, nil, SurfaceNetsMode)
+	for x := 0; x < 8; x++ {
+		top := 0
+		if x >= 4 {
+			top = 7
+		}
+		for z := 0; z < 8; z++ {
+			for y := 0; y <= top; y++ {
+				world.SDFData[x][y][z] = -1
+			}
+		}
+	}
+
+	model, err := world.CreateSurfaceNetsModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vertex := func(x, z int) []float32 {
+		i := (x*8 + z) * 8
+		return model.InterleavedData[i : i+8]
+	}
+
+	flat := vertex(1, 2)
+	if flat[5] != 0 || flat[6] != 1 || flat[7] != 0 {
+		t.Errorf("Expected an up normal on the floor, got %v", flat[5:])
+	}
+	if flat[3] != 1 || flat[4] != 2 {
+		t.Errorf("Expected floor UVs from x and z, got %v", flat[3:5])
+	}
+
+	cliff := vertex(3, 2)
+	if cliff[5] >= 0 || cliff[6] > 0.5 {
+		t.Errorf("Expected the cliff normal to face -x, got %v", cliff[5:])
+	}
+	if cliff[3] != 2 || cliff[4] != 0 {
+		t.Errorf("Expected cliff UVs from z and y, got %v", cliff[3:5])
+	}
+}
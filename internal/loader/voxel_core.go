@@ -402,6 +402,11 @@ func (world *VoxelWorld) CreateInstancedModel() (*renderer.Model, error) {
 			return nil, err
 		}
 
+		var blockAttrs []renderer.BlockAttributes
+		if Blocks.HasTextures() {
+			blockAttrs = make([]renderer.BlockAttributes, visibleCount)
+		}
+
 		// Second pass: populate instance matrices for visible voxels only
 		// Iterate chunkX -> chunkZ -> x -> y -> z for optimal cache locality
 		// (chunks stored as [chunkX][chunkZ], voxels as [x][y][z])
@@ -437,6 +442,9 @@ func (world *VoxelWorld) CreateInstancedModel() (*renderer.Model, error) {
 
 									// Assign color based on VoxelID
 									model.InstanceColors[instanceIndex] = GetVoxelColor(voxel.ID)
+									if blockAttrs != nil {
+										blockAttrs[instanceIndex] = Blocks.Attributes(voxel.ID)
+									}
 
 									instanceIndex++
 								}
@@ -447,6 +455,7 @@ func (world *VoxelWorld) CreateInstancedModel() (*renderer.Model, error) {
 			}
 		}
 
+		applyBlockTextures(model, blockAttrs)
 		model.InstanceMatricesUpdated = true
 		return model, nil
 	} else {
@@ -457,10 +466,13 @@ func (world *VoxelWorld) CreateInstancedModel() (*renderer.Model, error) {
 func (world *VoxelWorld) CreateSurfaceNetsModel() (*renderer.Model, error) {
 	var vertices []float32
 	var indices []int32
+	var blockAttrs []renderer.BlockAttributes
 
 	totalX := world.WorldSizeX * world.ChunkSize
 	totalZ := world.WorldSizeZ * world.ChunkSize
+	textured := Blocks.HasTextures()
 
+	heights := make([]float32, totalX*totalZ)
 	for x := 0; x < totalX; x++ {
 		for z := 0; z < totalZ; z++ {
 			height := float32(10)
@@ -470,14 +482,37 @@ func (world *VoxelWorld) CreateSurfaceNetsModel() (*renderer.Model, error) {
 					break
 				}
 			}
+			heights[x*totalZ+z] = height
+		}
+	}
+	heightAt := func(x, z int) float32 {
+		x = max(0, min(x, totalX-1))
+		z = max(0, min(z, totalZ-1))
+		return heights[x*totalZ+z]
+	}
+
+	for x := 0; x < totalX; x++ {
+		for z := 0; z < totalZ; z++ {
+			height := heightAt(x, z)
+			// Central differences of the height field, in voxels on every axis
+			normal := mgl32.Vec3{
+				(heightAt(x-1, z) - heightAt(x+1, z)) / 2,
+				1,
+				(heightAt(x, z-1) - heightAt(x, z+1)) / 2,
+			}.Normalize()
 
 			u := float32(x) / float32(totalX-1)
 			v := float32(z) / float32(totalZ-1)
+			if textured {
+				// Block textures repeat once per voxel on the plane facing the normal
+				u, v = surfaceNetsTexCoord(mgl32.Vec3{float32(x), height, float32(z)}, normal)
+				blockAttrs = append(blockAttrs, Blocks.Attributes(world.GetVoxel(x, int(height), z)))
+			}
 
 			vertices = append(vertices,
 				float32(x)*world.VoxelSize, height*world.VoxelSize, float32(z)*world.VoxelSize,
 				u, v,
-				0.0, 1.0, 0.0,
+				normal.X(), normal.Y(), normal.Z(),
 			)
 		}
 	}
@@ -520,11 +555,26 @@ func (world *VoxelWorld) CreateSurfaceNetsModel() (*renderer.Model, error) {
 
 	uniqueMaterial := *model.Material
 	model.Material = &uniqueMaterial
+	applyBlockTextures(model, blockAttrs)
 	model.CalculateBoundingSphere()
 
 	return model, nil
 }
 
+// surfaceNetsTexCoord projects a position in voxels onto the plane its normal
+// faces most. The thresholds match the shader's choice of top, side and bottom
+// layers, so side faces get upright textures.
+func surfaceNetsTexCoord(p, normal mgl32.Vec3) (float32, float32) {
+	switch {
+	case normal.Y() > 0.5 || normal.Y() < -0.5:
+		return p.X(), p.Z()
+	case math.Abs(float64(normal.X())) > math.Abs(float64(normal.Z())):
+		return p.Z(), p.Y()
+	default:
+		return p.X(), p.Y()
+	}
+}
+
 func (world *VoxelWorld) sampleHeightAt(x, z float32) float32 {
 	if world.RenderMode != SurfaceNetsMode {
 		return 0
@@ -830,6 +880,14 @@ func GetVoxelColor(voxelID VoxelID) mgl32.Vec3 {
 	if color, ok := CustomVoxelColors[voxelID]; ok {
 		return color
 	}
+	if block, ok := Blocks.Get(voxelID); ok {
+		if block.Color != (mgl32.Vec3{}) {
+			return block.Color
+		}
+		if block.textured() {
+			return mgl32.Vec3{1, 1, 1}
+		}
+	}
 
 	switch voxelID {
 	case 0:
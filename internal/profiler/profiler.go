@@ -0,0 +1,269 @@
+package profiler
+
+import (
+	"sync"
+	"time"
+)
+
+// Scope is one timed region of a frame
+type Scope struct {
+	Name     string
+	Start    time.Duration // Offset from the start of the frame
+	Duration time.Duration
+	Depth    int // Nesting level, 0 for top-level scopes
+}
+
+// Frame holds the timings recorded for one frame
+type Frame struct {
+	Index    uint64
+	Start    time.Duration // Offset from when the profiler was created
+	Duration time.Duration
+	CPU      []Scope
+	GPU      []Scope // Filled in a few frames late, once the GPU timer queries resolve
+}
+
+// Profiler records scoped CPU timers and GPU pass timings into a rolling history
+// of frames. Scopes must be opened and closed on the thread running the frame.
+type Profiler struct {
+	mu         sync.Mutex
+	enabled    bool
+	epoch      time.Time
+	history    []Frame // Ring buffer of finished frames
+	head       int     // Slot the next finished frame is written to
+	count      int
+	current    Frame
+	inFrame    bool
+	frameStart time.Time
+	open       []int // Indices into current.CPU of scopes not yet closed
+	nextIndex  uint64
+}
+
+// New creates a disabled profiler keeping the last historySize frames
+func New(historySize int) *Profiler {
+	return &Profiler{epoch: time.Now(), history: make([]Frame, max(historySize, 1)), nextIndex: 1}
+}
+
+// Default is the profiler the engine and renderer report to
+var Default = New(300)
+
+var noop = func() {}
+
+// SetEnabled starts or stops recording. History is kept while disabled.
+func (p *Profiler) SetEnabled(enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enabled = enabled
+	if !enabled {
+		p.inFrame = false
+		p.open = p.open[:0]
+	}
+}
+
+// Enabled returns true while the profiler records
+func (p *Profiler) Enabled() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.enabled
+}
+
+// BeginFrame starts a new frame and returns its index, or 0 while disabled
+func (p *Profiler) BeginFrame() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return 0
+	}
+	p.frameStart = time.Now()
+	p.current = Frame{Index: p.nextIndex, Start: p.frameStart.Sub(p.epoch)}
+	p.nextIndex++
+	p.inFrame = true
+	p.open = p.open[:0]
+	return p.current.Index
+}
+
+// CurrentFrame returns the index of the frame being recorded, or 0 outside a frame
+func (p *Profiler) CurrentFrame() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.inFrame {
+		return 0
+	}
+	return p.current.Index
+}
+
+// EndFrame closes any scopes left open and adds the frame to the history
+func (p *Profiler) EndFrame() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.inFrame {
+		return
+	}
+	now := time.Since(p.frameStart)
+	for _, i := range p.open {
+		p.current.CPU[i].Duration = now - p.current.CPU[i].Start
+	}
+	p.open = p.open[:0]
+	p.current.Duration = now
+	p.inFrame = false
+
+	p.history[p.head] = p.current
+	p.head = (p.head + 1) % len(p.history)
+	p.count = min(p.count+1, len(p.history))
+	p.current = Frame{}
+}
+
+// Begin opens a CPU scope and returns the function that closes it:
+//
+//	defer profiler.Begin("Culling")()
+func (p *Profiler) Begin(name string) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.inFrame {
+		return noop
+	}
+	index := len(p.current.CPU)
+	frame := p.current.Index
+	p.current.CPU = append(p.current.CPU, Scope{Name: name, Start: time.Since(p.frameStart), Depth: len(p.open)})
+	p.open = append(p.open, index)
+	return func() { p.end(frame, index) }
+}
+
+// end closes the scope at index, along with any scopes opened inside it
+func (p *Profiler) end(frame uint64, index int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.inFrame || p.current.Index != frame {
+		return
+	}
+	now := time.Since(p.frameStart)
+	for len(p.open) > 0 {
+		top := p.open[len(p.open)-1]
+		p.open = p.open[:len(p.open)-1]
+		p.current.CPU[top].Duration = now - p.current.CPU[top].Start
+		if top == index {
+			return
+		}
+	}
+}
+
+// AddGPUScope attaches a resolved GPU timing to the frame it was measured in.
+// Results for frames that already left the history are dropped.
+func (p *Profiler) AddGPUScope(frame uint64, scope Scope) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inFrame && p.current.Index == frame {
+		p.current.GPU = append(p.current.GPU, scope)
+		return
+	}
+	for i := 0; i < p.count; i++ {
+		f := &p.history[(p.head-1-i+len(p.history))%len(p.history)]
+		if f.Index == frame {
+			f.GPU = append(f.GPU, scope)
+			return
+		}
+	}
+}
+
+// Frames returns the recorded history, oldest first
+func (p *Profiler) Frames() []Frame {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	frames := make([]Frame, 0, p.count)
+	for i := p.count; i > 0; i-- {
+		f := p.history[(p.head-i+len(p.history))%len(p.history)]
+		f.CPU = append([]Scope(nil), f.CPU...)
+		f.GPU = append([]Scope(nil), f.GPU...)
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// Reset clears the history
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.history)
+	p.head, p.count = 0, 0
+}
+
+// ScopeStats summarises one named scope over the history
+type ScopeStats struct {
+	Name  string
+	GPU   bool
+	Depth int
+	Avg   time.Duration
+	Max   time.Duration
+	Last  time.Duration
+}
+
+// Stats returns per-scope timings over the history. Scopes that repeat within a
+// frame are summed. CPU scopes come first, each group in first-seen order.
+func (p *Profiler) Stats() []ScopeStats {
+	frames := p.Frames()
+
+	type key struct {
+		name string
+		gpu  bool
+	}
+	var order []key
+	stats := make(map[key]*ScopeStats)
+	totals := make(map[key]time.Duration)
+	counts := make(map[key]int)
+
+	for _, f := range frames {
+		perFrame := make(map[key]time.Duration)
+		for _, group := range []struct {
+			scopes []Scope
+			gpu    bool
+		}{{f.CPU, false}, {f.GPU, true}} {
+			for _, s := range group.scopes {
+				k := key{s.Name, group.gpu}
+				if _, seen := stats[k]; !seen {
+					stats[k] = &ScopeStats{Name: s.Name, GPU: group.gpu, Depth: s.Depth}
+					order = append(order, k)
+				}
+				perFrame[k] += s.Duration
+			}
+		}
+		for k, d := range perFrame {
+			st := stats[k]
+			st.Max = max(st.Max, d)
+			st.Last = d
+			totals[k] += d
+			counts[k]++
+		}
+	}
+
+	result := make([]ScopeStats, 0, len(order))
+	for _, gpu := range []bool{false, true} {
+		for _, k := range order {
+			if k.gpu != gpu {
+				continue
+			}
+			st := stats[k]
+			st.Avg = totals[k] / time.Duration(counts[k])
+			result = append(result, *st)
+		}
+	}
+	return result
+}
+
+// Package-level helpers report to Default
+
+// SetEnabled starts or stops the default profiler
+func SetEnabled(enabled bool) { Default.SetEnabled(enabled) }
+
+// Enabled returns true while the default profiler records
+func Enabled() bool { return Default.Enabled() }
+
+// BeginFrame starts a frame on the default profiler
+func BeginFrame() uint64 { return Default.BeginFrame() }
+
+// EndFrame finishes the default profiler's frame
+func EndFrame() { Default.EndFrame() }
+
+// CurrentFrame returns the default profiler's frame index
+func CurrentFrame() uint64 { return Default.CurrentFrame() }
+
+// Begin opens a CPU scope on the default profiler
+func Begin(name string) func() { return Default.Begin(name) }
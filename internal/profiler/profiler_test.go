@@ -0,0 +1,118 @@
+package profiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDisabledProfilerRecordsNothing(t *testing.T) {
+	p := New(4)
+	if p.BeginFrame() != 0 {
+		t.Error("A disabled profiler should not start frames")
+	}
+	p.Begin("scope")()
+	p.EndFrame()
+	if len(p.Frames()) != 0 {
+		t.Error("Expected no frames while disabled")
+	}
+}
+
+func TestNestedScopes(t *testing.T) {
+	p := New(4)
+	p.SetEnabled(true)
+	p.BeginFrame()
+	endOuter := p.Begin("outer")
+	endInner := p.Begin("inner")
+	time.Sleep(time.Millisecond)
+	endInner()
+	p.Begin("unclosed")
+	endOuter()
+	p.EndFrame()
+
+	frames := p.Frames()
+	if len(frames) != 1 || len(frames[0].CPU) != 3 {
+		t.Fatalf("Expected one frame with 3 scopes, got %+v", frames)
+	}
+	outer, inner, unclosed := frames[0].CPU[0], frames[0].CPU[1], frames[0].CPU[2]
+	if outer.Depth != 0 || inner.Depth != 1 || unclosed.Depth != 1 {
+		t.Errorf("Unexpected depths %d, %d, %d", outer.Depth, inner.Depth, unclosed.Depth)
+	}
+	if inner.Duration < time.Millisecond || outer.Duration < inner.Duration {
+		t.Errorf("Outer (%v) should contain inner (%v)", outer.Duration, inner.Duration)
+	}
+	if unclosed.Duration == 0 {
+		t.Error("Closing a scope should close the scopes opened inside it")
+	}
+}
+
+func TestHistoryAndGPUScopes(t *testing.T) {
+	p := New(3)
+	p.SetEnabled(true)
+	var indices []uint64
+	for i := 0; i < 5; i++ {
+		indices = append(indices, p.BeginFrame())
+		p.Begin("Render")()
+		p.EndFrame()
+	}
+
+	frames := p.Frames()
+	if len(frames) != 3 || frames[0].Index != indices[2] || frames[2].Index != indices[4] {
+		t.Fatalf("Expected the last 3 frames oldest first, got %d frames", len(frames))
+	}
+
+	// GPU results arrive late and are attached to the frame they measured
+	p.AddGPUScope(indices[3], Scope{Name: "Opaque", Duration: 2 * time.Millisecond})
+	p.AddGPUScope(indices[0], Scope{Name: "Opaque", Duration: time.Millisecond})
+	frames = p.Frames()
+	if len(frames[1].GPU) != 1 || len(frames[0].GPU) != 0 {
+		t.Error("GPU scope attached to the wrong frame")
+	}
+
+	stats := p.Stats()
+	if len(stats) != 2 || stats[0].Name != "Render" || stats[0].GPU || !stats[1].GPU {
+		t.Fatalf("Expected CPU Render then GPU Opaque, got %+v", stats)
+	}
+	if stats[1].Avg != 2*time.Millisecond || stats[1].Max != 2*time.Millisecond {
+		t.Errorf("Expected 2ms GPU average, got %v", stats[1].Avg)
+	}
+}
+
+func TestWriteChromeTrace(t *testing.T) {
+	p := New(4)
+	p.SetEnabled(true)
+	frame := p.BeginFrame()
+	p.Begin("Culling")()
+	p.EndFrame()
+	p.AddGPUScope(frame, Scope{Name: "Opaque", Start: time.Millisecond, Duration: time.Millisecond})
+
+	var buf bytes.Buffer
+	if err := p.WriteChromeTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace traceFile
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("Trace is not valid JSON: %v", err)
+	}
+	if trace.OtherData["go"] == "" {
+		t.Error("Expected build info in otherData")
+	}
+
+	found := map[string]traceEvent{}
+	for _, e := range trace.TraceEvents {
+		if e.Ph == "X" {
+			found[e.Cat+":"+e.Name] = e
+		}
+	}
+	if _, ok := found["cpu:Culling"]; !ok {
+		t.Error("Missing CPU scope event")
+	}
+	gpu, ok := found["gpu:Opaque"]
+	if !ok || gpu.Tid != traceGPUThread || gpu.Dur != 1000 {
+		t.Errorf("Expected a 1000us GPU event on the GPU thread, got %+v", gpu)
+	}
+	if _, ok := found["frame:Frame 1"]; !ok {
+		t.Error("Missing frame event")
+	}
+}
@@ -0,0 +1,104 @@
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Thread IDs used in exported traces
+const (
+	traceCPUThread = 1
+	traceGPUThread = 2
+)
+
+// traceEvent is an entry of the Chrome trace-event format (chrome://tracing, Perfetto)
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"` // Microseconds
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent      `json:"traceEvents"`
+	DisplayTimeUnit string            `json:"displayTimeUnit"`
+	OtherData       map[string]string `json:"otherData,omitempty"`
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// WriteChromeTrace writes the history as Chrome trace-event JSON. CPU scopes and
+// GPU passes appear as separate threads; build info is stored so traces from
+// different builds can be told apart.
+func (p *Profiler) WriteChromeTrace(w io.Writer) error {
+	frames := p.Frames()
+
+	events := []traceEvent{
+		{Name: "thread_name", Ph: "M", Pid: 1, Tid: traceCPUThread, Args: map[string]any{"name": "CPU"}},
+		{Name: "thread_name", Ph: "M", Pid: 1, Tid: traceGPUThread, Args: map[string]any{"name": "GPU"}},
+	}
+	for _, f := range frames {
+		events = append(events, traceEvent{
+			Name: fmt.Sprintf("Frame %d", f.Index), Cat: "frame", Ph: "X",
+			Ts: micros(f.Start), Dur: micros(f.Duration), Pid: 1, Tid: traceCPUThread,
+		})
+		for _, s := range f.CPU {
+			events = append(events, traceEvent{
+				Name: s.Name, Cat: "cpu", Ph: "X",
+				Ts: micros(f.Start + s.Start), Dur: micros(s.Duration), Pid: 1, Tid: traceCPUThread,
+			})
+		}
+		for _, s := range f.GPU {
+			events = append(events, traceEvent{
+				Name: s.Name, Cat: "gpu", Ph: "X",
+				Ts: micros(f.Start + s.Start), Dur: micros(s.Duration), Pid: 1, Tid: traceGPUThread,
+				Args: map[string]any{"frame": f.Index},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(traceFile{TraceEvents: events, DisplayTimeUnit: "ms", OtherData: buildInfo()})
+}
+
+// ExportChromeTrace writes the history to a trace file
+func (p *Profiler) ExportChromeTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteChromeTrace(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// buildInfo describes the running binary
+func buildInfo() map[string]string {
+	info := map[string]string{
+		"go":       runtime.Version(),
+		"platform": runtime.GOOS + "/" + runtime.GOARCH,
+		"captured": time.Now().Format(time.RFC3339),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision", "vcs.time", "vcs.modified":
+				info[setting.Key] = setting.Value
+			}
+		}
+	}
+	return info
+}
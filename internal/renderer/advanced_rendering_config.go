@@ -6,6 +6,7 @@ import "github.com/go-gl/mathgl/mgl32"
 // Based on techniques from NVIDIA GPU Gems, Real-Time Rendering, and modern research
 type AdvancedRenderingConfig struct {
 	// Core Lighting Features
+	// Has no shader code of its own: SSAO, volumetrics and GI have their own features
 	EnableAdvancedLighting bool `json:"enableAdvancedLighting"`
 
 	// Modern PBR Extensions
@@ -73,6 +74,14 @@ type AdvancedRenderingConfig struct {
 	// These are stored here for persistence but applied via engine/renderer, not shader uniforms
 	MSAASamples int  `json:"msaaSamples"` // 0, 2, 4, 8, 16 (hardware MSAA, requires restart)
 	EnableFXAA  bool `json:"enableFXAA"`  // Software FXAA post-processing
+
+	// Screen-Space Reflections - also applied at renderer level, see SSRSettings
+	EnableSSR       bool       `json:"enableSSR"`
+	SSRQuality      SSRQuality `json:"ssrQuality"` // "low", "medium" or "high" trace step budget
+	SSRMaxDistance  float32    `json:"ssrMaxDistance"`
+	SSRThickness    float32    `json:"ssrThickness"`
+	SSRIntensity    float32    `json:"ssrIntensity"`
+	SSRMaxRoughness float32    `json:"ssrMaxRoughness"`
 }
 
 // DefaultAdvancedRenderingConfig returns sensible defaults for all advanced rendering features
@@ -145,6 +154,26 @@ func DefaultAdvancedRenderingConfig() AdvancedRenderingConfig {
 		// Anti-Aliasing - good defaults
 		MSAASamples: 4,     // 4x MSAA (hardware)
 		EnableFXAA:  false, // FXAA disabled (use MSAA instead)
+
+		// Screen-Space Reflections - disabled by default for performance
+		EnableSSR:       false,
+		SSRQuality:      SSRQualityMedium,
+		SSRMaxDistance:  50.0,
+		SSRThickness:    0.5,
+		SSRIntensity:    1.0,
+		SSRMaxRoughness: 0.6,
+	}
+}
+
+// SSRSettings returns the reflection settings for OpenGLRenderer.SSR
+func (config AdvancedRenderingConfig) SSRSettings() SSRSettings {
+	return SSRSettings{
+		Enabled:      config.EnableSSR,
+		Quality:      config.SSRQuality,
+		MaxDistance:  config.SSRMaxDistance,
+		Thickness:    config.SSRThickness,
+		Intensity:    config.SSRIntensity,
+		MaxRoughness: config.SSRMaxRoughness,
 	}
 }
 
@@ -172,6 +201,8 @@ func HighQualityRenderingConfig() AdvancedRenderingConfig {
 	config.BloomIntensity = 0.5
 	config.FilteringQuality = 3
 	config.SSAOSampleCount = 32
+	config.EnableSSR = true
+	config.SSRQuality = SSRQualityHigh
 
 	// High quality AA
 	config.MSAASamples = 8 // 8x MSAA for high quality
@@ -194,9 +225,11 @@ func PerformanceRenderingConfig() AdvancedRenderingConfig {
 	config.EnableBloom = false
 	config.EnableSubsurfaceScattering = false
 	config.EnableSSAO = false
+	config.EnableSSR = false
 
 	// Lower quality settings for performance
 	config.FilteringQuality = 1
+	config.SSRQuality = SSRQualityLow
 
 	// Performance AA
 	config.MSAASamples = 2 // 2x MSAA for performance
@@ -232,38 +265,29 @@ func ApplyAdvancedRenderingConfig(model *Model, config AdvancedRenderingConfig)
 		model.CustomUniforms = make(map[string]interface{})
 	}
 
-	// Core lighting features
-	model.CustomUniforms["enableAdvancedLighting"] = config.EnableAdvancedLighting
+	// Feature toggles select a compiled shader variant
+	model.ShaderFeatures = config.ShaderFeatures()
 
 	// Modern PBR Extensions
-	model.CustomUniforms["enableClearcoat"] = config.EnableClearcoat
 	model.CustomUniforms["clearcoatRoughness"] = config.ClearcoatRoughness
 	model.CustomUniforms["clearcoatIntensity"] = config.ClearcoatIntensity
-	model.CustomUniforms["enableSheen"] = config.EnableSheen
 	model.CustomUniforms["sheenColor"] = config.SheenColor
 	model.CustomUniforms["sheenRoughness"] = config.SheenRoughness
-	model.CustomUniforms["enableTransmission"] = config.EnableTransmission
 	model.CustomUniforms["transmissionFactor"] = config.TransmissionFactor
 
 	// Advanced Lighting Models
-	model.CustomUniforms["enableMultipleScattering"] = config.EnableMultipleScattering
-	model.CustomUniforms["enableEnergyConservation"] = config.EnableEnergyConservation
-	model.CustomUniforms["enableImageBasedLighting"] = config.EnableImageBasedLighting
 	model.CustomUniforms["iblIntensity"] = config.IBLIntensity
 
 	// Apply noise settings
-	model.CustomUniforms["enablePerlinNoise"] = config.EnablePerlinNoise
 	model.CustomUniforms["noiseScale"] = config.NoiseScale
 	model.CustomUniforms["noiseOctaves"] = int32(config.NoiseOctaves)
 	model.CustomUniforms["noiseIntensity"] = config.NoiseIntensity
 
 	// Apply shadow settings
-	model.CustomUniforms["enableShadows"] = config.EnableAdvancedShadows
 	model.CustomUniforms["shadowIntensity"] = config.ShadowIntensity
 	model.CustomUniforms["shadowSoftness"] = config.ShadowSoftness
 
 	// Volumetric Lighting
-	model.CustomUniforms["enableVolumetricLighting"] = config.EnableVolumetricLighting
 	model.CustomUniforms["volumetricIntensity"] = config.VolumetricIntensity
 	model.CustomUniforms["volumetricSteps"] = int32(config.VolumetricSteps)
 	model.CustomUniforms["volumetricScattering"] = config.VolumetricScattering
@@ -275,24 +299,20 @@ func ApplyAdvancedRenderingConfig(model *Model, config AdvancedRenderingConfig)
 	model.CustomUniforms["scatteringColor"] = config.ScatteringColor
 
 	// SSAO settings
-	model.CustomUniforms["enableSSAO"] = config.EnableSSAO
 	model.CustomUniforms["ssaoIntensity"] = config.SSAOIntensity
 	model.CustomUniforms["ssaoRadius"] = config.SSAORadius
 	model.CustomUniforms["ssaoBias"] = config.SSAOBias
 	model.CustomUniforms["ssaoSampleCount"] = int32(config.SSAOSampleCount)
 
 	// Global Illumination
-	model.CustomUniforms["enableGlobalIllumination"] = config.EnableGlobalIllumination
 	model.CustomUniforms["giIntensity"] = config.GIIntensity
 	model.CustomUniforms["giBounces"] = int32(config.GIBounces)
 
 	// Bloom and HDR
-	model.CustomUniforms["enableBloom"] = config.EnableBloom
 	model.CustomUniforms["bloomThreshold"] = config.BloomThreshold
 	model.CustomUniforms["bloomIntensity"] = config.BloomIntensity
 	model.CustomUniforms["bloomRadius"] = config.BloomRadius
 
 	// Apply filtering settings
-	model.CustomUniforms["enableHighQualityFiltering"] = config.EnableHighQualityFiltering
 	model.CustomUniforms["filteringQuality"] = int32(config.FilteringQuality)
 }
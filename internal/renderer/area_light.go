@@ -0,0 +1,347 @@
+package renderer
+
+import (
+	"math"
+	"sync"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ltcTextureUnit is the first of the two units holding the LTC tables, kept
+// between the custom render textures and the block texture array
+const ltcTextureUnit = 13
+
+// ltcSize is the resolution of the LTC tables along roughness and view angle
+const ltcSize = 32
+
+// ltcSamples is the number of GGX samples used to fit each table entry
+const ltcSamples = 1024
+
+// CreateAreaLight creates a rectangle ("rect") or disk ("disk") light whose
+// surface is centered at position and emits along direction. The disk uses
+// width and height as its two diameters.
+func CreateAreaLight(mode string, position, direction, color mgl32.Vec3, intensity, width, height float32) *Light {
+	light := CreateLight()
+	light.Mode = mode
+	light.Position = position
+	light.Direction = direction.Normalize()
+	light.Color = color
+	light.Intensity = intensity
+	light.AreaWidth = width
+	light.AreaHeight = height
+	light.AmbientStrength = 0.05 // Interiors get their fill from the panel itself
+	return light
+}
+
+// IsArea reports whether the light is a rectangle or disk area light
+func (l *Light) IsArea() bool {
+	return l.Mode == "rect" || l.Mode == "disk"
+}
+
+// areaShape is the shader's light.areaShape: 0 for other lights, 1 for a rectangle, 2 for a disk
+func (l *Light) areaShape() int32 {
+	switch l.Mode {
+	case "rect":
+		return 1
+	case "disk":
+		return 2
+	}
+	return 0
+}
+
+// AreaAxes returns the half extents of an area light's surface. right, up and
+// Direction form a left-handed frame, so corners listed counter-clockwise in
+// right/up face away from the lit side as the LTC integral expects.
+func (l *Light) AreaAxes() (right, up mgl32.Vec3) {
+	right, up = lightFrame(l.Direction)
+	return right.Mul(l.AreaWidth / 2), up.Mul(l.AreaHeight / 2)
+}
+
+// lightFrame returns unit vectors across a light's direction, with right level
+// to the ground except for lights pointing straight up or down
+func lightFrame(direction mgl32.Vec3) (right, up mgl32.Vec3) {
+	facing := direction
+	if facing.Len() < 1e-6 {
+		facing = mgl32.Vec3{0, -1, 0}
+	}
+	facing = facing.Normalize()
+	reference := mgl32.Vec3{0, 1, 0}
+	if math.Abs(float64(facing.Dot(reference))) > 0.999 {
+		reference = mgl32.Vec3{0, 0, 1} // Ceiling and floor lights line up with the world Z axis
+	}
+	right = facing.Cross(reference).Normalize()
+	return right, right.Cross(facing)
+}
+
+// AreaOutline returns the edge of an area light's surface in world space,
+// using segments points for a disk. Rectangles always return their 4 corners.
+func (l *Light) AreaOutline(segments int) []mgl32.Vec3 {
+	right, up := l.AreaAxes()
+	if l.Mode == "rect" {
+		return []mgl32.Vec3{
+			l.Position.Sub(right).Sub(up),
+			l.Position.Add(right).Sub(up),
+			l.Position.Add(right).Add(up),
+			l.Position.Sub(right).Add(up),
+		}
+	}
+	points := make([]mgl32.Vec3, segments)
+	for i := range points {
+		angle := 2 * math.Pi * float64(i) / float64(segments)
+		s, c := math.Sincos(angle)
+		points[i] = l.Position.Add(right.Mul(float32(c))).Add(up.Mul(float32(s)))
+	}
+	return points
+}
+
+// ltcFit is a cosine lobe matching the GGX BRDF for one roughness and view
+// angle. The lobe is scaled by a and c across and along the tangent plane and
+// tilted to the average direction (dx, 0, dz); norm and fresnel are the BRDF's
+// directional albedo without and with the Schlick Fresnel weight.
+type ltcFit struct {
+	a, c, dx, dz  float32
+	norm, fresnel float32
+}
+
+// fitLTC fits a lobe to the GGX BRDF by matching its average direction and
+// spread, with the view at cosTheta from the normal in the tangent plane's +X
+func fitLTC(roughness, cosTheta float64) ltcFit {
+	alpha := math.Max(roughness, 0.08) // The forward shader's minimum roughness
+	alpha *= alpha
+	cosTheta = math.Min(math.Max(cosTheta, 1e-3), 1)
+	view := mgl32.Vec3{float32(math.Sqrt(1 - cosTheta*cosTheta)), 0, float32(cosTheta)}
+
+	type sample struct {
+		dir    [3]float64
+		weight float64
+	}
+	samples := make([]sample, 0, ltcSamples)
+	var sumW, sumF float64
+	var sumDir [3]float64
+	for i := 0; i < ltcSamples; i++ {
+		// Hammersley points importance sampled by the GGX distribution
+		u1 := (float64(i) + 0.5) / ltcSamples
+		u2 := radicalInverse(uint32(i))
+		cosH := math.Sqrt((1 - u1) / (1 + (alpha*alpha-1)*u1))
+		sinH := math.Sqrt(1 - cosH*cosH)
+		sinP, cosP := math.Sincos(2 * math.Pi * u2)
+		h := [3]float64{sinH * cosP, sinH * sinP, cosH}
+		vDotH := float64(view[0])*h[0] + float64(view[2])*h[2]
+		l := [3]float64{2*vDotH*h[0] - float64(view[0]), 2 * vDotH * h[1], 2*vDotH*h[2] - float64(view[2])}
+		if l[2] <= 0 || vDotH <= 0 {
+			continue
+		}
+		// BRDF * cos / pdf for GGX sampling with the separable Smith term
+		w := smithG1(cosTheta, alpha) * smithG1(l[2], alpha) * vDotH / (cosTheta * cosH)
+		samples = append(samples, sample{l, w})
+		sumW += w
+		sumF += w * math.Pow(1-vDotH, 5)
+		for k := range sumDir {
+			sumDir[k] += w * l[k]
+		}
+	}
+	if sumW <= 0 {
+		return ltcFit{a: 1, c: 1, dz: 1}
+	}
+
+	// The lobe stays in the view's plane by symmetry, so drop the Y component
+	dirLen := math.Hypot(sumDir[0], sumDir[2])
+	dx, dz := sumDir[0]/dirLen, sumDir[2]/dirLen
+
+	// Spread of the samples across and along the tangent direction of the lobe
+	var varX, varY float64
+	for _, s := range samples {
+		x := s.dir[0]*dz - s.dir[2]*dx
+		varX += s.weight * x * x
+		varY += s.weight * s.dir[1] * s.dir[1]
+	}
+	varX /= sumW
+	varY /= sumW
+
+	return ltcFit{
+		a:       float32(scaleForSpread(2 * varX)),
+		c:       float32(scaleForSpread(2 * varY)),
+		dx:      float32(dx),
+		dz:      float32(dz),
+		norm:    float32(sumW / ltcSamples),
+		fresnel: float32(sumF / ltcSamples),
+	}
+}
+
+// smithG1 is the GGX masking term for one direction
+func smithG1(cosTheta, alpha float64) float64 {
+	a2 := alpha * alpha
+	return 2 * cosTheta / (cosTheta + math.Sqrt(a2+(1-a2)*cosTheta*cosTheta))
+}
+
+// radicalInverse mirrors the bits of i behind the binary point
+func radicalInverse(i uint32) float64 {
+	i = (i << 16) | (i >> 16)
+	i = ((i & 0x55555555) << 1) | ((i & 0xAAAAAAAA) >> 1)
+	i = ((i & 0x33333333) << 2) | ((i & 0xCCCCCCCC) >> 2)
+	i = ((i & 0x0F0F0F0F) << 4) | ((i & 0xF0F0F0F0) >> 4)
+	i = ((i & 0x00FF00FF) << 8) | ((i & 0xFF00FF00) >> 8)
+	return float64(i) / (1 << 32)
+}
+
+// cosineSpread is the mean sin² of a cosine lobe whose slopes are scaled by s
+func cosineSpread(s float64) float64 {
+	k := s*s - 1
+	if math.Abs(k) < 1e-4 {
+		return s * s * (0.5 - k/3 + k*k/4)
+	}
+	return s * s * (1/k - math.Log1p(k)/(k*k))
+}
+
+// scaleForSpread inverts cosineSpread by bisection on a log scale
+func scaleForSpread(spread float64) float64 {
+	spread = math.Min(math.Max(spread, 1e-6), 1-1e-6)
+	lo, hi := math.Log(1e-4), math.Log(1e4)
+	for i := 0; i < 64; i++ {
+		mid := (lo + hi) / 2
+		if cosineSpread(math.Exp(mid)) < spread {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return math.Exp((lo + hi) / 2)
+}
+
+var (
+	ltcTablesOnce sync.Once
+	ltcMatrix     []float32 // a, c, dx, dz per entry
+	ltcAmplitude  []float32 // norm, fresnel per entry
+)
+
+// ltcTables fits every entry once, indexed by roughness along X and
+// sqrt(1 - cosTheta) along Y
+func ltcTables() (matrix, amplitude []float32) {
+	ltcTablesOnce.Do(func() {
+		ltcMatrix = make([]float32, 0, ltcSize*ltcSize*4)
+		ltcAmplitude = make([]float32, 0, ltcSize*ltcSize*2)
+		for y := 0; y < ltcSize; y++ {
+			v := float64(y) / (ltcSize - 1)
+			cosTheta := 1 - v*v
+			for x := 0; x < ltcSize; x++ {
+				fit := fitLTC(float64(x)/(ltcSize-1), cosTheta)
+				ltcMatrix = append(ltcMatrix, fit.a, fit.c, fit.dx, fit.dz)
+				ltcAmplitude = append(ltcAmplitude, fit.norm, fit.fresnel)
+			}
+		}
+	})
+	return ltcMatrix, ltcAmplitude
+}
+
+// bindLTCTextures uploads the LTC tables on first use and binds them to
+// ltcTextureUnit and the unit after it
+func (rend *OpenGLRenderer) bindLTCTextures() {
+	if rend.ltcTextures[0] == 0 {
+		matrix, amplitude := ltcTables()
+		gl.GenTextures(2, &rend.ltcTextures[0])
+		uploads := []struct {
+			internal int32
+			format   uint32
+			data     []float32
+		}{{gl.RGBA32F, gl.RGBA, matrix}, {gl.RG32F, gl.RG, amplitude}}
+		for i, u := range uploads {
+			gl.BindTexture(gl.TEXTURE_2D, rend.ltcTextures[i])
+			gl.TexImage2D(gl.TEXTURE_2D, 0, u.internal, ltcSize, ltcSize, 0, u.format, gl.FLOAT, gl.Ptr(u.data))
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		}
+	}
+	for i, tex := range rend.ltcTextures {
+		gl.ActiveTexture(gl.TEXTURE0 + ltcTextureUnit + uint32(i))
+		gl.BindTexture(gl.TEXTURE_2D, tex)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+}
+
+// ltcShaderSource shades rectangle and disk lights with linearly transformed
+// cosines, as ltc.glsl. The GGX lobe for the surface's roughness and view angle
+// is mapped to a clamped cosine, where the polygon's integral has a closed form.
+const ltcShaderSource = `
+uniform sampler2D ltcMatrix;    // Lobe scales a, c and direction dx, dz
+uniform sampler2D ltcAmplitude; // BRDF norm and Fresnel weight
+
+const float LTC_SIZE = 32.0;
+
+// ltcEdge integrates the cosine over one edge of a polygon on the unit sphere
+vec3 ltcEdge(vec3 v1, vec3 v2) {
+    float x = dot(v1, v2);
+    float y = abs(x);
+    float a = 0.8543985 + (0.4965155 + 0.0145206 * y) * y;
+    float b = 3.4175940 + (4.1616724 + y) * y;
+    float v = a / b;
+    float thetaSinTheta = x > 0.0 ? v : 0.5 * inversesqrt(max(1.0 - x * x, 1e-7)) - v;
+    return cross(v1, v2) * thetaSinTheta;
+}
+
+// ltcIntegrate returns the clamped cosine's integral over a polygon seen
+// through m, approximating the horizon clip with a sphere of the same vector form factor
+float ltcIntegrate(mat3 m, vec3 points[8], int count) {
+    vec3 L[8];
+    for (int i = 0; i < count; i++) {
+        L[i] = normalize(m * points[i]);
+    }
+    vec3 f = vec3(0.0);
+    for (int i = 0; i < count; i++) {
+        f += ltcEdge(L[i], L[(i + 1) % count]);
+    }
+    float len = length(f);
+    return max((len * len + f.z) / (len + 1.0), 0.0);
+}
+
+// areaLight returns the light reflected toward the viewer at P from a
+// rectangle (shape 1) or disk (shape 2) with half extents right and up
+vec3 areaLight(int shape, vec3 center, vec3 right, vec3 up, vec3 N, vec3 V, vec3 P,
+               vec3 albedo, vec3 F0, float metallic, float roughness, vec3 radiance) {
+    // One-sided: nothing reaches points behind the emitting face
+    if (dot(P - center, cross(up, right)) <= 0.0) {
+        return vec3(0.0);
+    }
+
+    vec3 points[8];
+    int count = 4;
+    if (shape == 1) {
+        points[0] = center - right - up;
+        points[1] = center + right - up;
+        points[2] = center + right + up;
+        points[3] = center - right + up;
+    } else {
+        // An octagon with the disk's area
+        count = 8;
+        for (int i = 0; i < 8; i++) {
+            float angle = float(i) * 0.78539816;
+            points[i] = center + (cos(angle) * right + sin(angle) * up) * 1.0539;
+        }
+    }
+    for (int i = 0; i < count; i++) {
+        points[i] -= P;
+    }
+
+    // Tangent frame with the view in the +X half of the XZ plane, as the tables were fitted
+    float NdotV = clamp(dot(N, V), 0.001, 1.0);
+    vec3 T1 = V - N * dot(N, V);
+    if (dot(T1, T1) < 1e-8) {
+        T1 = abs(N.y) < 0.999 ? cross(N, vec3(0.0, 1.0, 0.0)) : cross(N, vec3(1.0, 0.0, 0.0));
+    }
+    T1 = normalize(T1);
+    mat3 frame = transpose(mat3(T1, cross(N, T1), N));
+
+    vec2 uv = vec2(clamp(roughness, 0.0, 1.0), sqrt(1.0 - NdotV)) * ((LTC_SIZE - 1.0) / LTC_SIZE) + 0.5 / LTC_SIZE;
+    vec4 t1 = texture(ltcMatrix, uv);
+    vec2 t2 = texture(ltcAmplitude, uv).xy;
+    mat3 mInv = mat3(vec3(t1.w / t1.x, 0.0, t1.z),
+                     vec3(0.0, 1.0 / t1.y, 0.0),
+                     vec3(-t1.z / t1.x, 0.0, t1.w));
+
+    vec3 specular = ltcIntegrate(mInv * frame, points, count) * (F0 * t2.x + (1.0 - F0) * t2.y);
+    vec3 diffuse = ltcIntegrate(frame, points, count) * albedo * (1.0 - metallic);
+    return (diffuse + specular) * radiance;
+}
+`
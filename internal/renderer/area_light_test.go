@@ -0,0 +1,76 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestScaleForSpread(t *testing.T) {
+	for _, s := range []float64{0.01, 0.3, 1, 2.5, 40} {
+		if got := scaleForSpread(cosineSpread(s)); math.Abs(got-s)/s > 1e-4 {
+			t.Errorf("scaleForSpread(cosineSpread(%v)) = %v", s, got)
+		}
+	}
+	if got := cosineSpread(1); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("an unscaled cosine has mean sin² 1/2, got %v", got)
+	}
+}
+
+func TestFitLTC(t *testing.T) {
+	head := fitLTC(0.5, 1)
+	if math.Abs(float64(head.a-head.c)) > 0.05*float64(head.a) || math.Abs(float64(head.dx)) > 0.01 || head.dz < 0.999 {
+		t.Errorf("a head-on view should give a round lobe around the normal, got %+v", head)
+	}
+	if head.norm <= 0 || head.norm > 1.01 || head.fresnel <= 0 || head.fresnel >= head.norm {
+		t.Errorf("albedo terms out of range: norm %v fresnel %v", head.norm, head.fresnel)
+	}
+
+	smooth, rough := fitLTC(0.2, 0.7), fitLTC(0.9, 0.7)
+	if smooth.a >= rough.a || smooth.c >= rough.c {
+		t.Errorf("rougher surfaces should spread wider: %+v vs %+v", smooth, rough)
+	}
+	if smooth.dx >= 0 {
+		t.Errorf("a glossy lobe should lean toward the mirror direction, got dx %v", smooth.dx)
+	}
+	if grazing := fitLTC(0.5, 0.05); grazing.norm >= head.norm {
+		t.Errorf("masking should darken grazing views: %v >= %v", grazing.norm, head.norm)
+	}
+}
+
+func TestAreaOutline(t *testing.T) {
+	light := CreateAreaLight("rect", mgl32.Vec3{0, 3, 0}, mgl32.Vec3{0, -1, 0}, mgl32.Vec3{1, 1, 1}, 1, 2, 1)
+	corners := light.AreaOutline(32)
+	if len(corners) != 4 {
+		t.Fatalf("a rectangle has 4 corners, got %d", len(corners))
+	}
+	normal := corners[1].Sub(corners[0]).Cross(corners[2].Sub(corners[0]))
+	if normal.Normalize().Dot(light.Direction) > -0.999 {
+		t.Errorf("corners should wind away from the lit side, got normal %v", normal)
+	}
+	if w, h := corners[1].Sub(corners[0]).Len(), corners[2].Sub(corners[1]).Len(); math.Abs(float64(w-2)) > 1e-5 || math.Abs(float64(h-1)) > 1e-5 {
+		t.Errorf("rectangle is %vx%v, want 2x1", w, h)
+	}
+
+	disk := *light
+	disk.Mode = "disk"
+	for _, p := range disk.AreaOutline(16) {
+		if p.Sub(disk.Position).Dot(disk.Direction) > 1e-5 {
+			t.Errorf("disk point %v is off the surface plane", p)
+		}
+	}
+}
+
+func TestAreaLightRange(t *testing.T) {
+	small := CreateAreaLight("rect", mgl32.Vec3{}, mgl32.Vec3{0, -1, 0}, mgl32.Vec3{1, 1, 1}, 1, 1, 1)
+	r, bounded := lightRange(small)
+	if !bounded || r <= 0 {
+		t.Fatalf("area lights should be bounded, got %v, %v", r, bounded)
+	}
+	large := *small
+	large.AreaWidth = 4
+	if r2, _ := lightRange(&large); r2 <= r {
+		t.Errorf("a larger panel should reach further, got %v <= %v", r2, r)
+	}
+}
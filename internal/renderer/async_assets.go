@@ -0,0 +1,339 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"fmt"
+	"image"
+	"image/color"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/alitto/pond/v2"
+	"go.uber.org/zap"
+)
+
+// AssetState is the lifecycle stage of an asynchronously loaded asset
+type AssetState int
+
+const (
+	AssetLoading   AssetState = iota // Reading and decoding on a worker
+	AssetUploading                   // Waiting for its GPU upload on the main thread
+	AssetReady
+	AssetFailed
+)
+
+func (s AssetState) String() string {
+	switch s {
+	case AssetLoading:
+		return "loading"
+	case AssetUploading:
+		return "uploading"
+	case AssetReady:
+		return "ready"
+	}
+	return "failed"
+}
+
+// AssetHandle is a future for an asset loaded in the background. Callbacks run on
+// the main thread while the upload queue is processed, so they may touch GL state.
+type AssetHandle[T any] struct {
+	Name string
+
+	mu         sync.Mutex
+	state      AssetState
+	progress   float32
+	value      T
+	err        error
+	done       chan struct{}
+	uploads    *UploadQueue
+	onProgress []func(float32)
+	onReady    []func(T, error)
+}
+
+// NewAssetHandle creates a pending handle whose callbacks are delivered through uploads
+func NewAssetHandle[T any](name string, uploads *UploadQueue) *AssetHandle[T] {
+	return &AssetHandle[T]{Name: name, uploads: uploads, done: make(chan struct{})}
+}
+
+// ReadyAssetHandle returns an already completed handle, e.g. for a cache hit
+func ReadyAssetHandle[T any](name string, value T) *AssetHandle[T] {
+	h := NewAssetHandle[T](name, nil)
+	h.Complete(value, nil)
+	return h
+}
+
+// State returns the current lifecycle stage
+func (h *AssetHandle[T]) State() AssetState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+// Progress returns the load progress from 0 to 1
+func (h *AssetHandle[T]) Progress() float32 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.progress
+}
+
+// Result returns the asset and error once loading finished. ok is false while pending.
+func (h *AssetHandle[T]) Result() (value T, err error, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.value, h.err, h.state == AssetReady || h.state == AssetFailed
+}
+
+// Done is closed when the asset is ready or failed
+func (h *AssetHandle[T]) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until the asset finishes. Never call it from the main thread: the
+// final upload step runs there and would deadlock.
+func (h *AssetHandle[T]) Wait() (T, error) {
+	<-h.done
+	value, err, _ := h.Result()
+	return value, err
+}
+
+// OnProgress registers a callback for progress updates
+func (h *AssetHandle[T]) OnProgress(fn func(progress float32)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onProgress = append(h.onProgress, fn)
+}
+
+// OnReady registers a callback for completion. If the asset already finished it is
+// called immediately.
+func (h *AssetHandle[T]) OnReady(fn func(value T, err error)) {
+	h.mu.Lock()
+	if h.state == AssetReady || h.state == AssetFailed {
+		value, err := h.value, h.err
+		h.mu.Unlock()
+		fn(value, err)
+		return
+	}
+	h.onReady = append(h.onReady, fn)
+	h.mu.Unlock()
+}
+
+// SetProgress records progress from a worker and schedules the progress callbacks
+func (h *AssetHandle[T]) SetProgress(progress float32) {
+	h.mu.Lock()
+	h.progress = progress
+	callbacks := h.onProgress
+	h.mu.Unlock()
+
+	if len(callbacks) == 0 {
+		return
+	}
+	h.dispatch(func() {
+		for _, fn := range callbacks {
+			fn(progress)
+		}
+	})
+}
+
+// setState moves the handle to a new stage without completing it
+func (h *AssetHandle[T]) setState(state AssetState) {
+	h.mu.Lock()
+	h.state = state
+	h.mu.Unlock()
+}
+
+// Complete finishes the handle and runs the ready callbacks on the calling goroutine
+func (h *AssetHandle[T]) Complete(value T, err error) {
+	h.mu.Lock()
+	if h.state == AssetReady || h.state == AssetFailed {
+		h.mu.Unlock()
+		return
+	}
+	h.value, h.err = value, err
+	h.state = AssetReady
+	if err != nil {
+		h.state = AssetFailed
+	} else {
+		h.progress = 1
+	}
+	callbacks := h.onReady
+	h.onReady = nil
+	h.mu.Unlock()
+
+	close(h.done)
+	for _, fn := range callbacks {
+		fn(value, err)
+	}
+}
+
+// Fail completes the handle with an error from a worker, delivering it on the main thread
+func (h *AssetHandle[T]) Fail(err error) {
+	var zero T
+	h.dispatch(func() { h.Complete(zero, err) })
+}
+
+// dispatch runs fn on the main thread, or immediately without an upload queue
+func (h *AssetHandle[T]) dispatch(fn func()) {
+	if h.uploads == nil {
+		fn()
+		return
+	}
+	h.uploads.Push(fn)
+}
+
+// UploadQueue collects GPU work produced by loader goroutines for the main thread
+type UploadQueue struct {
+	mu    sync.Mutex
+	tasks []func()
+}
+
+// NewUploadQueue creates an empty upload queue
+func NewUploadQueue() *UploadQueue {
+	return &UploadQueue{}
+}
+
+// Push schedules a task to run on the main thread. Safe from any goroutine.
+func (q *UploadQueue) Push(task func()) {
+	q.mu.Lock()
+	q.tasks = append(q.tasks, task)
+	q.mu.Unlock()
+}
+
+// Len returns the number of tasks waiting
+func (q *UploadQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.tasks)
+}
+
+// Process runs queued tasks in order until the budget is used up. At least one task
+// runs per call so a single large upload can't stall the queue. A budget of 0 drains it.
+func (q *UploadQueue) Process(budget time.Duration) int {
+	start := time.Now()
+	ran := 0
+	for {
+		q.mu.Lock()
+		if len(q.tasks) == 0 {
+			q.mu.Unlock()
+			return ran
+		}
+		task := q.tasks[0]
+		q.tasks[0] = nil
+		q.tasks = q.tasks[1:]
+		q.mu.Unlock()
+
+		task()
+		ran++
+		if budget > 0 && time.Since(start) >= budget {
+			return ran
+		}
+	}
+}
+
+var (
+	assetPool     pond.Pool
+	assetPoolOnce sync.Once
+)
+
+// AssetPool returns the worker pool shared by all background asset loads
+func AssetPool() pond.Pool {
+	assetPoolOnce.Do(func() {
+		// Leave a core for the render thread
+		assetPool = pond.NewPool(max(runtime.NumCPU()-1, 1))
+	})
+	return assetPool
+}
+
+// Uploads returns the renderer's main-thread upload queue, processed at the start of every frame
+func (rend *OpenGLRenderer) Uploads() *UploadQueue {
+	return rend.uploads
+}
+
+// PendingUploads returns the number of GPU uploads still waiting for the main thread
+func (rend *OpenGLRenderer) PendingUploads() int {
+	return rend.uploads.Len()
+}
+
+// LoadTextureAsync loads a texture in the background (delegates to TextureManager)
+func (rend *OpenGLRenderer) LoadTextureAsync(filePath string, sampler SamplerSettings) *AssetHandle[uint32] {
+	return rend.textureManager.LoadTextureAsync(filePath, sampler, rend.uploads)
+}
+
+// loadMaterialTextureAsync shows a placeholder on the material until its texture is uploaded
+func (rend *OpenGLRenderer) loadMaterialTextureAsync(material *Material) *AssetHandle[uint32] {
+	placeholder := rend.placeholder()
+	rend.textureManager.AddReference(placeholder)
+	material.TextureID = placeholder
+
+	path := material.TexturePath
+	handle := rend.LoadTextureAsync(path, material.Sampler)
+	handle.OnReady(func(textureID uint32, err error) {
+		// The texture may have been replaced or removed while loading
+		if material.TextureID != placeholder || material.TexturePath != path {
+			if err == nil {
+				rend.textureManager.ReleaseTexture(textureID)
+			}
+			rend.textureManager.ReleaseTexture(placeholder)
+			return
+		}
+		if err != nil {
+			logger.Log.Warn("Failed to load texture for material, using default",
+				zap.String("material", material.Name),
+				zap.String("path", path),
+				zap.Error(err))
+			textureID = DefaultMaterial.TextureID
+			rend.textureManager.AddReference(textureID)
+		}
+		material.TextureID = textureID
+		rend.textureManager.ReleaseTexture(placeholder)
+	})
+	return handle
+}
+
+// placeholder returns the checkerboard texture shown while textures stream in
+func (rend *OpenGLRenderer) placeholder() uint32 {
+	if rend.placeholderTexture != 0 {
+		return rend.placeholderTexture
+	}
+	const size, cell = 64, 8
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			shade := uint8(96)
+			if (x/cell+y/cell)%2 == 0 {
+				shade = 160
+			}
+			img.SetRGBA(x, y, color.RGBA{shade, shade, shade, 255})
+		}
+	}
+	textureID, err := rend.textureManager.CreateTextureFromImage(img, "loading_placeholder")
+	if err != nil {
+		return DefaultMaterial.TextureID
+	}
+	rend.placeholderTexture = textureID
+	return textureID
+}
+
+// CreateSkyboxAsync decodes a skybox image on the asset pool and builds the skybox on
+// the main thread. Special paths (solid color, procedural) need no decoding.
+func CreateSkyboxAsync(texturePath string, uploads *UploadQueue) *AssetHandle[*Skybox] {
+	handle := NewAssetHandle[*Skybox](texturePath, uploads)
+	if texturePath == "dark_sky" || texturePath == "" || texturePath == ProceduralSkyboxPath {
+		uploads.Push(func() { handle.Complete(CreateSkybox(texturePath)) })
+		return handle
+	}
+
+	AssetPool().Submit(func() {
+		pixels, width, height, err := decodeSkyboxImage(texturePath)
+		if err != nil {
+			handle.Fail(fmt.Errorf("failed to load skybox texture %s: %v", texturePath, err))
+			return
+		}
+		handle.setState(AssetUploading)
+		handle.SetProgress(0.8)
+		uploads.Push(func() {
+			handle.Complete(newImageSkybox(uploadSkyboxTexture(pixels, width, height)), nil)
+		})
+	})
+	return handle
+}
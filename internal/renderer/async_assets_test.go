@@ -0,0 +1,91 @@
+package renderer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUploadQueueBudget(t *testing.T) {
+	q := NewUploadQueue()
+	order := []int{}
+	for i := 0; i < 3; i++ {
+		q.Push(func() {
+			order = append(order, i)
+			time.Sleep(5 * time.Millisecond)
+		})
+	}
+
+	// The first task always runs, even when it alone exceeds the budget
+	if ran := q.Process(time.Millisecond); ran != 1 {
+		t.Errorf("Expected 1 task within the budget, ran %d", ran)
+	}
+	if q.Len() != 2 {
+		t.Errorf("Expected 2 tasks left, got %d", q.Len())
+	}
+	if ran := q.Process(0); ran != 2 {
+		t.Errorf("A zero budget should drain the queue, ran %d", ran)
+	}
+	if len(order) != 3 || order[0] != 0 || order[2] != 2 {
+		t.Errorf("Tasks should run in push order, got %v", order)
+	}
+}
+
+func TestAssetHandleCallbacksRunOnQueue(t *testing.T) {
+	q := NewUploadQueue()
+	h := NewAssetHandle[string]("mesh", q)
+
+	var progress []float32
+	var result string
+	h.OnProgress(func(p float32) { progress = append(progress, p) })
+	h.OnReady(func(value string, err error) { result = value })
+
+	h.SetProgress(0.5)
+	q.Push(func() { h.Complete("loaded", nil) })
+	if len(progress) != 0 || result != "" {
+		t.Fatal("Callbacks must wait for the upload queue")
+	}
+	if h.State() != AssetLoading {
+		t.Errorf("Expected loading state, got %s", h.State())
+	}
+
+	q.Process(0)
+	if len(progress) != 1 || progress[0] != 0.5 {
+		t.Errorf("Expected one progress callback with 0.5, got %v", progress)
+	}
+	if result != "loaded" || h.State() != AssetReady || h.Progress() != 1 {
+		t.Errorf("Expected ready handle with value, got %q (%s, %v)", result, h.State(), h.Progress())
+	}
+
+	// Late subscribers are called immediately
+	late := false
+	h.OnReady(func(string, error) { late = true })
+	if !late {
+		t.Error("OnReady on a finished handle should run immediately")
+	}
+	select {
+	case <-h.Done():
+	default:
+		t.Error("Done channel should be closed")
+	}
+}
+
+func TestAssetHandleFail(t *testing.T) {
+	q := NewUploadQueue()
+	h := NewAssetHandle[int]("texture", q)
+	h.Fail(errors.New("missing file"))
+	if _, _, ok := h.Result(); ok {
+		t.Error("Failure should be delivered on the main thread, not immediately")
+	}
+
+	q.Process(0)
+	if _, err, ok := h.Result(); !ok || err == nil || h.State() != AssetFailed {
+		t.Errorf("Expected a failed handle, got err=%v state=%s", err, h.State())
+	}
+
+	// Completing twice keeps the first result
+	h.Complete(5, nil)
+	if value, _ := h.Wait(); value != 0 {
+		t.Errorf("A finished handle should not change, got %d", value)
+	}
+}
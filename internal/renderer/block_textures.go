@@ -0,0 +1,156 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// blockTextureUnit is kept clear of the units used by material asset textures
+const blockTextureUnit = 15
+
+// customTextureUnit is the first unit for render textures in Model.CustomUniforms
+const customTextureUnit = 8
+
+// BlockSampler keeps block textures crisp up close and mipmapped in the distance
+var BlockSampler = SamplerSettings{Wrap: WrapRepeat, Filter: FilterNearest}
+
+// BlockAttributes selects the texture array layers and surface values of a voxel
+// block. Layers are top, side and bottom; -1 falls back to the material texture.
+type BlockAttributes struct {
+	Layers    [3]float32
+	Metallic  float32 // Negative keeps the material's value
+	Roughness float32 // Negative keeps the material's value
+}
+
+// NoBlockAttributes draws a voxel with the model's own material
+var NoBlockAttributes = BlockAttributes{Layers: [3]float32{-1, -1, -1}, Metallic: -1, Roughness: -1}
+
+// LoadTextureArray loads images into the layers of a TEXTURE_2D_ARRAY, in order.
+// Every layer is resampled to the size of the first image. Arrays are cached by
+// their path list and released with ReleaseTexture like any other texture.
+func (tm *TextureManager) LoadTextureArray(paths []string, sampler SamplerSettings) (uint32, error) {
+	if len(paths) == 0 {
+		return 0, fmt.Errorf("texture array needs at least one layer")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	cacheKey := "array:" + sampler.cacheKey(strings.Join(paths, "|"))
+	if textureID, exists := tm.textureCache[cacheKey]; exists {
+		tm.textureRefCount[textureID]++
+		tm.stats.CacheHits++
+		return textureID, nil
+	}
+	tm.stats.CacheMisses++
+
+	images := make([]*image.RGBA, len(paths))
+	for i, path := range paths {
+		rgba, err := decodeImageFile(path)
+		if err != nil {
+			return 0, fmt.Errorf("texture array layer %d (%s): %v", i, path, err)
+		}
+		images[i] = rgba
+	}
+
+	width, height, pixels := packTextureArrayLayers(images)
+	textureID, size := uploadTextureArray(width, height, len(images), pixels, sampler)
+	tm.addToCache(cacheKey, textureID, size)
+	return textureID, nil
+}
+
+// packTextureArrayLayers resamples images to the first one's size and stacks them.
+// Rows are flipped so v=0 is the bottom of the image, matching the cube's side faces.
+func packTextureArrayLayers(images []*image.RGBA) (int, int, []byte) {
+	width, height := images[0].Rect.Dx(), images[0].Rect.Dy()
+	layerSize := width * height * 4
+	pixels := make([]byte, layerSize*len(images))
+
+	for layer, img := range images {
+		srcW, srcH := img.Rect.Dx(), img.Rect.Dy()
+		for y := 0; y < height; y++ {
+			srcY := (height - 1 - y) * srcH / height
+			for x := 0; x < width; x++ {
+				// Nearest neighbour keeps pixel-art blocks sharp
+				src := img.PixOffset(img.Rect.Min.X+x*srcW/width, img.Rect.Min.Y+srcY)
+				dst := layer*layerSize + (y*width+x)*4
+				copy(pixels[dst:dst+4], img.Pix[src:src+4])
+			}
+		}
+	}
+	return width, height, pixels
+}
+
+// uploadTextureArray creates the GL texture array and returns its ID and approximate GPU size
+func uploadTextureArray(width, height, layers int, pixels []byte, sampler SamplerSettings) (uint32, int64) {
+	var textureID uint32
+	gl.GenTextures(1, &textureID)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, textureID)
+	gl.TexImage3D(gl.TEXTURE_2D_ARRAY, 0, sampler.glInternalFormat(), int32(width), int32(height), int32(layers),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+
+	size := int64(len(pixels))
+	hasMips := !sampler.NoMipmaps
+	if hasMips {
+		gl.GenerateMipmap(gl.TEXTURE_2D_ARRAY)
+		size = size * 4 / 3
+	}
+	applySampler(gl.TEXTURE_2D_ARRAY, sampler, hasMips)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+	return textureID, size
+}
+
+// loadBlockTextures builds the model's block texture array on first use
+func (rend *OpenGLRenderer) loadBlockTextures(model *Model) error {
+	if len(model.BlockTexturePaths) == 0 || model.BlockTextureArray != 0 {
+		return nil
+	}
+	sampler := BlockSampler
+	sampler.ColorSpace = model.BlockTextureColorSpace
+	textureID, err := rend.textureManager.LoadTextureArray(model.BlockTexturePaths, sampler)
+	if err != nil {
+		return err
+	}
+	model.BlockTextureArray = textureID
+	return nil
+}
+
+// createBlockAttributeBuffer uploads BlockAttributes to locations 8 (layers) and 9
+// (metallic, roughness): one entry per instance for instanced models, otherwise per vertex.
+// Call with the model's VAO bound.
+func createBlockAttributeBuffer(model *Model) {
+	var vbo uint32
+	gl.GenBuffers(1, &vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+
+	stride := int32(unsafe.Sizeof(BlockAttributes{}))
+	gl.BufferData(gl.ARRAY_BUFFER, len(model.BlockAttributes)*int(stride), gl.Ptr(model.BlockAttributes), gl.STATIC_DRAW)
+
+	divisor := uint32(0)
+	if model.IsInstanced {
+		divisor = 1
+	}
+	gl.EnableVertexAttribArray(8)
+	gl.VertexAttribPointerWithOffset(8, 3, gl.FLOAT, false, stride, 0)
+	gl.VertexAttribDivisor(8, divisor)
+	gl.EnableVertexAttribArray(9)
+	gl.VertexAttribPointerWithOffset(9, 2, gl.FLOAT, false, stride, 12)
+	gl.VertexAttribDivisor(9, divisor)
+
+	model.BlockAttributeVBO = vbo
+}
+
+// bindBlockTextures binds the model's block texture array for the current draw
+func bindBlockTextures(uc *UniformCache, model *Model) {
+	if model.BlockTextureArray == 0 {
+		return
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + blockTextureUnit)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, model.BlockTextureArray)
+	uc.SetInt("blockTextures", blockTextureUnit)
+	gl.ActiveTexture(gl.TEXTURE0)
+}
@@ -0,0 +1,32 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPackTextureArrayLayers(t *testing.T) {
+	// 2x2 layer with a red top row
+	first := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	first.SetRGBA(0, 0, color.RGBA{255, 0, 0, 255})
+	first.SetRGBA(1, 0, color.RGBA{255, 0, 0, 255})
+
+	// 4x4 layer that must be resampled, green in its top-left quadrant
+	second := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	second.SetRGBA(0, 0, color.RGBA{0, 255, 0, 255})
+
+	width, height, pixels := packTextureArrayLayers([]*image.RGBA{first, second})
+	if width != 2 || height != 2 || len(pixels) != 2*2*2*4 {
+		t.Fatalf("Expected two 2x2 layers, got %dx%d with %d bytes", width, height, len(pixels))
+	}
+
+	// Rows are flipped, so the image's top row is the last row of the layer
+	if pixels[0] != 0 || pixels[2*4] != 255 || pixels[3*4] != 255 {
+		t.Errorf("First layer not flipped: %v", pixels[:16])
+	}
+	second0 := pixels[16:]
+	if second0[2*4+1] != 255 || second0[3*4+1] != 0 {
+		t.Errorf("Second layer not resampled: %v", second0)
+	}
+}
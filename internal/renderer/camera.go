@@ -10,6 +10,33 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// ProjectionMode selects how a camera projects the scene
+type ProjectionMode int
+
+const (
+	ProjectionPerspective  ProjectionMode = iota
+	ProjectionOrthographic                // Parallel projection sized by OrthoSize
+)
+
+// String returns the name scenes save the mode under
+func (m ProjectionMode) String() string {
+	if m == ProjectionOrthographic {
+		return "orthographic"
+	}
+	return "perspective"
+}
+
+// ParseProjectionMode reads a mode saved by String; anything else is perspective
+func ParseProjectionMode(name string) ProjectionMode {
+	if name == ProjectionOrthographic.String() {
+		return ProjectionOrthographic
+	}
+	return ProjectionPerspective
+}
+
+// DefaultOrthoSize is the half-height of an orthographic view in world units
+const DefaultOrthoSize = 10.0
+
 type Camera struct {
 	// HOT DATA - Accessed every frame for view/projection calculations
 	Position   mgl32.Vec3 // Camera position in world space
@@ -32,9 +59,24 @@ type Camera struct {
 	InvertMouse  bool       // Invert mouse Y axis
 	firstMouse   bool       // First mouse movement flag
 
+	// Projection
+	ProjectionMode ProjectionMode
+	OrthoSize      float32 // Half the visible height in world units when orthographic
+
+	// Controller moves the camera from input in place of the fly controls when set
+	Controller CameraController
+
 	// Identification
 	Name     string // Camera name for editor identification
 	IsActive bool   // Whether this is the active camera
+
+	// Multi-view rendering
+	Viewport ViewportRect // Window area drawn by RenderViews; zero is the whole window
+	Depth    int          // RenderViews draws lower depths first, so higher ones overlay them
+
+	// Lens effects, applied after bloom and FXAA
+	DepthOfField DepthOfFieldSettings
+	MotionBlur   MotionBlurSettings
 }
 
 type Plane struct {
@@ -65,6 +107,10 @@ func NewDefaultCamera(height int32, width int32) *Camera {
 		AspectRatio: float32(height) / float32(width),
 		firstMouse:  true,
 		InvertMouse: true,
+		OrthoSize:   DefaultOrthoSize,
+
+		DepthOfField: DefaultDepthOfFieldSettings(),
+		MotionBlur:   DefaultMotionBlurSettings(),
 	}
 	camera.updateCameraVectors()
 	camera.UpdateProjection()
@@ -72,7 +118,39 @@ func NewDefaultCamera(height int32, width int32) *Camera {
 }
 
 func (c *Camera) UpdateProjection() {
-	c.Projection = mgl32.Perspective(mgl32.DegToRad(c.Fov), c.AspectRatio, c.Near, c.Far)
+	if c.ProjectionMode == ProjectionOrthographic {
+		h := c.orthoHalfHeight()
+		w := h * c.AspectRatio
+		c.Projection = mgl32.Ortho(-w, w, -h, h, c.Near, c.Far)
+		return
+	}
+	c.Projection = c.perspectiveProjection()
+}
+
+// perspectiveProjection returns the perspective projection for the camera's
+// field of view, whatever its mode. The skybox uses it so it still fills an
+// orthographic view.
+func (c *Camera) perspectiveProjection() mgl32.Mat4 {
+	return mgl32.Perspective(mgl32.DegToRad(c.Fov), c.AspectRatio, c.Near, c.Far)
+}
+
+func (c *Camera) orthoHalfHeight() float32 {
+	if c.OrthoSize <= 0 {
+		return DefaultOrthoSize
+	}
+	return c.OrthoSize
+}
+
+// SetProjectionMode switches between perspective and orthographic projection
+func (c *Camera) SetProjectionMode(mode ProjectionMode) {
+	c.ProjectionMode = mode
+	c.UpdateProjection()
+}
+
+// SetOrthoSize sets the half-height of the orthographic view in world units
+func (c *Camera) SetOrthoSize(size float32) {
+	c.OrthoSize = size
+	c.UpdateProjection()
 }
 
 // Setter methods that automatically update projection
@@ -184,6 +262,13 @@ func (c *Camera) ProcessMouseMovement(xoffset, yoffset float32, constrainPitch b
 	MarkFrustumDirty()
 }
 
+// SetRotation sets yaw and pitch in degrees and updates the direction vectors
+func (c *Camera) SetRotation(yaw, pitch float32) {
+	c.Yaw, c.Pitch = yaw, pitch
+	c.updateCameraVectors()
+	MarkFrustumDirty()
+}
+
 func (c *Camera) LookAt(target mgl32.Vec3) {
 	direction := c.Position.Sub(target).Normalize()
 	c.Yaw = float32(math.Atan2(float64(direction.Z()), float64(direction.X())))
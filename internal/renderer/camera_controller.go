@@ -0,0 +1,346 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CameraInput is one frame of input for a CameraController
+type CameraInput struct {
+	Move mgl32.Vec3 // Right, up and forward axes, each -1..1
+	Look mgl32.Vec2 // Mouse movement in pixels while looking around, y up
+	Pan  mgl32.Vec2 // Mouse movement in pixels while panning, y up
+	Zoom float32    // Scroll steps; positive zooms in
+	Fast bool       // Move faster, like Shift with the fly controls
+}
+
+// CameraController drives a camera from input each frame
+type CameraController interface {
+	Update(cam *Camera, input CameraInput, deltaTime float32)
+	Settings() CameraControllerSettings
+}
+
+// CameraMode names a built-in camera controller
+type CameraMode string
+
+const (
+	CameraModeFly    CameraMode = "fly"
+	CameraModeOrbit  CameraMode = "orbit"
+	CameraModeFollow CameraMode = "follow"
+	CameraModeRTS    CameraMode = "rts"
+)
+
+// CameraModes lists the built-in controllers, for editor pickers
+var CameraModes = []CameraMode{CameraModeFly, CameraModeOrbit, CameraModeFollow, CameraModeRTS}
+
+// CameraControllerSettings configures a built-in controller, as saved in scenes
+type CameraControllerSettings struct {
+	Mode      CameraMode `json:"mode"`
+	Target    [3]float32 `json:"target"`              // Orbit target, RTS focus point, or offset from the followed model
+	Follow    string     `json:"follow,omitempty"`    // Name of the model followed in follow mode
+	Distance  float32    `json:"distance,omitempty"`  // Orbit and follow arm length, RTS height
+	Yaw       float32    `json:"yaw"`                 // Degrees
+	Pitch     float32    `json:"pitch"`               // Degrees; the RTS camera looks this far below the horizon
+	Stiffness float32    `json:"stiffness,omitempty"` // How fast the follow camera catches up; 0 snaps
+}
+
+// NewCameraController builds the controller for settings, following target in
+// follow mode. Fly mode returns nil, which leaves the camera on the fly controls.
+func NewCameraController(s CameraControllerSettings, target *Model) CameraController {
+	point := mgl32.Vec3{s.Target[0], s.Target[1], s.Target[2]}
+	switch s.Mode {
+	case CameraModeOrbit:
+		c := NewOrbitController(point, s.Distance)
+		c.Yaw, c.Pitch = s.Yaw, s.Pitch
+		return c
+	case CameraModeFollow:
+		c := NewFollowController(target, s.Distance)
+		c.Offset = point
+		c.Yaw, c.Pitch = s.Yaw, s.Pitch
+		c.Stiffness = s.Stiffness
+		return c
+	case CameraModeRTS:
+		c := NewRTSController(point, s.Distance)
+		c.Yaw = s.Yaw
+		if s.Pitch != 0 {
+			c.Pitch = s.Pitch
+		}
+		return c
+	}
+	return nil
+}
+
+// CameraControllerMode returns the mode of a camera's controller
+func CameraControllerMode(cam *Camera) CameraMode {
+	if cam.Controller == nil {
+		return CameraModeFly
+	}
+	return cam.Controller.Settings().Mode
+}
+
+const (
+	defaultRotateSpeed = 0.3   // Degrees per pixel
+	defaultZoomSpeed   = 0.1   // Fraction of the distance per scroll step
+	panPerPixel        = 0.002 // Fraction of the distance per pixel
+)
+
+// OrbitController circles a target point. Look input swings the camera around
+// it, pan input slides the target across the view and zoom changes the distance.
+type OrbitController struct {
+	Target      mgl32.Vec3
+	Distance    float32
+	Yaw, Pitch  float32 // Degrees; the camera's view direction
+	MinDistance float32
+	MaxDistance float32
+	RotateSpeed float32 // Degrees per pixel
+	ZoomSpeed   float32 // Fraction of the distance per scroll step
+}
+
+// NewOrbitController returns an orbit controller looking at target from distance
+func NewOrbitController(target mgl32.Vec3, distance float32) *OrbitController {
+	if distance <= 0 {
+		distance = 10
+	}
+	return &OrbitController{
+		Target:      target,
+		Distance:    distance,
+		Yaw:         -90,
+		Pitch:       -20,
+		MinDistance: 0.5,
+		MaxDistance: 5000,
+		RotateSpeed: defaultRotateSpeed,
+		ZoomSpeed:   defaultZoomSpeed,
+	}
+}
+
+func (o *OrbitController) Update(cam *Camera, input CameraInput, deltaTime float32) {
+	o.Yaw += input.Look.X() * o.RotateSpeed
+	o.Pitch = mgl32.Clamp(o.Pitch+lookPitch(cam, input.Look.Y())*o.RotateSpeed, -89, 89)
+	o.Distance = cam.zoom(o.Distance, input.Zoom, o.ZoomSpeed, o.MinDistance, o.MaxDistance)
+
+	if input.Pan != (mgl32.Vec2{}) {
+		right := cam.Front.Cross(cam.Up).Normalize()
+		step := o.Distance * panPerPixel
+		o.Target = o.Target.Sub(right.Mul(input.Pan.X() * step)).Sub(cam.Up.Mul(input.Pan.Y() * step))
+	}
+	cam.orbit(o.Target, o.Yaw, o.Pitch, o.Distance)
+}
+
+func (o *OrbitController) Settings() CameraControllerSettings {
+	return CameraControllerSettings{
+		Mode:     CameraModeOrbit,
+		Target:   o.Target,
+		Distance: o.Distance,
+		Yaw:      o.Yaw,
+		Pitch:    o.Pitch,
+	}
+}
+
+// FollowController trails a model on a spring arm pointing back from it along
+// Yaw/Pitch; look input swings the arm. The arm shortens when a model's
+// bounding sphere lies between the followed point and the camera.
+type FollowController struct {
+	Target      *Model
+	Obstacles   func() []*Model // Models the arm can't pass through; nil disables collisions
+	Offset      mgl32.Vec3      // Point followed, relative to the target's position
+	Distance    float32
+	Yaw, Pitch  float32 // Degrees; the camera's view direction
+	Stiffness   float32 // How fast the camera catches up, per second; 0 snaps
+	MinDistance float32
+	MaxDistance float32
+	RotateSpeed float32
+	ZoomSpeed   float32
+
+	position mgl32.Vec3 // Where the spring has pulled the camera
+	placed   bool
+}
+
+// NewFollowController returns a follow controller trailing target at distance
+func NewFollowController(target *Model, distance float32) *FollowController {
+	if distance <= 0 {
+		distance = 8
+	}
+	return &FollowController{
+		Target:      target,
+		Offset:      mgl32.Vec3{0, 1.5, 0},
+		Distance:    distance,
+		Yaw:         -90,
+		Pitch:       -15,
+		Stiffness:   8,
+		MinDistance: 1,
+		MaxDistance: 500,
+		RotateSpeed: defaultRotateSpeed,
+		ZoomSpeed:   defaultZoomSpeed,
+	}
+}
+
+func (f *FollowController) Update(cam *Camera, input CameraInput, deltaTime float32) {
+	if f.Target == nil {
+		return
+	}
+	f.Yaw += input.Look.X() * f.RotateSpeed
+	f.Pitch = mgl32.Clamp(f.Pitch+lookPitch(cam, input.Look.Y())*f.RotateSpeed, -89, 89)
+	f.Distance = cam.zoom(f.Distance, input.Zoom, f.ZoomSpeed, f.MinDistance, f.MaxDistance)
+
+	focus := f.Target.Position.Add(f.Offset)
+	cam.orbit(focus, f.Yaw, f.Pitch, f.Distance)
+	arm := f.armLength(focus, cam.Front.Mul(-1), f.Distance)
+	if arm < f.Distance {
+		cam.orbit(focus, f.Yaw, f.Pitch, arm)
+	}
+	if !f.placed || f.Stiffness <= 0 {
+		f.position, f.placed = cam.Position, true
+		return
+	}
+
+	// Exponential approach keeps the lag independent of frame rate
+	pull := 1 - float32(math.Exp(float64(-f.Stiffness*deltaTime)))
+	f.position = f.position.Add(cam.Position.Sub(f.position).Mul(pull))
+	// The spring eases back out but never lags behind an obstacle
+	if back := f.position.Sub(focus); arm < f.Distance && back.Len() > arm {
+		f.position = focus.Add(back.Normalize().Mul(arm))
+	}
+	cam.Position = f.position
+	if dir := focus.Sub(f.position); dir.Len() > 1e-4 {
+		cam.Front = dir.Normalize()
+		cam.Right = cam.WorldUp.Cross(cam.Front).Normalize()
+		cam.Up = cam.Front.Cross(cam.Right).Normalize()
+	}
+}
+
+// followArmPadding is the gap kept between the camera and what blocks the arm
+const followArmPadding = 0.2
+
+// armLength returns how far the arm reaches from focus along dir before it
+// enters an obstacle's bounding sphere. Spheres holding the focus point are
+// skipped, as the camera can't get out of them along the arm anyway.
+func (f *FollowController) armLength(focus, dir mgl32.Vec3, length float32) float32 {
+	if f.Obstacles == nil {
+		return length
+	}
+	reach := length
+	for _, m := range f.Obstacles() {
+		r := m.BoundingSphereRadius
+		toCenter := m.BoundingSphereCenter.Sub(focus)
+		if m == f.Target || r <= 0 || toCenter.LenSqr() <= r*r {
+			continue
+		}
+		along := toCenter.Dot(dir)
+		offAxisSq := toCenter.LenSqr() - along*along
+		if along <= 0 || offAxisSq >= r*r {
+			continue
+		}
+		reach = min(reach, along-float32(math.Sqrt(float64(r*r-offAxisSq)))-followArmPadding)
+	}
+	return max(reach, min(length, followArmPadding))
+}
+
+func (f *FollowController) Settings() CameraControllerSettings {
+	s := CameraControllerSettings{
+		Mode:      CameraModeFollow,
+		Target:    f.Offset,
+		Distance:  f.Distance,
+		Yaw:       f.Yaw,
+		Pitch:     f.Pitch,
+		Stiffness: f.Stiffness,
+	}
+	if f.Target != nil {
+		s.Follow = f.Target.Name
+	}
+	return s
+}
+
+// RTSController looks down on a focus point from above. Move input and panning
+// slide the focus across the ground relative to the view, look input turns the
+// view and zoom changes the height.
+type RTSController struct {
+	Focus       mgl32.Vec3
+	Height      float32 // Above the focus point
+	Yaw         float32 // Degrees
+	Pitch       float32 // Degrees below the horizon
+	PanSpeed    float32 // Units per second at height 10, scaled with the height
+	MinHeight   float32
+	MaxHeight   float32
+	RotateSpeed float32
+	ZoomSpeed   float32
+}
+
+// NewRTSController returns an RTS controller over focus at height
+func NewRTSController(focus mgl32.Vec3, height float32) *RTSController {
+	if height <= 0 {
+		height = 30
+	}
+	return &RTSController{
+		Focus:       focus,
+		Height:      height,
+		Yaw:         -90,
+		Pitch:       55,
+		PanSpeed:    15,
+		MinHeight:   2,
+		MaxHeight:   2000,
+		RotateSpeed: defaultRotateSpeed,
+		ZoomSpeed:   defaultZoomSpeed,
+	}
+}
+
+func (r *RTSController) Update(cam *Camera, input CameraInput, deltaTime float32) {
+	r.Yaw += input.Look.X() * r.RotateSpeed
+	r.Height = cam.zoom(r.Height, input.Zoom, r.ZoomSpeed, r.MinHeight, r.MaxHeight)
+
+	yaw := float64(mgl32.DegToRad(r.Yaw))
+	forward := mgl32.Vec3{float32(math.Cos(yaw)), 0, float32(math.Sin(yaw))}
+	right := mgl32.Vec3{-forward.Z(), 0, forward.X()}
+
+	speed := r.PanSpeed * r.Height / 10
+	if input.Fast {
+		speed *= 2.5
+	}
+	move := right.Mul(input.Move.X()).Add(forward.Mul(input.Move.Z())).Mul(speed * deltaTime)
+	drag := right.Mul(input.Pan.X()).Add(forward.Mul(input.Pan.Y())).Mul(r.Height * panPerPixel)
+	r.Focus = r.Focus.Add(move).Sub(drag)
+
+	pitch := mgl32.Clamp(r.Pitch, 5, 89)
+	distance := r.Height / float32(math.Sin(float64(mgl32.DegToRad(pitch))))
+	cam.orbit(r.Focus, r.Yaw, -pitch, distance)
+}
+
+func (r *RTSController) Settings() CameraControllerSettings {
+	return CameraControllerSettings{
+		Mode:     CameraModeRTS,
+		Target:   r.Focus,
+		Distance: r.Height,
+		Yaw:      r.Yaw,
+		Pitch:    r.Pitch,
+	}
+}
+
+// orbit places the camera distance back from target, looking at it along yaw and pitch
+func (c *Camera) orbit(target mgl32.Vec3, yaw, pitch, distance float32) {
+	c.Yaw, c.Pitch = yaw, pitch
+	c.updateCameraVectors()
+	c.Position = target.Sub(c.Front.Mul(distance))
+	MarkFrustumDirty()
+}
+
+// zoom scales distance by the scroll steps within [min, max]. Orthographic
+// cameras scale their size by the same amount so zooming works in both modes.
+func (c *Camera) zoom(distance, steps, speed, min, max float32) float32 {
+	if steps == 0 {
+		return distance
+	}
+	zoomed := mgl32.Clamp(distance*float32(math.Pow(float64(1-speed), float64(steps))), min, max)
+	if c.ProjectionMode == ProjectionOrthographic && distance > 0 {
+		c.SetOrthoSize(c.orthoHalfHeight() * zoomed / distance)
+	}
+	return zoomed
+}
+
+// lookPitch turns vertical look input into a pitch change, honouring InvertMouse
+// the way the fly controls do
+func lookPitch(cam *Camera, dy float32) float32 {
+	if cam.InvertMouse {
+		return -dy
+	}
+	return dy
+}
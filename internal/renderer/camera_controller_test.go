@@ -0,0 +1,131 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestOrthographicProjection(t *testing.T) {
+	cam := NewDefaultCamera(800, 600)
+	cam.OrthoSize = 5
+	cam.SetProjectionMode(ProjectionOrthographic)
+
+	// A point at the top edge of the view stays there at any depth
+	cam.Position = mgl32.Vec3{0, 0, 0}
+	cam.Front = mgl32.Vec3{0, 0, -1}
+	cam.Up = mgl32.Vec3{0, 1, 0}
+	for _, depth := range []float32{1, 50, 500} {
+		ndc := mgl32.TransformCoordinate(mgl32.Vec3{0, 5, -depth}, cam.GetViewProjection())
+		if d := ndc.Y() - 1; d > 1e-4 || d < -1e-4 {
+			t.Errorf("top edge at depth %v projects to y = %v, want 1", depth, ndc.Y())
+		}
+	}
+
+	cam.SetProjectionMode(ProjectionPerspective)
+	if cam.Projection[11] != -1 {
+		t.Error("switching back should restore the perspective projection")
+	}
+}
+
+func TestOrbitControllerKeepsDistance(t *testing.T) {
+	cam := NewDefaultCamera(800, 600)
+	target := mgl32.Vec3{5, 2, -3}
+	orbit := NewOrbitController(target, 12)
+
+	orbit.Update(cam, CameraInput{Look: mgl32.Vec2{100, 40}}, 0.016)
+	if d := cam.Position.Sub(target).Len(); d < 11.99 || d > 12.01 {
+		t.Errorf("camera is %v from the target, want 12", d)
+	}
+	if toTarget := target.Sub(cam.Position).Normalize(); toTarget.Sub(cam.Front).Len() > 1e-4 {
+		t.Errorf("camera looks along %v, want %v", cam.Front, toTarget)
+	}
+
+	orbit.Update(cam, CameraInput{Zoom: 2}, 0.016)
+	if orbit.Distance >= 12 {
+		t.Errorf("zooming in left the distance at %v", orbit.Distance)
+	}
+}
+
+func TestFollowControllerSpring(t *testing.T) {
+	cam := NewDefaultCamera(800, 600)
+	target := &Model{Name: "player"}
+	follow := NewFollowController(target, 6)
+	follow.Update(cam, CameraInput{}, 0.016)
+	start := cam.Position
+
+	// The camera lags behind a jump, then settles on the arm
+	target.Position = mgl32.Vec3{10, 0, 0}
+	follow.Update(cam, CameraInput{}, 0.016)
+	moved := cam.Position.Sub(start).Len()
+	if moved <= 0 || moved >= 10 {
+		t.Errorf("camera moved %v after one frame, want part of the way", moved)
+	}
+	for range 200 {
+		follow.Update(cam, CameraInput{}, 0.016)
+	}
+	if want := start.Add(mgl32.Vec3{10, 0, 0}); cam.Position.Sub(want).Len() > 1e-3 {
+		t.Errorf("camera settled at %v, want %v", cam.Position, want)
+	}
+
+	if s := follow.Settings(); s.Follow != "player" || s.Mode != CameraModeFollow {
+		t.Errorf("settings = %+v", s)
+	}
+}
+
+func TestRTSControllerPansOnGround(t *testing.T) {
+	cam := NewDefaultCamera(800, 600)
+	rts := NewRTSController(mgl32.Vec3{0, 0, 0}, 20)
+	rts.Update(cam, CameraInput{}, 0.016)
+	if y := cam.Position.Y(); y < 19.99 || y > 20.01 {
+		t.Errorf("camera height = %v, want 20", y)
+	}
+
+	rts.Update(cam, CameraInput{Move: mgl32.Vec3{1, 0, 1}}, 0.5)
+	if rts.Focus.Y() != 0 {
+		t.Errorf("panning moved the focus off the ground to %v", rts.Focus)
+	}
+	if rts.Focus.Len() == 0 {
+		t.Error("move input should pan the focus")
+	}
+}
+
+func TestNewCameraControllerRoundTrip(t *testing.T) {
+	for _, s := range []CameraControllerSettings{
+		{Mode: CameraModeOrbit, Target: [3]float32{1, 2, 3}, Distance: 7, Yaw: 30, Pitch: -10},
+		{Mode: CameraModeRTS, Target: [3]float32{4, 0, 4}, Distance: 40, Yaw: -45, Pitch: 60},
+	} {
+		if got := NewCameraController(s, nil).Settings(); got != s {
+			t.Errorf("settings round trip = %+v, want %+v", got, s)
+		}
+	}
+	if NewCameraController(CameraControllerSettings{Mode: CameraModeFly}, nil) != nil {
+		t.Error("fly mode should leave the camera without a controller")
+	}
+}
+
+func TestFollowControllerArmCollision(t *testing.T) {
+	cam := NewDefaultCamera(800, 600)
+	target := &Model{Name: "player"}
+	follow := NewFollowController(target, 10)
+	follow.Offset = mgl32.Vec3{}
+	follow.Stiffness = 0
+	follow.Update(cam, CameraInput{}, 0.016)
+	behind := cam.Front.Mul(-1)
+
+	// A wall 4 units behind the player, and terrain around the player itself
+	wall := &Model{Name: "wall", BoundingSphereCenter: behind.Mul(5), BoundingSphereRadius: 1}
+	ground := &Model{Name: "ground", BoundingSphereRadius: 50}
+	follow.Obstacles = func() []*Model { return []*Model{target, wall, ground} }
+	follow.Update(cam, CameraInput{}, 0.016)
+	if d := cam.Position.Len(); math.Abs(float64(d-(4-followArmPadding))) > 1e-3 {
+		t.Errorf("arm should stop short of the wall at %v, got %v", 4-followArmPadding, d)
+	}
+
+	// Swinging the arm away from the wall restores its length
+	follow.Update(cam, CameraInput{Look: mgl32.Vec2{300, 0}}, 0.016)
+	if d := cam.Position.Len(); math.Abs(float64(d-10)) > 1e-3 {
+		t.Errorf("unblocked arm should reach 10, got %v", d)
+	}
+}
@@ -0,0 +1,533 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"fmt"
+	"math"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// previousInstancesUnit holds last frame's instance matrices in the velocity pass
+const previousInstancesUnit = 4
+
+// maxDepthOfFieldRadius bounds the blur radius in pixels; the gather takes about radius² samples
+const maxDepthOfFieldRadius = 32
+
+// DepthOfFieldSettings describes a thin lens. World units are taken as meters,
+// and the focal length follows from the camera's field of view and SensorHeight.
+type DepthOfFieldSettings struct {
+	Enabled       bool    `json:"enabled"`
+	FocusDistance float32 `json:"focus_distance"` // Distance to the sharp plane in world units
+	FStop         float32 `json:"f_stop"`         // Aperture as focal length over diameter; lower blurs more
+	SensorHeight  float32 `json:"sensor_height"`  // Film back height in millimeters; 24 is full frame
+	BokehBlades   int     `json:"bokeh_blades"`   // Aperture blades shaping out-of-focus highlights; below 3 is round
+	MaxRadius     float32 `json:"max_radius"`     // Largest blur radius in pixels
+}
+
+// DefaultDepthOfFieldSettings returns a disabled full-frame lens at f/2.8 focused 10 units away
+func DefaultDepthOfFieldSettings() DepthOfFieldSettings {
+	return DepthOfFieldSettings{
+		Enabled:       false,
+		FocusDistance: 10.0,
+		FStop:         2.8,
+		SensorHeight:  24.0,
+		BokehBlades:   6,
+		MaxRadius:     16.0,
+	}
+}
+
+// blurScale returns the blur radius in pixels of a point infinitely far away.
+// A point at distance d blurs by blurScale * |1 - FocusDistance/d|, the thin
+// lens circle of confusion A·f·|d - s| / (d·(s - f)) scaled from the sensor to the screen.
+func (s DepthOfFieldSettings) blurScale(fovDegrees float32, screenHeight int32) float32 {
+	if s.FStop <= 0 || s.SensorHeight <= 0 || fovDegrees <= 0 {
+		return 0
+	}
+	sensor := float64(s.SensorHeight)
+	focal := 0.5 * sensor / math.Tan(float64(mgl32.DegToRad(fovDegrees))/2)
+	aperture := focal / float64(s.FStop)
+	focus := math.Max(float64(s.FocusDistance)*1000, focal*1.001) // Millimeters, beyond the lens
+	coc := aperture * focal / (focus - focal)
+	return float32(coc / sensor * float64(screenHeight) * 0.5)
+}
+
+// blurRadius returns the blur radius in pixels of a point at distance, as the depth of field pass computes it
+func (s DepthOfFieldSettings) blurRadius(distance, fovDegrees float32, screenHeight int32) float32 {
+	if distance <= 0 {
+		return 0
+	}
+	radius := s.blurScale(fovDegrees, screenHeight) * float32(math.Abs(float64(1-s.FocusDistance/distance)))
+	return min(radius, s.maxRadius())
+}
+
+func (s DepthOfFieldSettings) maxRadius() float32 {
+	return min(max(s.MaxRadius, 1), maxDepthOfFieldRadius)
+}
+
+// MotionBlurSettings describes per-object motion blur. Only object motion
+// blurs; moving the camera does not.
+type MotionBlurSettings struct {
+	Enabled   bool    `json:"enabled"`
+	Shutter   float32 `json:"shutter"`    // Fraction of the frame the shutter stays open; 0.5 is a 180° shutter
+	Samples   int     `json:"samples"`    // Taps along each pixel's motion
+	MaxRadius float32 `json:"max_radius"` // Longest blur in pixels
+}
+
+// DefaultMotionBlurSettings returns disabled motion blur with a 180° shutter
+func DefaultMotionBlurSettings() MotionBlurSettings {
+	return MotionBlurSettings{
+		Enabled:   false,
+		Shutter:   0.5,
+		Samples:   12,
+		MaxRadius: 32.0,
+	}
+}
+
+// depthOfFieldActive reports whether a camera's view gets depth of field;
+// orthographic views have no lens to model
+func depthOfFieldActive(camera Camera) bool {
+	dof := camera.DepthOfField
+	return dof.Enabled && dof.FStop > 0 && dof.FocusDistance > 0 && camera.ProjectionMode != ProjectionOrthographic
+}
+
+// motionBlurActive reports whether a camera's view gets motion blur
+func motionBlurActive(camera Camera) bool {
+	return camera.MotionBlur.Enabled && camera.MotionBlur.Shutter > 0 && camera.MotionBlur.Samples > 0
+}
+
+// motionHistory keeps a model's transforms from the last two frames it was
+// drawn with motion blur, so every view of a frame blurs by the same motion
+type motionHistory struct {
+	frame             uint64 // Frame current was recorded in
+	current           mgl32.Mat4
+	previous          mgl32.Mat4
+	currentInstances  []mgl32.Mat4
+	previousInstances []mgl32.Mat4
+	uploaded          bool   // previousInstances is on the GPU for this frame
+	buffer            uint32 // previousInstances as a buffer texture, four texels per matrix
+	texture           uint32
+}
+
+// motionFor returns the model's motion for a frame, recording its transforms
+// the first time the frame asks. Without a record from the frame before, the
+// model is taken to be still.
+func (m *Model) motionFor(frame uint64) *motionHistory {
+	h := m.motion
+	if h == nil {
+		h = &motionHistory{}
+		m.motion = h
+	}
+	if h.frame == frame {
+		return h
+	}
+	if h.frame != 0 && h.frame+1 == frame {
+		h.previous = h.current
+		h.previousInstances, h.currentInstances = h.currentInstances, h.previousInstances
+	} else {
+		h.previous = m.ModelMatrix
+		h.previousInstances = append(h.previousInstances[:0], m.InstanceModelMatrices...)
+	}
+	h.current = m.ModelMatrix
+	h.currentInstances = append(h.currentInstances[:0], m.InstanceModelMatrices...)
+	if len(h.previousInstances) != len(h.currentInstances) {
+		// Instances were added or removed; their old order no longer lines up
+		h.previousInstances = append(h.previousInstances[:0], h.currentInstances...)
+	}
+	h.frame = frame
+	h.uploaded = false
+	return h
+}
+
+// bindPreviousInstances uploads last frame's instance matrices once per frame
+// and binds them to previousInstancesUnit. Returns false for models without instances.
+func (h *motionHistory) bindPreviousInstances() bool {
+	if len(h.previousInstances) == 0 {
+		return false
+	}
+	if h.buffer == 0 {
+		gl.GenBuffers(1, &h.buffer)
+		gl.GenTextures(1, &h.texture)
+		gl.BindTexture(gl.TEXTURE_BUFFER, h.texture)
+		gl.TexBuffer(gl.TEXTURE_BUFFER, gl.RGBA32F, h.buffer)
+	}
+	if !h.uploaded {
+		gl.BindBuffer(gl.TEXTURE_BUFFER, h.buffer)
+		gl.BufferData(gl.TEXTURE_BUFFER, len(h.previousInstances)*int(unsafe.Sizeof(mgl32.Mat4{})), gl.Ptr(h.previousInstances), gl.STREAM_DRAW)
+		gl.BindBuffer(gl.TEXTURE_BUFFER, 0)
+		h.uploaded = true
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + previousInstancesUnit)
+	gl.BindTexture(gl.TEXTURE_BUFFER, h.texture)
+	gl.ActiveTexture(gl.TEXTURE0)
+	return true
+}
+
+// delete releases the instance buffer
+func (h *motionHistory) delete() {
+	if h.buffer != 0 {
+		gl.DeleteTextures(1, &h.texture)
+		gl.DeleteBuffers(1, &h.buffer)
+		h.buffer, h.texture = 0, 0
+	}
+}
+
+// advanceMotionFrame starts a new frame for motion blur. Redraws for captures
+// and render textures belong to the frame they are drawn in.
+func (rend *OpenGLRenderer) advanceMotionFrame() {
+	if rend.renderingTexture == nil && !rend.capturing {
+		rend.motionFrame++
+	}
+}
+
+// cameraEffectBuffers holds a view's velocity buffer and the color passed
+// between depth of field and motion blur
+type cameraEffectBuffers struct {
+	velocityFBO uint32
+	velocity    uint32 // Screen motion since last frame in texture coordinates, RG16F
+	fbos        [2]uint32
+	colors      [2]uint32
+	width       int32
+	height      int32
+}
+
+// cameraEffects returns the effect buffers of the view being drawn, sized to target
+func (rend *OpenGLRenderer) cameraEffects(target *postProcessTarget) *cameraEffectBuffers {
+	for len(rend.cameraEffectTargets) <= rend.activeView {
+		e := &cameraEffectBuffers{}
+		gl.GenFramebuffers(1, &e.velocityFBO)
+		gl.GenFramebuffers(2, &e.fbos[0])
+		rend.cameraEffectTargets = append(rend.cameraEffectTargets, e)
+	}
+	e := rend.cameraEffectTargets[rend.activeView]
+	if e.width != target.width || e.height != target.height {
+		e.resize(target.width, target.height)
+	}
+	return e
+}
+
+// resize recreates the attachments at a new size. The velocity framebuffer
+// borrows the scene depth when drawn.
+func (e *cameraEffectBuffers) resize(width, height int32) {
+	e.deleteAttachments()
+	e.velocity = gBufferTexture(gl.RG16F, gl.RG, gl.FLOAT, width, height)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, e.velocityFBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, e.velocity, 0)
+	for i := range e.colors {
+		e.colors[i] = mipTexture(gl.RGBA8, gl.RGBA, width, height, 1, gl.LINEAR)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, e.fbos[i])
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, e.colors[i], 0)
+		if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+			logger.Log.Error(fmt.Sprintf("Camera effect framebuffer incomplete after resize! Status: 0x%X", status))
+		}
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	e.width, e.height = width, height
+	logger.Log.Info(fmt.Sprintf("Resized camera effect buffers (%dx%d)", width, height))
+}
+
+func (e *cameraEffectBuffers) deleteAttachments() {
+	for _, id := range []*uint32{&e.velocity, &e.colors[0], &e.colors[1]} {
+		if *id != 0 {
+			gl.DeleteTextures(1, id)
+			*id = 0
+		}
+	}
+}
+
+// delete releases the framebuffers and their attachments
+func (e *cameraEffectBuffers) delete() {
+	e.deleteAttachments()
+	gl.DeleteFramebuffers(1, &e.velocityFBO)
+	gl.DeleteFramebuffers(2, &e.fbos[0])
+	e.velocityFBO, e.fbos = 0, [2]uint32{}
+}
+
+// renderVelocity writes the screen motion of every visible opaque surface
+// since last frame, testing against the scene depth already in target
+func (rend *OpenGLRenderer) renderVelocity(target *postProcessTarget, viewProjection mgl32.Mat4) {
+	effects := rend.cameraEffects(target)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, effects.velocityFBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.TEXTURE_2D, target.depth, 0)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, target.fbo)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		return
+	}
+
+	var clearColor [4]float32
+	var depthFunc int32
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &clearColor[0])
+	gl.GetIntegerv(gl.DEPTH_FUNC, &depthFunc)
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	gl.ClearColor(clearColor[0], clearColor[1], clearColor[2], clearColor[3])
+
+	if rend.velocityShader.Name == "" {
+		rend.velocityShader = NewShaderFromFiles("velocity", "velocity.vert", "velocity.frag")
+	}
+	shader := &rend.velocityShader
+	shader.Use()
+	if shader.program == 0 {
+		return
+	}
+	shader.SetMat4("viewProjection", viewProjection)
+	shader.SetInt("previousInstances", previousInstancesUnit)
+
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthFunc(gl.LEQUAL)
+	gl.DepthMask(false)
+	gl.Disable(gl.BLEND)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+
+	for _, model := range rend.visibleModels {
+		if model.IsDirty {
+			model.calculateModelMatrix()
+			model.IsDirty = false
+		}
+		motion := model.motionFor(rend.motionFrame)
+		shader.SetMat4("model", model.ModelMatrix)
+		shader.SetMat4("previousModel", motion.previous)
+		shader.SetBool("hasPreviousInstances", model.IsInstanced && motion.bindPreviousInstances())
+
+		gl.BindVertexArray(model.VAO)
+		if len(model.MaterialGroups) > 0 {
+			for _, group := range model.MaterialGroups {
+				if group.Material != nil && group.Material.Alpha < 0.99 {
+					continue
+				}
+				rend.drawElements(model, shader, group.IndexCount, int(group.IndexStart)*4)
+			}
+		} else if model.Material == nil || model.Material.Alpha >= 0.99 {
+			rend.drawElements(model, shader, int32(len(model.Faces)), 0)
+		}
+	}
+	gl.BindVertexArray(0)
+
+	gl.DepthFunc(uint32(depthFunc))
+	gl.DepthMask(true)
+	if !rend.depthTestState {
+		gl.Disable(gl.DEPTH_TEST)
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	if Debug {
+		gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+	}
+	// The velocity pass binds its own program, so force the next model to rebind
+	rend.currentShaderProgram = 0
+}
+
+// renderCameraEffects applies depth of field and then motion blur to the
+// bloom and FXAA result in effects.colors[0]. The last pass draws into
+// viewport of the output framebuffer. Call with the screen quad bound.
+func (rend *OpenGLRenderer) renderCameraEffects(effects *cameraEffectBuffers, target *postProcessTarget, camera Camera, viewport [4]int32, scissor bool) {
+	dof, motion := depthOfFieldActive(camera), motionBlurActive(camera)
+	texelSize := mgl32.Vec2{1.0 / float32(target.width), 1.0 / float32(target.height)}
+	source := effects.colors[0]
+
+	// bindOutput targets the output for the last pass and the other color buffer otherwise
+	bindOutput := func(last bool) {
+		if last {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, rend.outputFBO)
+			gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+			if scissor {
+				gl.Enable(gl.SCISSOR_TEST)
+			}
+		} else {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, effects.fbos[1])
+			gl.Viewport(0, 0, target.width, target.height)
+		}
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+	}
+
+	if dof {
+		if rend.depthOfFieldShader.Name == "" {
+			rend.depthOfFieldShader = NewShaderFromFiles("depth_of_field", "fullscreen.vert", "depth_of_field.frag")
+		}
+		shader := &rend.depthOfFieldShader
+		bindOutput(!motion)
+		shader.Use()
+		settings := camera.DepthOfField
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D, source)
+		gl.ActiveTexture(gl.TEXTURE1)
+		gl.BindTexture(gl.TEXTURE_2D, target.depth)
+		gl.ActiveTexture(gl.TEXTURE0)
+		shader.SetInt("screenTexture", 0)
+		shader.SetInt("depthTexture", 1)
+		shader.SetVec2("texelSize", texelSize)
+		shader.SetFloat("near", camera.Near)
+		shader.SetFloat("far", camera.Far)
+		shader.SetFloat("focusDistance", settings.FocusDistance)
+		shader.SetFloat("blurScale", settings.blurScale(camera.Fov, target.height))
+		shader.SetFloat("maxRadius", settings.maxRadius())
+		shader.SetInt("bokehBlades", int32(settings.BokehBlades))
+		gl.DrawArrays(gl.TRIANGLES, 0, 6)
+		source = effects.colors[1]
+	}
+
+	if motion {
+		if rend.motionBlurShader.Name == "" {
+			rend.motionBlurShader = NewShaderFromFiles("motion_blur", "fullscreen.vert", "motion_blur.frag")
+		}
+		shader := &rend.motionBlurShader
+		bindOutput(true)
+		shader.Use()
+		settings := camera.MotionBlur
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D, source)
+		gl.ActiveTexture(gl.TEXTURE1)
+		gl.BindTexture(gl.TEXTURE_2D, effects.velocity)
+		gl.ActiveTexture(gl.TEXTURE0)
+		shader.SetInt("screenTexture", 0)
+		shader.SetInt("velocityBuffer", 1)
+		shader.SetVec2("texelSize", texelSize)
+		shader.SetFloat("shutter", settings.Shutter)
+		shader.SetInt("samples", int32(settings.Samples))
+		shader.SetFloat("maxRadius", settings.MaxRadius)
+		gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	}
+}
+
+// velocityVertexShaderSource projects each vertex with this frame's and last frame's transforms
+const velocityVertexShaderSource = `#version 410 core
+layout(location = 0) in vec3 inPosition;
+layout(location = 3) in mat4 instanceModel;
+
+uniform bool isInstanced;
+uniform mat4 model;
+uniform mat4 previousModel;
+uniform mat4 viewProjection;
+uniform samplerBuffer previousInstances; // Last frame's instance matrices, four texels each
+uniform bool hasPreviousInstances;
+
+out vec4 currentClip;
+out vec4 previousClip;
+
+void main() {
+    mat4 current = model;
+    mat4 previous = previousModel;
+    if (isInstanced) {
+        mat4 lastInstance = instanceModel;
+        if (hasPreviousInstances) {
+            int i = gl_InstanceID * 4;
+            lastInstance = mat4(texelFetch(previousInstances, i), texelFetch(previousInstances, i + 1),
+                                texelFetch(previousInstances, i + 2), texelFetch(previousInstances, i + 3));
+        }
+        current = model * instanceModel;
+        previous = previousModel * lastInstance;
+    }
+    currentClip = viewProjection * current * vec4(inPosition, 1.0);
+    previousClip = viewProjection * previous * vec4(inPosition, 1.0);
+    gl_Position = currentClip;
+}
+`
+
+// velocityFragmentShaderSource stores the motion in texture coordinates
+const velocityFragmentShaderSource = `#version 410 core
+in vec4 currentClip;
+in vec4 previousClip;
+out vec2 velocity;
+
+void main() {
+    velocity = (currentClip.xy / currentClip.w - previousClip.xy / previousClip.w) * 0.5;
+}
+`
+
+// depthOfFieldFragmentShaderSource gathers each pixel's blur from samples on
+// a golden-angle spiral, each taken when its own blur reaches the pixel.
+// Blades bend the spiral into the aperture's polygon, shaping the bokeh.
+const depthOfFieldFragmentShaderSource = `#version 410 core
+in vec2 TexCoords;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform sampler2D depthTexture;
+uniform vec2 texelSize;
+uniform float near;
+uniform float far;
+uniform float focusDistance;
+uniform float blurScale; // Blur radius in pixels infinitely far away
+uniform float maxRadius;
+uniform int bokehBlades;
+
+const float GOLDEN_ANGLE = 2.39996323;
+const float RADIUS_STEP = 0.5; // Smaller steps take more samples
+
+float viewDistance(vec2 uv) {
+    float z = texture(depthTexture, uv).r * 2.0 - 1.0;
+    return 2.0 * near * far / (far + near - z * (far - near));
+}
+
+float blurRadius(float distance) {
+    return min(blurScale * abs(1.0 - focusDistance / distance), maxRadius);
+}
+
+// apertureEdge scales a direction's radius out to the edge of the aperture polygon
+float apertureEdge(float angle) {
+    if (bokehBlades < 3) {
+        return 1.0;
+    }
+    float segment = 6.28318531 / float(bokehBlades);
+    return cos(0.5 * segment) / cos(mod(angle, segment) - 0.5 * segment);
+}
+
+void main() {
+    float centerDistance = viewDistance(TexCoords);
+    float centerRadius = blurRadius(centerDistance);
+    vec3 color = texture(screenTexture, TexCoords).rgb;
+    float total = 1.0;
+    float radius = RADIUS_STEP;
+    for (float angle = 0.0; radius < maxRadius; angle += GOLDEN_ANGLE) {
+        vec2 uv = TexCoords + vec2(cos(angle), sin(angle)) * apertureEdge(angle) * radius * texelSize;
+        vec3 sampleColor = texture(screenTexture, uv).rgb;
+        float sampleDistance = viewDistance(uv);
+        float sampleRadius = blurRadius(sampleDistance);
+        if (sampleDistance > centerDistance) {
+            // Keep blurred backgrounds from spreading over sharper things in front
+            sampleRadius = min(sampleRadius, centerRadius * 2.0);
+        }
+        float weight = smoothstep(radius - 0.5, radius + 0.5, sampleRadius);
+        color += mix(color / total, sampleColor, weight);
+        total += 1.0;
+        radius += RADIUS_STEP / radius;
+    }
+    FragColor = vec4(color / total, 1.0);
+}
+`
+
+// motionBlurFragmentShaderSource averages taps along each pixel's motion
+// during the open shutter, centered on the pixel
+const motionBlurFragmentShaderSource = `#version 410 core
+in vec2 TexCoords;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform sampler2D velocityBuffer;
+uniform vec2 texelSize;
+uniform float shutter;
+uniform int samples;
+uniform float maxRadius;
+
+void main() {
+    vec2 velocity = texture(velocityBuffer, TexCoords).xy * shutter;
+    float pixels = length(velocity / texelSize);
+    if (pixels < 0.5) {
+        FragColor = vec4(texture(screenTexture, TexCoords).rgb, 1.0);
+        return;
+    }
+    velocity *= min(pixels, maxRadius) / pixels;
+
+    // Offset the taps per pixel so few samples dither instead of banding
+    float jitter = fract(52.9829189 * fract(dot(gl_FragCoord.xy, vec2(0.06711056, 0.00583715))));
+    vec3 color = vec3(0.0);
+    for (int i = 0; i < samples; i++) {
+        float t = (float(i) + jitter) / float(samples) - 0.5;
+        color += texture(screenTexture, TexCoords + velocity * t).rgb;
+    }
+    FragColor = vec4(color / float(samples), 1.0);
+}
+`
@@ -0,0 +1,90 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDepthOfFieldBlur(t *testing.T) {
+	// A 50mm lens on full frame at f/2 focused at 2m blurs infinity by a
+	// 0.641mm circle, 14.4px of radius at 1080 lines
+	dof := DefaultDepthOfFieldSettings()
+	dof.FocusDistance = 2
+	dof.FStop = 2
+	dof.MaxRadius = 32
+	fov := float32(2 * math.Atan(12.0/50.0) * 180 / math.Pi)
+
+	if got := dof.blurScale(fov, 1080); math.Abs(float64(got-14.42)) > 0.05 {
+		t.Errorf("blur at infinity %v px, want 14.42", got)
+	}
+	if got := dof.blurRadius(2, fov, 1080); got != 0 {
+		t.Errorf("the focus distance should be sharp, got %v px", got)
+	}
+	if near, far := dof.blurRadius(1, fov, 1080), dof.blurRadius(4, fov, 1080); near <= far || far <= 0 {
+		t.Errorf("closer points should blur more, got %v px at 1m and %v px at 4m", near, far)
+	}
+
+	stopped := dof
+	stopped.FStop = 8
+	if wide, narrow := dof.blurScale(fov, 1080), stopped.blurScale(fov, 1080); math.Abs(float64(wide/narrow-4)) > 1e-3 {
+		t.Errorf("f/2 should blur four times f/8, got %v and %v", wide, narrow)
+	}
+	if got := dof.blurRadius(0.1, fov, 1080); got != 32 {
+		t.Errorf("blur should stop at MaxRadius, got %v", got)
+	}
+}
+
+func TestCameraEffectsActive(t *testing.T) {
+	camera := Camera{DepthOfField: DefaultDepthOfFieldSettings(), MotionBlur: DefaultMotionBlurSettings()}
+	if depthOfFieldActive(camera) || motionBlurActive(camera) {
+		t.Error("effects should start disabled")
+	}
+	camera.DepthOfField.Enabled = true
+	camera.MotionBlur.Enabled = true
+	if !depthOfFieldActive(camera) || !motionBlurActive(camera) {
+		t.Error("enabled effects should be active")
+	}
+	camera.ProjectionMode = ProjectionOrthographic
+	if depthOfFieldActive(camera) {
+		t.Error("orthographic cameras have no depth of field")
+	}
+}
+
+func TestMotionHistory(t *testing.T) {
+	m := &Model{ModelMatrix: mgl32.Translate3D(1, 0, 0)}
+	h := m.motionFor(1)
+	if h.previous != h.current {
+		t.Error("a model without history should be still")
+	}
+
+	m.ModelMatrix = mgl32.Translate3D(2, 0, 0)
+	h = m.motionFor(2)
+	if h.previous != mgl32.Translate3D(1, 0, 0) || h.current != m.ModelMatrix {
+		t.Errorf("frame 2 should move from x=1 to x=2, got %v to %v", h.previous.Col(3), h.current.Col(3))
+	}
+
+	// Later views of the same frame see the same motion
+	m.ModelMatrix = mgl32.Translate3D(5, 0, 0)
+	if h = m.motionFor(2); h.previous != mgl32.Translate3D(1, 0, 0) || h.current != mgl32.Translate3D(2, 0, 0) {
+		t.Error("the history should not change within a frame")
+	}
+
+	// A skipped frame leaves nothing to compare with
+	if h = m.motionFor(4); h.previous != h.current {
+		t.Error("a model missing last frame should be still")
+	}
+
+	m.InstanceModelMatrices = []mgl32.Mat4{mgl32.Ident4(), mgl32.Translate3D(0, 1, 0)}
+	m.motionFor(5)
+	moved := []mgl32.Mat4{mgl32.Translate3D(0, 2, 0), mgl32.Translate3D(0, 3, 0)}
+	m.InstanceModelMatrices = moved
+	if h = m.motionFor(6); h.previousInstances[1] != mgl32.Translate3D(0, 1, 0) || h.currentInstances[0] != moved[0] {
+		t.Error("instances should keep last frame's matrices")
+	}
+	m.InstanceModelMatrices = append(moved, mgl32.Ident4())
+	if h = m.motionFor(7); len(h.previousInstances) != 3 || h.previousInstances[0] != moved[0] {
+		t.Error("a changed instance count should restart the history")
+	}
+}
@@ -0,0 +1,285 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// maxCaptureSize matches the post-processing framebuffer limit
+const maxCaptureSize = 8192
+
+// ScreenshotOptions controls a screenshot's resolution
+type ScreenshotOptions struct {
+	Scale       int // Output size as a multiple of the viewport; above 1 renders offscreen
+	Supersample int // Extra resolution rendered per output pixel and averaged down
+}
+
+type screenshotRequest struct {
+	path    string
+	options ScreenshotOptions
+	handle  *AssetHandle[string]
+}
+
+// frameRecorder writes every rendered frame to a numbered PNG
+type frameRecorder struct {
+	dir    string
+	frames int
+	writes sync.WaitGroup
+	slots  chan struct{} // Bounds the frames waiting to be encoded
+	mu     sync.Mutex
+	err    error
+}
+
+// Screenshot saves the next frame, after post-processing and before any UI, to a
+// PNG. The handle resolves to the written path on the main thread.
+func (rend *OpenGLRenderer) Screenshot(path string, options ScreenshotOptions) *AssetHandle[string] {
+	options.Scale = max(options.Scale, 1)
+	options.Supersample = max(options.Supersample, 1)
+	handle := NewAssetHandle[string](path, rend.uploads)
+	rend.screenshots = append(rend.screenshots, screenshotRequest{path: path, options: options, handle: handle})
+	return handle
+}
+
+// StartRecording writes each following frame to dir as frame_00000.png, ... With
+// fps above 0 the frame clock advances 1/fps per frame, so the sequence plays back
+// at that rate and is the same however slowly frames are encoded.
+func (rend *OpenGLRenderer) StartRecording(dir string, fps int) error {
+	if rend.recorder != nil {
+		return fmt.Errorf("already recording to %s", rend.recorder.dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	rend.recorder = &frameRecorder{dir: dir, slots: make(chan struct{}, runtime.NumCPU()*2)}
+	if fps > 0 {
+		Clock.SetFixedStep(time.Second / time.Duration(fps))
+	}
+	return nil
+}
+
+// IsRecording returns true while frames are being recorded
+func (rend *OpenGLRenderer) IsRecording() bool {
+	return rend.recorder != nil
+}
+
+// StopRecording waits for queued frames to be written and returns how many were
+// recorded, along with the first write error
+func (rend *OpenGLRenderer) StopRecording() (int, error) {
+	rec := rend.recorder
+	if rec == nil {
+		return 0, fmt.Errorf("not recording")
+	}
+	rend.recorder = nil
+	Clock.SetFixedStep(0)
+	rec.writes.Wait()
+	return rec.frames, rec.err
+}
+
+// capturePending returns true if the finished frame should be read back
+func (rend *OpenGLRenderer) capturePending() bool {
+	return !rend.capturing && rend.renderingTexture == nil && (rend.recorder != nil || len(rend.screenshots) > 0)
+}
+
+// processCaptures serves recording and screenshot requests once the frame is
+// complete. redraw renders the frame again for offscreen screenshots.
+func (rend *OpenGLRenderer) processCaptures(redraw func()) {
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	width, height := int(viewport[2]), int(viewport[3])
+
+	var frame *image.RGBA
+	if rend.recorder != nil || rend.needsFrameReadback() {
+		frame = readFramebuffer(rend.outputFBO, width, height)
+	}
+
+	if rec := rend.recorder; rec != nil {
+		path := filepath.Join(rec.dir, fmt.Sprintf("frame_%05d.png", rec.frames))
+		rec.frames++
+		rec.slots <- struct{}{} // Wait here if the encoders fall behind
+		rec.writes.Add(1)
+		AssetPool().Submit(func() {
+			defer rec.writes.Done()
+			defer func() { <-rec.slots }()
+			if err := writePNG(path, frame); err != nil {
+				rec.mu.Lock()
+				if rec.err == nil {
+					rec.err = err
+				}
+				rec.mu.Unlock()
+			}
+		})
+	}
+
+	requests := rend.screenshots
+	rend.screenshots = nil
+	for _, req := range requests {
+		img := frame
+		if req.options.Scale > 1 || req.options.Supersample > 1 {
+			var err error
+			img, err = rend.renderOffscreen(redraw, width*req.options.Scale, height*req.options.Scale, req.options.Supersample)
+			if err != nil {
+				req.handle.Fail(err)
+				continue
+			}
+		}
+		req := req
+		AssetPool().Submit(func() {
+			if err := writePNG(req.path, img); err != nil {
+				req.handle.Fail(err)
+				return
+			}
+			rend.uploads.Push(func() { req.handle.Complete(req.path, nil) })
+		})
+	}
+}
+
+// needsFrameReadback returns true if a pending screenshot uses the frame as shown
+func (rend *OpenGLRenderer) needsFrameReadback() bool {
+	for _, req := range rend.screenshots {
+		if req.options.Scale == 1 && req.options.Supersample == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderOffscreen redraws into a temporary framebuffer at width*supersample x
+// height*supersample and averages it down to width x height
+func (rend *OpenGLRenderer) renderOffscreen(redraw func(), width, height, supersample int) (*image.RGBA, error) {
+	var maxSize int32
+	gl.GetIntegerv(gl.MAX_RENDERBUFFER_SIZE, &maxSize)
+	limit := min(int(maxSize), maxCaptureSize)
+	// Give up supersampling before resolution
+	for supersample > 1 && max(width, height)*supersample > limit {
+		supersample--
+	}
+	if max(width, height) > limit {
+		return nil, fmt.Errorf("screenshot of %dx%d exceeds the %d pixel limit", width, height, limit)
+	}
+	renderW, renderH := int32(width*supersample), int32(height*supersample)
+
+	var fbo, color, depth uint32
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+	gl.GenTextures(1, &color)
+	gl.BindTexture(gl.TEXTURE_2D, color)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, renderW, renderH, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, color, 0)
+	gl.GenRenderbuffers(1, &depth)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, renderW, renderH)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, depth)
+	defer func() {
+		gl.DeleteFramebuffers(1, &fbo)
+		gl.DeleteTextures(1, &color)
+		gl.DeleteRenderbuffers(1, &depth)
+	}()
+
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	previousOutput := rend.outputFBO
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, previousOutput)
+		return nil, fmt.Errorf("capture framebuffer incomplete: 0x%X", status)
+	}
+
+	// Post-processing follows the viewport and resolves into outputFBO
+	rend.outputFBO = fbo
+	rend.capturing = true
+	gl.Viewport(0, 0, renderW, renderH)
+	redraw()
+	img := readFramebuffer(fbo, int(renderW), int(renderH))
+
+	rend.capturing = false
+	rend.outputFBO = previousOutput
+	gl.BindFramebuffer(gl.FRAMEBUFFER, previousOutput)
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+
+	return downsample(img, supersample), nil
+}
+
+// readFramebuffer reads a framebuffer's color into a top-down opaque image
+func readFramebuffer(fbo uint32, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fbo)
+	if fbo == 0 {
+		gl.ReadBuffer(gl.BACK)
+	}
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, 0)
+
+	flipRows(img)
+	// Blending leaves partial alpha in the color buffer; captures are always opaque
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	return img
+}
+
+// flipRows converts GL's bottom-up rows to image order in place
+func flipRows(img *image.RGBA) {
+	row := make([]byte, img.Stride)
+	for top, bottom := 0, img.Rect.Dy()-1; top < bottom; top, bottom = top+1, bottom-1 {
+		a := img.Pix[top*img.Stride : (top+1)*img.Stride]
+		b := img.Pix[bottom*img.Stride : (bottom+1)*img.Stride]
+		copy(row, a)
+		copy(a, b)
+		copy(b, row)
+	}
+}
+
+// downsample averages factor x factor blocks into single pixels
+func downsample(img *image.RGBA, factor int) *image.RGBA {
+	if factor <= 1 {
+		return img
+	}
+	w, h := img.Rect.Dx()/factor, img.Rect.Dy()/factor
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	samples := uint32(factor * factor)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum [4]uint32
+			for sy := 0; sy < factor; sy++ {
+				src := img.PixOffset(x*factor, y*factor+sy)
+				for sx := 0; sx < factor; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += uint32(img.Pix[src+sx*4+c])
+					}
+				}
+			}
+			dst := out.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				out.Pix[dst+c] = uint8((sum[c] + samples/2) / samples)
+			}
+		}
+	}
+	return out
+}
+
+// writePNG encodes img to path, creating the directory if needed
+func writePNG(path string, img *image.RGBA) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
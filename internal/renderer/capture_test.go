@@ -0,0 +1,76 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlipRows(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 2; x++ {
+			img.Set(x, y, color.RGBA{uint8(y), uint8(x), 0, 255})
+		}
+	}
+	flipRows(img)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 2; x++ {
+			got := img.RGBAAt(x, y)
+			if got.R != uint8(2-y) || got.G != uint8(x) {
+				t.Errorf("pixel (%d,%d) = %v, want row %d", x, y, got, 2-y)
+			}
+		}
+	}
+}
+
+func TestDownsampleAveragesBlocks(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	// Left block alternates black and white, right block is solid red
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			v := uint8(255 * ((x + y) % 2))
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+			img.Set(x+2, y, color.RGBA{200, 0, 0, 255})
+		}
+	}
+
+	out := downsample(img, 2)
+	if out.Rect.Dx() != 2 || out.Rect.Dy() != 1 {
+		t.Fatalf("size = %v, want 2x1", out.Rect.Size())
+	}
+	if got := out.RGBAAt(0, 0); got != (color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("checker block = %v, want mid gray", got)
+	}
+	if got := out.RGBAAt(1, 0); got != (color.RGBA{200, 0, 0, 255}) {
+		t.Errorf("solid block = %v, want unchanged", got)
+	}
+	if downsample(img, 1) != img {
+		t.Error("factor 1 should return the image unchanged")
+	}
+}
+
+func TestWritePNGCreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shots", "frame.png")
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.RGBA{10, 20, 30, 255})
+	if err := writePNG(path, img); err != nil {
+		t.Fatalf("writePNG: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	decoded, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if r, g, b, _ := decoded.At(1, 1).RGBA(); r>>8 != 10 || g>>8 != 20 || b>>8 != 30 {
+		t.Errorf("pixel = (%d, %d, %d), want (10, 20, 30)", r>>8, g>>8, b>>8)
+	}
+}
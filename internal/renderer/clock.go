@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"sync"
+	"time"
+)
+
+// FrameClock is the time source for animation. It follows the wall clock until a
+// fixed step is set; then every frame advances time by exactly that step, however
+// long the frame took, so captured sequences are reproducible.
+type FrameClock struct {
+	mu     sync.Mutex
+	offset time.Duration // Added to wall time so switching modes never goes backwards
+	fixed  time.Duration
+	now    time.Time // Current time while a fixed step is active
+	last   time.Time // Time of the previous Tick
+}
+
+// Clock drives frame deltas, water and time-of-day animation
+var Clock = &FrameClock{}
+
+// Now returns the current frame time
+func (c *FrameClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nowLocked()
+}
+
+func (c *FrameClock) nowLocked() time.Time {
+	if c.fixed > 0 {
+		return c.now
+	}
+	return time.Now().Add(c.offset)
+}
+
+// Since returns the frame time elapsed since t
+func (c *FrameClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// SetFixedStep makes every Tick advance by step. Zero returns to wall time.
+func (c *FrameClock) SetFixedStep(step time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := c.nowLocked()
+	c.fixed = step
+	if step > 0 {
+		c.now = current
+	} else {
+		c.offset = current.Sub(time.Now())
+	}
+}
+
+// FixedStep returns the fixed step, or 0 when following the wall clock
+func (c *FrameClock) FixedStep() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fixed
+}
+
+// Tick starts a new frame and returns the time since the previous one
+func (c *FrameClock) Tick() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fixed > 0 {
+		c.now = c.now.Add(c.fixed)
+	}
+	now := c.nowLocked()
+	if c.last.IsZero() {
+		c.last = now
+	}
+	delta := now.Sub(c.last)
+	c.last = now
+	return delta
+}
@@ -0,0 +1,44 @@
+package renderer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameClockFixedStep(t *testing.T) {
+	c := &FrameClock{}
+	c.Tick()
+	c.SetFixedStep(time.Second / 30)
+	start := c.Now()
+	for i := 0; i < 3; i++ {
+		if d := c.Tick(); i > 0 && d != time.Second/30 {
+			t.Errorf("tick %d = %v, want %v", i, d, time.Second/30)
+		}
+	}
+	if got := c.Since(start); got != 3*(time.Second/30) {
+		t.Errorf("elapsed = %v, want three steps", got)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if got := c.Since(start); got != 3*(time.Second/30) {
+		t.Errorf("fixed time moved with the wall clock: %v", got)
+	}
+}
+
+func TestFrameClockResumesWithoutJumpingBack(t *testing.T) {
+	c := &FrameClock{}
+	c.SetFixedStep(time.Hour)
+	c.Tick()
+	c.Tick()
+	before := c.Now()
+
+	c.SetFixedStep(0)
+	if c.FixedStep() != 0 {
+		t.Fatal("fixed step should be cleared")
+	}
+	if after := c.Now(); after.Before(before) {
+		t.Errorf("clock went backwards from %v to %v", before, after)
+	}
+	if d := c.Tick(); d < 0 {
+		t.Errorf("negative delta %v after leaving fixed step", d)
+	}
+}
@@ -0,0 +1,67 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// TextureColorSpace says whether a texture holds colors or raw data
+type TextureColorSpace string
+
+const (
+	ColorSpaceSRGB   TextureColorSpace = "srgb"   // Albedo and other colors, decoded to linear when sampled
+	ColorSpaceLinear TextureColorSpace = "linear" // Normals, masks and other data, sampled as stored
+)
+
+// TextureColorSpaces lists the valid color spaces, in display order
+var TextureColorSpaces = []TextureColorSpace{ColorSpaceSRGB, ColorSpaceLinear}
+
+// isSRGB returns true unless the settings mark the texture as linear data
+func (s SamplerSettings) isSRGB() bool {
+	return s.ColorSpace != ColorSpaceLinear
+}
+
+// glInternalFormat returns the uncompressed internal format for 8-bit RGBA texels
+func (s SamplerSettings) glInternalFormat() int32 {
+	if s.isSRGB() {
+		return gl.SRGB8_ALPHA8
+	}
+	return gl.RGBA8
+}
+
+// SRGBToLinear decodes one sRGB-encoded channel
+func SRGBToLinear(c float32) float32 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return float32(math.Pow((float64(c)+0.055)/1.055, 2.4))
+}
+
+// LinearToSRGB encodes one linear channel for display
+func LinearToSRGB(c float32) float32 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return float32(1.055*math.Pow(float64(c), 1/2.4) - 0.055)
+}
+
+// SRGBToLinear3 decodes an sRGB color
+func SRGBToLinear3(c [3]float32) [3]float32 {
+	return [3]float32{SRGBToLinear(c[0]), SRGBToLinear(c[1]), SRGBToLinear(c[2])}
+}
+
+// LinearToSRGB3 encodes a linear color
+func LinearToSRGB3(c [3]float32) [3]float32 {
+	return [3]float32{LinearToSRGB(c[0]), LinearToSRGB(c[1]), LinearToSRGB(c[2])}
+}
+
+// colorSpaceShaderSource is the GLSL counterpart of LinearToSRGB, included as
+// color_space.glsl by passes that draw sRGB textures straight to the display.
+// It inverts the hardware decode exactly, so those textures look as authored.
+const colorSpaceShaderSource = `
+vec3 linearToSRGB(vec3 c) {
+    c = max(c, vec3(0.0));
+    return mix(c * 12.92, 1.055 * pow(c, vec3(1.0 / 2.4)) - 0.055, step(vec3(0.0031308), c));
+}
+`
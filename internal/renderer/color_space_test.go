@@ -0,0 +1,37 @@
+package renderer
+
+import "testing"
+
+func TestSRGBRoundTrip(t *testing.T) {
+	for _, c := range []float32{0, 0.002, 0.04, 0.2, 0.5, 0.8, 1} {
+		if got := LinearToSRGB(SRGBToLinear(c)); got < c-1e-5 || got > c+1e-5 {
+			t.Errorf("round trip of %v gave %v", c, got)
+		}
+	}
+	// Mid grey on screen is about a fifth of the light
+	if mid := SRGBToLinear(0.5); mid < 0.21 || mid > 0.22 {
+		t.Errorf("SRGBToLinear(0.5) = %v, want ~0.214", mid)
+	}
+	if got := LinearToSRGB3([3]float32{0, 1, 0.214}); got[0] != 0 || got[1] < 0.9999 || got[2] < 0.49 || got[2] > 0.51 {
+		t.Errorf("LinearToSRGB3 = %v", got)
+	}
+}
+
+func TestSamplerColorSpace(t *testing.T) {
+	linear := SamplerSettings{ColorSpace: ColorSpaceLinear}
+	if linear.isSRGB() || !(SamplerSettings{}).isSRGB() {
+		t.Error("textures should be sRGB unless tagged linear")
+	}
+	if (SamplerSettings{ColorSpace: ColorSpaceSRGB}).cacheKey("a.png") != "a.png" {
+		t.Error("an explicit srgb tag should share the default cache entry")
+	}
+	if linear.cacheKey("a.png") == "a.png" {
+		t.Error("linear and sRGB copies of a texture must not share a cache entry")
+	}
+	if err := (SamplerSettings{ColorSpace: "rec2020"}).validate(); err == nil {
+		t.Error("expected an error for an unknown color space")
+	}
+	if _, err := ParseMaterialAsset([]byte(`{"textures": [{"name": "normalMap", "path": "n.png", "sampler": {"colorSpace": "linear"}}]}`)); err != nil {
+		t.Errorf("materials should accept linear textures: %v", err)
+	}
+}
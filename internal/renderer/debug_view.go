@@ -0,0 +1,459 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"go.uber.org/zap"
+)
+
+// DebugViewMode replaces the scene's shading with a diagnostic view
+type DebugViewMode int
+
+const (
+	DebugViewLit        DebugViewMode = iota // Normal shading
+	DebugViewNormals                         // World-space normals as colors
+	DebugViewUVs                             // Texture coordinates over a checker
+	DebugViewAlbedo                          // Texture and material color, unlit
+	DebugViewLighting                        // Lighting on white surfaces
+	DebugViewDepth                           // Distance from the camera, white near
+	DebugViewOverdraw                        // Fragments drawn per pixel, brighter is more
+	DebugViewInstanceID                      // A color per model and per instance
+	DebugViewLOD                             // Level of detail picked from screen coverage
+)
+
+// DebugView is the view mode every camera draws with. Wireframe stays on Debug
+// and combines with any mode.
+var DebugView DebugViewMode
+
+// Bounding volume overlays, drawn over the scene in any view mode
+var (
+	ShowBoundingSpheres bool
+	ShowBoundingBoxes   bool
+)
+
+var debugViewNames = [...]string{"lit", "normals", "uv", "albedo", "lighting", "depth", "overdraw", "instances", "lod"}
+
+// DebugViewModes lists every mode, for editor pickers
+var DebugViewModes = []DebugViewMode{
+	DebugViewLit, DebugViewNormals, DebugViewUVs, DebugViewAlbedo, DebugViewLighting,
+	DebugViewDepth, DebugViewOverdraw, DebugViewInstanceID, DebugViewLOD,
+}
+
+func (m DebugViewMode) String() string {
+	if m < 0 || int(m) >= len(debugViewNames) {
+		return "lit"
+	}
+	return debugViewNames[m]
+}
+
+// ParseDebugViewMode looks a mode up by name, as shown by String
+func ParseDebugViewMode(name string) (DebugViewMode, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range debugViewNames {
+		if n == name {
+			return DebugViewMode(i), true
+		}
+	}
+	return DebugViewLit, false
+}
+
+// replacesShading reports whether the mode draws with the debug shader instead of the scene's own
+func (m DebugViewMode) replacesShading() bool {
+	return m != DebugViewLit && m != DebugViewLighting
+}
+
+// lodCoverage are the screen coverages, as a fraction of the view height, below
+// which a model drops to the next level of detail
+var lodCoverage = [...]float32{0.5, 0.2, 0.08}
+
+// LODLevel returns the level of detail for a screen coverage, 0 being the most
+// detailed. Meshes only have one level so far; the LOD view shows these levels
+// so the thresholds can be tuned against real scenes.
+func LODLevel(coverage float32) int {
+	for i, threshold := range lodCoverage {
+		if coverage >= threshold {
+			return i
+		}
+	}
+	return len(lodCoverage)
+}
+
+// ScreenCoverage returns how much of the view height a sphere covers, 1 being all of it
+func (c *Camera) ScreenCoverage(center mgl32.Vec3, radius float32) float32 {
+	if c.ProjectionMode == ProjectionOrthographic {
+		return min(radius/c.orthoHalfHeight(), 1)
+	}
+	distance := center.Sub(c.Position).Len()
+	if distance <= radius {
+		return 1
+	}
+	halfHeight := distance * float32(math.Tan(float64(mgl32.DegToRad(c.Fov)/2)))
+	return min(radius/halfHeight, 1)
+}
+
+// LocalBounds returns the corners of the box around the mesh in model space
+func (m *Model) LocalBounds() (mgl32.Vec3, mgl32.Vec3) {
+	if len(m.Vertices) < 3 {
+		return mgl32.Vec3{}, mgl32.Vec3{}
+	}
+	lo := mgl32.Vec3{m.Vertices[0], m.Vertices[1], m.Vertices[2]}
+	hi := lo
+	for i := 3; i+2 < len(m.Vertices); i += 3 {
+		for axis := 0; axis < 3; axis++ {
+			v := m.Vertices[i+axis]
+			lo[axis] = min(lo[axis], v)
+			hi[axis] = max(hi[axis], v)
+		}
+	}
+	return lo, hi
+}
+
+// WorldBounds returns the axis-aligned box around the model in world space,
+// covering every instance of instanced models
+func (m *Model) WorldBounds() (mgl32.Vec3, mgl32.Vec3) {
+	lo, hi := m.LocalBounds()
+	if !m.IsInstanced || len(m.InstanceModelMatrices) == 0 {
+		return transformBounds(m.ModelMatrix, lo, hi)
+	}
+	worldLo, worldHi := transformBounds(m.ModelMatrix.Mul4(m.InstanceModelMatrices[0]), lo, hi)
+	for _, instance := range m.InstanceModelMatrices[1:] {
+		a, b := transformBounds(m.ModelMatrix.Mul4(instance), lo, hi)
+		for axis := 0; axis < 3; axis++ {
+			worldLo[axis] = min(worldLo[axis], a[axis])
+			worldHi[axis] = max(worldHi[axis], b[axis])
+		}
+	}
+	return worldLo, worldHi
+}
+
+// transformBounds returns the axis-aligned box around a box moved by matrix
+func transformBounds(matrix mgl32.Mat4, lo, hi mgl32.Vec3) (mgl32.Vec3, mgl32.Vec3) {
+	center := mgl32.TransformCoordinate(lo.Add(hi).Mul(0.5), matrix)
+	half := hi.Sub(lo).Mul(0.5)
+	var extent mgl32.Vec3
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			extent[row] += float32(math.Abs(float64(matrix.At(row, col)))) * half[col]
+		}
+	}
+	return center.Sub(extent), center.Add(extent)
+}
+
+// debugLines batches colored line segments for overlays
+type debugLines struct {
+	shader   Shader
+	vao, vbo uint32
+	vertices []float32 // Position and color per vertex
+}
+
+// line adds a segment from a to b
+func (l *debugLines) line(a, b, color mgl32.Vec3) {
+	l.vertices = append(l.vertices,
+		a.X(), a.Y(), a.Z(), color.X(), color.Y(), color.Z(),
+		b.X(), b.Y(), b.Z(), color.X(), color.Y(), color.Z())
+}
+
+// box adds the twelve edges of an axis-aligned box
+func (l *debugLines) box(lo, hi, color mgl32.Vec3) {
+	corner := func(i int) mgl32.Vec3 {
+		c := lo
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<axis) != 0 {
+				c[axis] = hi[axis]
+			}
+		}
+		return c
+	}
+	for i := 0; i < 8; i++ {
+		for axis := 0; axis < 3; axis++ {
+			if j := i | 1<<axis; j != i {
+				l.line(corner(i), corner(j), color)
+			}
+		}
+	}
+}
+
+const sphereSegments = 32
+
+// sphere adds a circle around each axis of a sphere
+func (l *debugLines) sphere(center mgl32.Vec3, radius float32, color mgl32.Vec3) {
+	point := func(axis, i int) mgl32.Vec3 {
+		angle := 2 * math.Pi * float64(i) / sphereSegments
+		s, c := float32(math.Sin(angle))*radius, float32(math.Cos(angle))*radius
+		switch axis {
+		case 0:
+			return center.Add(mgl32.Vec3{0, s, c})
+		case 1:
+			return center.Add(mgl32.Vec3{s, 0, c})
+		}
+		return center.Add(mgl32.Vec3{s, c, 0})
+	}
+	for axis := 0; axis < 3; axis++ {
+		for i := 0; i < sphereSegments; i++ {
+			l.line(point(axis, i), point(axis, i+1), color)
+		}
+	}
+}
+
+// draw uploads the batched lines, draws them and clears the batch
+func (l *debugLines) draw(viewProjection mgl32.Mat4) {
+	if len(l.vertices) == 0 {
+		return
+	}
+	if l.vao == 0 {
+		l.shader = NewShaderFromFiles("debug_lines", "debug_lines.vert", "debug_lines.frag")
+		gl.GenVertexArrays(1, &l.vao)
+		gl.GenBuffers(1, &l.vbo)
+		gl.BindVertexArray(l.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, l.vbo)
+		gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 6*4, gl.PtrOffset(0))
+		gl.EnableVertexAttribArray(0)
+		gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 6*4, gl.PtrOffset(3*4))
+		gl.EnableVertexAttribArray(1)
+	}
+	l.shader.Use()
+	if l.shader.program == 0 {
+		l.vertices = l.vertices[:0]
+		return
+	}
+	l.shader.SetMat4("viewProjection", viewProjection)
+
+	gl.BindVertexArray(l.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, l.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(l.vertices)*4, gl.Ptr(l.vertices), gl.STREAM_DRAW)
+	gl.DrawArrays(gl.LINES, 0, int32(len(l.vertices)/6))
+	gl.BindVertexArray(0)
+	l.vertices = l.vertices[:0]
+}
+
+func (l *debugLines) delete() {
+	if l.vao != 0 {
+		gl.DeleteVertexArrays(1, &l.vao)
+		gl.DeleteBuffers(1, &l.vbo)
+		l.vao, l.vbo = 0, 0
+	}
+}
+
+var (
+	boundingSphereColor = mgl32.Vec3{0.2, 0.9, 1.0}
+	boundingBoxColor    = mgl32.Vec3{1.0, 0.85, 0.2}
+)
+
+// renderBoundsOverlay draws the bounding volumes of the visible models
+func (rend *OpenGLRenderer) renderBoundsOverlay(viewProjection mgl32.Mat4) {
+	if !ShowBoundingSpheres && !ShowBoundingBoxes {
+		return
+	}
+	for _, model := range rend.visibleModels {
+		if ShowBoundingSpheres {
+			rend.debugLines.sphere(model.BoundingSphereCenter, model.BoundingSphereRadius, boundingSphereColor)
+		}
+		if ShowBoundingBoxes {
+			lo, hi := model.WorldBounds()
+			rend.debugLines.box(lo, hi, boundingBoxColor)
+		}
+	}
+	rend.currentShaderProgram = 0
+	rend.debugLines.draw(viewProjection)
+}
+
+// renderDebugView draws the visible models with the debug shader in place of their own
+func (rend *OpenGLRenderer) renderDebugView(viewProjection mgl32.Mat4, camera Camera) {
+	if rend.debugViewShader.Name == "" {
+		rend.debugViewShader = NewShaderFromFiles("debug_view", "debug_view.vert", "debug_view.frag")
+	}
+	shader := &rend.debugViewShader
+	shader.Use()
+	if shader.program == 0 {
+		return
+	}
+	rend.currentShaderProgram = shader.program
+
+	shader.SetInt("debugMode", int32(DebugView))
+	shader.SetMat4("viewProjection", viewProjection)
+	shader.SetVec3("viewPos", camera.Position)
+	shader.SetFloat("nearPlane", camera.Near)
+	shader.SetFloat("farPlane", camera.Far)
+	shader.SetInt("textureSampler", 0)
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	if DebugView == DebugViewOverdraw {
+		// Every fragment adds up, hidden or not
+		rend.setDepthTest(false)
+		gl.DepthMask(false)
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.ONE, gl.ONE)
+	} else {
+		gl.DepthMask(true)
+		gl.Disable(gl.BLEND)
+	}
+
+	for i, model := range rend.visibleModels {
+		if model.IsDirty {
+			model.calculateModelMatrix()
+			model.IsDirty = false
+		}
+		shader.SetMat4("model", model.ModelMatrix)
+		shader.SetInt("objectID", int32(i))
+		shader.SetInt("lodLevel", int32(LODLevel(camera.ScreenCoverage(model.BoundingSphereCenter, model.BoundingSphereRadius))))
+
+		gl.BindVertexArray(model.VAO)
+		if len(model.MaterialGroups) > 0 {
+			for _, group := range model.MaterialGroups {
+				rend.bindDebugMaterial(shader, group.Material)
+				rend.drawElements(model, shader, group.IndexCount, int(group.IndexStart)*4)
+			}
+		} else {
+			rend.bindDebugMaterial(shader, model.Material)
+			rend.drawElements(model, shader, int32(len(model.Faces)), 0)
+		}
+	}
+	gl.BindVertexArray(0)
+
+	// Back to the state the normal passes leave behind
+	rend.setDepthTest(DepthTestEnabled)
+	rend.resetMaterialRenderState()
+}
+
+// bindDebugMaterial sets the color and texture the albedo view shows
+func (rend *OpenGLRenderer) bindDebugMaterial(shader *Shader, material *Material) {
+	if material == nil {
+		material = DefaultMaterial
+	}
+	shader.SetVec3("diffuseColor", mgl32.Vec3(material.DiffuseColor))
+	textureID := material.TextureID
+	if textureID == 0 {
+		textureID = DefaultMaterial.TextureID
+	}
+	gl.BindTexture(gl.TEXTURE_2D, textureID)
+}
+
+// shadingTexture returns the texture to bind for a model in the current view.
+// The lighting view swaps every texture for white so only light shows.
+func (rend *OpenGLRenderer) shadingTexture(textureID uint32) uint32 {
+	if DebugView != DebugViewLighting {
+		return textureID
+	}
+	if rend.whiteTexture == 0 {
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.Set(0, 0, color.White)
+		id, err := rend.CreateTextureFromImage(img)
+		if err != nil {
+			logger.Log.Error("Failed to create white texture for the lighting view", zap.Error(err))
+			return textureID
+		}
+		rend.whiteTexture = id
+	}
+	return rend.whiteTexture
+}
+
+const debugViewVertexShaderSource = `#version 330 core
+
+layout(location = 0) in vec3 inPosition;
+layout(location = 1) in vec2 inTexCoord;
+layout(location = 2) in vec3 inNormal;
+layout(location = 3) in mat4 instanceModel;
+layout(location = 7) in vec3 instanceColor;
+
+uniform bool isInstanced;
+uniform mat4 model;
+uniform mat4 viewProjection;
+
+out vec3 FragPos;
+out vec3 Normal;
+out vec2 fragTexCoord;
+out vec3 InstanceColor;
+flat out int InstanceID;
+
+void main() {
+    mat4 modelMatrix = isInstanced ? (model * instanceModel) : model;
+    FragPos = vec3(modelMatrix * vec4(inPosition, 1.0));
+    Normal = normalize(mat3(modelMatrix) * inNormal);
+    fragTexCoord = inTexCoord;
+    InstanceColor = isInstanced ? instanceColor : vec3(1.0);
+    InstanceID = isInstanced ? gl_InstanceID : -1;
+    gl_Position = viewProjection * vec4(FragPos, 1.0);
+}
+`
+
+const debugViewFragmentShaderSource = `#version 330 core
+
+in vec3 FragPos;
+in vec3 Normal;
+in vec2 fragTexCoord;
+in vec3 InstanceColor;
+flat in int InstanceID;
+
+uniform int debugMode; // DebugViewMode
+uniform sampler2D textureSampler;
+uniform vec3 diffuseColor;
+uniform vec3 viewPos;
+uniform float nearPlane;
+uniform float farPlane;
+uniform int objectID;
+uniform int lodLevel;
+
+out vec4 FragColor;
+
+vec3 hashColor(int id) {
+    uint h = uint(id) * 2654435761u;
+    h ^= h >> 16;
+    return vec3(float(h & 255u), float((h >> 8) & 255u), float((h >> 16) & 255u)) / 255.0 * 0.8 + 0.2;
+}
+
+void main() {
+    vec3 color = vec3(1.0, 0.0, 1.0);
+    if (debugMode == 1) {
+        color = normalize(Normal) * 0.5 + 0.5;
+    } else if (debugMode == 2) {
+        vec2 cell = floor(fragTexCoord * 8.0);
+        float checker = mod(cell.x + cell.y, 2.0) * 0.5 + 0.5;
+        color = vec3(fract(fragTexCoord), 0.0) * checker;
+    } else if (debugMode == 3) {
+        color = diffuseColor * texture(textureSampler, fragTexCoord).rgb * InstanceColor;
+    } else if (debugMode == 5) {
+        // Logarithmic so nearby detail doesn't wash out over long view distances
+        float d = log(max(distance(FragPos, viewPos), nearPlane) / nearPlane) / log(farPlane / nearPlane);
+        color = vec3(1.0 - clamp(d, 0.0, 1.0));
+    } else if (debugMode == 6) {
+        color = vec3(0.1, 0.04, 0.01);
+    } else if (debugMode == 7) {
+        color = hashColor(objectID * 7919 + InstanceID + 1);
+    } else if (debugMode == 8) {
+        vec3 levels[4] = vec3[](vec3(0.2, 0.8, 0.2), vec3(0.9, 0.9, 0.2), vec3(1.0, 0.55, 0.1), vec3(0.9, 0.15, 0.1));
+        color = levels[clamp(lodLevel, 0, 3)];
+    }
+    FragColor = vec4(color, 1.0);
+}
+`
+
+const debugLinesVertexShaderSource = `#version 330 core
+
+layout(location = 0) in vec3 inPosition;
+layout(location = 1) in vec3 inColor;
+
+uniform mat4 viewProjection;
+
+out vec3 lineColor;
+
+void main() {
+    lineColor = inColor;
+    gl_Position = viewProjection * vec4(inPosition, 1.0);
+}
+`
+
+const debugLinesFragmentShaderSource = `#version 330 core
+
+in vec3 lineColor;
+out vec4 FragColor;
+
+void main() {
+    FragColor = vec4(lineColor, 1.0);
+}
+`
@@ -0,0 +1,89 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestParseDebugViewMode(t *testing.T) {
+	for _, mode := range DebugViewModes {
+		got, ok := ParseDebugViewMode(mode.String())
+		if !ok || got != mode {
+			t.Errorf("ParseDebugViewMode(%q) = %v, %v", mode.String(), got, ok)
+		}
+	}
+	if got, ok := ParseDebugViewMode(" Normals "); !ok || got != DebugViewNormals {
+		t.Errorf("names should ignore case and spaces, got %v, %v", got, ok)
+	}
+	if _, ok := ParseDebugViewMode("xray"); ok {
+		t.Error("unknown names should not parse")
+	}
+}
+
+func TestLODLevelFromCoverage(t *testing.T) {
+	cam := NewDefaultCamera(800, 600)
+	cam.Position = mgl32.Vec3{0, 0, 0}
+
+	previous := -1
+	for _, distance := range []float32{2, 20, 60, 200, 2000} {
+		level := LODLevel(cam.ScreenCoverage(mgl32.Vec3{0, 0, -distance}, 1))
+		if level < previous {
+			t.Errorf("level %d at distance %v is more detailed than %d closer in", level, distance, previous)
+		}
+		previous = level
+	}
+	if previous != len(lodCoverage) {
+		t.Errorf("a distant model should reach the last level, got %d", previous)
+	}
+	if c := cam.ScreenCoverage(cam.Position, 1); c != 1 {
+		t.Errorf("a camera inside the sphere should have full coverage, got %v", c)
+	}
+}
+
+func TestWorldBounds(t *testing.T) {
+	model := &Model{
+		Vertices: []float32{-1, -1, -1, 1, 1, 1, 0, 0.5, 0},
+		Position: mgl32.Vec3{10, 0, 0},
+		Scale:    mgl32.Vec3{2, 2, 2},
+		Rotation: mgl32.QuatRotate(mgl32.DegToRad(45), mgl32.Vec3{0, 1, 0}),
+	}
+	model.calculateModelMatrix()
+
+	lo, hi := model.WorldBounds()
+	// A 4-unit cube turned 45 degrees about Y is 4*sqrt(2) wide in X and Z
+	half := float32(2 * 1.41421356)
+	if want := (mgl32.Vec3{10 - half, -2, -half}); lo.Sub(want).Len() > 1e-3 {
+		t.Errorf("min = %v, want %v", lo, want)
+	}
+	if want := (mgl32.Vec3{10 + half, 2, half}); hi.Sub(want).Len() > 1e-3 {
+		t.Errorf("max = %v, want %v", hi, want)
+	}
+
+	model.IsInstanced = true
+	model.InstanceModelMatrices = []mgl32.Mat4{mgl32.Ident4(), mgl32.Translate3D(0, 5, 0)}
+	if _, hi := model.WorldBounds(); hi.Y() < 11.99 || hi.Y() > 12.01 {
+		t.Errorf("instanced bounds should cover the raised instance, max y = %v", hi.Y())
+	}
+}
+
+func TestDebugLinesShapes(t *testing.T) {
+	var lines debugLines
+	lines.box(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{1, 2, 3}, boundingBoxColor)
+	if n := len(lines.vertices) / 12; n != 12 {
+		t.Errorf("box has %d edges, want 12", n)
+	}
+
+	lines.vertices = lines.vertices[:0]
+	center := mgl32.Vec3{1, 2, 3}
+	lines.sphere(center, 4, boundingSphereColor)
+	if n := len(lines.vertices) / 12; n != 3*sphereSegments {
+		t.Errorf("sphere has %d segments, want %d", n, 3*sphereSegments)
+	}
+	for i := 0; i < len(lines.vertices); i += 6 {
+		p := mgl32.Vec3{lines.vertices[i], lines.vertices[i+1], lines.vertices[i+2]}
+		if d := p.Sub(center).Len(); d < 3.999 || d > 4.001 {
+			t.Fatalf("sphere point %v is %v from the center, want 4", p, d)
+		}
+	}
+}
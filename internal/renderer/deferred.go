@@ -0,0 +1,756 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderPath selects how opaque geometry is lit
+type RenderPath int
+
+const (
+	RenderPathForward  RenderPath = iota // Each model is shaded as it is drawn, by the primary light only
+	RenderPathDeferred                   // Opaque default-shaded models are lit from a G-buffer by every light
+)
+
+var renderPathNames = [...]string{"forward", "deferred"}
+
+// RenderPaths lists every path, for editor pickers
+var RenderPaths = []RenderPath{RenderPathForward, RenderPathDeferred}
+
+func (p RenderPath) String() string {
+	if p < 0 || int(p) >= len(renderPathNames) {
+		return "forward"
+	}
+	return renderPathNames[p]
+}
+
+// ParseRenderPath looks a path up by name, as shown by String
+func ParseRenderPath(name string) (RenderPath, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range renderPathNames {
+		if n == name {
+			return RenderPath(i), true
+		}
+	}
+	return RenderPathForward, false
+}
+
+// lightCutoff is the fraction of full brightness below which a point light is
+// no longer drawn by the deferred path
+const lightCutoff = 1.0 / 256
+
+// lightRange returns how far a point light reaches before its attenuated
+// brightness drops below lightCutoff. bounded is false when the falloff never
+// gets there, so the light covers the whole screen. Area lights are measured
+// from their center and ignore the attenuation factors.
+func lightRange(light *Light) (radius float32, bounded bool) {
+	peak := light.Intensity * max(light.Color[0], light.Color[1], light.Color[2])
+	if light.IsArea() {
+		// Head on, a surface of area A lights a point d away by about A / (pi d²)
+		w, h := float64(light.AreaWidth), float64(light.AreaHeight)
+		reach := math.Sqrt(float64(peak) * math.Abs(w*h) / (math.Pi * lightCutoff))
+		return float32(reach + math.Hypot(w, h)/2), true
+	}
+	// Solve constant + linear*d + quadratic*d² = peak / cutoff for d
+	k := float64(peak/lightCutoff - light.ConstantAtten)
+	if k <= 0 {
+		return 0, true
+	}
+	l, q := float64(light.LinearAtten), float64(light.QuadraticAtten)
+	switch {
+	case q > 0:
+		return float32((-l + math.Sqrt(l*l+4*q*k)) / (2 * q)), true
+	case l > 0:
+		return float32(k / l), true
+	}
+	return 0, false
+}
+
+// lightScissor returns the pixels of a width x height target a light's sphere
+// can touch. ok is false when the sphere is entirely off screen.
+func lightScissor(center mgl32.Vec3, radius float32, viewProjection mgl32.Mat4, eye mgl32.Vec3, width, height int32) (x, y, w, h int32, ok bool) {
+	if center.Sub(eye).Len() <= radius {
+		return 0, 0, width, height, true
+	}
+	lo := mgl32.Vec2{float32(math.Inf(1)), float32(math.Inf(1))}
+	hi := mgl32.Vec2{float32(math.Inf(-1)), float32(math.Inf(-1))}
+	for i := 0; i < 8; i++ {
+		corner := center
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<axis) != 0 {
+				corner[axis] += radius
+			} else {
+				corner[axis] -= radius
+			}
+		}
+		clip := viewProjection.Mul4x1(corner.Vec4(1))
+		if clip.W() <= 0 {
+			// A corner behind the camera can't be projected; light the whole view
+			return 0, 0, width, height, true
+		}
+		ndc := mgl32.Vec2{clip.X() / clip.W(), clip.Y() / clip.W()}
+		lo = mgl32.Vec2{min(lo[0], ndc[0]), min(lo[1], ndc[1])}
+		hi = mgl32.Vec2{max(hi[0], ndc[0]), max(hi[1], ndc[1])}
+	}
+	if lo[0] >= 1 || lo[1] >= 1 || hi[0] <= -1 || hi[1] <= -1 {
+		return 0, 0, 0, 0, false
+	}
+	x0 := int32(math.Floor(float64((max(lo[0], -1)*0.5 + 0.5) * float32(width))))
+	y0 := int32(math.Floor(float64((max(lo[1], -1)*0.5 + 0.5) * float32(height))))
+	x1 := int32(math.Ceil(float64((min(hi[0], 1)*0.5 + 0.5) * float32(width))))
+	y1 := int32(math.Ceil(float64((min(hi[1], 1)*0.5 + 0.5) * float32(height))))
+	return x0, y0, x1 - x0, y1 - y0, x1 > x0 && y1 > y0
+}
+
+// gBuffer holds the surface attributes of a view's opaque geometry and the
+// light summed over them
+type gBuffer struct {
+	fbo      uint32
+	albedo   uint32 // Albedo, 8 bits per channel
+	normal   uint32 // World normal and material exposure, half float
+	surface  uint32 // Metallic, roughness and emissive
+	depth    uint32 // Depth, read back to rebuild world positions
+	lightFBO uint32
+	light    uint32 // HDR sum of every light, half float
+	width    int32
+	height   int32
+}
+
+// newGBuffer creates the framebuffers; attachments are made by resize
+func newGBuffer() *gBuffer {
+	g := &gBuffer{}
+	gl.GenFramebuffers(1, &g.fbo)
+	gl.GenFramebuffers(1, &g.lightFBO)
+	return g
+}
+
+// gBufferTexture allocates a screen-sized texture read back texel for texel
+func gBufferTexture(internalFormat int32, format, xtype uint32, width, height int32) uint32 {
+	var id uint32
+	gl.GenTextures(1, &id)
+	gl.BindTexture(gl.TEXTURE_2D, id)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, width, height, 0, format, xtype, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	return id
+}
+
+// resize recreates the attachments at a new size
+func (g *gBuffer) resize(width, height int32) {
+	g.deleteAttachments()
+	g.albedo = gBufferTexture(gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, width, height)
+	g.normal = gBufferTexture(gl.RGBA16F, gl.RGBA, gl.FLOAT, width, height)
+	g.surface = gBufferTexture(gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, width, height)
+	g.depth = gBufferTexture(gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.FLOAT, width, height)
+	g.light = gBufferTexture(gl.RGBA16F, gl.RGBA, gl.FLOAT, width, height)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, g.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, g.albedo, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT1, gl.TEXTURE_2D, g.normal, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT2, gl.TEXTURE_2D, g.surface, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, g.depth, 0)
+	attachments := [...]uint32{gl.COLOR_ATTACHMENT0, gl.COLOR_ATTACHMENT1, gl.COLOR_ATTACHMENT2}
+	gl.DrawBuffers(int32(len(attachments)), &attachments[0])
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		logger.Log.Error(fmt.Sprintf("G-buffer incomplete after resize! Status: 0x%X", status))
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, g.lightFBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, g.light, 0)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		logger.Log.Error("Deferred light buffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	g.width, g.height = width, height
+	logger.Log.Info(fmt.Sprintf("Resized G-buffer (%dx%d)", width, height))
+}
+
+func (g *gBuffer) deleteAttachments() {
+	for _, id := range []*uint32{&g.albedo, &g.normal, &g.surface, &g.depth, &g.light} {
+		if *id != 0 {
+			gl.DeleteTextures(1, id)
+			*id = 0
+		}
+	}
+}
+
+// delete releases the framebuffers and their attachments
+func (g *gBuffer) delete() {
+	g.deleteAttachments()
+	gl.DeleteFramebuffers(1, &g.fbo)
+	gl.DeleteFramebuffers(1, &g.lightFBO)
+	g.fbo, g.lightFBO = 0, 0
+}
+
+// bind puts the G-buffer textures on units 0 to 3
+func (g *gBuffer) bind(shader *Shader) {
+	for i, texture := range []uint32{g.albedo, g.normal, g.surface, g.depth} {
+		gl.ActiveTexture(gl.TEXTURE0 + uint32(i))
+		gl.BindTexture(gl.TEXTURE_2D, texture)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+	shader.SetInt("gAlbedo", 0)
+	shader.SetInt("gNormal", 1)
+	shader.SetInt("gSurface", 2)
+	shader.SetInt("gDepth", 3)
+}
+
+// gBuffer returns the G-buffer of the view being drawn
+func (rend *OpenGLRenderer) gBuffer() *gBuffer {
+	for len(rend.gBuffers) <= rend.activeView {
+		rend.gBuffers = append(rend.gBuffers, newGBuffer())
+	}
+	return rend.gBuffers[rend.activeView]
+}
+
+// drawsDeferred reports whether the deferred path draws a model's opaque groups.
+// Custom shaders and materials, water among them, stay forward.
+func (rend *OpenGLRenderer) drawsDeferred(model *Model) bool {
+	if model.CustomMaterial != nil {
+		return false
+	}
+	return !model.Shader.IsValid() || model.Shader.fragmentFile == rend.defaultShader.fragmentFile
+}
+
+// gBufferShaderFor picks the geometry pass variant for a model
+func (rend *OpenGLRenderer) gBufferShaderFor(model *Model) *Shader {
+	if rend.gBufferShader.Name == "" {
+		rend.gBufferShader = NewShaderFromFiles("gbuffer", "default.vert", "gbuffer.frag")
+	}
+	var features ShaderFeatures
+	if model.BlockTextureArray != 0 && DebugView != DebugViewLighting {
+		features = FeatureBlockTextures
+	}
+	return rend.shaderVariant(&rend.gBufferShader, features)
+}
+
+// renderDeferred draws the opaque groups of deferred models into the view's
+// G-buffer, lights them one light at a time and resolves the result into the
+// bound framebuffer along with its depth, so the forward passes draw on top.
+// Returns false when the G-buffer can't be used and every model goes forward.
+func (rend *OpenGLRenderer) renderDeferred(viewProjection mgl32.Mat4, camera Camera, primary *Light) bool {
+	var viewport, scissorBox [4]int32
+	var target int32
+	var clearColor [4]float32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	gl.GetIntegerv(gl.SCISSOR_BOX, &scissorBox[0])
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &target)
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &clearColor[0])
+	scissor := gl.IsEnabled(gl.SCISSOR_TEST)
+	if viewport[2] <= 0 || viewport[3] <= 0 {
+		return false
+	}
+
+	g := rend.gBuffer()
+	width, height := postProcessSize(viewport[2]), postProcessSize(viewport[3])
+	if width != g.width || height != g.height {
+		g.resize(width, height)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, g.fbo)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(target))
+		return false
+	}
+	gl.Viewport(0, 0, width, height)
+	gl.Disable(gl.SCISSOR_TEST)
+
+	endGeometry := rend.profilePass("G-Buffer")
+	rend.renderGBuffer(viewProjection)
+	endGeometry()
+
+	endLighting := rend.profilePass("Deferred Lighting")
+	inverseViewProjection := viewProjection.Inv()
+	lights := rend.Lights
+	if len(lights) == 0 && primary != nil {
+		lights = []*Light{primary}
+	}
+	rend.renderDeferredLights(g, lights, viewProjection, inverseViewProjection, camera)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(target))
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	gl.Scissor(scissorBox[0], scissorBox[1], scissorBox[2], scissorBox[3])
+	if scissor {
+		gl.Enable(gl.SCISSOR_TEST)
+	}
+	rend.resolveDeferred(g, inverseViewProjection, camera, primary)
+	endLighting()
+
+	gl.ClearColor(clearColor[0], clearColor[1], clearColor[2], clearColor[3])
+	if Debug {
+		gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+	}
+	// The lighting passes bind their own programs, so force the next model to rebind
+	rend.currentShaderProgram = 0
+	return true
+}
+
+// renderGBuffer writes the surface attributes of every visible deferred model
+func (rend *OpenGLRenderer) renderGBuffer(viewProjection mgl32.Mat4) {
+	gl.ClearColor(0, 0, 0, 0)
+	gl.DepthMask(true)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Disable(gl.BLEND)
+	rend.setFaceCulling(FaceCullingEnabled)
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	var program uint32
+	for _, model := range rend.visibleModels {
+		if !rend.drawsDeferred(model) {
+			continue
+		}
+		if model.IsDirty {
+			model.calculateModelMatrix()
+			model.IsDirty = false
+		}
+		shader := rend.gBufferShaderFor(model)
+		if program != shader.program || !shader.isCompiled {
+			shader.Use()
+			program = shader.program
+		}
+		if shader.program == 0 {
+			continue
+		}
+		shader.SetMat4("viewProjection", viewProjection)
+		shader.SetMat4("model", model.ModelMatrix)
+		shader.SetInt("textureSampler", 0)
+
+		gl.BindVertexArray(model.VAO)
+		bindBlockTextures(shader.uniformCache, model)
+		if len(model.MaterialGroups) > 0 {
+			for _, group := range model.MaterialGroups {
+				if group.Material != nil && group.Material.Alpha < 0.99 {
+					continue
+				}
+				rend.drawGBufferMaterial(shader, group.Material)
+				rend.drawElements(model, shader, group.IndexCount, int(group.IndexStart)*4)
+			}
+		} else if model.Material == nil || model.Material.Alpha >= 0.99 {
+			rend.drawGBufferMaterial(shader, model.Material)
+			rend.drawElements(model, shader, int32(len(model.Faces)), 0)
+		}
+	}
+	gl.BindVertexArray(0)
+	gl.Enable(gl.BLEND)
+}
+
+// drawGBufferMaterial sets the material uniforms and texture of the geometry pass
+func (rend *OpenGLRenderer) drawGBufferMaterial(shader *Shader, material *Material) {
+	rend.setMaterialUniforms(shader, material)
+	textureID := DefaultMaterial.TextureID
+	if material != nil && material.TextureID != 0 {
+		textureID = material.TextureID
+	}
+	gl.BindTexture(gl.TEXTURE_2D, rend.shadingTexture(textureID))
+}
+
+// renderDeferredLights sums every light over the G-buffer, each limited to the
+// pixels its range can reach
+func (rend *OpenGLRenderer) renderDeferredLights(g *gBuffer, lights []*Light, viewProjection, inverseViewProjection mgl32.Mat4, camera Camera) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, g.lightFBO)
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	if rend.deferredLightShader.Name == "" {
+		rend.deferredLightShader = NewShaderFromFiles("deferred_light", "fullscreen.vert", "deferred_light.frag")
+	}
+	shader := &rend.deferredLightShader
+	shader.Use()
+	if shader.program == 0 {
+		return
+	}
+	g.bind(shader)
+	shader.SetMat4("inverseViewProjection", inverseViewProjection)
+	shader.SetVec3("viewPos", camera.Position)
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.BindVertexArray(rend.screenQuadVAO)
+
+	frustum := camera.CalculateFrustum()
+	for _, light := range lights {
+		if light == nil || light.Intensity <= 0 {
+			continue
+		}
+		x, y, w, h := int32(0), int32(0), g.width, g.height
+		radius := float32(0)
+		if light.Mode != "directional" {
+			var bounded bool
+			radius, bounded = lightRange(light)
+			if bounded {
+				if radius <= 0 || !frustum.IntersectsSphere(light.Position, radius) {
+					continue
+				}
+				var visible bool
+				x, y, w, h, visible = lightScissor(light.Position, radius, viewProjection, camera.Position, g.width, g.height)
+				if !visible {
+					continue
+				}
+			}
+		}
+		gl.Scissor(x, y, w, h)
+
+		isDirectional := int32(0)
+		if light.Mode == "directional" {
+			isDirectional = 1
+		}
+		shader.SetVec3("light.position", light.Position)
+		shader.SetVec3("light.color", light.Color)
+		shader.SetFloat("light.intensity", light.Intensity)
+		shader.SetFloat("light.temperature", light.Temperature)
+		shader.SetInt("light.isDirectional", isDirectional)
+		shader.SetVec3("light.direction", light.Direction)
+		shader.SetFloat("light.constantAtten", light.ConstantAtten)
+		shader.SetFloat("light.linearAtten", light.LinearAtten)
+		shader.SetFloat("light.quadraticAtten", light.QuadraticAtten)
+		shader.SetFloat("light.radius", radius)
+		areaShape := light.areaShape()
+		shader.SetInt("light.areaShape", areaShape)
+		if areaShape != 0 {
+			right, up := light.AreaAxes()
+			shader.SetVec3("light.areaRight", right)
+			shader.SetVec3("light.areaUp", up)
+			rend.bindLTCTextures()
+			shader.SetInt("ltcMatrix", ltcTextureUnit)
+			shader.SetInt("ltcAmplitude", ltcTextureUnit+1)
+		}
+		if light.usesIES() {
+			right, up := lightFrame(light.Direction)
+			shader.SetInt("light.hasIES", 1)
+			shader.SetVec3("light.iesRight", right)
+			shader.SetVec3("light.iesUp", up)
+			rend.bindIESTexture(light.IES)
+			shader.SetInt("iesProfile", iesTextureUnit)
+		} else {
+			shader.SetInt("light.hasIES", 0)
+		}
+		gl.DrawArrays(gl.TRIANGLES, 0, 6)
+		rend.lastDrawCalls++
+	}
+
+	gl.BindVertexArray(0)
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	if rend.depthTestState {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+}
+
+// resolveDeferred adds ambient light, exposure, tone mapping and fog, and writes
+// the G-buffer depth so later passes test against the deferred geometry. Pixels
+// without geometry are left alone, keeping the sky.
+func (rend *OpenGLRenderer) resolveDeferred(g *gBuffer, inverseViewProjection mgl32.Mat4, camera Camera, primary *Light) {
+	if rend.deferredResolveShader.Name == "" {
+		rend.deferredResolveShader = NewShaderFromFiles("deferred_resolve", "fullscreen.vert", "light_resolve.frag")
+	}
+	shader := &rend.deferredResolveShader
+	shader.Use()
+	if shader.program == 0 {
+		return
+	}
+	g.bind(shader)
+	gl.ActiveTexture(gl.TEXTURE4)
+	gl.BindTexture(gl.TEXTURE_2D, g.light)
+	gl.ActiveTexture(gl.TEXTURE0)
+	shader.SetInt("lightBuffer", 4)
+	shader.SetMat4("inverseViewProjection", inverseViewProjection)
+	shader.SetVec3("viewPos", camera.Position)
+	if primary != nil {
+		shader.SetVec3("ambientColor", primary.Color.Mul(primary.AmbientStrength))
+		shader.SetFloat("ambientTemperature", primary.Temperature)
+	} else {
+		shader.SetVec3("ambientColor", mgl32.Vec3{})
+	}
+	if shader.uniformCache != nil {
+		setFogUniforms(shader.uniformCache, rend.Fog, camera.Far)
+	}
+
+	var depthFunc int32
+	gl.GetIntegerv(gl.DEPTH_FUNC, &depthFunc)
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthFunc(gl.ALWAYS)
+	gl.DepthMask(true)
+	gl.Disable(gl.BLEND)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+
+	gl.BindVertexArray(rend.screenQuadVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	gl.BindVertexArray(0)
+	rend.lastDrawCalls++
+
+	gl.DepthFunc(uint32(depthFunc))
+	if !rend.depthTestState {
+		gl.Disable(gl.DEPTH_TEST)
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+}
+
+// gBufferFragmentShaderSource is the geometry pass, drawn with default.vert
+const gBufferFragmentShaderSource = `#version 330 core
+in vec2 fragTexCoord;
+in vec3 Normal;
+in vec3 FragPos;
+in vec3 InstanceColor;
+
+uniform sampler2D textureSampler;
+uniform vec3 diffuseColor;
+uniform float metallic;
+uniform float roughness;
+uniform float exposure;
+
+#ifdef FEATURE_BLOCK_TEXTURES
+flat in float BlockLayer;
+flat in vec2 BlockSurface; // Negative components keep the material's value
+uniform sampler2DArray blockTextures;
+#endif
+
+layout(location = 0) out vec4 gAlbedo;  // Albedo
+layout(location = 1) out vec4 gNormal;  // World normal, exposure
+layout(location = 2) out vec4 gSurface; // Metallic, roughness, emissive
+
+void main() {
+    vec4 texColor = texture(textureSampler, fragTexCoord);
+    float surfaceMetallic = metallic;
+    float surfaceRoughness = roughness;
+#ifdef FEATURE_BLOCK_TEXTURES
+    if (BlockLayer >= 0.0) {
+        texColor = texture(blockTextures, vec3(fragTexCoord, BlockLayer));
+    }
+    surfaceMetallic = BlockSurface.x < 0.0 ? metallic : BlockSurface.x;
+    surfaceRoughness = BlockSurface.y < 0.0 ? roughness : BlockSurface.y;
+#endif
+
+    gAlbedo = vec4(diffuseColor * texColor.rgb * InstanceColor, 1.0);
+    gNormal = vec4(normalize(Normal), exposure);
+    // Exposures above 10 mark sun-like objects that skip lighting, as in the forward shader
+    gSurface = vec4(surfaceMetallic, surfaceRoughness, exposure > 10.0 ? 1.0 : 0.0, 1.0);
+}
+`
+
+// deferredLightingShaderSource holds the lighting model shared by the deferred
+// passes as lighting.glsl. It follows the default forward shader so switching
+// paths keeps materials looking the same.
+const deferredLightingShaderSource = `
+const float PI = 3.14159265359;
+
+vec3 kelvinToRGB(float kelvin) {
+    kelvin = clamp(kelvin, 1000.0, 12000.0);
+    if (kelvin < 3000.0) {
+        return mix(vec3(1.0, 0.4, 0.0), vec3(1.0, 0.7, 0.3), (kelvin - 1000.0) / 2000.0);
+    } else if (kelvin < 6500.0) {
+        return mix(vec3(1.0, 0.7, 0.3), vec3(1.0, 1.0, 1.0), (kelvin - 3000.0) / 3500.0);
+    }
+    return mix(vec3(1.0, 1.0, 1.0), vec3(0.7, 0.8, 1.0), (kelvin - 6500.0) / 5500.0);
+}
+
+// worldPosition rebuilds a G-buffer texel's position from its depth
+vec3 worldPosition(vec2 uv, float depth, mat4 inverseViewProjection) {
+    vec4 world = inverseViewProjection * vec4(vec3(uv, depth) * 2.0 - 1.0, 1.0);
+    return world.xyz / world.w;
+}
+
+vec3 fresnelSchlick(float cosTheta, vec3 F0) {
+    float f = clamp(1.0 - cosTheta, 0.0, 1.0);
+    float f2 = f * f;
+    return F0 + (1.0 - F0) * (f2 * f2 * f);
+}
+
+float distributionGGX(vec3 N, vec3 H, float roughness) {
+    float a = roughness * roughness;
+    float a2 = a * a;
+    float NdotH = max(dot(N, H), 0.0);
+    float denom = NdotH * NdotH * (a2 - 1.0) + 1.0;
+    return min(a2 / (PI * denom * denom), 10.0);
+}
+
+float geometrySchlickGGX(float NdotV, float roughness) {
+    float r = roughness + 1.0;
+    float k = (r * r) / 8.0;
+    return NdotV / (NdotV * (1.0 - k) + k);
+}
+
+float geometrySmith(vec3 N, vec3 V, vec3 L, float roughness) {
+    return geometrySchlickGGX(max(dot(N, V), 0.0), roughness) * geometrySchlickGGX(max(dot(N, L), 0.0), roughness);
+}
+
+// surfaceF0 is the reflectance at normal incidence, with the forward shader's metal tints
+vec3 surfaceF0(vec3 albedo, float metallic) {
+    vec3 F0 = vec3(0.04);
+    if (metallic <= 0.5) {
+        return mix(F0, albedo, metallic);
+    }
+    vec3 metalF0;
+    if (albedo.r > albedo.g && albedo.r > albedo.b) {
+        metalF0 = mix(vec3(0.95, 0.64, 0.54), albedo, 0.7);
+    } else if (albedo.g > albedo.r && albedo.g > albedo.b) {
+        metalF0 = mix(vec3(0.66, 0.88, 0.71), albedo, 0.7);
+    } else if (albedo.b > albedo.r && albedo.b > albedo.g) {
+        metalF0 = mix(vec3(0.56, 0.57, 0.58), albedo, 0.7);
+    } else {
+        metalF0 = mix(vec3(0.91, 0.92, 0.92), albedo, 0.5);
+    }
+    return mix(F0, metalF0, metallic);
+}
+
+// shadeLight returns the diffuse and specular light reflected toward the viewer from one light
+vec3 shadeLight(vec3 N, vec3 V, vec3 L, vec3 albedo, float metallic, float roughness, vec3 radiance) {
+    vec3 H = normalize(L + V);
+    float NdotV = clamp(dot(N, V), 0.001, 1.0);
+    float NdotL = max(dot(N, L), 0.0);
+    float adjustedRoughness = max(roughness, 0.08);
+
+    vec3 F = fresnelSchlick(clamp(dot(H, V), 0.001, 1.0), surfaceF0(albedo, metallic));
+    vec3 kD = (vec3(1.0) - F) * (1.0 - metallic);
+    vec3 specular = distributionGGX(N, H, adjustedRoughness) * geometrySmith(N, V, L, adjustedRoughness) * F /
+                    (4.0 * NdotV * NdotL + 0.0001);
+    specular *= pow(NdotV, 0.6) * 0.5;
+    return (kD * albedo / PI + specular) * radiance * NdotL;
+}
+
+// environmentReflection is the forward shader's flat sky reflection
+vec3 environmentReflection(float roughness, float metallic) {
+    float reflectionStrength = (1.0 - roughness * 0.9) * 0.5;
+    return vec3(0.6, 0.7, 0.9) * mix(0.05, 0.3, metallic) * reflectionStrength;
+}
+
+vec3 ACESFilm(vec3 x) {
+    return clamp((x * (2.51 * x + 0.03)) / (x * (2.43 * x + 0.59) + 0.14), 0.0, 1.0);
+}
+`
+
+// deferredLightFragmentShaderSource adds one light's contribution to the light buffer
+const deferredLightFragmentShaderSource = `#version 410 core
+in vec2 TexCoords;
+out vec4 FragColor;
+
+uniform sampler2D gAlbedo;
+uniform sampler2D gNormal;
+uniform sampler2D gSurface;
+uniform sampler2D gDepth;
+uniform mat4 inverseViewProjection;
+uniform vec3 viewPos;
+uniform struct Light {
+    vec3 position;
+    vec3 color;
+    float intensity;
+    float temperature;
+    int isDirectional;
+    vec3 direction;
+    float constantAtten;
+    float linearAtten;
+    float quadraticAtten;
+    float radius; // Point and area lights stop here; 0 is unbounded
+    int areaShape; // 0 = none, 1 = rectangle, 2 = disk
+    vec3 areaRight;
+    vec3 areaUp;
+    int hasIES;    // 1 when iesProfile shapes a point or spot light
+    vec3 iesRight; // Horizontal angle 0 and 90 of the profile
+    vec3 iesUp;
+} light;
+
+#include "lighting.glsl"
+#include "ltc.glsl"
+#include "ies.glsl"
+
+void main() {
+    float depth = texture(gDepth, TexCoords).r;
+    vec4 surface = texture(gSurface, TexCoords);
+    if (depth >= 1.0 || surface.b > 0.5) {
+        discard;
+    }
+    vec3 fragPos = worldPosition(TexCoords, depth, inverseViewProjection);
+    vec3 albedo = texture(gAlbedo, TexCoords).rgb;
+    vec3 N = normalize(texture(gNormal, TexCoords).xyz);
+    vec3 V = normalize(viewPos - fragPos);
+    vec3 lightColor = light.color * kelvinToRGB(light.temperature);
+
+    if (light.areaShape != 0) {
+        if (light.radius > 0.0 && length(light.position - fragPos) > light.radius) {
+            discard;
+        }
+        FragColor = vec4(areaLight(light.areaShape, light.position, light.areaRight, light.areaUp, N, V, fragPos,
+                                   albedo, surfaceF0(albedo, surface.r), surface.r, surface.g,
+                                   lightColor * light.intensity), 1.0);
+        return;
+    }
+
+    vec3 L;
+    float attenuation = 1.0;
+    if (light.isDirectional == 1) {
+        L = normalize(light.direction);
+    } else {
+        vec3 lightVec = light.position - fragPos;
+        float distance = length(lightVec);
+        if (light.radius > 0.0 && distance > light.radius) {
+            discard;
+        }
+        L = lightVec / distance;
+        attenuation = 1.0 / (light.constantAtten + light.linearAtten * distance + light.quadraticAtten * distance * distance);
+        if (light.hasIES == 1) {
+            attenuation *= iesFactor(-L, light.direction, light.iesRight, light.iesUp);
+        }
+    }
+
+    vec3 color = shadeLight(N, V, L, albedo, surface.r, surface.g, lightColor * light.intensity * attenuation);
+    // Soft fill on faces turned away, as the forward shader does for its light
+    color += max(-dot(N, L) * 0.3, 0.0) * lightColor * attenuation * albedo * 0.2;
+    FragColor = vec4(color, 1.0);
+}
+`
+
+// deferredResolveFragmentShaderSource turns the summed light into the final color
+const deferredResolveFragmentShaderSource = `#version 410 core
+in vec2 TexCoords;
+out vec4 FragColor;
+
+uniform sampler2D gAlbedo;
+uniform sampler2D gNormal;
+uniform sampler2D gSurface;
+uniform sampler2D gDepth;
+uniform sampler2D lightBuffer;
+uniform mat4 inverseViewProjection;
+uniform vec3 viewPos;
+uniform vec3 ambientColor; // Primary light color times its ambient strength
+uniform float ambientTemperature;
+
+#include "lighting.glsl"
+#include "fog.glsl"
+
+void main() {
+    float depth = texture(gDepth, TexCoords).r;
+    if (depth >= 1.0) {
+        discard;
+    }
+    gl_FragDepth = depth;
+
+    vec4 surface = texture(gSurface, TexCoords);
+    if (surface.b > 0.5) {
+        FragColor = vec4(1.0);
+        return;
+    }
+    vec3 albedo = texture(gAlbedo, TexCoords).rgb;
+    vec4 normal = texture(gNormal, TexCoords);
+    vec3 fragPos = worldPosition(TexCoords, depth, inverseViewProjection);
+
+    vec3 color = ambientColor * kelvinToRGB(ambientTemperature) * albedo * 0.8;
+    color += texture(lightBuffer, TexCoords).rgb;
+    color += environmentReflection(surface.g, surface.r) * 0.3;
+
+    color = ACESFilm(color * normal.w);
+    color = pow(color, vec3(1.0 / 2.2));
+    FragColor = vec4(applySceneFog(color, fragPos, viewPos), 1.0);
+}
+`
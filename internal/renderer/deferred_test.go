@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestParseRenderPath(t *testing.T) {
+	for _, path := range RenderPaths {
+		got, ok := ParseRenderPath(" " + path.String() + " ")
+		if !ok || got != path {
+			t.Errorf("ParseRenderPath(%q) = %v, %v", path.String(), got, ok)
+		}
+	}
+	if got, ok := ParseRenderPath(""); ok || got != RenderPathForward {
+		t.Error("scenes without a path should fall back to forward")
+	}
+}
+
+func TestLightRange(t *testing.T) {
+	light := CreatePointLight(mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}, 1, 100)
+	radius, bounded := lightRange(light)
+	if !bounded {
+		t.Fatal("a light with quadratic falloff should be bounded")
+	}
+	atten := 1 / (light.ConstantAtten + light.LinearAtten*radius + light.QuadraticAtten*radius*radius)
+	if math.Abs(float64(atten-lightCutoff)) > 1e-5 {
+		t.Errorf("brightness at the range is %v, want the cutoff %v", atten, lightCutoff)
+	}
+
+	brighter := *light
+	brighter.Intensity = 4
+	if r, _ := lightRange(&brighter); r <= radius {
+		t.Errorf("a brighter light should reach further, got %v <= %v", r, radius)
+	}
+
+	linear := *light
+	linear.QuadraticAtten = 0
+	if r, ok := lightRange(&linear); !ok || r != (256-1)/linear.LinearAtten {
+		t.Errorf("linear falloff range %v, %v", r, ok)
+	}
+
+	constant := *light
+	constant.LinearAtten, constant.QuadraticAtten = 0, 0
+	if _, ok := lightRange(&constant); ok {
+		t.Error("a light without falloff should cover the whole screen")
+	}
+
+	off := *light
+	off.Intensity = 0
+	if r, ok := lightRange(&off); !ok || r != 0 {
+		t.Error("a dark light should reach nowhere")
+	}
+}
+
+func TestLightScissor(t *testing.T) {
+	camera := NewDefaultCamera(800, 600)
+	camera.Position = mgl32.Vec3{0, 0, 10} // Facing -Z, at the origin
+	vp := camera.GetViewProjection()
+
+	x, y, w, h, ok := lightScissor(mgl32.Vec3{}, 1, vp, camera.Position, 800, 600)
+	if !ok || w <= 0 || h <= 0 || w >= 800 || h >= 600 {
+		t.Fatalf("a small light in front should get a partial rect, got %d,%d %dx%d", x, y, w, h)
+	}
+	if cx, cy := x+w/2, y+h/2; cx < 390 || cx > 410 || cy < 290 || cy > 310 {
+		t.Errorf("a light at the view center should be scissored around it, got center %d,%d", cx, cy)
+	}
+
+	if _, _, w, h, ok := lightScissor(camera.Position, 1, vp, camera.Position, 800, 600); !ok || w != 800 || h != 600 {
+		t.Error("a light around the camera should cover the whole view")
+	}
+	if _, _, _, _, ok := lightScissor(mgl32.Vec3{1000, 0, 0}, 1, vp, camera.Position, 800, 600); ok {
+		t.Error("a light far to the side should be skipped")
+	}
+}
@@ -0,0 +1,196 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// FogMode selects the distance fog falloff curve
+type FogMode string
+
+const (
+	FogNone               FogMode = "none" // No distance fog (height fog may still apply)
+	FogExponential        FogMode = "exp"  // 1 - e^(-density * d)
+	FogExponentialSquared FogMode = "exp2" // 1 - e^(-(density * d)^2)
+)
+
+// FogSettings describes scene-wide distance and height fog.
+// The same settings feed the default, water and skybox shaders so distant
+// geometry and the horizon fade into one color.
+type FogSettings struct {
+	Mode    FogMode    // Distance fog curve
+	Color   mgl32.Vec3 // Fog color (display space, like the clear color)
+	Density float32    // Distance fog density per world unit
+
+	// Height fog: density decays exponentially above HeightBase
+	HeightFogEnabled bool
+	HeightDensity    float32 // Density at HeightBase
+	HeightBase       float32 // World-space Y where the height fog is densest
+	HeightFalloff    float32 // How quickly density decays with altitude
+
+	MaxOpacity float32 // Upper bound for the final fog factor (0-1)
+	SkyBlend   float32 // How far above the horizon the sky is fogged (0-1 of the up vector)
+}
+
+// DefaultFogSettings returns disabled fog with reasonable values ready to be switched on
+func DefaultFogSettings() FogSettings {
+	return FogSettings{
+		Mode:             FogNone,
+		Color:            mgl32.Vec3{0.6, 0.7, 0.8},
+		Density:          0.0015,
+		HeightFogEnabled: false,
+		HeightDensity:    0.02,
+		HeightBase:       0.0,
+		HeightFalloff:    0.05,
+		MaxOpacity:       1.0,
+		SkyBlend:         0.3,
+	}
+}
+
+// IsEnabled reports whether any fog term contributes to the image
+func (f FogSettings) IsEnabled() bool {
+	distance := (f.Mode == FogExponential || f.Mode == FogExponentialSquared) && f.Density > 0
+	height := f.HeightFogEnabled && f.HeightDensity > 0
+	return (distance || height) && f.MaxOpacity > 0
+}
+
+// modeIndex maps the fog mode to the integer the shaders expect
+func (f FogSettings) modeIndex() int32 {
+	switch f.Mode {
+	case FogExponential:
+		return 1
+	case FogExponentialSquared:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Factor returns the fog amount (0 = clear, 1 = fully fogged) for a point seen
+// from cameraPos. It mirrors computeSceneFog in fogShaderSource.
+func (f FogSettings) Factor(worldPos, cameraPos mgl32.Vec3) float32 {
+	if !f.IsEnabled() {
+		return 0
+	}
+
+	delta := worldPos.Sub(cameraPos)
+	dist := float64(delta.Len())
+
+	distanceFog := 0.0
+	switch f.Mode {
+	case FogExponential:
+		distanceFog = 1.0 - math.Exp(-float64(f.Density)*dist)
+	case FogExponentialSquared:
+		d := float64(f.Density) * dist
+		distanceFog = 1.0 - math.Exp(-d*d)
+	}
+
+	heightFog := 0.0
+	if f.HeightFogEnabled && dist > 0 {
+		b := math.Max(float64(f.HeightFalloff), 1e-4)
+		rayY := float64(delta.Y()) / dist
+		camTerm := math.Exp(-clampFloat64((float64(cameraPos.Y())-float64(f.HeightBase))*b, -80, 80))
+		var optical float64
+		if math.Abs(rayY) > 1e-4 {
+			optical = float64(f.HeightDensity) / b * camTerm * (1.0 - math.Exp(-dist*rayY*b)) / rayY
+		} else {
+			optical = float64(f.HeightDensity) * camTerm * dist
+		}
+		heightFog = 1.0 - math.Exp(-optical)
+	}
+
+	fog := 1.0 - (1.0-distanceFog)*(1.0-heightFog)
+	return float32(clampFloat64(fog, 0, float64(f.MaxOpacity)))
+}
+
+func clampFloat64(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// setFogUniforms uploads fog settings to a shader that includes fog.glsl
+func setFogUniforms(cache *UniformCache, fog FogSettings, skyDistance float32) {
+	if !fog.IsEnabled() {
+		cache.SetInt("sceneFog.mode", 0)
+		cache.SetInt("sceneFog.heightEnabled", 0)
+		return
+	}
+	cache.SetInt("sceneFog.mode", fog.modeIndex())
+	cache.SetVec3("sceneFog.color", fog.Color[0], fog.Color[1], fog.Color[2])
+	cache.SetFloat("sceneFog.density", fog.Density)
+	heightEnabled := int32(0)
+	if fog.HeightFogEnabled {
+		heightEnabled = 1
+	}
+	cache.SetInt("sceneFog.heightEnabled", heightEnabled)
+	cache.SetFloat("sceneFog.heightDensity", fog.HeightDensity)
+	cache.SetFloat("sceneFog.heightBase", fog.HeightBase)
+	cache.SetFloat("sceneFog.heightFalloff", fog.HeightFalloff)
+	cache.SetFloat("sceneFog.maxOpacity", fog.MaxOpacity)
+	cache.SetFloat("sceneFog.skyBlend", fog.SkyBlend)
+	cache.SetFloat("sceneFog.skyDistance", skyDistance)
+}
+
+// fogShaderSource is shared by every shader that takes part in scene fog as
+// fog.glsl. It is included before main() so all passes evaluate the exact same curve.
+const fogShaderSource = `
+// Scene fog (distance + height), shared by default, water and skybox shaders
+uniform struct SceneFog {
+    int mode;             // 0 = off, 1 = exponential, 2 = exponential squared
+    vec3 color;
+    float density;
+    bool heightEnabled;
+    float heightDensity;
+    float heightBase;
+    float heightFalloff;
+    float maxOpacity;
+    float skyBlend;       // Fraction of the upper hemisphere that receives fog
+    float skyDistance;    // Distance used for the sky (camera far plane)
+} sceneFog;
+
+float computeSceneFog(vec3 worldPos, vec3 cameraPos) {
+    if (sceneFog.mode == 0 && !sceneFog.heightEnabled) {
+        return 0.0;
+    }
+
+    vec3 delta = worldPos - cameraPos;
+    float dist = length(delta);
+
+    float distanceFog = 0.0;
+    if (sceneFog.mode == 1) {
+        distanceFog = 1.0 - exp(-sceneFog.density * dist);
+    } else if (sceneFog.mode == 2) {
+        float d = sceneFog.density * dist;
+        distanceFog = 1.0 - exp(-d * d);
+    }
+
+    // Analytic integral of an exponential height density along the view ray
+    float heightFog = 0.0;
+    if (sceneFog.heightEnabled && dist > 0.0) {
+        float b = max(sceneFog.heightFalloff, 0.0001);
+        float rayY = delta.y / dist;
+        float camTerm = exp(-clamp((cameraPos.y - sceneFog.heightBase) * b, -80.0, 80.0));
+        float optical;
+        if (abs(rayY) > 0.0001) {
+            optical = sceneFog.heightDensity / b * camTerm * (1.0 - exp(-dist * rayY * b)) / rayY;
+        } else {
+            optical = sceneFog.heightDensity * camTerm * dist;
+        }
+        heightFog = 1.0 - exp(-optical);
+    }
+
+    float fog = 1.0 - (1.0 - distanceFog) * (1.0 - heightFog);
+    return clamp(fog, 0.0, sceneFog.maxOpacity);
+}
+
+// Sky has no depth: fog it as if it sat on the far plane, fading out above the horizon
+float computeSceneSkyFog(vec3 dir, vec3 cameraPos) {
+    vec3 farPos = cameraPos + dir * sceneFog.skyDistance;
+    float fog = computeSceneFog(farPos, cameraPos);
+    return fog * (1.0 - smoothstep(0.0, max(sceneFog.skyBlend, 0.001), dir.y));
+}
+
+vec3 applySceneFog(vec3 color, vec3 worldPos, vec3 cameraPos) {
+    return mix(color, sceneFog.color, computeSceneFog(worldPos, cameraPos));
+}
+`
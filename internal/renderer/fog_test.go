@@ -0,0 +1,84 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDefaultFogSettingsDisabled(t *testing.T) {
+	fog := DefaultFogSettings()
+
+	if fog.IsEnabled() {
+		t.Error("Default fog should be disabled")
+	}
+
+	if fog.Factor(mgl32.Vec3{0, 0, -1000}, mgl32.Vec3{}) != 0 {
+		t.Error("Disabled fog should not contribute")
+	}
+}
+
+func TestFogDistanceModes(t *testing.T) {
+	camera := mgl32.Vec3{0, 10, 0}
+	point := mgl32.Vec3{0, 10, -100}
+
+	fog := DefaultFogSettings()
+	fog.Density = 0.01
+
+	fog.Mode = FogExponential
+	exp := fog.Factor(point, camera)
+	if want := float32(1 - math.Exp(-1)); math.Abs(float64(exp-want)) > 1e-5 {
+		t.Errorf("Expected exp fog %f, got %f", want, exp)
+	}
+
+	fog.Mode = FogExponentialSquared
+	exp2 := fog.Factor(point, camera)
+	if want := float32(1 - math.Exp(-1)); math.Abs(float64(exp2-want)) > 1e-5 {
+		t.Errorf("Expected exp2 fog %f, got %f", want, exp2)
+	}
+
+	// exp2 stays clearer than exp close to the camera
+	near := mgl32.Vec3{0, 10, -20}
+	fog.Mode = FogExponential
+	nearExp := fog.Factor(near, camera)
+	fog.Mode = FogExponentialSquared
+	nearExp2 := fog.Factor(near, camera)
+	if nearExp2 >= nearExp {
+		t.Errorf("Expected exp2 (%f) to be lighter than exp (%f) near the camera", nearExp2, nearExp)
+	}
+}
+
+func TestFogMaxOpacity(t *testing.T) {
+	fog := DefaultFogSettings()
+	fog.Mode = FogExponential
+	fog.Density = 1.0
+	fog.MaxOpacity = 0.6
+
+	if f := fog.Factor(mgl32.Vec3{0, 0, -1000}, mgl32.Vec3{}); f != 0.6 {
+		t.Errorf("Expected fog clamped to 0.6, got %f", f)
+	}
+}
+
+func TestHeightFogThinsWithAltitude(t *testing.T) {
+	fog := DefaultFogSettings()
+	fog.HeightFogEnabled = true
+	fog.HeightDensity = 0.05
+	fog.HeightFalloff = 0.1
+
+	if !fog.IsEnabled() {
+		t.Fatal("Height fog alone should enable fog")
+	}
+
+	low := fog.Factor(mgl32.Vec3{0, 0, -200}, mgl32.Vec3{0, 0, 0})
+	high := fog.Factor(mgl32.Vec3{0, 100, -200}, mgl32.Vec3{0, 100, 0})
+	if low <= high {
+		t.Errorf("Expected denser fog near the base (%f) than high above it (%f)", low, high)
+	}
+
+	// Looking up out of the fog layer should be clearer than looking along it
+	up := fog.Factor(mgl32.Vec3{0, 200, 0}, mgl32.Vec3{0, 0, 0})
+	if up >= low {
+		t.Errorf("Expected upward ray (%f) to be clearer than horizontal ray (%f)", up, low)
+	}
+}
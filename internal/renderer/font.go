@@ -0,0 +1,444 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+const (
+	glyphEmPixels   = 48   // Em size glyphs are rasterized at before the distance transform
+	glyphSpread     = 6    // Pixels of distance stored on each side of an outline
+	fontAtlasWidth  = 1024 // Atlas rows are this wide; the atlas grows in height
+	fontAtlasStart  = 256
+	fontAtlasMaxLen = 4096
+)
+
+// Font is a TrueType or OpenType font. Glyphs are rendered on first use into a
+// signed distance field atlas, so text stays sharp at any size.
+type Font struct {
+	Name string
+
+	face *sfnt.Font
+	buf  sfnt.Buffer
+	ppem fixed.Int26_6
+
+	ascent, descent, lineHeight float32 // In ems
+
+	glyphs map[rune]*fontGlyph
+	atlas  glyphAtlas
+}
+
+// fontGlyph is a cached glyph. Extents are in ems relative to the pen on the
+// baseline, y up, and include the distance field's spread.
+type fontGlyph struct {
+	index   sfnt.GlyphIndex
+	advance float32
+
+	x0, y0, x1, y1 float32
+	ax, ay, aw, ah int // Atlas rect in pixels; aw == 0 for blank glyphs
+}
+
+// glyphAtlas packs distance fields into rows of a single-channel image
+type glyphAtlas struct {
+	pixels         []byte
+	height         int
+	penX, penY     int
+	rowHeight      int
+	version        int // Bumped when glyphs are added; layouts and the GPU copy compare it
+	textureID      uint32
+	textureVersion int
+}
+
+var (
+	fontCache   = make(map[string]*Font)
+	fontCacheMu sync.Mutex
+	defaultFont *Font
+)
+
+// ParseFont reads a TTF or OTF font from memory
+func ParseFont(name string, data []byte) (*Font, error) {
+	face, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	f := &Font{
+		Name:   name,
+		face:   face,
+		ppem:   fixed.I(glyphEmPixels),
+		glyphs: make(map[rune]*fontGlyph),
+	}
+	metrics, err := face.Metrics(&f.buf, f.ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	f.ascent = fixedToEm(metrics.Ascent)
+	f.descent = fixedToEm(metrics.Descent)
+	f.lineHeight = fixedToEm(metrics.Height)
+	if f.lineHeight <= 0 {
+		f.lineHeight = f.ascent + f.descent
+	}
+	f.atlas.pixels = make([]byte, fontAtlasWidth*fontAtlasStart)
+	f.atlas.height = fontAtlasStart
+	return f, nil
+}
+
+// LoadFont loads a font file, sharing fonts already loaded from the same path
+func LoadFont(path string) (*Font, error) {
+	fontCacheMu.Lock()
+	defer fontCacheMu.Unlock()
+	if f, ok := fontCache[path]; ok {
+		return f, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ParseFont(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), data)
+	if err != nil {
+		return nil, err
+	}
+	fontCache[path] = f
+	return f, nil
+}
+
+// DefaultFont returns the built-in Go Regular font
+func DefaultFont() *Font {
+	fontCacheMu.Lock()
+	defer fontCacheMu.Unlock()
+	if defaultFont == nil {
+		f, err := ParseFont("Go Regular", goregular.TTF)
+		if err != nil {
+			panic(err) // The embedded font always parses
+		}
+		defaultFont = f
+	}
+	return defaultFont
+}
+
+// LineHeight returns the distance between baselines in ems
+func (f *Font) LineHeight() float32 {
+	return f.lineHeight
+}
+
+func fixedToEm(v fixed.Int26_6) float32 {
+	return float32(v) / 64 / glyphEmPixels
+}
+
+// glyph returns the cached glyph for r, rendering it into the atlas on first use.
+// Runes the font lacks use its .notdef glyph.
+func (f *Font) glyph(r rune) *fontGlyph {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	g := &fontGlyph{}
+	f.glyphs[r] = g
+
+	index, err := f.face.GlyphIndex(&f.buf, r)
+	if err != nil {
+		return g
+	}
+	g.index = index
+	if advance, err := f.face.GlyphAdvance(&f.buf, index, f.ppem, font.HintingNone); err == nil {
+		g.advance = fixedToEm(advance)
+	}
+	segments, err := f.face.LoadGlyph(&f.buf, index, f.ppem, nil)
+	if err != nil || len(segments) == 0 {
+		return g
+	}
+
+	bounds := segments.Bounds()
+	minX, minY := bounds.Min.X.Floor()-glyphSpread, bounds.Min.Y.Floor()-glyphSpread
+	maxX, maxY := bounds.Max.X.Ceil()+glyphSpread, bounds.Max.Y.Ceil()+glyphSpread
+	w, h := maxX-minX, maxY-minY
+
+	x, y, ok := f.atlas.allocate(w, h)
+	if !ok {
+		return g
+	}
+	field := distanceField(rasterizeGlyph(segments, minX, minY, w, h), w, h, glyphSpread)
+	for row := 0; row < h; row++ {
+		copy(f.atlas.pixels[(y+row)*fontAtlasWidth+x:], field[row*w:(row+1)*w])
+	}
+	f.atlas.version++
+
+	// sfnt's y axis points down; glyph extents are kept y up
+	g.x0, g.x1 = float32(minX)/glyphEmPixels, float32(maxX)/glyphEmPixels
+	g.y0, g.y1 = -float32(maxY)/glyphEmPixels, -float32(minY)/glyphEmPixels
+	g.ax, g.ay, g.aw, g.ah = x, y, w, h
+	return g
+}
+
+// kern returns the kerning adjustment between two glyphs in ems. Pairs come
+// from the font's kern table; fonts that only kern through GPOS get none.
+func (f *Font) kern(a, b sfnt.GlyphIndex) float32 {
+	k, err := f.face.Kern(&f.buf, a, b, f.ppem, font.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return fixedToEm(k)
+}
+
+// allocate finds room for a w×h rect, growing the atlas when it runs out of rows
+func (a *glyphAtlas) allocate(w, h int) (int, int, bool) {
+	if w > fontAtlasWidth {
+		return 0, 0, false
+	}
+	if a.penX+w > fontAtlasWidth {
+		a.penX, a.penY, a.rowHeight = 0, a.penY+a.rowHeight, 0
+	}
+	for a.penY+h > a.height {
+		if a.height*2 > fontAtlasMaxLen {
+			return 0, 0, false
+		}
+		a.height *= 2
+		grown := make([]byte, fontAtlasWidth*a.height)
+		copy(grown, a.pixels)
+		a.pixels = grown
+	}
+	x, y := a.penX, a.penY
+	a.penX += w + 1
+	a.rowHeight = max(a.rowHeight, h+1)
+	return x, y, true
+}
+
+// rasterizeGlyph returns the coverage of a glyph outline in a w×h box at (minX, minY)
+func rasterizeGlyph(segments sfnt.Segments, minX, minY, w, h int) []byte {
+	r := vector.NewRasterizer(w, h)
+	point := func(p fixed.Point26_6) (float32, float32) {
+		return float32(p.X)/64 - float32(minX), float32(p.Y)/64 - float32(minY)
+	}
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			r.ClosePath()
+			r.MoveTo(point(seg.Args[0]))
+		case sfnt.SegmentOpLineTo:
+			r.LineTo(point(seg.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			bx, by := point(seg.Args[0])
+			cx, cy := point(seg.Args[1])
+			r.QuadTo(bx, by, cx, cy)
+		case sfnt.SegmentOpCubeTo:
+			bx, by := point(seg.Args[0])
+			cx, cy := point(seg.Args[1])
+			dx, dy := point(seg.Args[2])
+			r.CubeTo(bx, by, cx, cy, dx, dy)
+		}
+	}
+	r.ClosePath()
+	mask := image.NewAlpha(image.Rect(0, 0, w, h))
+	r.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return mask.Pix
+}
+
+// distanceField turns a coverage mask into a signed distance field. The outline
+// maps to 128, inside is brighter, and spread pixels away saturates.
+func distanceField(coverage []byte, w, h int, spread float64) []byte {
+	const far = 1e20
+	toInside := make([]float64, w*h)
+	toOutside := make([]float64, w*h)
+	for i, c := range coverage {
+		if c >= 128 {
+			toInside[i], toOutside[i] = 0, far
+		} else {
+			toInside[i], toOutside[i] = far, 0
+		}
+	}
+	squaredDistance2D(toInside, w, h)
+	squaredDistance2D(toOutside, w, h)
+
+	field := make([]byte, w*h)
+	for i := range field {
+		d := math.Sqrt(toInside[i]) - math.Sqrt(toOutside[i]) // Positive outside
+		v := 0.5 - d/(2*spread)
+		field[i] = byte(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return field
+}
+
+// squaredDistance2D replaces each value with the squared distance to the nearest
+// zero, using the separable transform of Felzenszwalb and Huttenlocher
+func squaredDistance2D(grid []float64, w, h int) {
+	n := max(w, h)
+	f, d := make([]float64, n), make([]float64, n)
+	v, z := make([]int, n), make([]float64, n+1)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			f[y] = grid[y*w+x]
+		}
+		squaredDistance1D(f[:h], d[:h], v, z)
+		for y := 0; y < h; y++ {
+			grid[y*w+x] = d[y]
+		}
+	}
+	for y := 0; y < h; y++ {
+		copy(f, grid[y*w:(y+1)*w])
+		squaredDistance1D(f[:w], d[:w], v, z)
+		copy(grid[y*w:], d[:w])
+	}
+}
+
+func squaredDistance1D(f, d []float64, v []int, z []float64) {
+	// Where the parabolas rooted at q and p intersect
+	intersect := func(q, p int) float64 {
+		fq, fp := float64(q), float64(p)
+		return ((f[q] + fq*fq) - (f[p] + fp*fp)) / (2*fq - 2*fp)
+	}
+	k := 0
+	v[0], z[0], z[1] = 0, math.Inf(-1), math.Inf(1)
+	for q := 1; q < len(f); q++ {
+		s := intersect(q, v[k])
+		for s <= z[k] {
+			k--
+			s = intersect(q, v[k])
+		}
+		k++
+		v[k], z[k], z[k+1] = q, s, math.Inf(1)
+	}
+	k = 0
+	for q := range f {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		dq := float64(q - v[k])
+		d[q] = dq*dq + f[v[k]]
+	}
+}
+
+// TextAlign places each line relative to the text's origin
+type TextAlign string
+
+const (
+	AlignLeft   TextAlign = "left"   // Lines start at the origin
+	AlignCenter TextAlign = "center" // Lines are centered on the origin
+	AlignRight  TextAlign = "right"  // Lines end at the origin
+)
+
+// TextAligns lists the alignments, in display order
+var TextAligns = []TextAlign{AlignLeft, AlignCenter, AlignRight}
+
+// TextLayout controls how a string is broken into lines and placed
+type TextLayout struct {
+	Size        float32 // Em size in output units
+	Align       TextAlign
+	LineSpacing float32 // Multiple of the font's line height; 0 is single spacing
+	MaxWidth    float32 // Lines wrap at spaces beyond this width; 0 never wraps
+}
+
+// GlyphQuad is one glyph of laid out text. Positions are y up with the top of
+// the first line at 0; UVs address the font atlas.
+type GlyphQuad struct {
+	X0, Y0, X1, Y1 float32
+	U0, V0, U1, V1 float32
+}
+
+// Layout breaks text into lines at newlines and, with MaxWidth set, at spaces,
+// then places the glyphs with kerning. It returns the quads and the block size.
+func (f *Font) Layout(text string, layout TextLayout) ([]GlyphQuad, float32, float32) {
+	size := layout.Size
+	if size <= 0 {
+		size = 1
+	}
+	spacing := layout.LineSpacing
+	if spacing <= 0 {
+		spacing = 1
+	}
+	maxWidth := layout.MaxWidth / size
+
+	var lines [][]rune
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		lines = append(lines, f.wrap([]rune(paragraph), maxWidth)...)
+	}
+
+	// Render every glyph first so the atlas height, and with it the UVs, is final
+	for _, line := range lines {
+		for _, r := range line {
+			f.glyph(r)
+		}
+	}
+
+	var quads []GlyphQuad
+	var width float32
+	atlasHeight := float32(f.atlas.height)
+	for i, line := range lines {
+		lineWidth := f.measure(line)
+		width = max(width, lineWidth)
+		var penX float32
+		switch layout.Align {
+		case AlignCenter:
+			penX = -lineWidth / 2
+		case AlignRight:
+			penX = -lineWidth
+		}
+		baseline := -f.ascent - float32(i)*f.lineHeight*spacing
+
+		var previous *fontGlyph
+		for _, r := range line {
+			g := f.glyph(r)
+			if previous != nil {
+				penX += f.kern(previous.index, g.index)
+			}
+			previous = g
+			if g.aw > 0 {
+				quads = append(quads, GlyphQuad{
+					X0: (penX + g.x0) * size, Y0: (baseline + g.y0) * size,
+					X1: (penX + g.x1) * size, Y1: (baseline + g.y1) * size,
+					U0: float32(g.ax) / fontAtlasWidth, V0: float32(g.ay+g.ah) / atlasHeight,
+					U1: float32(g.ax+g.aw) / fontAtlasWidth, V1: float32(g.ay) / atlasHeight,
+				})
+			}
+			penX += g.advance
+		}
+	}
+	height := f.ascent + f.descent + float32(max(len(lines)-1, 0))*f.lineHeight*spacing
+	return quads, width * size, height * size
+}
+
+// measure returns the advance width of a line in ems, kerning included
+func (f *Font) measure(line []rune) float32 {
+	var width float32
+	var previous *fontGlyph
+	for _, r := range line {
+		g := f.glyph(r)
+		if previous != nil {
+			width += f.kern(previous.index, g.index)
+		}
+		previous = g
+		width += g.advance
+	}
+	return width
+}
+
+// wrap splits a paragraph into lines no wider than maxWidth ems, breaking at
+// spaces. Words wider than a line get a line of their own.
+func (f *Font) wrap(paragraph []rune, maxWidth float32) [][]rune {
+	if maxWidth <= 0 || f.measure(paragraph) <= maxWidth {
+		return [][]rune{paragraph}
+	}
+	var lines [][]rune
+	var line []rune
+	for _, word := range strings.Split(string(paragraph), " ") {
+		candidate := []rune(word)
+		if len(line) > 0 {
+			candidate = append(append(append([]rune{}, line...), ' '), candidate...)
+		}
+		if len(line) > 0 && f.measure(candidate) > maxWidth {
+			lines = append(lines, line)
+			line = []rune(word)
+			continue
+		}
+		line = candidate
+	}
+	return append(lines, line)
+}
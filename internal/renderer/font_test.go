@@ -0,0 +1,85 @@
+package renderer
+
+import "testing"
+
+func TestDistanceField(t *testing.T) {
+	const size = 32
+	coverage := make([]byte, size*size)
+	for y := 8; y < 24; y++ {
+		for x := 8; x < 24; x++ {
+			coverage[y*size+x] = 255
+		}
+	}
+	field := distanceField(coverage, size, size, glyphSpread)
+
+	if v := field[16*size+16]; v != 255 {
+		t.Errorf("center of the square = %d, want 255", v)
+	}
+	if v := field[0]; v != 0 {
+		t.Errorf("far outside = %d, want 0", v)
+	}
+	inside, outside := field[16*size+8], field[16*size+7]
+	if inside <= 128 || outside >= 128 {
+		t.Errorf("edge pixels = %d inside, %d outside; want them on either side of 128", inside, outside)
+	}
+	if field[16*size+5] >= outside {
+		t.Error("the field should fall off away from the edge")
+	}
+}
+
+func TestFontLayout(t *testing.T) {
+	f := DefaultFont()
+
+	quads, width, height := f.Layout("Hi", TextLayout{Size: 10})
+	if len(quads) != 2 {
+		t.Fatalf("got %d quads for two letters", len(quads))
+	}
+	if width <= 0 || quads[1].X0 <= quads[0].X0 {
+		t.Errorf("glyphs should advance to the right, width %v", width)
+	}
+	if quads[0].Y1 > 0 || quads[0].Y0 >= quads[0].Y1 {
+		t.Errorf("glyphs should hang below the top edge, got y %v..%v", quads[0].Y0, quads[0].Y1)
+	}
+
+	if quads, _, _ := f.Layout("a b", TextLayout{Size: 10}); len(quads) != 2 {
+		t.Errorf("spaces should not produce quads, got %d", len(quads))
+	}
+
+	_, _, twoLines := f.Layout("Hi\nHi", TextLayout{Size: 10})
+	if got, want := twoLines-height, f.LineHeight()*10; got < want-1e-3 || got > want+1e-3 {
+		t.Errorf("a second line added %v, want one line height %v", got, want)
+	}
+
+	centered, centerWidth, _ := f.Layout("Hi", TextLayout{Size: 10, Align: AlignCenter})
+	if left := centered[0].X0 + glyphSpread*10.0/glyphEmPixels; left > -centerWidth/2+1 || left < -centerWidth/2-1 {
+		t.Errorf("centered text should start half its width left of the origin, starts at %v", left)
+	}
+	right, _, _ := f.Layout("Hi", TextLayout{Size: 10, Align: AlignRight})
+	if right[1].X1 > glyphSpread*10.0/glyphEmPixels+1 {
+		t.Errorf("right-aligned text should end at the origin, ends at %v", right[1].X1)
+	}
+}
+
+func TestFontWrapAndUnicode(t *testing.T) {
+	f := DefaultFont()
+
+	_, _, oneLine := f.Layout("one two three", TextLayout{Size: 10})
+	_, wrappedWidth, wrapped := f.Layout("one two three", TextLayout{Size: 10, MaxWidth: 25})
+	if wrapped <= oneLine {
+		t.Error("text wider than MaxWidth should wrap onto more lines")
+	}
+	if wrappedWidth > 25 {
+		t.Errorf("wrapped lines are %v wide, want at most 25", wrappedWidth)
+	}
+
+	for _, r := range "éЖΩ" {
+		if g := f.glyph(r); g.index == 0 || g.aw == 0 {
+			t.Errorf("glyph %q should come from the font, got index %d", r, g.index)
+		}
+	}
+	before := f.atlas.version
+	f.Layout("éЖΩ", TextLayout{Size: 10})
+	if f.atlas.version != before {
+		t.Error("cached glyphs should not be rendered again")
+	}
+}
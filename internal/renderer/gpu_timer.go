@@ -0,0 +1,144 @@
+package renderer
+
+import (
+	"Gopher3D/internal/profiler"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Frames a GPU timing may lag behind before its queries are read back
+const gpuTimerLatency = 4
+
+// gpuTimer measures render passes with timestamp queries. Results are read
+// gpuTimerLatency frames later so the CPU never stalls waiting for the GPU.
+type gpuTimer struct {
+	frames [gpuTimerLatency]gpuTimerFrame
+	slot   *gpuTimerFrame // Frame currently being recorded
+	free   []uint32       // Query objects ready for reuse
+	depth  int
+}
+
+type gpuTimerFrame struct {
+	index  uint64
+	start  uint32 // Timestamp taken when the frame began
+	scopes []gpuTimerScope
+}
+
+type gpuTimerScope struct {
+	name       string
+	depth      int
+	begin, end uint32
+}
+
+// query returns an unused query object
+func (t *gpuTimer) query() uint32 {
+	if n := len(t.free); n > 0 {
+		id := t.free[n-1]
+		t.free = t.free[:n-1]
+		return id
+	}
+	var id uint32
+	gl.GenQueries(1, &id)
+	return id
+}
+
+// beginFrame reads back the oldest frame's results and starts recording frame
+func (t *gpuTimer) beginFrame(frame uint64) {
+	slot := &t.frames[frame%gpuTimerLatency]
+	if slot.index == frame && slot.start != 0 {
+		return // Render ran again within the same frame
+	}
+	t.resolve(slot)
+
+	slot.index = frame
+	slot.start = t.query()
+	gl.QueryCounter(slot.start, gl.TIMESTAMP)
+	t.slot = slot
+	t.depth = 0
+}
+
+// begin starts timing a pass and returns the function that ends it
+func (t *gpuTimer) begin(name string) func() {
+	if t.slot == nil {
+		return func() {}
+	}
+	scope := gpuTimerScope{name: name, depth: t.depth, begin: t.query(), end: t.query()}
+	gl.QueryCounter(scope.begin, gl.TIMESTAMP)
+	t.depth++
+	slot := t.slot
+	return func() {
+		gl.QueryCounter(scope.end, gl.TIMESTAMP)
+		t.depth--
+		slot.scopes = append(slot.scopes, scope)
+	}
+}
+
+// resolve hands a finished frame's timings to the profiler and recycles its
+// queries. Results the GPU hasn't produced yet are dropped rather than waited on.
+func (t *gpuTimer) resolve(slot *gpuTimerFrame) {
+	if slot.start == 0 {
+		return
+	}
+	available := int32(gl.TRUE)
+	if n := len(slot.scopes); n > 0 {
+		gl.GetQueryObjectiv(slot.scopes[n-1].end, gl.QUERY_RESULT_AVAILABLE, &available)
+	}
+
+	var frameStart uint64
+	if available == gl.TRUE {
+		gl.GetQueryObjectui64v(slot.start, gl.QUERY_RESULT, &frameStart)
+	}
+	for _, s := range slot.scopes {
+		if available == gl.TRUE {
+			var begin, end uint64
+			gl.GetQueryObjectui64v(s.begin, gl.QUERY_RESULT, &begin)
+			gl.GetQueryObjectui64v(s.end, gl.QUERY_RESULT, &end)
+			profiler.Default.AddGPUScope(slot.index, profiler.Scope{
+				Name:     s.name,
+				Start:    time.Duration(begin - frameStart),
+				Duration: time.Duration(end - begin),
+				Depth:    s.depth,
+			})
+		}
+		t.free = append(t.free, s.begin, s.end)
+	}
+	t.free = append(t.free, slot.start)
+	slot.start = 0
+	slot.scopes = slot.scopes[:0]
+}
+
+// delete releases every query object
+func (t *gpuTimer) delete() {
+	for i := range t.frames {
+		slot := &t.frames[i]
+		if slot.start != 0 {
+			t.free = append(t.free, slot.start)
+		}
+		for _, s := range slot.scopes {
+			t.free = append(t.free, s.begin, s.end)
+		}
+		*slot = gpuTimerFrame{}
+	}
+	if len(t.free) > 0 {
+		gl.DeleteQueries(int32(len(t.free)), &t.free[0])
+	}
+	t.free = nil
+	t.slot = nil
+}
+
+// profilePass opens a CPU scope and a GPU timer for a render pass and returns
+// the function that closes both
+func (rend *OpenGLRenderer) profilePass(name string) func() {
+	frame := profiler.CurrentFrame()
+	if frame == 0 {
+		return func() {}
+	}
+	rend.gpuTimer.beginFrame(frame)
+	endGPU := rend.gpuTimer.begin(name)
+	endCPU := profiler.Begin(name)
+	return func() {
+		endGPU()
+		endCPU()
+	}
+}
@@ -0,0 +1,271 @@
+package renderer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// iesTextureUnit holds the primary light's IES profile, below the LTC tables
+const iesTextureUnit = 12
+
+// IES lookup texture resolution along the vertical (0-180°) and horizontal (0-360°) angles
+const (
+	iesTextureWidth  = 128
+	iesTextureHeight = 64
+)
+
+// IESProfile is a photometric light distribution read from an IES LM-63 file,
+// in type C photometry: vertical angle 0 points along the light's Direction
+// (nadir) and horizontal angles turn around it. Candela holds one row of
+// vertical samples per horizontal angle.
+type IESProfile struct {
+	Path             string // File the profile was loaded from, saved in scenes
+	VerticalAngles   []float32
+	HorizontalAngles []float32
+	Candela          [][]float32
+	MaxCandela       float32
+}
+
+var (
+	iesCache   = make(map[string]*IESProfile)
+	iesCacheMu sync.Mutex
+)
+
+// LoadIESProfile loads an IES file, sharing profiles already loaded from the same path
+func LoadIESProfile(path string) (*IESProfile, error) {
+	iesCacheMu.Lock()
+	defer iesCacheMu.Unlock()
+	if p, ok := iesCache[path]; ok {
+		return p, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseIES(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	p.Path = path
+	iesCache[path] = p
+	return p, nil
+}
+
+// ParseIES reads an IES LM-63 (1986, 1991, 1995 or 2002) photometric file.
+// Tilt data is skipped; only type C photometry is supported.
+func ParseIES(data []byte) (*IESProfile, error) {
+	text := strings.ReplaceAll(string(data), "\r", "")
+	tilt, found := "", false
+	for len(text) > 0 && !found {
+		var line string
+		line, text, _ = strings.Cut(text, "\n")
+		tilt, found = strings.CutPrefix(strings.TrimSpace(line), "TILT=")
+	}
+	if !found {
+		return nil, fmt.Errorf("missing TILT= line")
+	}
+
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == ','
+	})
+	next := func(what string) (float64, error) {
+		if len(fields) == 0 {
+			return 0, fmt.Errorf("file ends before %s", what)
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		fields = fields[1:]
+		if err != nil {
+			return 0, fmt.Errorf("bad %s: %w", what, err)
+		}
+		return v, nil
+	}
+	list := func(n int, what string) ([]float32, error) {
+		out := make([]float32, n)
+		for i := range out {
+			v, err := next(what)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = float32(v)
+		}
+		return out, nil
+	}
+
+	if strings.TrimSpace(tilt) == "INCLUDE" {
+		// Lamp geometry, then angle and multiplier pairs
+		if _, err := next("tilt geometry"); err != nil {
+			return nil, err
+		}
+		pairs, err := next("tilt pair count")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := list(2*int(pairs), "tilt data"); err != nil {
+			return nil, err
+		}
+	}
+
+	header, err := list(13, "photometric header")
+	if err != nil {
+		return nil, err
+	}
+	multiplier := header[2]
+	numVertical, numHorizontal := int(header[3]), int(header[4])
+	if numVertical < 1 || numHorizontal < 1 {
+		return nil, fmt.Errorf("needs at least one vertical and horizontal angle, got %d and %d", numVertical, numHorizontal)
+	}
+	if header[5] != 1 {
+		return nil, fmt.Errorf("only type C photometry is supported, got type %v", header[5])
+	}
+
+	p := &IESProfile{}
+	if p.VerticalAngles, err = list(numVertical, "vertical angles"); err != nil {
+		return nil, err
+	}
+	if p.HorizontalAngles, err = list(numHorizontal, "horizontal angles"); err != nil {
+		return nil, err
+	}
+	if !slices.IsSorted(p.VerticalAngles) || !slices.IsSorted(p.HorizontalAngles) {
+		return nil, fmt.Errorf("angles must be in ascending order")
+	}
+	p.Candela = make([][]float32, numHorizontal)
+	for h := range p.Candela {
+		if p.Candela[h], err = list(numVertical, "candela values"); err != nil {
+			return nil, err
+		}
+		for v := range p.Candela[h] {
+			p.Candela[h][v] *= multiplier
+			p.MaxCandela = max(p.MaxCandela, p.Candela[h][v])
+		}
+	}
+	return p, nil
+}
+
+// Intensity returns the candela toward the given angles in degrees, divided by
+// the peak so the brightest direction is 1. Horizontal symmetry follows the
+// last horizontal angle: 0 is round, 90 mirrors each quadrant and 180 mirrors
+// across the 0-180 plane. Angles outside the measured range are dark.
+func (p *IESProfile) Intensity(vertical, horizontal float32) float32 {
+	if p.MaxCandela <= 0 {
+		return 0
+	}
+	vs := p.VerticalAngles
+	if vertical < vs[0] || vertical > vs[len(vs)-1] {
+		return 0
+	}
+
+	h := float32(math.Mod(float64(horizontal), 360))
+	if h < 0 {
+		h += 360
+	}
+	switch last := p.HorizontalAngles[len(p.HorizontalAngles)-1]; {
+	case len(p.HorizontalAngles) == 1:
+		h = p.HorizontalAngles[0]
+	case last == 90:
+		if h > 180 {
+			h = 360 - h
+		}
+		if h > 90 {
+			h = 180 - h
+		}
+	case last == 180:
+		if h > 180 {
+			h = 360 - h
+		}
+	}
+
+	hi, ht := angleSegment(p.HorizontalAngles, h)
+	vi, vt := angleSegment(vs, vertical)
+	row := func(i int) float32 {
+		c := p.Candela[i]
+		if vi+1 >= len(c) {
+			return c[vi]
+		}
+		return c[vi] + (c[vi+1]-c[vi])*vt
+	}
+	value := row(hi)
+	if hi+1 < len(p.Candela) {
+		value += (row(hi+1) - value) * ht
+	}
+	return value / p.MaxCandela
+}
+
+// angleSegment finds the sample at or below angle and how far angle is toward
+// the next one, clamping to the ends
+func angleSegment(angles []float32, angle float32) (int, float32) {
+	i := sort.Search(len(angles), func(i int) bool { return angles[i] > angle }) - 1
+	if i < 0 {
+		return 0, 0
+	}
+	if i >= len(angles)-1 {
+		return len(angles) - 1, 0
+	}
+	return i, (angle - angles[i]) / (angles[i+1] - angles[i])
+}
+
+// bake samples the profile at texel centers, vertical angles along X and
+// horizontal along Y. Round profiles need a single row.
+func (p *IESProfile) bake() (data []float32, width, height int) {
+	width, height = iesTextureWidth, iesTextureHeight
+	if len(p.HorizontalAngles) == 1 {
+		height = 1
+	}
+	data = make([]float32, 0, width*height)
+	for y := 0; y < height; y++ {
+		horizontal := (float32(y) + 0.5) / float32(height) * 360
+		for x := 0; x < width; x++ {
+			data = append(data, p.Intensity((float32(x)+0.5)/float32(width)*180, horizontal))
+		}
+	}
+	return data, width, height
+}
+
+// usesIES reports whether the light's IES profile applies; directional and
+// area lights ignore it
+func (l *Light) usesIES() bool {
+	return l.IES != nil && (l.Mode == "point" || l.Mode == "spot")
+}
+
+// bindIESTexture uploads a profile's lookup texture on first use and binds it to iesTextureUnit
+func (rend *OpenGLRenderer) bindIESTexture(profile *IESProfile) {
+	tex, ok := rend.iesTextures[profile]
+	if !ok {
+		data, width, height := profile.bake()
+		gl.GenTextures(1, &tex)
+		gl.BindTexture(gl.TEXTURE_2D, tex)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R32F, int32(width), int32(height), 0, gl.RED, gl.FLOAT, gl.Ptr(data))
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT) // Horizontal angles wrap around
+		if rend.iesTextures == nil {
+			rend.iesTextures = make(map[*IESProfile]uint32)
+		}
+		rend.iesTextures[profile] = tex
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + iesTextureUnit)
+	gl.BindTexture(gl.TEXTURE_2D, tex)
+	gl.ActiveTexture(gl.TEXTURE0)
+}
+
+// iesShaderSource applies IES profiles, as ies.glsl
+const iesShaderSource = `
+uniform sampler2D iesProfile; // Candela over the peak, vertical angle along X and horizontal along Y
+
+// iesFactor scales a light toward a fragment by its profile. axis is the
+// profile's nadir and right its 0° horizontal direction.
+float iesFactor(vec3 lightToFrag, vec3 axis, vec3 right, vec3 up) {
+    vec3 d = normalize(lightToFrag);
+    float vertical = acos(clamp(dot(d, normalize(axis)), -1.0, 1.0));
+    float horizontal = atan(dot(d, up), dot(d, right) + 1e-7);
+    return texture(iesProfile, vec2(vertical / 3.14159265, horizontal / 6.28318531)).r;
+}
+`
@@ -0,0 +1,89 @@
+package renderer
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// A quadrant-symmetric type C profile with tilt data and comma separators
+const testIES = `IESNA:LM-63-2002
+[TEST] Gopher3D test
+[MANUFAC] None
+TILT=INCLUDE
+1
+3
+0, 45, 90
+1, 0.9, 0.8
+1 1000 2 3 2 1 2 0.5 0.5 0
+1.0 1.0 100
+0 45 90
+0 90
+100 50 0
+200 100 0
+`
+
+func TestParseIES(t *testing.T) {
+	p, err := ParseIES([]byte(testIES))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.VerticalAngles) != 3 || len(p.HorizontalAngles) != 2 || len(p.Candela) != 2 {
+		t.Fatalf("unexpected layout: %+v", p)
+	}
+	if p.MaxCandela != 400 {
+		t.Errorf("candela should include the multiplier, peak %v, want 400", p.MaxCandela)
+	}
+
+	round := strings.Replace(testIES, "1 1000 2 3 2 1", "1 1000 2 3 1 1", 1)
+	round = strings.Replace(round, "0 90\n", "0\n", 1)
+	if p, err := ParseIES([]byte(round)); err != nil || len(p.Candela) != 1 {
+		t.Errorf("round profile: %v", err)
+	}
+
+	for name, bad := range map[string]string{
+		"no tilt":   strings.Replace(testIES, "TILT=INCLUDE", "", 1),
+		"type B":    strings.Replace(testIES, "1 1000 2 3 2 1", "1 1000 2 3 2 2", 1),
+		"truncated": testIES[:len(testIES)-10],
+		"unsorted":  strings.Replace(testIES, "0 45 90\n0 90", "0 90 45\n0 90", 1),
+	} {
+		if _, err := ParseIES([]byte(bad)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestIESIntensity(t *testing.T) {
+	p, err := ParseIES([]byte(testIES))
+	if err != nil {
+		t.Fatal(err)
+	}
+	near := func(got, want float32) bool { return math.Abs(float64(got-want)) < 1e-5 }
+
+	if got := p.Intensity(0, 90); !near(got, 1) {
+		t.Errorf("peak should be 1, got %v", got)
+	}
+	if got := p.Intensity(22.5, 0); !near(got, 0.375) {
+		t.Errorf("vertical interpolation got %v, want 0.375", got)
+	}
+	if got := p.Intensity(0, 45); !near(got, 0.75) {
+		t.Errorf("horizontal interpolation got %v, want 0.75", got)
+	}
+	// Quadrant symmetry mirrors 90-360 back into 0-90
+	for _, h := range []float32{90, 270} {
+		if got := p.Intensity(0, h); !near(got, 1) {
+			t.Errorf("Intensity(0, %v) = %v, want the 90° row", h, got)
+		}
+	}
+	if got, want := p.Intensity(10, 300), p.Intensity(10, 60); !near(got, want) {
+		t.Errorf("300° should mirror 60°, got %v and %v", got, want)
+	}
+	if got := p.Intensity(120, 0); got != 0 {
+		t.Errorf("angles past the measured range should be dark, got %v", got)
+	}
+
+	data, w, h := p.bake()
+	if w != iesTextureWidth || h != iesTextureHeight || len(data) != w*h {
+		t.Errorf("bake size %dx%d with %d texels", w, h, len(data))
+	}
+}
@@ -0,0 +1,378 @@
+package renderer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// FirstInstanceAttributeLocation is the first vertex attribute location free for
+// custom per-instance data; lower ones hold the mesh, instance matrices, colors and
+// voxel block data. GL 4.1 guarantees 16 locations, leaving 10 to 15.
+const (
+	FirstInstanceAttributeLocation = 10
+	LastInstanceAttributeLocation  = 15
+)
+
+// InstanceAttributeType is the shader type of a per-instance attribute
+type InstanceAttributeType int
+
+const (
+	InstanceFloat InstanceAttributeType = iota
+	InstanceVec2
+	InstanceVec3
+	InstanceVec4
+	InstanceUint
+)
+
+// Components returns how many values one instance holds
+func (t InstanceAttributeType) Components() int {
+	switch t {
+	case InstanceVec2:
+		return 2
+	case InstanceVec3:
+		return 3
+	case InstanceVec4:
+		return 4
+	}
+	return 1
+}
+
+// GLSLType returns the type to declare the attribute with in a shader
+func (t InstanceAttributeType) GLSLType() string {
+	return [...]string{"float", "vec2", "vec3", "vec4", "uint"}[t]
+}
+
+// instanceRange tracks which instances changed since the last upload, as [lo, hi)
+type instanceRange struct{ lo, hi int }
+
+func (r *instanceRange) add(lo, hi int) {
+	if r.hi <= r.lo {
+		r.lo, r.hi = lo, hi
+		return
+	}
+	r.lo, r.hi = min(r.lo, lo), max(r.hi, hi)
+}
+
+func (r instanceRange) empty() bool { return r.hi <= r.lo }
+
+// InstanceAttribute is extra per-instance data a custom shader reads at Location,
+// declared as `layout(location = N) in <type> <name>;`. Changed instances are
+// uploaded before the model's next draw.
+type InstanceAttribute struct {
+	Name     string
+	Type     InstanceAttributeType
+	Location uint32
+
+	values   []uint32 // Components() values per instance; floats are stored as their bits
+	changed  instanceRange
+	vbo      uint32
+	capacity int // GPU buffer size in bytes
+}
+
+// Len returns the number of instances the attribute holds
+func (a *InstanceAttribute) Len() int {
+	return len(a.values) / a.Type.Components()
+}
+
+// Set stores an instance's value. Float types take up to four components; the
+// uint type takes the first one.
+func (a *InstanceAttribute) Set(index int, value ...float32) {
+	if a.Type == InstanceUint {
+		if len(value) > 0 {
+			a.SetUint(index, uint32(value[0]))
+		}
+		return
+	}
+	n := a.Type.Components()
+	if index < 0 || index >= a.Len() {
+		return
+	}
+	for c := 0; c < n && c < len(value); c++ {
+		a.values[index*n+c] = math.Float32bits(value[c])
+	}
+	a.changed.add(index, index+1)
+}
+
+// SetUint stores a uint instance's value
+func (a *InstanceAttribute) SetUint(index int, value uint32) {
+	if a.Type != InstanceUint || index < 0 || index >= a.Len() {
+		return
+	}
+	a.values[index] = value
+	a.changed.add(index, index+1)
+}
+
+// Float returns an instance's value; uint attributes are converted
+func (a *InstanceAttribute) Float(index int) mgl32.Vec4 {
+	var v mgl32.Vec4
+	if index < 0 || index >= a.Len() {
+		return v
+	}
+	n := a.Type.Components()
+	for c := 0; c < n; c++ {
+		bits := a.values[index*n+c]
+		if a.Type == InstanceUint {
+			v[c] = float32(bits)
+		} else {
+			v[c] = math.Float32frombits(bits)
+		}
+	}
+	return v
+}
+
+// Uint returns a uint instance's value
+func (a *InstanceAttribute) Uint(index int) uint32 {
+	if a.Type != InstanceUint || index < 0 || index >= a.Len() {
+		return 0
+	}
+	return a.values[index]
+}
+
+// resize grows or shrinks the attribute to count instances; new ones are zero
+func (a *InstanceAttribute) resize(count int) {
+	n := a.Type.Components()
+	old := a.Len()
+	if count*n <= cap(a.values) {
+		a.values = a.values[:count*n]
+		clear(a.values[min(old, count)*n:])
+	} else {
+		a.values = append(a.values, make([]uint32, count*n-len(a.values))...)
+	}
+	if count > old {
+		a.changed.add(old, count)
+	}
+}
+
+// move copies instance from over instance to, for swap removal
+func (a *InstanceAttribute) move(from, to int) {
+	n := a.Type.Components()
+	copy(a.values[to*n:to*n+n], a.values[from*n:from*n+n])
+	a.changed.add(to, to+1)
+}
+
+// removeOrdered drops an instance, shifting later ones down
+func (a *InstanceAttribute) removeOrdered(index int) {
+	n := a.Type.Components()
+	a.values = append(a.values[:index*n], a.values[(index+1)*n:]...)
+	a.changed.add(index, a.Len())
+}
+
+// AddInstanceAttribute declares a per-instance attribute with a zero value for
+// every current instance
+func (m *Model) AddInstanceAttribute(name string, t InstanceAttributeType, location uint32) (*InstanceAttribute, error) {
+	if location < FirstInstanceAttributeLocation || location > LastInstanceAttributeLocation {
+		return nil, fmt.Errorf("instance attribute %q: location %d is outside %d-%d",
+			name, location, FirstInstanceAttributeLocation, LastInstanceAttributeLocation)
+	}
+	if t < InstanceFloat || t > InstanceUint {
+		return nil, fmt.Errorf("instance attribute %q: unknown type %d", name, t)
+	}
+	for _, a := range m.InstanceAttributes {
+		if a.Name == name {
+			return nil, fmt.Errorf("instance attribute %q already exists", name)
+		}
+		if a.Location == location {
+			return nil, fmt.Errorf("instance attribute %q: location %d is used by %q", name, location, a.Name)
+		}
+	}
+	attr := &InstanceAttribute{Name: name, Type: t, Location: location}
+	attr.resize(m.InstanceCount)
+	m.InstanceAttributes = append(m.InstanceAttributes, attr)
+	return attr, nil
+}
+
+// InstanceAttribute returns the per-instance attribute with the given name, or nil
+func (m *Model) InstanceAttribute(name string) *InstanceAttribute {
+	for _, a := range m.InstanceAttributes {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// InstanceAttributeGLSL returns the vertex shader declarations of the model's
+// per-instance attributes, for use in a custom shader
+func (m *Model) InstanceAttributeGLSL() string {
+	var b strings.Builder
+	for _, a := range m.InstanceAttributes {
+		fmt.Fprintf(&b, "layout(location = %d) in %s %s;\n", a.Location, a.Type.GLSLType(), a.Name)
+	}
+	return b.String()
+}
+
+// AddInstance appends an instance and returns its index. Its color is white and
+// its attributes are zero; only the new instance is uploaded.
+func (m *Model) AddInstance(matrix mgl32.Mat4) int {
+	index := len(m.InstanceModelMatrices)
+	m.InstanceModelMatrices = append(m.InstanceModelMatrices, matrix)
+	if len(m.InstanceColors) > 0 {
+		m.InstanceColors = append(m.InstanceColors, mgl32.Vec3{1, 1, 1})
+	}
+	for _, a := range m.InstanceAttributes {
+		a.resize(index + 1)
+	}
+	m.InstanceCount = len(m.InstanceModelMatrices)
+	m.instanceChanges.add(index, index+1)
+	return index
+}
+
+// RemoveInstance removes an instance by moving the last one into its place, so
+// only that slot is uploaded. Use RemoveModelInstance to keep the order.
+func (m *Model) RemoveInstance(index int) {
+	last := len(m.InstanceModelMatrices) - 1
+	if index < 0 || index > last {
+		return
+	}
+	if index != last {
+		m.InstanceModelMatrices[index] = m.InstanceModelMatrices[last]
+		if len(m.InstanceColors) > last {
+			m.InstanceColors[index] = m.InstanceColors[last]
+		}
+		for _, a := range m.InstanceAttributes {
+			a.move(last, index)
+		}
+		m.instanceChanges.add(index, index+1)
+	}
+	m.InstanceModelMatrices = m.InstanceModelMatrices[:last]
+	if len(m.InstanceColors) > last {
+		m.InstanceColors = m.InstanceColors[:last]
+	}
+	for _, a := range m.InstanceAttributes {
+		a.resize(last)
+	}
+	m.InstanceCount = last
+}
+
+// SetInstanceMatrix replaces an instance's transform
+func (m *Model) SetInstanceMatrix(index int, matrix mgl32.Mat4) {
+	if index < 0 || index >= len(m.InstanceModelMatrices) {
+		return
+	}
+	m.InstanceModelMatrices[index] = matrix
+	m.instanceChanges.add(index, index+1)
+}
+
+// instanceDataChanged reports whether any instance data needs uploading before a draw
+func (m *Model) instanceDataChanged() bool {
+	if m.InstanceMatricesUpdated || !m.instanceChanges.empty() {
+		return true
+	}
+	for _, a := range m.InstanceAttributes {
+		if a.vbo == 0 || !a.changed.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// updateInstanceData uploads the instance data that changed since the last draw.
+// Call with the model's VAO bound.
+func (rend *OpenGLRenderer) updateInstanceData(model *Model) {
+	count := len(model.InstanceModelMatrices)
+	changes := model.instanceChanges
+	if model.InstanceMatricesUpdated {
+		changes = instanceRange{0, count}
+	}
+	if count == 0 {
+		changes = instanceRange{}
+	} else if model.InstanceVBO == 0 {
+		// Instances were added to a model that had none when it was added
+		createInstanceMatrixBuffer(model)
+		changes = instanceRange{}
+	}
+	if !changes.empty() && model.InstanceVBO != 0 {
+		gl.BindBuffer(gl.ARRAY_BUFFER, model.InstanceVBO)
+		model.InstanceVBOCapacity = uploadInstanceBuffer(gl.Ptr(model.InstanceModelMatrices), count,
+			int(unsafe.Sizeof(mgl32.Mat4{})), model.InstanceVBOCapacity, changes)
+	}
+	if !changes.empty() && model.InstanceColorVBO != 0 && len(model.InstanceColors) > 0 {
+		gl.BindBuffer(gl.ARRAY_BUFFER, model.InstanceColorVBO)
+		model.instanceColorCapacity = uploadInstanceBuffer(gl.Ptr(model.InstanceColors), len(model.InstanceColors),
+			int(unsafe.Sizeof(mgl32.Vec3{})), model.instanceColorCapacity, changes)
+	}
+	model.InstanceMatricesUpdated = false
+	model.instanceChanges = instanceRange{}
+
+	for _, a := range model.InstanceAttributes {
+		if a.vbo == 0 {
+			createInstanceAttributeBuffer(a)
+		} else if !a.changed.empty() && a.Len() > 0 {
+			gl.BindBuffer(gl.ARRAY_BUFFER, a.vbo)
+			a.capacity = uploadInstanceBuffer(gl.Ptr(a.values), a.Len(), a.Type.Components()*4, a.capacity, a.changed)
+		}
+		a.changed = instanceRange{}
+	}
+}
+
+// createInstanceMatrixBuffer uploads the instance matrices and points locations
+// 3-6 at them. Call with the model's VAO bound.
+func createInstanceMatrixBuffer(model *Model) {
+	gl.GenBuffers(1, &model.InstanceVBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, model.InstanceVBO)
+
+	// Exact size first; uploadInstanceBuffer grows it when instances are added
+	matrixSize := int(unsafe.Sizeof(mgl32.Mat4{}))
+	model.InstanceVBOCapacity = len(model.InstanceModelMatrices) * matrixSize
+	gl.BufferData(gl.ARRAY_BUFFER, model.InstanceVBOCapacity, gl.Ptr(model.InstanceModelMatrices), gl.DYNAMIC_DRAW)
+
+	for i := 0; i < 4; i++ {
+		gl.EnableVertexAttribArray(3 + uint32(i))
+		gl.VertexAttribPointerWithOffset(3+uint32(i), 4, gl.FLOAT, false, int32(matrixSize), uintptr(i*16))
+		gl.VertexAttribDivisor(3+uint32(i), 1)
+	}
+}
+
+// createInstanceAttributeBuffer uploads an attribute and points its location at it.
+// Call with the model's VAO bound.
+func createInstanceAttributeBuffer(a *InstanceAttribute) {
+	gl.GenBuffers(1, &a.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, a.vbo)
+	a.capacity = 0
+	if a.Len() > 0 {
+		a.capacity = uploadInstanceBuffer(gl.Ptr(a.values), a.Len(), a.Type.Components()*4, 0, instanceRange{0, a.Len()})
+	}
+
+	stride := int32(a.Type.Components() * 4)
+	gl.EnableVertexAttribArray(a.Location)
+	if a.Type == InstanceUint {
+		gl.VertexAttribIPointerWithOffset(a.Location, 1, gl.UNSIGNED_INT, stride, 0)
+	} else {
+		gl.VertexAttribPointerWithOffset(a.Location, int32(a.Type.Components()), gl.FLOAT, false, stride, 0)
+	}
+	gl.VertexAttribDivisor(a.Location, 1)
+}
+
+// uploadInstanceBuffer writes count elements of stride bytes into the bound
+// ARRAY_BUFFER and returns its capacity. A buffer that is too small is
+// reallocated with half again the room needed; otherwise only the changed
+// range is rewritten.
+func uploadInstanceBuffer(data unsafe.Pointer, count, stride, capacity int, changed instanceRange) int {
+	size := count * stride
+	if size > capacity {
+		newCapacity := size + size/2
+		gl.BufferData(gl.ARRAY_BUFFER, newCapacity, nil, gl.DYNAMIC_DRAW)
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, data)
+		return newCapacity
+	}
+	lo, hi := max(changed.lo, 0), min(changed.hi, count)
+	if hi > lo {
+		gl.BufferSubData(gl.ARRAY_BUFFER, lo*stride, (hi-lo)*stride, unsafe.Add(data, lo*stride))
+	}
+	return capacity
+}
+
+// deleteInstanceAttributeBuffers frees the GPU buffers of a model's attributes
+func deleteInstanceAttributeBuffers(model *Model) {
+	for _, a := range model.InstanceAttributes {
+		if a.vbo != 0 {
+			gl.DeleteBuffers(1, &a.vbo)
+			a.vbo, a.capacity = 0, 0
+		}
+	}
+}
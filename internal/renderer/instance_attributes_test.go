@@ -0,0 +1,110 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestAddInstanceAttributeValidates(t *testing.T) {
+	model := &Model{IsInstanced: true}
+	for range 3 {
+		model.AddInstance(mgl32.Ident4())
+	}
+
+	wind, err := model.AddInstanceAttribute("windPhase", InstanceFloat, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wind.Len() != 3 {
+		t.Errorf("attribute holds %d instances, want 3", wind.Len())
+	}
+	if _, err := model.AddInstanceAttribute("layer", InstanceUint, 10); err == nil {
+		t.Error("a second attribute at the same location should fail")
+	}
+	if _, err := model.AddInstanceAttribute("windPhase", InstanceVec2, 11); err == nil {
+		t.Error("a duplicate name should fail")
+	}
+	if _, err := model.AddInstanceAttribute("tint", InstanceVec4, 7); err == nil {
+		t.Error("locations used by the default layout should be rejected")
+	}
+	if model.InstanceAttribute("windPhase") != wind {
+		t.Error("InstanceAttribute should find the attribute by name")
+	}
+
+	model.AddInstanceAttribute("layer", InstanceUint, 11)
+	glsl := model.InstanceAttributeGLSL()
+	for _, want := range []string{"layout(location = 10) in float windPhase;", "layout(location = 11) in uint layer;"} {
+		if !strings.Contains(glsl, want) {
+			t.Errorf("GLSL declarations %q are missing %q", glsl, want)
+		}
+	}
+}
+
+func TestInstanceAttributeValues(t *testing.T) {
+	model := &Model{IsInstanced: true}
+	model.AddInstance(mgl32.Ident4())
+	model.AddInstance(mgl32.Ident4())
+	offset, _ := model.AddInstanceAttribute("offset", InstanceVec3, 12)
+	layer, _ := model.AddInstanceAttribute("layer", InstanceUint, 13)
+
+	offset.Set(1, 1, 2, 3)
+	if got := offset.Float(1); got != (mgl32.Vec4{1, 2, 3, 0}) {
+		t.Errorf("offset = %v, want (1, 2, 3)", got)
+	}
+	layer.SetUint(0, 7)
+	if layer.Uint(0) != 7 {
+		t.Errorf("layer = %d, want 7", layer.Uint(0))
+	}
+	offset.Set(5, 1) // Out of range is ignored
+	if offset.Len() != 2 {
+		t.Errorf("setting out of range changed the length to %d", offset.Len())
+	}
+}
+
+func TestInstanceChangesStaySmall(t *testing.T) {
+	model := &Model{
+		IsInstanced:           true,
+		InstanceCount:         1,
+		InstanceModelMatrices: []mgl32.Mat4{mgl32.Ident4()},
+		InstanceColors:        []mgl32.Vec3{{1, 0, 0}},
+	}
+	for i := 1; i < 10; i++ {
+		model.AddInstance(mgl32.Translate3D(float32(i), 0, 0))
+	}
+	damage, _ := model.AddInstanceAttribute("damage", InstanceFloat, 10)
+	for i := range 10 {
+		damage.Set(i, float32(i))
+	}
+	model.instanceChanges, damage.changed = instanceRange{}, instanceRange{}
+
+	// Adding one instance only touches its slot
+	index := model.AddInstance(mgl32.Translate3D(10, 0, 0))
+	if model.instanceChanges != (instanceRange{index, index + 1}) || damage.changed != (instanceRange{index, index + 1}) {
+		t.Errorf("adding changed %v and %v, want only instance %d", model.instanceChanges, damage.changed, index)
+	}
+	model.instanceChanges, damage.changed = instanceRange{}, instanceRange{}
+
+	// Swap removal moves the last instance into the hole
+	model.RemoveInstance(2)
+	if model.InstanceCount != 10 || len(model.InstanceColors) != 10 || damage.Len() != 10 {
+		t.Fatalf("after removal: %d instances, %d colors, %d values", model.InstanceCount, len(model.InstanceColors), damage.Len())
+	}
+	if model.InstanceModelMatrices[2].Col(3).X() != 10 || damage.Float(2).X() != 0 {
+		t.Errorf("instance 2 should now hold the last instance, got x = %v", model.InstanceModelMatrices[2].Col(3).X())
+	}
+	if model.instanceChanges != (instanceRange{2, 3}) {
+		t.Errorf("swap removal changed %v, want only instance 2", model.instanceChanges)
+	}
+
+	// Ordered removal keeps the order and re-uploads the tail
+	model.instanceChanges = instanceRange{}
+	model.RemoveModelInstance(0)
+	if damage.Float(0).X() != 1 || model.InstanceModelMatrices[0].Col(3).X() != 1 {
+		t.Error("ordered removal should shift later instances down")
+	}
+	if model.instanceChanges != (instanceRange{0, 9}) {
+		t.Errorf("ordered removal changed %v, want the whole tail", model.instanceChanges)
+	}
+}
@@ -0,0 +1,503 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.uber.org/zap"
+)
+
+// MaterialAssetExtension is the file extension of material assets
+const MaterialAssetExtension = ".gmat"
+
+// MaterialParamType is the shader type of a material parameter
+type MaterialParamType string
+
+const (
+	ParamFloat MaterialParamType = "float"
+	ParamInt   MaterialParamType = "int"
+	ParamBool  MaterialParamType = "bool"
+	ParamVec2  MaterialParamType = "vec2"
+	ParamVec3  MaterialParamType = "vec3"
+	ParamVec4  MaterialParamType = "vec4"
+	ParamColor MaterialParamType = "color" // vec3 edited with a color picker
+)
+
+// BlendMode selects how a material is blended with what is behind it
+type BlendMode string
+
+const (
+	BlendOpaque   BlendMode = "opaque"
+	BlendAlpha    BlendMode = "alpha"
+	BlendAdditive BlendMode = "additive"
+	BlendMultiply BlendMode = "multiply"
+)
+
+// CullMode selects which faces a material culls
+type CullMode string
+
+const (
+	CullBack  CullMode = "back"
+	CullFront CullMode = "front"
+	CullNone  CullMode = "none"
+)
+
+// BlendModes and CullModes list the valid render state values, in display order
+var (
+	BlendModes = []BlendMode{BlendOpaque, BlendAlpha, BlendAdditive, BlendMultiply}
+	CullModes  = []CullMode{CullBack, CullFront, CullNone}
+)
+
+// MaterialParam is a typed uniform declared by a material. Value always holds
+// Components() floats; ints and bools are stored as whole numbers.
+type MaterialParam struct {
+	Name  string            `json:"name"`
+	Type  MaterialParamType `json:"type"`
+	Value []float32         `json:"value"`
+	Min   float32           `json:"min,omitempty"` // Editor slider range, unused when Min == Max
+	Max   float32           `json:"max,omitempty"`
+}
+
+// MaterialTexture binds an image to a sampler2D uniform
+type MaterialTexture struct {
+	Name string `json:"name"` // Sampler uniform name
+	Path string `json:"path"` // Relative to the .gmat file unless absolute
+
+	Sampler SamplerSettings `json:"sampler,omitzero"`
+
+	textureID uint32
+	loaded    bool
+}
+
+// MaterialShaderRef names the shader files of a material. They are resolved
+// through the shader library, so project files in the shader directory work
+// alongside the built-in ones. Empty stages use default.vert/default.frag.
+type MaterialShaderRef struct {
+	Vertex   string `json:"vertex,omitempty"`
+	Fragment string `json:"fragment,omitempty"`
+}
+
+// MaterialRenderState holds the fixed-function state a material draws with
+type MaterialRenderState struct {
+	Blend      BlendMode `json:"blend,omitempty"`
+	Cull       CullMode  `json:"cull,omitempty"`
+	DepthWrite *bool     `json:"depthWrite,omitempty"` // Defaults to on for opaque materials
+}
+
+// MaterialAsset is a user material loaded from a .gmat JSON file
+type MaterialAsset struct {
+	Version     int                 `json:"version,omitempty"` // Format version, see materialAssetVersion
+	Name        string              `json:"name"`
+	Shader      MaterialShaderRef   `json:"shader"`
+	Parameters  []MaterialParam     `json:"parameters,omitempty"`
+	Textures    []MaterialTexture   `json:"textures,omitempty"`
+	RenderState MaterialRenderState `json:"renderState"`
+
+	Path     string          `json:"-"` // File the asset was loaded from
+	shader   *Shader         // Built lazily from Shader
+	textures *TextureManager // Holds the references of the loaded texture slots
+}
+
+// materialAssetVersion is the current .gmat format version. Version 1 samples
+// untagged textures as sRGB.
+const materialAssetVersion = 1
+
+// materialAssets caches loaded assets by absolute path so models share them
+var materialAssets = make(map[string]*MaterialAsset)
+
+// LoadMaterialAsset loads a .gmat file, returning the cached asset if it was already loaded
+func LoadMaterialAsset(path string) (*MaterialAsset, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = filepath.Clean(path)
+	}
+	if asset, ok := materialAssets[key]; ok {
+		return asset, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := ParseMaterialAsset(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	asset.Path = key
+	if asset.Name == "" {
+		asset.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	materialAssets[key] = asset
+	return asset, nil
+}
+
+// ReloadMaterialAsset re-reads the asset's file in place so every model using it updates
+func ReloadMaterialAsset(asset *MaterialAsset) error {
+	data, err := os.ReadFile(asset.Path)
+	if err != nil {
+		return err
+	}
+	fresh, err := ParseMaterialAsset(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(asset.Path), err)
+	}
+	fresh.Path = asset.Path
+	if fresh.Name == "" {
+		fresh.Name = asset.Name
+	}
+	asset.Release()
+	*asset = *fresh
+	return nil
+}
+
+// Release drops the references held by the asset's textures. Models still
+// drawing the asset load them again on their next draw.
+func (m *MaterialAsset) Release() {
+	for i := range m.Textures {
+		t := &m.Textures[i]
+		if t.textureID != 0 && m.textures != nil {
+			m.textures.ReleaseTexture(t.textureID)
+		}
+		t.textureID = 0
+		t.loaded = false
+	}
+	m.textures = nil
+}
+
+// ParseMaterialAsset decodes and validates .gmat JSON
+func ParseMaterialAsset(data []byte) (*MaterialAsset, error) {
+	var asset MaterialAsset
+	if err := json.Unmarshal(data, &asset); err != nil {
+		return nil, err
+	}
+	asset.migrate()
+	if err := asset.validate(); err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// migrate upgrades assets saved by older versions to materialAssetVersion
+func (m *MaterialAsset) migrate() {
+	if m.Version < 1 {
+		// Textures were sampled as stored before color spaces existed, so keep
+		// untagged slots linear. Render textures are always drawn as sRGB.
+		for i := range m.Textures {
+			t := &m.Textures[i]
+			if _, ok := RenderTextureName(t.Path); !ok && t.Sampler.ColorSpace == "" {
+				t.Sampler.ColorSpace = ColorSpaceLinear
+			}
+		}
+	}
+	m.Version = materialAssetVersion
+}
+
+// validate fills in defaults and rejects malformed declarations
+func (m *MaterialAsset) validate() error {
+	seen := make(map[string]bool)
+	for i := range m.Parameters {
+		p := &m.Parameters[i]
+		if p.Name == "" {
+			return fmt.Errorf("parameter %d has no name", i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate parameter %q", p.Name)
+		}
+		seen[p.Name] = true
+
+		n := p.Type.Components()
+		if n == 0 {
+			return fmt.Errorf("parameter %q has unknown type %q", p.Name, p.Type)
+		}
+		if len(p.Value) == 0 {
+			p.Value = make([]float32, n)
+		} else if len(p.Value) != n {
+			return fmt.Errorf("parameter %q: %s needs %d values, got %d", p.Name, p.Type, n, len(p.Value))
+		}
+	}
+
+	for i, t := range m.Textures {
+		if t.Name == "" || t.Path == "" {
+			return fmt.Errorf("texture %d needs a name and a path", i)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("duplicate parameter %q", t.Name)
+		}
+		seen[t.Name] = true
+		if err := t.Sampler.validate(); err != nil {
+			return fmt.Errorf("texture %q: %w", t.Name, err)
+		}
+	}
+
+	switch m.RenderState.Blend {
+	case "":
+		m.RenderState.Blend = BlendOpaque
+	case BlendOpaque, BlendAlpha, BlendAdditive, BlendMultiply:
+	default:
+		return fmt.Errorf("unknown blend mode %q", m.RenderState.Blend)
+	}
+	switch m.RenderState.Cull {
+	case "":
+		m.RenderState.Cull = CullBack
+	case CullBack, CullFront, CullNone:
+	default:
+		return fmt.Errorf("unknown cull mode %q", m.RenderState.Cull)
+	}
+	return nil
+}
+
+// Components returns how many floats a parameter of this type holds (0 if unknown)
+func (t MaterialParamType) Components() int {
+	switch t {
+	case ParamFloat, ParamInt, ParamBool:
+		return 1
+	case ParamVec2:
+		return 2
+	case ParamVec3, ParamColor:
+		return 3
+	case ParamVec4:
+		return 4
+	}
+	return 0
+}
+
+// UnmarshalJSON accepts scalar values and booleans as well as arrays
+func (p *MaterialParam) UnmarshalJSON(data []byte) error {
+	type plain MaterialParam
+	var raw struct {
+		plain
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = MaterialParam(raw.plain)
+	p.Value = nil
+
+	value := strings.TrimSpace(string(raw.Value))
+	switch {
+	case value == "" || value == "null":
+	case value == "true" || value == "false":
+		p.Value = []float32{0}
+		if value == "true" {
+			p.Value[0] = 1
+		}
+	case strings.HasPrefix(value, "["):
+		if err := json.Unmarshal(raw.Value, &p.Value); err != nil {
+			return fmt.Errorf("parameter %q: %w", p.Name, err)
+		}
+	default:
+		var f float32
+		if err := json.Unmarshal(raw.Value, &f); err != nil {
+			return fmt.Errorf("parameter %q: %w", p.Name, err)
+		}
+		p.Value = []float32{f}
+	}
+	return nil
+}
+
+// MarshalJSON writes single-component values as scalars so saved files stay readable
+func (p MaterialParam) MarshalJSON() ([]byte, error) {
+	type plain MaterialParam
+	out := struct {
+		plain
+		Value interface{} `json:"value"`
+	}{plain: plain(p), Value: p.Value}
+
+	if len(p.Value) == 1 {
+		switch p.Type {
+		case ParamBool:
+			out.Value = p.Value[0] != 0
+		case ParamInt:
+			out.Value = int32(p.Value[0])
+		default:
+			out.Value = p.Value[0]
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Param returns the parameter with the given name, or nil
+func (m *MaterialAsset) Param(name string) *MaterialParam {
+	for i := range m.Parameters {
+		if m.Parameters[i].Name == name {
+			return &m.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// SetParam sets a declared parameter's value
+func (m *MaterialAsset) SetParam(name string, values ...float32) error {
+	p := m.Param(name)
+	if p == nil {
+		return fmt.Errorf("material %q has no parameter %q", m.Name, name)
+	}
+	if len(values) != len(p.Value) {
+		return fmt.Errorf("parameter %q: %s needs %d values, got %d", name, p.Type, len(p.Value), len(values))
+	}
+	copy(p.Value, values)
+	return nil
+}
+
+// SetTexture points a declared sampler at a new image, loaded on next draw
+func (m *MaterialAsset) SetTexture(name, path string) error {
+	for i := range m.Textures {
+		if m.Textures[i].Name == name {
+			m.Textures[i] = MaterialTexture{Name: name, Path: path, Sampler: m.Textures[i].Sampler}
+			return nil
+		}
+	}
+	return fmt.Errorf("material %q has no texture %q", m.Name, name)
+}
+
+// ResolvePath resolves a path in the asset relative to the .gmat file. Render
+// texture paths are returned unchanged.
+func (m *MaterialAsset) ResolvePath(path string) string {
+	if _, ok := RenderTextureName(path); ok || filepath.IsAbs(path) || m.Path == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(m.Path), filepath.FromSlash(path))
+}
+
+// Encode serializes the asset as indented .gmat JSON
+func (m *MaterialAsset) Encode() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// Save writes the asset back to the file it was loaded from
+func (m *MaterialAsset) Save() error {
+	if m.Path == "" {
+		return fmt.Errorf("material %q has no file", m.Name)
+	}
+	data, err := m.Encode()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.Path, data, 0644)
+}
+
+// IsTransparent returns true if the material draws in the transparent pass
+func (s MaterialRenderState) IsTransparent() bool {
+	return s.Blend != "" && s.Blend != BlendOpaque
+}
+
+// WritesDepth returns whether the material writes to the depth buffer
+func (s MaterialRenderState) WritesDepth() bool {
+	if s.DepthWrite != nil {
+		return *s.DepthWrite
+	}
+	return !s.IsTransparent()
+}
+
+// shaderProgram returns the material's own shader, or nil if it uses the model's
+func (m *MaterialAsset) shaderProgram() *Shader {
+	if m.Shader.Vertex == "" && m.Shader.Fragment == "" {
+		return nil
+	}
+	if m.shader == nil {
+		vertex, fragment := m.Shader.Vertex, m.Shader.Fragment
+		if vertex == "" {
+			vertex = "default.vert"
+		}
+		if fragment == "" {
+			fragment = "default.frag"
+		}
+		shader := NewShaderFromFiles("material:"+m.Name, vertex, fragment)
+		m.shader = &shader
+	}
+	return m.shader
+}
+
+// applyMaterialRenderState sets blending, culling and depth writes for a material
+func (rend *OpenGLRenderer) applyMaterialRenderState(state MaterialRenderState) {
+	gl.DepthMask(state.WritesDepth())
+
+	switch state.Blend {
+	case BlendAlpha:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	case BlendAdditive:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+	case BlendMultiply:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.DST_COLOR, gl.ZERO)
+	default:
+		gl.Disable(gl.BLEND)
+	}
+
+	switch state.Cull {
+	case CullNone:
+		rend.setFaceCulling(false)
+	case CullFront:
+		rend.setFaceCulling(true)
+		gl.CullFace(gl.FRONT)
+	default:
+		rend.setFaceCulling(FaceCullingEnabled)
+	}
+}
+
+// resetMaterialRenderState restores the state the built-in passes expect
+func (rend *OpenGLRenderer) resetMaterialRenderState() {
+	rend.setFaceCulling(FaceCullingEnabled)
+	gl.CullFace(gl.BACK)
+	gl.DepthMask(true)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+}
+
+// setMaterialAssetUniforms uploads a material's parameters and binds its textures.
+// Textures use units 1 and up; unit 0 stays the model's textureSampler.
+func (rend *OpenGLRenderer) setMaterialAssetUniforms(uc *UniformCache, m *MaterialAsset) {
+	if m == nil {
+		return
+	}
+
+	for i := range m.Parameters {
+		p := &m.Parameters[i]
+		v := p.Value
+		switch p.Type {
+		case ParamFloat:
+			uc.SetFloat(p.Name, v[0])
+		case ParamInt, ParamBool:
+			uc.SetInt(p.Name, int32(v[0]))
+		case ParamVec2:
+			uc.SetVec2(p.Name, v[0], v[1])
+		case ParamVec3, ParamColor:
+			uc.SetVec3(p.Name, v[0], v[1], v[2])
+		case ParamVec4:
+			uc.SetVec4(p.Name, v[0], v[1], v[2], v[3])
+		}
+	}
+
+	unit := uint32(1)
+	for i := range m.Textures {
+		t := &m.Textures[i]
+		if !t.loaded {
+			t.loaded = true
+			m.textures = rend.textureManager
+			id, err := rend.textureManager.LoadTextureWithSampler(m.ResolvePath(t.Path), t.Sampler)
+			if err != nil {
+				logger.Log.Warn("Failed to load material texture",
+					zap.String("material", m.Name), zap.String("path", t.Path), zap.Error(err))
+			}
+			t.textureID = id
+		}
+		if t.textureID == 0 {
+			continue
+		}
+		gl.ActiveTexture(gl.TEXTURE0 + unit)
+		gl.BindTexture(gl.TEXTURE_2D, t.textureID)
+		uc.SetInt(t.Name, int32(unit))
+		unit++
+	}
+	if unit > 1 {
+		gl.ActiveTexture(gl.TEXTURE0)
+	}
+}
@@ -0,0 +1,196 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMaterialJSON = `{
+  "name": "toon",
+  "shader": {"fragment": "toon.frag"},
+  "parameters": [
+    {"name": "bands", "type": "int", "value": 3},
+    {"name": "outline", "type": "bool", "value": true},
+    {"name": "rimColor", "type": "color", "value": [1, 0.5, 0]},
+    {"name": "rimPower", "type": "float", "value": 2.5, "min": 0, "max": 8},
+    {"name": "offset", "type": "vec2"}
+  ],
+  "textures": [
+    {"name": "rampTexture", "path": "textures/ramp.png"}
+  ],
+  "renderState": {"blend": "additive", "cull": "none"}
+}`
+
+func TestParseMaterialAsset(t *testing.T) {
+	m, err := ParseMaterialAsset([]byte(testMaterialJSON))
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+
+	if p := m.Param("bands"); p == nil || p.Value[0] != 3 {
+		t.Errorf("Expected bands = 3, got %+v", p)
+	}
+	if p := m.Param("outline"); p == nil || p.Value[0] != 1 {
+		t.Errorf("Expected outline = true, got %+v", p)
+	}
+	if p := m.Param("rimColor"); p == nil || len(p.Value) != 3 || p.Value[1] != 0.5 {
+		t.Errorf("Expected rimColor {1, 0.5, 0}, got %+v", p)
+	}
+	if p := m.Param("offset"); p == nil || len(p.Value) != 2 {
+		t.Errorf("Missing values should default to zeros, got %+v", p)
+	}
+
+	if !m.RenderState.IsTransparent() {
+		t.Error("Additive materials should draw in the transparent pass")
+	}
+	if m.RenderState.WritesDepth() {
+		t.Error("Blended materials should not write depth by default")
+	}
+	if m.shaderProgram() == nil || m.shaderProgram().vertexFile != "default.vert" {
+		t.Error("Expected a material shader with the default vertex stage")
+	}
+}
+
+func TestParseMaterialAssetDefaults(t *testing.T) {
+	m, err := ParseMaterialAsset([]byte(`{"name": "plain"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.RenderState.Blend != BlendOpaque || m.RenderState.Cull != CullBack {
+		t.Errorf("Expected opaque/back defaults, got %s/%s", m.RenderState.Blend, m.RenderState.Cull)
+	}
+	if !m.RenderState.WritesDepth() {
+		t.Error("Opaque materials should write depth")
+	}
+	if m.shaderProgram() != nil {
+		t.Error("A material without shader files should use the model's shader")
+	}
+}
+
+func TestParseMaterialAssetErrors(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{"parameters": [{"name": "x", "type": "mat3"}]}`, "unknown type"},
+		{`{"parameters": [{"name": "x", "type": "vec3", "value": [1, 2]}]}`, "needs 3 values"},
+		{`{"parameters": [{"name": "x", "type": "float"}, {"name": "x", "type": "int"}]}`, "duplicate"},
+		{`{"textures": [{"name": "albedo"}]}`, "needs a name and a path"},
+		{`{"renderState": {"blend": "screen"}}`, "unknown blend mode"},
+		{`{"renderState": {"cull": "both"}}`, "unknown cull mode"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseMaterialAsset([]byte(tt.json)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseMaterialAsset(%s): expected error containing %q, got %v", tt.json, tt.want, err)
+		}
+	}
+}
+
+func TestMaterialAssetEncodeRoundTrip(t *testing.T) {
+	m, err := ParseMaterialAsset([]byte(testMaterialJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetParam("rimPower", 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetParam("rimColor", 1); err == nil {
+		t.Error("Expected error when setting a color with one value")
+	}
+
+	data, err := m.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"value": true`) {
+		t.Errorf("Expected bools to be saved as JSON booleans, got:\n%s", data)
+	}
+
+	decoded, err := ParseMaterialAsset(data)
+	if err != nil {
+		t.Fatalf("Failed to re-parse encoded material: %v", err)
+	}
+	if p := decoded.Param("rimPower"); p == nil || p.Value[0] != 4 {
+		t.Errorf("Expected rimPower = 4 after round trip, got %+v", p)
+	}
+	if decoded.RenderState != m.RenderState || len(decoded.Textures) != 1 {
+		t.Error("Render state and textures should survive a round trip")
+	}
+}
+
+func TestMaterialAssetMigration(t *testing.T) {
+	old := `{"name": "old", "shader": {"fragment": "old.frag"}, "textures": [
+		{"name": "ramp", "path": "ramp.png"},
+		{"name": "albedo", "path": "albedo.png", "sampler": {"colorSpace": "srgb"}},
+		{"name": "screen", "path": "` + RenderTexturePath("monitor") + `"}]}`
+	m, err := ParseMaterialAsset([]byte(old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != materialAssetVersion {
+		t.Errorf("Expected version %d after migration, got %d", materialAssetVersion, m.Version)
+	}
+	if got := m.Textures[0].Sampler.ColorSpace; got != ColorSpaceLinear {
+		t.Errorf("Untagged textures in old assets should stay linear, got %q", got)
+	}
+	if got := m.Textures[1].Sampler.ColorSpace; got != ColorSpaceSRGB {
+		t.Errorf("Tagged textures should keep their color space, got %q", got)
+	}
+	if got := m.Textures[2].Sampler.ColorSpace; got != "" {
+		t.Errorf("Render textures should not be tagged, got %q", got)
+	}
+
+	current, err := ParseMaterialAsset([]byte(testMaterialJSON[:1] + `"version": 1,` + testMaterialJSON[1:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := current.Textures[0].Sampler.ColorSpace; got != "" {
+		t.Errorf("Current assets should sample untagged textures as sRGB, got %q", got)
+	}
+}
+
+func TestLoadMaterialAsset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "glow.gmat")
+	if err := os.WriteFile(path, []byte(testMaterialJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := LoadMaterialAsset(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := LoadMaterialAsset(path); again != m {
+		t.Error("Loading the same file twice should return the cached asset")
+	}
+	if got := m.ResolvePath(m.Textures[0].Path); got != filepath.Join(dir, "textures", "ramp.png") {
+		t.Errorf("Expected texture next to the material, got %s", got)
+	}
+
+	// Saved edits are picked up by a reload of the shared asset
+	m.SetParam("bands", 5)
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	m.SetParam("bands", 1)
+	if err := ReloadMaterialAsset(m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Param("bands").Value[0] != 5 {
+		t.Errorf("Expected bands = 5 after reload, got %v", m.Param("bands").Value[0])
+	}
+}
+
+func TestMaterialAssetRelease(t *testing.T) {
+	m, err := ParseMaterialAsset([]byte(testMaterialJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Textures[0].textureID, m.Textures[0].loaded = 7, true
+	m.Release()
+	if tex := m.Textures[0]; tex.textureID != 0 || tex.loaded {
+		t.Errorf("Release should forget loaded textures so the next draw loads them again, got %+v", tex)
+	}
+}
@@ -58,6 +58,8 @@ type SerializedMaterial struct {
 	Exposure      float32    `json:"exposure"`
 	Alpha         float32    `json:"alpha"`
 	TexturePath   string     `json:"texture_path,omitempty"`
+
+	Sampler SamplerSettings `json:"sampler,omitzero"`
 }
 
 // SerializeMesh converts a Model's mesh data to SerializedMesh
@@ -381,6 +383,7 @@ func SerializeModelToJSON(model *Model) ([]byte, error) {
 			Exposure:      model.Material.Exposure,
 			Alpha:         model.Material.Alpha,
 			TexturePath:   model.Material.TexturePath,
+			Sampler:       model.Material.Sampler,
 		}
 	}
 
@@ -49,20 +49,31 @@ type Model struct {
 	InstanceVBO             uint32     // Instance Vertex Buffer Object (for instanced rendering)
 	InstanceVBOCapacity     int        // GPU buffer capacity in bytes for buffer reuse optimization
 	InstanceColorVBO        uint32     // Instance Color VBO (for per-instance colors)
+	BlockAttributeVBO       uint32     // Voxel block layers and surface values (locations 8, 9)
+	BlockTextureArray       uint32     // Texture array sampled by voxel block faces
 	InstanceCount           int        // Number of instances
 	IsDirty                 bool       // Needs recalculation flag
 	IsInstanced             bool       // Instanced rendering flag
 	InstanceMatricesUpdated bool       // Flag to track if matrices need GPU upload
 
 	// MEDIUM DATA - Conditional/periodic access
-	BoundingSphereCenter  mgl32.Vec3             // For frustum culling
-	BoundingSphereRadius  float32                // For frustum culling
-	IsBatched             bool                   // Batching flag
-	Shader                Shader                 // Custom shader for this model
-	CustomUniforms        map[string]interface{} // Custom uniforms for this model
-	Metadata              map[string]interface{} // General metadata for editor/game logic
-	InstanceModelMatrices []mgl32.Mat4           // Instance model matrices (bulk data)
-	InstanceColors        []mgl32.Vec3           // Per-instance colors (optional, for voxels)
+	BoundingSphereCenter   mgl32.Vec3             // For frustum culling
+	BoundingSphereRadius   float32                // For frustum culling
+	IsBatched              bool                   // Batching flag
+	Shader                 Shader                 // Custom shader for this model
+	ShaderFeatures         ShaderFeatures         // Compile-time shader variant for this model
+	CustomMaterial         *MaterialAsset         // User material (.gmat); overrides shader and render state
+	CustomUniforms         map[string]interface{} // Custom uniforms for this model
+	Metadata               map[string]interface{} // General metadata for editor/game logic
+	InstanceModelMatrices  []mgl32.Mat4           // Instance model matrices (bulk data)
+	InstanceColors         []mgl32.Vec3           // Per-instance colors (optional, for voxels)
+	BlockAttributes        []BlockAttributes      // Per-instance (instanced) or per-vertex voxel block data
+	BlockTexturePaths      []string               // Layers of BlockTextureArray, loaded in AddModel
+	BlockTextureColorSpace TextureColorSpace      // Color space of BlockTexturePaths; unset is sRGB
+	InstanceAttributes     []*InstanceAttribute   // Custom per-instance shader data
+	instanceChanges        instanceRange          // Instances whose matrix or color changed since the last upload
+	instanceColorCapacity  int                    // GPU size of InstanceColorVBO in bytes
+	motion                 *motionHistory         // Recent transforms for motion blur, nil until first blurred
 
 	// COLD DATA - Initialization only or rarely accessed
 	Id              int             // Model identifier
@@ -94,8 +105,9 @@ type Material struct {
 	TextureID     uint32     // OpenGL texture ID
 
 	// COLD DATA - Rarely accessed (identification only)
-	Name        string // Material name for debugging
-	TexturePath string // Path to texture file (loaded lazily when OpenGL is ready)
+	Name        string          // Material name for debugging
+	TexturePath string          // Path to texture file (loaded lazily when OpenGL is ready)
+	Sampler     SamplerSettings // Wrap, filter and anisotropy for the texture
 }
 
 func (m *Model) X() float32 {
@@ -273,6 +285,7 @@ func (m *Model) ensureMaterial() {
 			Exposure:      m.Material.Exposure,
 			Alpha:         m.Material.Alpha,
 			TexturePath:   m.Material.TexturePath,
+			Sampler:       m.Material.Sampler,
 		}
 	} else {
 		// Fix incomplete materials (from MTL files that only set Name)
@@ -425,8 +438,8 @@ func (m *Model) SetInstancePosition(index int, position mgl32.Vec3) {
 		// Apply TRS transformations in correct order
 		m.InstanceModelMatrices[index] = translationMatrix.Mul4(rotationMatrix).Mul4(scaleMatrix)
 
-		// Mark instance matrices as needing GPU update
-		m.InstanceMatricesUpdated = true
+		// Only this instance is uploaded before the next draw
+		m.instanceChanges.add(index, index+1)
 	}
 }
 
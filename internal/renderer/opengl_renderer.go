@@ -2,10 +2,12 @@ package renderer
 
 import (
 	"Gopher3D/internal/logger"
+	"Gopher3D/internal/profiler"
 	"fmt"
 	"image"
 	"sort"
 	"strings"
+	"time"
 	"unsafe"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -16,7 +18,8 @@ import (
 
 var currentTextureID uint32 = ^uint32(0) // Initialize with an invalid value
 var frustum Frustum
-var frustumDirty bool = true // Track if frustum needs recalculation
+var frustumDirty bool = true         // Track if frustum needs recalculation
+var frustumViewProjection mgl32.Mat4 // View-projection the frustum was last built from
 
 // SetFrustumDirty marks frustum as needing recalculation
 func SetFrustumDirty() {
@@ -27,19 +30,21 @@ type OpenGLRenderer struct {
 	defaultShader        Shader
 	defaultUniformCache  *UniformCache // Cache for default shader uniforms
 	Models               []*Model
-	Lights               []*Light                 // Scene lights
-	instanceVBO          uint32                   // Buffer for instance model matrices
-	currentShaderProgram uint32                   // Track currently bound shader to avoid unnecessary switches
-	skybox               *Skybox                  // Optional skybox
-	shaderCaches         map[uint32]*UniformCache // Cache per shader program
-	textureManager       *TextureManager          // Central texture cache and lifecycle management
+	Lights               []*Light                     // Scene lights
+	instanceVBO          uint32                       // Buffer for instance model matrices
+	currentShaderProgram uint32                       // Track currently bound shader to avoid unnecessary switches
+	skybox               *Skybox                      // Optional skybox
+	shaderVariants       map[shaderVariantKey]*Shader // Feature variants of base shaders, built lazily
+	textureManager       *TextureManager              // Central texture cache and lifecycle management
 
 	// GL state tracking to avoid redundant state changes
 	faceCullingState bool // Current face culling state
 	depthTestState   bool // Current depth test state
 
 	// Performance tracking for editor
-	lastDrawCalls int // Number of draw calls in last frame
+	lastDrawCalls int      // Number of draw calls in last frame
+	visibleModels []*Model // Models that passed frustum culling this frame, reused between frames
+	gpuTimer      gpuTimer // Timestamp queries for the profiler's GPU passes
 
 	// Editor-controllable settings
 	ClearColorR float32 // Background clear color - Red
@@ -51,29 +56,81 @@ type OpenGLRenderer struct {
 	UseSkyboxImage  bool   // Whether to use skybox image instead of solid color
 
 	// Anti-aliasing settings
-	EnableFXAA         bool   // Software FXAA post-processing
-	EnableMSAAState    bool   // Hardware MSAA enabled state
-	fxaaShader         Shader // FXAA post-processing shader
-	postProcessFBO     uint32 // Framebuffer for post-processing
-	postProcessTexture uint32 // Color texture for post-processing
-	postProcessDepth   uint32 // Depth renderbuffer
-	screenQuadVAO      uint32 // Full-screen quad for post-processing
-	screenQuadVBO      uint32 // VBO for screen quad
-	screenWidth        int32  // Window width for post-processing
-	screenHeight       int32  // Window height for post-processing
-	viewportWidth      int32  // Current viewport width
-	viewportHeight     int32  // Current viewport height
+	EnableFXAA      bool                 // Software FXAA post-processing
+	EnableMSAAState bool                 // Hardware MSAA enabled state
+	fxaaShader      Shader               // FXAA post-processing shader
+	postTargets     []*postProcessTarget // Scene buffers for post-processing, one per view
+	screenQuadVAO   uint32               // Full-screen quad for post-processing
+	screenQuadVBO   uint32               // VBO for screen quad
+	screenWidth     int32                // Window width for post-processing
+	screenHeight    int32                // Window height for post-processing
 
 	// Bloom settings
 	EnableBloom    bool    // Bloom post-processing
 	BloomThreshold float32 // Brightness threshold for bloom
 	BloomIntensity float32 // Bloom effect intensity
 	bloomShader    Shader  // Bloom extraction+blur shader
-	bloomFBO       uint32  // Framebuffer for bloom
-	bloomTexture   uint32  // Bloom texture
 
 	// Passthrough shader for when no effects are enabled
 	passthroughShader Shader
+
+	// Deferred shading, used by RenderPathDeferred
+	RenderPath            RenderPath
+	gBuffers              []*gBuffer // One per view, like postTargets
+	gBufferShader         Shader     // Built on first use of the deferred path
+	deferredLightShader   Shader
+	deferredResolveShader Shader
+
+	// Screen-space reflections, drawn when post-processing targets are available
+	SSR                SSRSettings
+	ssrTargets         []*ssrBuffers // One per view, like gBuffers
+	hiZShader          Shader        // Built on first use of reflections
+	ssrTraceShader     Shader
+	ssrCompositeShader Shader
+
+	// Camera lens effects, drawn after bloom and FXAA when the camera enables them
+	cameraEffectTargets []*cameraEffectBuffers // One per view, like gBuffers
+	velocityShader      Shader                 // Built on first use of motion blur
+	depthOfFieldShader  Shader
+	motionBlurShader    Shader
+	motionFrame         uint64 // Frames drawn, for telling motion blur history apart
+
+	ltcTextures [2]uint32              // Area light lookup tables, uploaded on first use
+	iesTextures map[*IESProfile]uint32 // IES profile lookup textures, uploaded on first use
+
+	// Scene fog settings, shared by every fog-aware shader
+	Fog         FogSettings
+	fogBackdrop *Skybox // Solid color sky drawn behind the scene when fog is on and no skybox image is set
+
+	// Background asset streaming
+	AsyncTextureLoading bool          // Load model textures in the background, showing a placeholder meanwhile
+	UploadBudget        time.Duration // Max main-thread time per frame spent on queued GPU uploads
+	uploads             *UploadQueue
+	placeholderTexture  uint32
+
+	// Screenshots and frame recording
+	outputFBO   uint32 // Framebuffer the finished frame is drawn to; 0 is the window
+	capturing   bool   // Rendering an offscreen screenshot
+	screenshots []screenshotRequest
+	recorder    *frameRecorder
+
+	// Multiple camera views
+	viewOrder    []*Camera // Cameras of the current RenderViews call by depth, reused between frames
+	activeView   int       // Index of the view being drawn, selecting its post-processing buffers
+	drawingViews bool      // Inside RenderViews
+
+	renderingTexture *RenderTexture      // Render texture being drawn, if any
+	reflections      []*PlanarReflection // Drawn before each view
+
+	// Debug views
+	debugViewShader Shader     // Built on first use of a debug view
+	debugLines      debugLines // Bounding volume overlays
+	whiteTexture    uint32     // Stands in for every texture in the lighting view
+
+	// Screen labels and world text
+	textShader Shader // Built on first text drawn
+	texts      []*Text
+	ui         uiOverlay // In-game UI drawn by DrawUI
 }
 
 func (rend *OpenGLRenderer) Init(width, height int32, _ *glfw.Window) {
@@ -95,6 +152,11 @@ func (rend *OpenGLRenderer) Init(width, height int32, _ *glfw.Window) {
 	rend.textureManager = NewTextureManager()
 	logger.Log.Info("TextureManager initialized")
 
+	// Query extensions up front so loader goroutines can check formats without GL calls
+	queryGLCaps()
+	rend.uploads = NewUploadQueue()
+	rend.UploadBudget = 4 * time.Millisecond
+
 	SetDefaultTexture(rend)
 	gl.Viewport(0, 0, width, height)
 	rend.screenWidth = width
@@ -110,15 +172,15 @@ func (rend *OpenGLRenderer) Init(width, height int32, _ *glfw.Window) {
 	gl.Enable(gl.MULTISAMPLE)
 
 	// Initialize shader cache map
-	rend.shaderCaches = make(map[uint32]*UniformCache)
-
-	// Initialize viewport dimensions for post-processing
-	rend.viewportWidth = width
-	rend.viewportHeight = height
+	rend.shaderVariants = make(map[shaderVariantKey]*Shader)
 
 	// Initialize MSAA state (enabled by default)
 	rend.EnableMSAAState = true
 
+	// Fog is off until a scene or example enables it
+	rend.Fog = DefaultFogSettings()
+	rend.SSR = DefaultSSRSettings()
+
 	// Initialize post-processing pipeline (for FXAA)
 	rend.initPostProcessing(width, height)
 
@@ -186,27 +248,7 @@ func (rend *OpenGLRenderer) AddModel(model *Model) {
 	gl.EnableVertexAttribArray(2)
 
 	if model.IsInstanced && len(model.InstanceModelMatrices) > 0 {
-		// Create a dedicated instance VBO for transformation matrices
-		var instanceVBO uint32
-		gl.GenBuffers(1, &instanceVBO)
-		gl.BindBuffer(gl.ARRAY_BUFFER, instanceVBO)
-
-		// Calculate buffer size - allocate exact size first, growth handled by UpdateInstanceMatrices
-		matrixSize := int(unsafe.Sizeof(mgl32.Mat4{}))
-		initialSize := len(model.InstanceModelMatrices) * matrixSize
-
-		// Upload actual data with exact size
-		gl.BufferData(gl.ARRAY_BUFFER, initialSize, gl.Ptr(model.InstanceModelMatrices), gl.DYNAMIC_DRAW)
-
-		// Store the instance VBO and current capacity for buffer reuse optimization
-		model.InstanceVBO = instanceVBO
-		model.InstanceVBOCapacity = initialSize
-
-		for i := 0; i < 4; i++ {
-			gl.EnableVertexAttribArray(3 + uint32(i))
-			gl.VertexAttribPointerWithOffset(3+uint32(i), 4, gl.FLOAT, false, int32(unsafe.Sizeof(mgl32.Mat4{})), uintptr(i*16))
-			gl.VertexAttribDivisor(3+uint32(i), 1)
-		}
+		createInstanceMatrixBuffer(model)
 
 		// Create instance color VBO (location 7) if colors are provided
 		if len(model.InstanceColors) > 0 {
@@ -225,9 +267,14 @@ func (rend *OpenGLRenderer) AddModel(model *Model) {
 
 			// Store the color VBO for potential updates
 			model.InstanceColorVBO = instanceColorVBO
+			model.instanceColorCapacity = colorBufferSize
 		}
 	}
 
+	if len(model.BlockAttributes) > 0 {
+		createBlockAttributeBuffer(model)
+	}
+
 	model.VAO = vao
 	model.VBO = vbo
 	model.EBO = ebo
@@ -240,6 +287,9 @@ func (rend *OpenGLRenderer) AddModel(model *Model) {
 
 	// Load textures for materials (now that OpenGL is initialized)
 	rend.loadModelTextures(model)
+	if err := rend.loadBlockTextures(model); err != nil {
+		logger.Log.Warn("Failed to load voxel block textures", zap.Error(err))
+	}
 
 	// Sort material groups by texture ID to minimize state changes
 	rend.sortMaterialGroupsByTexture(model)
@@ -280,9 +330,11 @@ func (rend *OpenGLRenderer) loadModelTextures(model *Model) {
 		for i := range model.MaterialGroups {
 			material := model.MaterialGroups[i].Material
 			if material != nil {
-				if material.TexturePath != "" && material.TextureID == 0 {
+				if material.TexturePath != "" && material.TextureID == 0 && rend.AsyncTextureLoading {
+					rend.loadMaterialTextureAsync(material)
+				} else if material.TexturePath != "" && material.TextureID == 0 {
 					// Texture path is set but not loaded yet - use texture manager
-					textureID, err := rend.textureManager.LoadTexture(material.TexturePath)
+					textureID, err := rend.textureManager.LoadTextureWithSampler(material.TexturePath, material.Sampler)
 					if err != nil {
 						logger.Log.Warn("Failed to load texture for material, using default",
 							zap.String("material", material.Name),
@@ -316,9 +368,11 @@ func (rend *OpenGLRenderer) loadModelTextures(model *Model) {
 			}
 		}
 	} else if model.Material != nil {
-		if model.Material.TexturePath != "" && model.Material.TextureID == 0 {
+		if model.Material.TexturePath != "" && model.Material.TextureID == 0 && rend.AsyncTextureLoading {
+			rend.loadMaterialTextureAsync(model.Material)
+		} else if model.Material.TexturePath != "" && model.Material.TextureID == 0 {
 			// Single material model with texture path - use texture manager
-			textureID, err := rend.textureManager.LoadTexture(model.Material.TexturePath)
+			textureID, err := rend.textureManager.LoadTextureWithSampler(model.Material.TexturePath, model.Material.Sampler)
 			if err != nil {
 				logger.Log.Warn("Failed to load texture for material, using default",
 					zap.String("material", model.Material.Name),
@@ -353,6 +407,8 @@ func (rend *OpenGLRenderer) loadModelTextures(model *Model) {
 }
 
 func (rend *OpenGLRenderer) RemoveModel(model *Model) {
+	rend.removeSurfaceReflections(model)
+
 	// Release all material group textures
 	for _, group := range model.MaterialGroups {
 		if group.Material != nil && group.Material.TextureID != 0 {
@@ -363,6 +419,9 @@ func (rend *OpenGLRenderer) RemoveModel(model *Model) {
 	if model.Material != nil && model.Material.TextureID != 0 {
 		rend.textureManager.ReleaseTexture(model.Material.TextureID)
 	}
+	if model.CustomMaterial != nil {
+		model.CustomMaterial.Release()
+	}
 
 	// Clean up OpenGL resources
 	if model.VAO != 0 {
@@ -388,6 +447,19 @@ func (rend *OpenGLRenderer) RemoveModel(model *Model) {
 		gl.DeleteBuffers(1, &model.InstanceColorVBO)
 		model.InstanceColorVBO = 0
 	}
+	deleteInstanceAttributeBuffers(model)
+	if model.BlockAttributeVBO != 0 {
+		gl.DeleteBuffers(1, &model.BlockAttributeVBO)
+		model.BlockAttributeVBO = 0
+	}
+	if model.BlockTextureArray != 0 {
+		rend.textureManager.ReleaseTexture(model.BlockTextureArray)
+		model.BlockTextureArray = 0
+	}
+	if model.motion != nil {
+		model.motion.delete()
+		model.motion = nil
+	}
 
 	// Remove from models list
 	for i, m := range rend.Models {
@@ -401,18 +473,47 @@ func (rend *OpenGLRenderer) RemoveModel(model *Model) {
 		zap.Int("materialGroups", len(model.MaterialGroups)))
 }
 
+// RemoveModelInstance removes an instance, keeping the order of the rest.
+// Every later instance is uploaded again; RemoveInstance avoids that.
 func (model *Model) RemoveModelInstance(index int) {
-	if index >= len(model.InstanceModelMatrices) {
+	if index < 0 || index >= len(model.InstanceModelMatrices) {
 		return
 	}
 	model.InstanceModelMatrices = append(model.InstanceModelMatrices[:index], model.InstanceModelMatrices[index+1:]...)
-	model.InstanceCount--
+	if index < len(model.InstanceColors) {
+		model.InstanceColors = append(model.InstanceColors[:index], model.InstanceColors[index+1:]...)
+	}
+	for _, a := range model.InstanceAttributes {
+		if index < a.Len() {
+			a.removeOrdered(index)
+		}
+	}
+	model.InstanceCount = len(model.InstanceModelMatrices)
+	model.instanceChanges.add(index, model.InstanceCount)
 }
 
 func (rend *OpenGLRenderer) Render(camera Camera, light *Light) {
 	// Reset draw call counter
 	rend.lastDrawCalls = 0
 
+	// RenderViews updates them once for all views
+	if !rend.drawingViews {
+		rend.updateRenderTextures(light)
+		rend.advanceMotionFrame()
+	}
+
+	// Finish background loads before drawing so they show up this frame
+	endUploads := rend.profilePass("Uploads")
+	rend.uploads.Process(rend.UploadBudget)
+	endUploads()
+
+	// Pick up edited shader files before anything binds a program
+	pollShaderChanges()
+	rend.defaultShader.refresh()
+	if rend.defaultUniformCache.program != rend.defaultShader.program {
+		rend.defaultUniformCache = NewUniformCache(rend.defaultShader.program)
+	}
+
 	// Use lights from the Lights array if available, otherwise use passed light
 	var activeLight *Light
 	if len(rend.Lights) > 0 {
@@ -421,35 +522,37 @@ func (rend *OpenGLRenderer) Render(camera Camera, light *Light) {
 		activeLight = light // Fallback to passed light for backward compatibility
 	}
 
-	// Post-processing: render scene to FBO if FXAA or Bloom is enabled
-	postProcessActive := false
-	if rend.EnableFXAA || rend.EnableBloom {
-		// Only enable post-processing if FBO is valid
-		if rend.postProcessFBO != 0 && rend.postProcessTexture != 0 && rend.screenQuadVAO != 0 {
-			// Check if viewport changed
-			currentViewport := [4]int32{}
-			gl.GetIntegerv(gl.VIEWPORT, &currentViewport[0])
-
-			if currentViewport[2] > 0 && currentViewport[3] > 0 {
-				// Resize FBOs if viewport changed
-				if currentViewport[2] != rend.viewportWidth || currentViewport[3] != rend.viewportHeight {
-					rend.viewportWidth = currentViewport[2]
-					rend.viewportHeight = currentViewport[3]
-					rend.resizePostProcessing(currentViewport[2], currentViewport[3])
-				}
+	// Reflections follow this view's camera, so they are drawn per view
+	rend.updatePlanarReflections(camera, light)
+
+	// Post-processing: render scene to FBO if FXAA, Bloom or reflections are enabled
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	scissor := gl.IsEnabled(gl.SCISSOR_TEST)
+	var target *postProcessTarget
+	cameraEffects := depthOfFieldActive(camera) || motionBlurActive(camera)
+	if (rend.EnableFXAA || rend.EnableBloom || rend.SSR.Enabled || cameraEffects) && !DebugView.replacesShading() && rend.renderingTexture == nil && rend.screenQuadVAO != 0 && viewport[2] > 0 && viewport[3] > 0 {
+		target = rend.postTarget()
+		// Resize FBOs if viewport changed
+		width, height := postProcessSize(viewport[2]), postProcessSize(viewport[3])
+		if width != target.width || height != target.height {
+			target.resize(width, height)
+		}
 
-				// Bind scene FBO
-				gl.BindFramebuffer(gl.FRAMEBUFFER, rend.postProcessFBO)
+		// Bind scene FBO
+		gl.BindFramebuffer(gl.FRAMEBUFFER, target.fbo)
 
-				// Verify FBO is complete
-				status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
-				if status == gl.FRAMEBUFFER_COMPLETE {
-					postProcessActive = true
-				} else {
-					// FBO not ready, render directly to screen
-					gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
-				}
+		// Verify FBO is complete
+		if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) == gl.FRAMEBUFFER_COMPLETE {
+			// The scene fills the target; the view's rect is restored when resolving
+			gl.Viewport(0, 0, width, height)
+			if scissor {
+				gl.Disable(gl.SCISSOR_TEST)
 			}
+		} else {
+			// FBO not ready, render directly to screen
+			gl.BindFramebuffer(gl.FRAMEBUFFER, rend.outputFBO)
+			target = nil
 		}
 	}
 
@@ -461,19 +564,36 @@ func (rend *OpenGLRenderer) Render(camera Camera, light *Light) {
 	}
 
 	// Priority: 1. Editor clear color, 2. Skybox color, 3. Black default
+	var backgroundColor mgl32.Vec3
 	if rend.ClearColorR != 0.0 || rend.ClearColorG != 0.0 || rend.ClearColorB != 0.0 {
-		gl.ClearColor(rend.ClearColorR, rend.ClearColorG, rend.ClearColorB, 1.0)
+		backgroundColor = mgl32.Vec3{rend.ClearColorR, rend.ClearColorG, rend.ClearColorB}
 	} else if rend.skybox != nil && rend.skybox.Shader.skyColor != (mgl32.Vec3{}) && rend.skybox.TextureID == 0 {
-		gl.ClearColor(rend.skybox.Shader.skyColor.X(), rend.skybox.Shader.skyColor.Y(), rend.skybox.Shader.skyColor.Z(), 1.0)
-	} else {
-		gl.ClearColor(0.0, 0.0, 0.0, 1.0)
+		backgroundColor = rend.skybox.Shader.skyColor
 	}
+	if DebugView.replacesShading() {
+		backgroundColor = mgl32.Vec3{}
+	}
+	gl.ClearColor(backgroundColor.X(), backgroundColor.Y(), backgroundColor.Z(), 1.0)
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-	// Render skybox if it exists and has a texture
-	if rend.skybox != nil && rend.skybox.TextureID != 0 {
-		rend.skybox.Render(camera)
+	// Procedural skies follow the primary directional light
+	if rend.skybox != nil && rend.skybox.IsProcedural() && activeLight != nil && activeLight.Mode == "directional" {
+		rend.skybox.SunDirection = activeLight.Direction
+	}
+
+	// Render skybox if it exists and has a texture or is procedural
+	endSky := rend.profilePass("Sky")
+	if DebugView.replacesShading() {
+		// Debug views show geometry against black
+	} else if rend.skybox != nil && (rend.skybox.TextureID != 0 || rend.skybox.IsProcedural()) {
+		rend.skybox.RenderWithFog(camera, rend.Fog)
+	} else if rend.Fog.IsEnabled() {
+		// A flat clear color can't fade into the fog, so draw it as a sky that can
+		rend.renderFogBackdrop(camera, backgroundColor)
 	}
+	endSky()
+	// Sky passes bind their own program, so force the next model to rebind
+	rend.currentShaderProgram = 0
 
 	// Set depth test state
 	rend.setDepthTest(DepthTestEnabled)
@@ -488,26 +608,86 @@ func (rend *OpenGLRenderer) Render(camera Camera, light *Light) {
 	rend.setFaceCulling(FaceCullingEnabled)
 
 	// Calculate frustum only if camera moved
-	if FrustumCullingEnabled && frustumDirty {
+	endCulling := profiler.Begin("Culling")
+	if FrustumCullingEnabled && (frustumDirty || viewProjection != frustumViewProjection) {
 		frustum = camera.CalculateFrustum()
+		frustumViewProjection = viewProjection
 		frustumDirty = false
 	}
-
-	// Pass 1: Render Opaque Objects (Alpha >= 0.99)
-	// We render these first so they write to the depth buffer
+	// Cull once for both passes
+	rend.visibleModels = rend.visibleModels[:0]
 	for _, model := range rend.Models {
-		rend.renderModelInternal(model, viewProjection, activeLight, camera, false)
+		if FrustumCullingEnabled && !frustum.IntersectsSphere(model.BoundingSphereCenter, model.BoundingSphereRadius) {
+			continue
+		}
+		// A model can't show the texture being drawn
+		if rt := rend.renderingTexture; rt != nil && model.usesTexture(rt) {
+			continue
+		}
+		rend.visibleModels = append(rend.visibleModels, model)
 	}
+	endCulling()
 
-	// Pass 2: Render Transparent Objects (Alpha < 0.99)
-	// We render these second so they blend correctly with opaque objects behind them
-	// Note: For perfect transparency, these should be sorted back-to-front
-	for _, model := range rend.Models {
-		rend.renderModelInternal(model, viewProjection, activeLight, camera, true)
+	if DebugView.replacesShading() {
+		endDebug := rend.profilePass("Debug View")
+		rend.renderDebugView(viewProjection, camera)
+		endDebug()
+	} else {
+		// The deferred path lights opaque default-shaded models from a G-buffer by
+		// every light; the rest, and all transparent groups, are drawn forward
+		deferred := rend.RenderPath == RenderPathDeferred && rend.renderingTexture == nil && rend.screenQuadVAO != 0 &&
+			rend.renderDeferred(viewProjection, camera, activeLight)
+
+		// Pass 1: Render Opaque Objects (Alpha >= 0.99)
+		// We render these first so they write to the depth buffer
+		endOpaque := rend.profilePass("Opaque")
+		for _, model := range rend.visibleModels {
+			if deferred && rend.drawsDeferred(model) {
+				continue
+			}
+			rend.renderModelInternal(model, viewProjection, activeLight, camera, false)
+		}
+		endOpaque()
+
+		// Reflections sample the finished opaque scene, before transparents cover it
+		if target != nil && rend.SSR.Enabled {
+			endReflections := rend.profilePass("Reflections")
+			rend.renderSSR(target, viewProjection, camera, deferred, backgroundColor)
+			endReflections()
+		}
+
+		// Pass 2: Render Transparent Objects (Alpha < 0.99)
+		// We render these second so they blend correctly with opaque objects behind them
+		// Note: For perfect transparency, these should be sorted back-to-front
+		endTransparent := rend.profilePass("Transparent")
+		for _, model := range rend.visibleModels {
+			rend.renderModelInternal(model, viewProjection, activeLight, camera, true)
+		}
+		endTransparent()
+
+		if target != nil && motionBlurActive(camera) {
+			endVelocity := rend.profilePass("Velocity")
+			rend.renderVelocity(target, viewProjection)
+			endVelocity()
+		}
+	}
+	rend.renderBoundsOverlay(viewProjection)
+	rend.renderTexts(TextWorld, camera, viewport)
+
+	if target != nil {
+		endPost := rend.profilePass("Post-Processing")
+		rend.renderPostProcess(target, viewport, scissor, camera)
+		endPost()
+	}
+
+	// Labels belong to the screen, not to render textures or reflections
+	if rend.renderingTexture == nil {
+		rend.renderTexts(TextScreen, camera, viewport)
 	}
 
-	if postProcessActive {
-		rend.renderPostProcess()
+	// RenderViews captures once every view is drawn
+	if !rend.drawingViews && rend.capturePending() {
+		rend.processCaptures(func() { rend.Render(camera, light) })
 	}
 
 	// GL state is now managed through setFaceCulling() and setDepthTest()
@@ -515,11 +695,6 @@ func (rend *OpenGLRenderer) Render(camera Camera, light *Light) {
 
 // renderModelInternal handles rendering a single model for a specific pass (opaque or transparent)
 func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl32.Mat4, activeLight *Light, camera Camera, renderTransparent bool) {
-	// Skip rendering if the model is outside the frustum
-	if FrustumCullingEnabled && !frustum.IntersectsSphere(model.BoundingSphereCenter, model.BoundingSphereRadius) {
-		return
-	}
-
 	if model.IsDirty {
 		model.calculateModelMatrix()
 		model.IsDirty = false
@@ -529,28 +704,24 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 	var shader *Shader
 	var uniformCache *UniformCache
 
-	if model.Shader.IsValid() {
-		shader = &model.Shader
-		// Ensure custom shader is compiled before using
-		if !shader.isCompiled {
-			shader.Compile()
-		}
-		// Verify shader compiled successfully (program > 0)
-		if shader.program == 0 {
-			shader = &rend.defaultShader
-			uniformCache = rend.defaultUniformCache
-		} else {
-			// Get or create cache for this shader
-			if cache, exists := rend.shaderCaches[shader.program]; exists {
-				uniformCache = cache
-			} else {
-				uniformCache = NewUniformCache(shader.program)
-				rend.shaderCaches[shader.program] = uniformCache
-			}
-		}
-	} else {
+	shader = rend.modelShader(model)
+	if shader != &rend.defaultShader {
+		// Compile on first use, rebuild if its shader files changed
+		shader.refresh()
+	}
+	// Verify shader compiled successfully (program > 0)
+	if shader.program == 0 {
 		shader = &rend.defaultShader
+	}
+	if shader == &rend.defaultShader {
 		uniformCache = rend.defaultUniformCache
+	} else {
+		// The cache lives on the shader and is rebuilt with its program, so a
+		// reloaded program never inherits stale locations
+		if shader.uniformCache == nil || shader.uniformCache.program != shader.program {
+			shader.uniformCache = NewUniformCache(shader.program)
+		}
+		uniformCache = shader.uniformCache
 	}
 
 	// Switch shader if needed
@@ -567,6 +738,7 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 
 	// Bind vertex array
 	gl.BindVertexArray(model.VAO)
+	bindBlockTextures(uniformCache, model)
 
 	// Check if model has multiple material groups
 	if len(model.MaterialGroups) > 0 {
@@ -581,6 +753,9 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 				alpha = group.Material.Alpha
 			}
 			isTransparent := alpha < 0.99
+			if model.CustomMaterial != nil {
+				isTransparent = model.CustomMaterial.RenderState.IsTransparent()
+			}
 
 			// Skip if this group doesn't match the current pass
 			if renderTransparent != isTransparent {
@@ -591,7 +766,9 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 			rend.setMaterialUniforms(shader, group.Material)
 
 			// Configure GL state for transparency
-			if isTransparent {
+			if model.CustomMaterial != nil {
+				rend.applyMaterialRenderState(model.CustomMaterial.RenderState)
+			} else if isTransparent {
 				gl.DepthMask(false) // Disable depth writing for transparent objects
 				gl.Enable(gl.BLEND)
 				gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
@@ -606,21 +783,25 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 			// Bind texture
 			if group.Material != nil && group.Material.TextureID != 0 {
 				if group.Material.TextureID != currentTextureID {
-					gl.BindTexture(gl.TEXTURE_2D, group.Material.TextureID)
+					gl.BindTexture(gl.TEXTURE_2D, rend.shadingTexture(group.Material.TextureID))
 					gl.Uniform1i(textureSamplerLoc, 0)
 					currentTextureID = group.Material.TextureID
 				}
 			} else if group.Material != nil && group.Material.TextureID == 0 {
 				if DefaultMaterial.TextureID != 0 && DefaultMaterial.TextureID != currentTextureID {
-					gl.BindTexture(gl.TEXTURE_2D, DefaultMaterial.TextureID)
+					gl.BindTexture(gl.TEXTURE_2D, rend.shadingTexture(DefaultMaterial.TextureID))
 					gl.Uniform1i(textureSamplerLoc, 0)
 					currentTextureID = DefaultMaterial.TextureID
 				}
 			}
+			rend.setMaterialAssetUniforms(uniformCache, model.CustomMaterial)
 
 			// Draw
 			rend.drawElements(model, shader, group.IndexCount, int(group.IndexStart)*4)
 		}
+		if model.CustomMaterial != nil {
+			rend.resetMaterialRenderState()
+		}
 	} else {
 		// Single material rendering
 		alpha := float32(1.0)
@@ -628,13 +809,18 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 			alpha = model.Material.Alpha
 		}
 		isTransparent := alpha < 0.99
+		if model.CustomMaterial != nil {
+			isTransparent = model.CustomMaterial.RenderState.IsTransparent()
+		}
 
 		// Only render if it matches the current pass
 		if renderTransparent == isTransparent {
 			// Always set material uniforms - setMaterialUniforms handles nil by using DefaultMaterial
 			rend.setMaterialUniforms(shader, model.Material)
 
-			if isTransparent {
+			if model.CustomMaterial != nil {
+				rend.applyMaterialRenderState(model.CustomMaterial.RenderState)
+			} else if isTransparent {
 				gl.DepthMask(false)
 				gl.Enable(gl.BLEND)
 				gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
@@ -649,17 +835,21 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 			// Bind texture
 			textureSamplerLoc := uniformCache.GetLocation("textureSampler")
 			if model.Material != nil && model.Material.TextureID != 0 {
-				gl.BindTexture(gl.TEXTURE_2D, model.Material.TextureID)
+				gl.BindTexture(gl.TEXTURE_2D, rend.shadingTexture(model.Material.TextureID))
 				gl.Uniform1i(textureSamplerLoc, 0)
 			} else {
 				if DefaultMaterial.TextureID != 0 {
-					gl.BindTexture(gl.TEXTURE_2D, DefaultMaterial.TextureID)
+					gl.BindTexture(gl.TEXTURE_2D, rend.shadingTexture(DefaultMaterial.TextureID))
 					gl.Uniform1i(textureSamplerLoc, 0)
 				}
 			}
+			rend.setMaterialAssetUniforms(uniformCache, model.CustomMaterial)
 
 			// Draw
 			rend.drawElements(model, shader, int32(len(model.Faces)), 0)
+			if model.CustomMaterial != nil {
+				rend.resetMaterialRenderState()
+			}
 		}
 	}
 	gl.BindVertexArray(0)
@@ -668,9 +858,8 @@ func (rend *OpenGLRenderer) renderModelInternal(model *Model, viewProjection mgl
 // drawElements handles the actual draw call (instanced or regular)
 func (rend *OpenGLRenderer) drawElements(model *Model, shader *Shader, count int32, offset int) {
 	if model.IsInstanced && len(model.InstanceModelMatrices) > 0 {
-		if model.InstanceMatricesUpdated {
-			rend.UpdateInstanceMatrices(model)
-			model.InstanceMatricesUpdated = false
+		if model.instanceDataChanged() {
+			rend.updateInstanceData(model)
 		}
 		shader.SetInt("isInstanced", 1)
 		gl.DrawElementsInstanced(gl.TRIANGLES, count, gl.UNSIGNED_INT, gl.PtrOffset(offset), int32(model.InstanceCount))
@@ -715,6 +904,28 @@ func (rend *OpenGLRenderer) setCommonUniformsCached(cache *UniformCache, viewPro
 		cache.SetFloat("light.linearAtten", light.LinearAtten)
 		cache.SetFloat("light.quadraticAtten", light.QuadraticAtten)
 
+		areaShape := light.areaShape()
+		cache.SetInt("light.areaShape", areaShape)
+		if areaShape != 0 {
+			right, up := light.AreaAxes()
+			cache.SetVec3("light.areaRight", right[0], right[1], right[2])
+			cache.SetVec3("light.areaUp", up[0], up[1], up[2])
+			rend.bindLTCTextures()
+			cache.SetInt("ltcMatrix", ltcTextureUnit)
+			cache.SetInt("ltcAmplitude", ltcTextureUnit+1)
+		}
+
+		if light.usesIES() {
+			right, up := lightFrame(light.Direction)
+			cache.SetInt("light.hasIES", 1)
+			cache.SetVec3("light.iesRight", right[0], right[1], right[2])
+			cache.SetVec3("light.iesUp", up[0], up[1], up[2])
+			rend.bindIESTexture(light.IES)
+			cache.SetInt("iesProfile", iesTextureUnit)
+		} else {
+			cache.SetInt("light.hasIES", 0)
+		}
+
 		// Water shader specific light uniforms (backward compatibility)
 		cache.SetVec3("lightPos", light.Position[0], light.Position[1], light.Position[2])
 		cache.SetVec3("lightColor", light.Color[0], light.Color[1], light.Color[2])
@@ -724,6 +935,23 @@ func (rend *OpenGLRenderer) setCommonUniformsCached(cache *UniformCache, viewPro
 
 	// Set view position
 	cache.SetVec3("viewPos", camera.Position[0], camera.Position[1], camera.Position[2])
+
+	// Scene fog
+	setFogUniforms(cache, rend.Fog, camera.Far)
+}
+
+// renderFogBackdrop draws the background color as a solid sky so the horizon picks up fog
+func (rend *OpenGLRenderer) renderFogBackdrop(camera Camera, color mgl32.Vec3) {
+	if rend.fogBackdrop == nil {
+		backdrop, err := CreateSolidColorSkybox(color.X(), color.Y(), color.Z())
+		if err != nil {
+			logger.Log.Error("Failed to create fog backdrop", zap.Error(err))
+			return
+		}
+		rend.fogBackdrop = backdrop
+	}
+	rend.fogBackdrop.UpdateColor(color.X(), color.Y(), color.Z())
+	rend.fogBackdrop.RenderWithFog(camera, rend.Fog)
 }
 
 // setMaterialUniforms sets material-specific uniforms
@@ -734,9 +962,13 @@ func (rend *OpenGLRenderer) setMaterialUniforms(shader *Shader, material *Materi
 	}
 
 	// Set diffuse color
+	diffuseColor := material.DiffuseColor
+	if DebugView == DebugViewLighting {
+		diffuseColor = [3]float32{1, 1, 1}
+	}
 	diffuseColorLoc := gl.GetUniformLocation(shader.program, gl.Str("diffuseColor\x00"))
 	if diffuseColorLoc != -1 {
-		gl.Uniform3fv(diffuseColorLoc, 1, &material.DiffuseColor[0])
+		gl.Uniform3fv(diffuseColorLoc, 1, &diffuseColor[0])
 	}
 
 	// Set specular color
@@ -780,8 +1012,14 @@ func (rend *OpenGLRenderer) setShaderSpecificUniforms(shader *Shader, uc *Unifor
 	}
 
 	// Set all custom uniforms stored in the model
+	textureUnit := int32(customTextureUnit)
 	for name, value := range model.CustomUniforms {
 		switch v := value.(type) {
+		case *RenderTexture:
+			gl.ActiveTexture(gl.TEXTURE0 + uint32(textureUnit))
+			gl.BindTexture(gl.TEXTURE_2D, v.TextureID)
+			uc.SetInt(name, textureUnit)
+			textureUnit++
 		case float32:
 			uc.SetFloat(name, v)
 		case int32:
@@ -817,21 +1055,77 @@ func (rend *OpenGLRenderer) setShaderSpecificUniforms(shader *Shader, uc *Unifor
 			// Skip unknown types
 		}
 	}
+	if textureUnit > customTextureUnit {
+		gl.ActiveTexture(gl.TEXTURE0)
+	}
 }
 
 func (rend *OpenGLRenderer) SetSkybox(skybox *Skybox) {
 	rend.skybox = skybox
 }
 
+// GetSkybox returns the current skybox (nil if none is set)
+func (rend *OpenGLRenderer) GetSkybox() *Skybox {
+	return rend.skybox
+}
+
 func (rend *OpenGLRenderer) Cleanup() {
 	for _, model := range rend.Models {
 		gl.DeleteVertexArrays(1, &model.VAO)
 		gl.DeleteBuffers(1, &model.VBO)
 		gl.DeleteBuffers(1, &model.EBO)
+		if model.motion != nil {
+			model.motion.delete()
+		}
 	}
 	if rend.skybox != nil {
 		rend.skybox.Cleanup()
 	}
+	if rend.fogBackdrop != nil {
+		rend.fogBackdrop.Cleanup()
+	}
+	rend.gpuTimer.delete()
+	for _, target := range rend.postTargets {
+		target.delete()
+	}
+	rend.postTargets = nil
+	for _, g := range rend.gBuffers {
+		g.delete()
+	}
+	rend.gBuffers = nil
+	for _, s := range rend.ssrTargets {
+		s.delete()
+	}
+	rend.ssrTargets = nil
+	for _, e := range rend.cameraEffectTargets {
+		e.delete()
+	}
+	rend.cameraEffectTargets = nil
+	if rend.ltcTextures[0] != 0 {
+		gl.DeleteTextures(2, &rend.ltcTextures[0])
+		rend.ltcTextures = [2]uint32{}
+	}
+	for _, tex := range rend.iesTextures {
+		gl.DeleteTextures(1, &tex)
+	}
+	rend.iesTextures = nil
+	rend.reflections = nil
+	rend.debugLines.delete()
+	for _, t := range rend.texts {
+		t.deleteBuffers()
+	}
+	rend.ui.delete(rend.textureManager)
+	deleteFontTextures()
+	if rend.whiteTexture != 0 {
+		gl.DeleteTextures(1, &rend.whiteTexture)
+		rend.whiteTexture = 0
+	}
+	for _, rt := range rend.RenderTextures() {
+		rend.RemoveRenderTexture(rt.Name)
+	}
+	if rend.recorder != nil {
+		rend.StopRecording()
+	}
 }
 
 // LoadTexture loads a texture from file (delegates to TextureManager for caching)
@@ -840,6 +1134,25 @@ func (rend *OpenGLRenderer) LoadTexture(filePath string) (uint32, error) {
 	return rend.textureManager.LoadTexture(filePath)
 }
 
+// LoadTextureWithSampler loads a texture with explicit sampler settings (delegates to TextureManager)
+func (rend *OpenGLRenderer) LoadTextureWithSampler(filePath string, sampler SamplerSettings) (uint32, error) {
+	return rend.textureManager.LoadTextureWithSampler(filePath, sampler)
+}
+
+// ReloadMaterialTexture re-uploads a material's texture after its sampler settings changed
+func (rend *OpenGLRenderer) ReloadMaterialTexture(material *Material) error {
+	if material == nil || material.TexturePath == "" {
+		return nil
+	}
+	textureID, err := rend.textureManager.LoadTextureWithSampler(material.TexturePath, material.Sampler)
+	if err != nil {
+		return err
+	}
+	rend.textureManager.ReleaseTexture(material.TextureID)
+	material.TextureID = textureID
+	return nil
+}
+
 // CreateTextureFromImage creates a texture from an image.Image (delegates to TextureManager)
 // Used for embedded textures like default texture
 func (rend *OpenGLRenderer) CreateTextureFromImage(img image.Image) (uint32, error) {
@@ -978,22 +1291,9 @@ func (rend *OpenGLRenderer) UpdateInstanceMatrices(model *Model) {
 	}
 
 	gl.BindBuffer(gl.ARRAY_BUFFER, model.InstanceVBO)
-
-	matrixSize := int(unsafe.Sizeof(mgl32.Mat4{}))
-	currentSize := len(model.InstanceModelMatrices) * matrixSize
-
-	// Check if buffer needs to grow (reallocation required)
-	if currentSize > model.InstanceVBOCapacity {
-		// Buffer too small - need to reallocate with gl.BufferData
-		// Allocate 50% more than needed to reduce future reallocations
-		newCapacity := int(float32(currentSize) * 1.5)
-		gl.BufferData(gl.ARRAY_BUFFER, newCapacity, gl.Ptr(model.InstanceModelMatrices), gl.DYNAMIC_DRAW)
-		model.InstanceVBOCapacity = newCapacity
-	} else {
-		// Buffer is large enough - use faster BufferSubData (no reallocation)
-		// This is 2-5x faster than BufferData for updates
-		gl.BufferSubData(gl.ARRAY_BUFFER, 0, currentSize, gl.Ptr(model.InstanceModelMatrices))
-	}
+	count := len(model.InstanceModelMatrices)
+	model.InstanceVBOCapacity = uploadInstanceBuffer(gl.Ptr(model.InstanceModelMatrices), count,
+		int(unsafe.Sizeof(mgl32.Mat4{})), model.InstanceVBOCapacity, instanceRange{0, count})
 }
 
 // GetDefaultShader returns a copy of the default shader for models that need it
@@ -1011,6 +1311,16 @@ func (rend *OpenGLRenderer) GetModels() []*Model {
 	return rend.Models
 }
 
+// FindModel returns the first model with the given name, or nil
+func (rend *OpenGLRenderer) FindModel(name string) *Model {
+	for _, model := range rend.Models {
+		if model.Name == name {
+			return model
+		}
+	}
+	return nil
+}
+
 // GetDrawCalls returns the number of draw calls from the last frame
 func (rend *OpenGLRenderer) GetDrawCalls() int {
 	return rend.lastDrawCalls
@@ -1042,108 +1352,12 @@ func (rend *OpenGLRenderer) EnableMSAA(enable bool) {
 	}
 }
 
-// resizePostProcessing recreates framebuffer textures when viewport size changes
-func (rend *OpenGLRenderer) resizePostProcessing(width, height int32) {
-	// Safety limit to prevent GPU crashes
-	if width > 8192 {
-		width = 8192
-	}
-	if height > 8192 {
-		height = 8192
-	}
-	if width < 1 {
-		width = 1
-	}
-	if height < 1 {
-		height = 1
-	}
-
-	// Delete old textures and renderbuffers
-	if rend.postProcessTexture != 0 {
-		gl.DeleteTextures(1, &rend.postProcessTexture)
-	}
-	if rend.bloomTexture != 0 {
-		gl.DeleteTextures(1, &rend.bloomTexture)
-	}
-	if rend.postProcessDepth != 0 {
-		gl.DeleteRenderbuffers(1, &rend.postProcessDepth)
-	}
-
-	// Recreate post-process color texture
-	gl.GenTextures(1, &rend.postProcessTexture)
-	gl.BindTexture(gl.TEXTURE_2D, rend.postProcessTexture)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-
-	// Recreate depth renderbuffer
-	gl.GenRenderbuffers(1, &rend.postProcessDepth)
-	gl.BindRenderbuffer(gl.RENDERBUFFER, rend.postProcessDepth)
-	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
-
-	// Reattach to FBO
-	gl.BindFramebuffer(gl.FRAMEBUFFER, rend.postProcessFBO)
-	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, rend.postProcessTexture, 0)
-	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, rend.postProcessDepth)
-
-	// Check framebuffer completeness
-	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
-		logger.Log.Error(fmt.Sprintf("Post-process framebuffer incomplete after resize! Status: 0x%X", status))
-	}
-
-	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
-
-	// Recreate bloom texture (HDR) - Downscaled
-	bloomWidth := width / 4
-	bloomHeight := height / 4
-	if bloomWidth < 1 {
-		bloomWidth = 1
-	}
-	if bloomHeight < 1 {
-		bloomHeight = 1
-	}
-
-	gl.GenTextures(1, &rend.bloomTexture)
-	gl.BindTexture(gl.TEXTURE_2D, rend.bloomTexture)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA16F, bloomWidth, bloomHeight, 0, gl.RGBA, gl.FLOAT, nil)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
-
-	// Reattach to bloom FBO
-	gl.BindFramebuffer(gl.FRAMEBUFFER, rend.bloomFBO)
-	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, rend.bloomTexture, 0)
-	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
-
-	logger.Log.Info(fmt.Sprintf("Resized post-processing framebuffers (Scene: %dx%d, Bloom: %dx%d)", width, height, bloomWidth, bloomHeight))
-}
-
 // initPostProcessing initializes framebuffer and shader for post-processing effects (FXAA)
 func (rend *OpenGLRenderer) initPostProcessing(width, height int32) {
-	// Create framebuffer for rendering scene to texture
-	gl.GenFramebuffers(1, &rend.postProcessFBO)
-	gl.BindFramebuffer(gl.FRAMEBUFFER, rend.postProcessFBO)
-
-	// Create color texture attachment
-	gl.GenTextures(1, &rend.postProcessTexture)
-	gl.BindTexture(gl.TEXTURE_2D, rend.postProcessTexture)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, rend.postProcessTexture, 0)
-
-	// Create depth renderbuffer
-	gl.GenRenderbuffers(1, &rend.postProcessDepth)
-	gl.BindRenderbuffer(gl.RENDERBUFFER, rend.postProcessDepth)
-	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
-	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, rend.postProcessDepth)
-
-	// Check framebuffer completeness
-	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
-		logger.Log.Error("Framebuffer is not complete!")
-	}
-	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	// Scene buffers for the main view; other views get theirs on first use
+	target := newPostProcessTarget()
+	target.resize(width, height)
+	rend.postTargets = []*postProcessTarget{target}
 
 	// Create screen quad for post-processing
 	quadVertices := []float32{
@@ -1180,47 +1394,29 @@ func (rend *OpenGLRenderer) initPostProcessing(width, height int32) {
 	rend.passthroughShader = InitPassthroughShader()
 	rend.passthroughShader.Compile()
 
-	// Create bloom framebuffer (downscaled for performance)
-	bloomWidth := width / 4
-	bloomHeight := height / 4
-	if bloomWidth < 1 {
-		bloomWidth = 1
-	}
-	if bloomHeight < 1 {
-		bloomHeight = 1
-	}
-
-	gl.GenFramebuffers(1, &rend.bloomFBO)
-	gl.BindFramebuffer(gl.FRAMEBUFFER, rend.bloomFBO)
-
-	gl.GenTextures(1, &rend.bloomTexture)
-	gl.BindTexture(gl.TEXTURE_2D, rend.bloomTexture)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA16F, bloomWidth, bloomHeight, 0, gl.RGBA, gl.FLOAT, nil) // HDR texture
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
-	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, rend.bloomTexture, 0)
-
-	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
-		logger.Log.Error("Bloom framebuffer is not complete!")
-	}
-	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
-
 	// Initialize bloom defaults
 	rend.BloomThreshold = 0.8 // Lower threshold to catch more bright areas
 	rend.BloomIntensity = 0.4 // Moderate bloom intensity
 
-	logger.Log.Info(fmt.Sprintf("Post-processing initialized (Bloom: %dx%d)", bloomWidth, bloomHeight))
+	logger.Log.Info(fmt.Sprintf("Post-processing initialized (%dx%d)", width, height))
 }
 
-func (rend *OpenGLRenderer) renderPostProcess() {
-	if rend.viewportWidth == 0 || rend.viewportHeight == 0 || rend.screenQuadVAO == 0 {
-		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
-		return
+// renderPostProcess resolves target into viewport of the output framebuffer,
+// through the camera's lens effects when it has any
+func (rend *OpenGLRenderer) renderPostProcess(target *postProcessTarget, viewport [4]int32, scissor bool, camera Camera) {
+	var effects *cameraEffectBuffers
+	if depthOfFieldActive(camera) || motionBlurActive(camera) {
+		// Bloom and FXAA feed the lens effects, which draw the output
+		effects = rend.cameraEffects(target)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, effects.fbos[0])
+		gl.Viewport(0, 0, target.width, target.height)
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, rend.outputFBO)
+		gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+		if scissor {
+			gl.Enable(gl.SCISSOR_TEST)
+		}
 	}
-
-	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
 	gl.Disable(gl.DEPTH_TEST)
 	gl.DepthMask(false)
 	gl.Disable(gl.BLEND)
@@ -1231,9 +1427,9 @@ func (rend *OpenGLRenderer) renderPostProcess() {
 	gl.Clear(gl.COLOR_BUFFER_BIT)
 	gl.BindVertexArray(rend.screenQuadVAO)
 	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, rend.postProcessTexture)
+	gl.BindTexture(gl.TEXTURE_2D, target.texture)
 
-	texelSize := mgl32.Vec2{1.0 / float32(rend.viewportWidth), 1.0 / float32(rend.viewportHeight)}
+	texelSize := mgl32.Vec2{1.0 / float32(target.width), 1.0 / float32(target.height)}
 
 	// Determine which shader to use based on enabled effects
 	if rend.EnableBloom && rend.bloomShader.program != 0 {
@@ -1260,6 +1456,9 @@ func (rend *OpenGLRenderer) renderPostProcess() {
 	}
 
 	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	if effects != nil {
+		rend.renderCameraEffects(effects, target, camera, viewport, scissor)
+	}
 	gl.BindVertexArray(0)
 	gl.Enable(gl.DEPTH_TEST)
 	gl.DepthMask(true)
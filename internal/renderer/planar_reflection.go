@@ -0,0 +1,243 @@
+package renderer
+
+import (
+	"math"
+	"slices"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DefaultReflectionResolution is the reflection texture size as a fraction of the view
+const DefaultReflectionResolution = 0.5
+
+// PlanarReflection renders the scene mirrored about a plane into a render texture
+// before each view is drawn, for water and mirrors. Geometry behind the plane is
+// clipped by moving the mirrored camera's near plane onto it.
+type PlanarReflection struct {
+	Name       string
+	Surface    *Model     // Reflecting model; the plane follows its transform and it is left out of the reflection
+	Point      mgl32.Vec3 // A point on the plane, in Surface's space when it is set
+	Normal     mgl32.Vec3 // Plane normal, in Surface's space when it is set. Either side may face the camera.
+	Resolution float32    // Texture size as a fraction of the view
+	ClipOffset float32    // Geometry this far behind the plane is still reflected, hiding gaps where the surface moves
+
+	Texture *RenderTexture // Sampled through RenderTexturePath(Name); set by AddPlanarReflection
+}
+
+// NewPlanarReflection returns a reflection in the surface model's local XZ plane
+func NewPlanarReflection(name string, surface *Model) *PlanarReflection {
+	return &PlanarReflection{
+		Name:       name,
+		Surface:    surface,
+		Normal:     mgl32.Vec3{0, 1, 0},
+		Resolution: DefaultReflectionResolution,
+	}
+}
+
+// Plane returns a world-space point on the plane and its unit normal
+func (p *PlanarReflection) Plane() (mgl32.Vec3, mgl32.Vec3) {
+	point, normal := p.Point, p.Normal
+	if p.Surface != nil {
+		if p.Surface.IsDirty {
+			p.Surface.calculateModelMatrix()
+			p.Surface.IsDirty = false
+		}
+		m := p.Surface.ModelMatrix
+		point = m.Mul4x1(point.Vec4(1)).Vec3()
+		normal = m.Mat3().Inv().Transpose().Mul3x1(normal)
+	}
+	if normal.Len() < 1e-6 {
+		normal = mgl32.Vec3{0, 1, 0}
+	}
+	return point, normal.Normalize()
+}
+
+// reflectCamera mirrors cam about the plane through point with the given unit
+// normal. Front and Up are mirrored as well, which keeps triangle winding but
+// flips the image left to right, so samplers read it at (1 - u, v).
+func reflectCamera(cam Camera, point, normal mgl32.Vec3) Camera {
+	mirror := func(v mgl32.Vec3) mgl32.Vec3 {
+		return v.Sub(normal.Mul(2 * v.Dot(normal)))
+	}
+	view := cam
+	view.Position = cam.Position.Sub(normal.Mul(2 * cam.Position.Sub(point).Dot(normal)))
+	view.Front = mirror(cam.Front)
+	view.Up = mirror(cam.Up)
+	view.Right = mirror(cam.Right)
+	return view
+}
+
+// obliqueProjection moves proj's near plane onto a view-space clip plane, so
+// everything on its negative side is clipped. The camera must be on that side.
+// See Lengyel, "Oblique View Frustum Depth Projection and Clipping"; q is the
+// far corner of the frustum opposite the plane, which works for perspective
+// and orthographic projections alike.
+func obliqueProjection(proj mgl32.Mat4, plane mgl32.Vec4) mgl32.Mat4 {
+	sign := func(v float32) float32 {
+		switch {
+		case v > 0:
+			return 1
+		case v < 0:
+			return -1
+		}
+		return 0
+	}
+	q := proj.Inv().Mul4x1(mgl32.Vec4{sign(plane.X()), sign(plane.Y()), 1, 1})
+	c := plane.Mul(2 / plane.Dot(q))
+	proj[2] = c.X() - proj[3]
+	proj[6] = c.Y() - proj[7]
+	proj[10] = c.Z() - proj[11]
+	proj[14] = c.W() - proj[15]
+	return proj
+}
+
+// AddPlanarReflection starts rendering a reflection every frame
+func (rend *OpenGLRenderer) AddPlanarReflection(p *PlanarReflection) {
+	if slices.Contains(rend.reflections, p) {
+		return
+	}
+	p.Texture = rend.RenderTexture(p.Name)
+	rend.reflections = append(rend.reflections, p)
+}
+
+// RemovePlanarReflection stops rendering a reflection and releases its texture
+func (rend *OpenGLRenderer) RemovePlanarReflection(p *PlanarReflection) {
+	i := slices.Index(rend.reflections, p)
+	if i < 0 {
+		return
+	}
+	rend.reflections = slices.Delete(rend.reflections, i, i+1)
+	rend.RemoveRenderTexture(p.Name)
+	p.Texture = nil
+}
+
+// PlanarReflections returns the reflections being rendered
+func (rend *OpenGLRenderer) PlanarReflections() []*PlanarReflection {
+	return slices.Clone(rend.reflections)
+}
+
+// SurfaceReflection returns the reflection on a model, or nil
+func (rend *OpenGLRenderer) SurfaceReflection(model *Model) *PlanarReflection {
+	for _, p := range rend.reflections {
+		if p.Surface == model {
+			return p
+		}
+	}
+	return nil
+}
+
+// removeSurfaceReflections drops the reflections on a model leaving the scene
+func (rend *OpenGLRenderer) removeSurfaceReflections(model *Model) {
+	for _, p := range rend.PlanarReflections() {
+		if p.Surface == model {
+			rend.RemovePlanarReflection(p)
+		}
+	}
+}
+
+// updatePlanarReflections renders each reflection as seen from camera, at the
+// current viewport size scaled by its resolution
+func (rend *OpenGLRenderer) updatePlanarReflections(camera Camera, light *Light) {
+	if len(rend.reflections) == 0 || rend.renderingTexture != nil {
+		return
+	}
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	for _, p := range rend.reflections {
+		if p.Surface != nil && !slices.Contains(rend.Models, p.Surface) {
+			continue
+		}
+		point, normal := p.Plane()
+		distance := normal.Dot(camera.Position.Sub(point))
+		if float32(math.Abs(float64(distance))) < 1e-4 {
+			continue // Looking along the plane; nothing to reflect
+		}
+		if distance < 0 {
+			normal = normal.Mul(-1)
+		}
+
+		scale := p.Resolution
+		if scale <= 0 {
+			scale = DefaultReflectionResolution
+		}
+		rt := p.Texture
+		rt.Width = max(int32(float32(viewport[2])*scale), 1)
+		rt.Height = max(int32(float32(viewport[3])*scale), 1)
+
+		view := rt.fitCamera(reflectCamera(camera, point, normal))
+		plane := mgl32.Vec4{normal.X(), normal.Y(), normal.Z(), p.ClipOffset - normal.Dot(point)}
+		view.Projection = obliqueProjection(view.Projection, view.GetViewMatrix().Inv().Transpose().Mul4x1(plane))
+		rend.drawRenderTexture(rt, view, light)
+	}
+}
+
+// NewMirrorMaterial returns a material showing a planar reflection, tinted by
+// its tint parameter and rippled by distortionStrength
+func NewMirrorMaterial(reflection *PlanarReflection) *MaterialAsset {
+	return &MaterialAsset{
+		Version: materialAssetVersion,
+		Name:    reflection.Name,
+		Shader:  MaterialShaderRef{Vertex: "mirror.vert", Fragment: "mirror.frag"},
+		Parameters: []MaterialParam{
+			{Name: "tint", Type: ParamColor, Value: []float32{0.95, 0.95, 0.95}},
+			{Name: "distortionStrength", Type: ParamFloat, Value: []float32{0}, Min: 0, Max: 0.5},
+		},
+		Textures:    []MaterialTexture{{Name: "reflectionTexture", Path: RenderTexturePath(reflection.Name)}},
+		RenderState: MaterialRenderState{Blend: BlendOpaque, Cull: CullBack},
+	}
+}
+
+// Mirror shaders, used by NewMirrorMaterial as mirror.vert and mirror.frag
+const mirrorVertexShaderSource = `#version 330 core
+
+layout(location = 0) in vec3 inPosition;
+layout(location = 1) in vec2 inTexCoord;
+layout(location = 3) in mat4 instanceModel;
+
+uniform bool isInstanced;
+uniform mat4 model;
+uniform mat4 viewProjection;
+
+out vec3 FragPos;
+out vec2 fragTexCoord;
+out vec4 ClipPos; // Screen position, for sampling the reflection
+
+void main() {
+    mat4 modelMatrix = isInstanced ? (model * instanceModel) : model;
+    FragPos = vec3(modelMatrix * vec4(inPosition, 1.0));
+    fragTexCoord = inTexCoord;
+    ClipPos = viewProjection * vec4(FragPos, 1.0);
+    gl_Position = ClipPos;
+}
+`
+
+const mirrorFragmentShaderSource = `#version 330 core
+
+in vec3 FragPos;
+in vec2 fragTexCoord;
+in vec4 ClipPos;
+
+uniform sampler2D reflectionTexture; // Planar reflection, drawn flipped left to right
+uniform vec3 tint;
+uniform float distortionStrength;
+
+out vec4 FragColor;
+
+#include "color_space.glsl"
+
+void main() {
+    vec2 screenUV = ClipPos.xy / ClipPos.w * 0.5 + 0.5;
+    vec2 uv = vec2(1.0 - screenUV.x, screenUV.y);
+
+    // Warp the lookup like uneven glass
+    vec2 ripple = vec2(sin(FragPos.y * 3.1 + FragPos.x * 1.7), cos(FragPos.x * 2.3 + FragPos.z * 2.9));
+    uv += ripple * distortionStrength * 0.02;
+
+    // The reflection already carries fog for the full path through the mirror
+    // Render textures are sRGB, so encode the decoded sample again for display
+    vec3 color = linearToSRGB(texture(reflectionTexture, clamp(uv, 0.001, 0.999)).rgb) * tint;
+    FragColor = vec4(color, 1.0);
+}
+`
@@ -0,0 +1,94 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestReflectCamera(t *testing.T) {
+	cam := NewDefaultCamera(600, 800)
+	cam.Position = mgl32.Vec3{3, 10, 5}
+	cam.SetRotation(-90, -30)
+
+	// Water at y = 2
+	view := reflectCamera(*cam, mgl32.Vec3{0, 2, 0}, mgl32.Vec3{0, 1, 0})
+	if want := (mgl32.Vec3{3, -6, 5}); !view.Position.ApproxEqual(want) {
+		t.Errorf("mirrored position = %v, want %v", view.Position, want)
+	}
+	if want := (mgl32.Vec3{cam.Front.X(), -cam.Front.Y(), cam.Front.Z()}); !view.Front.ApproxEqual(want) {
+		t.Errorf("mirrored front = %v, want %v", view.Front, want)
+	}
+
+	// A point above the water seen directly, and its mirror image seen in the
+	// reflection, land on the same screen position with x flipped
+	world := mgl32.Vec3{4, 6, -20}
+	image := mgl32.Vec3{world.X(), 4 - world.Y(), world.Z()}
+	direct := mgl32.TransformCoordinate(image, cam.GetViewProjection())
+	reflected := mgl32.TransformCoordinate(world, view.GetViewProjection())
+	if math.Abs(float64(direct.X()+reflected.X())) > 1e-4 || math.Abs(float64(direct.Y()-reflected.Y())) > 1e-4 {
+		t.Errorf("reflection at %v, want mirror of %v", reflected, direct)
+	}
+}
+
+func TestObliqueProjectionClipsAtPlane(t *testing.T) {
+	t.Run("perspective", func(t *testing.T) {
+		testObliqueProjection(t, mgl32.Perspective(mgl32.DegToRad(60), 1.5, 0.1, 1000))
+	})
+	t.Run("orthographic", func(t *testing.T) {
+		testObliqueProjection(t, mgl32.Ortho(-15, 15, -10, 10, 0.1, 1000))
+	})
+}
+
+func testObliqueProjection(t *testing.T, proj mgl32.Mat4) {
+	// Tilted view-space plane through (0, -1, -5), camera on its negative side
+	normal := mgl32.Vec3{0, 0.6, -0.8}
+	point := mgl32.Vec3{0, -1, -5}
+	plane := mgl32.Vec4{normal.X(), normal.Y(), normal.Z(), -normal.Dot(point)}
+	if plane.W() >= 0 {
+		t.Fatal("test plane must have the camera on its negative side")
+	}
+	oblique := obliqueProjection(proj, plane)
+
+	along := mgl32.Vec3{0, 0.8, 0.6}
+	for _, p := range []mgl32.Vec3{point, point.Add(mgl32.Vec3{2, 0, 0}), point.Add(along.Mul(0.5))} {
+		clip := oblique.Mul4x1(p.Vec4(1))
+		if ndcZ := clip.Z() / clip.W(); math.Abs(float64(ndcZ+1)) > 1e-3 {
+			t.Errorf("point %v on the plane has depth %v, want -1", p, ndcZ)
+		}
+	}
+
+	// Beyond the plane is kept, in front of it is clipped
+	beyond := oblique.Mul4x1(point.Add(normal.Mul(2)).Vec4(1))
+	if z := beyond.Z() / beyond.W(); z <= -1 || z > 1 {
+		t.Errorf("point beyond the plane has depth %v, want inside (-1, 1]", z)
+	}
+	before := oblique.Mul4x1(point.Sub(normal.Mul(2)).Vec4(1))
+	if z := before.Z() / before.W(); z >= -1 {
+		t.Errorf("point in front of the plane has depth %v, want clipped", z)
+	}
+
+	// x and y are untouched
+	for _, i := range []int{0, 1, 3, 4, 5, 7, 8, 9, 11, 12, 13, 15} {
+		if oblique[i] != proj[i] {
+			t.Errorf("element %d changed from %v to %v", i, proj[i], oblique[i])
+		}
+	}
+}
+
+func TestPlanarReflectionPlaneFollowsSurface(t *testing.T) {
+	surface := &Model{Scale: mgl32.Vec3{2, 2, 2}}
+	surface.SetPositionVec(mgl32.Vec3{0, 5, 0})
+	// Stand the mirror upright, facing +Z
+	surface.SetRotationQuat(mgl32.QuatRotate(mgl32.DegToRad(90), mgl32.Vec3{1, 0, 0}))
+
+	p := NewPlanarReflection("mirror", surface)
+	point, normal := p.Plane()
+	if !point.ApproxEqual(mgl32.Vec3{0, 5, 0}) {
+		t.Errorf("plane point = %v, want the surface position", point)
+	}
+	if normal.Sub(mgl32.Vec3{0, 0, 1}).Len() > 1e-5 {
+		t.Errorf("plane normal = %v, want +Z", normal)
+	}
+}
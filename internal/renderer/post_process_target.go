@@ -0,0 +1,114 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// maxPostProcessSize is a safety limit to prevent GPU crashes
+const maxPostProcessSize = 8192
+
+// postProcessTarget holds the scene color and depth a view is drawn into before
+// post-processing resolves it to the output framebuffer
+type postProcessTarget struct {
+	fbo          uint32 // Framebuffer for post-processing
+	texture      uint32 // Color texture for post-processing
+	depth        uint32 // Depth and stencil texture, sampled by depth of field
+	bloomFBO     uint32 // Framebuffer for bloom
+	bloomTexture uint32 // Bloom texture, a quarter of the scene size
+	width        int32
+	height       int32
+}
+
+// newPostProcessTarget creates the framebuffers; attachments are made by resize
+func newPostProcessTarget() *postProcessTarget {
+	t := &postProcessTarget{}
+	gl.GenFramebuffers(1, &t.fbo)
+	gl.GenFramebuffers(1, &t.bloomFBO)
+	return t
+}
+
+// postProcessSize clamps a viewport dimension to what the targets support
+func postProcessSize(size int32) int32 {
+	return min(max(size, 1), maxPostProcessSize)
+}
+
+// resize recreates the attachments at a new size
+func (t *postProcessTarget) resize(width, height int32) {
+	width, height = postProcessSize(width), postProcessSize(height)
+	t.deleteAttachments()
+
+	// Scene color texture
+	gl.GenTextures(1, &t.texture)
+	gl.BindTexture(gl.TEXTURE_2D, t.texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+
+	// Scene depth, a texture so post effects can read it
+	gl.GenTextures(1, &t.depth)
+	gl.BindTexture(gl.TEXTURE_2D, t.depth)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH24_STENCIL8, width, height, 0, gl.DEPTH_STENCIL, gl.UNSIGNED_INT_24_8, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.texture, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.TEXTURE_2D, t.depth, 0)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		logger.Log.Error(fmt.Sprintf("Post-process framebuffer incomplete after resize! Status: 0x%X", status))
+	}
+
+	// Bloom texture (HDR) - Downscaled
+	bloomWidth, bloomHeight := max(width/4, 1), max(height/4, 1)
+	gl.GenTextures(1, &t.bloomTexture)
+	gl.BindTexture(gl.TEXTURE_2D, t.bloomTexture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA16F, bloomWidth, bloomHeight, 0, gl.RGBA, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.bloomFBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.bloomTexture, 0)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		logger.Log.Error("Bloom framebuffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	t.width, t.height = width, height
+	logger.Log.Info(fmt.Sprintf("Resized post-processing framebuffers (Scene: %dx%d, Bloom: %dx%d)", width, height, bloomWidth, bloomHeight))
+}
+
+func (t *postProcessTarget) deleteAttachments() {
+	if t.texture != 0 {
+		gl.DeleteTextures(1, &t.texture)
+	}
+	if t.bloomTexture != 0 {
+		gl.DeleteTextures(1, &t.bloomTexture)
+	}
+	if t.depth != 0 {
+		gl.DeleteTextures(1, &t.depth)
+	}
+	t.texture, t.bloomTexture, t.depth = 0, 0, 0
+}
+
+// delete releases the framebuffers and their attachments
+func (t *postProcessTarget) delete() {
+	t.deleteAttachments()
+	gl.DeleteFramebuffers(1, &t.fbo)
+	gl.DeleteFramebuffers(1, &t.bloomFBO)
+	t.fbo, t.bloomFBO = 0, 0
+}
+
+// postTarget returns the post-processing buffers of the view being drawn
+func (rend *OpenGLRenderer) postTarget() *postProcessTarget {
+	for len(rend.postTargets) <= rend.activeView {
+		rend.postTargets = append(rend.postTargets, newPostProcessTarget())
+	}
+	return rend.postTargets[rend.activeView]
+}
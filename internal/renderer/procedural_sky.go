@@ -0,0 +1,321 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AtmosphereSettings drives the procedural sky (single scattering, Rayleigh + Mie).
+// Distances are in meters; the scene is assumed to sit at ground level on the planet.
+type AtmosphereSettings struct {
+	RayleighCoefficient mgl32.Vec3 // Rayleigh scattering per meter (per RGB wavelength)
+	MieCoefficient      float32    // Mie scattering per meter (haze/aerosols)
+	RayleighScaleHeight float32    // Altitude where Rayleigh density falls to 1/e
+	MieScaleHeight      float32    // Altitude where Mie density falls to 1/e
+	MieDirectionality   float32    // Mie phase asymmetry g (0 = isotropic, ~0.76 = hazy sun halo)
+	SunIntensity        float32    // Incoming sun radiance
+	PlanetRadius        float32
+	AtmosphereRadius    float32
+	Exposure            float32    // Tone mapping exposure for the sky
+	NightColor          mgl32.Vec3 // Floor color so the night sky is not pitch black
+}
+
+// DefaultAtmosphereSettings returns Earth-like scattering values
+func DefaultAtmosphereSettings() AtmosphereSettings {
+	return AtmosphereSettings{
+		RayleighCoefficient: mgl32.Vec3{5.5e-6, 13.0e-6, 22.4e-6},
+		MieCoefficient:      21e-6,
+		RayleighScaleHeight: 8e3,
+		MieScaleHeight:      1.2e3,
+		MieDirectionality:   0.758,
+		SunIntensity:        22.0,
+		PlanetRadius:        6371e3,
+		AtmosphereRadius:    6471e3,
+		Exposure:            1.0,
+		NightColor:          mgl32.Vec3{0.01, 0.015, 0.03},
+	}
+}
+
+// CreateProceduralSkybox creates a sky rendered from the atmosphere model
+func CreateProceduralSkybox(settings AtmosphereSettings) (*Skybox, error) {
+	skybox := &Skybox{}
+	initSkyboxGeometry(skybox)
+
+	atmosphere := settings
+	skybox.Atmosphere = &atmosphere
+	skybox.SunDirection = mgl32.Vec3{0, 1, 0}
+
+	skybox.Shader = InitProceduralSkyShader()
+	if err := skybox.Shader.Compile(); err != nil {
+		return nil, err
+	}
+
+	return skybox, nil
+}
+
+// InitProceduralSkyShader creates the atmospheric scattering sky shader
+func InitProceduralSkyShader() Shader {
+	return Shader{
+		vertexSource:   skyboxVertexShaderSource,
+		fragmentSource: proceduralSkyFragmentShaderSource,
+		vertexFile:     "skybox.vert",
+		fragmentFile:   "procedural_sky.frag",
+		Name:           "procedural_sky",
+	}
+}
+
+func (a *AtmosphereSettings) setUniforms(shader *Shader, sunDirection mgl32.Vec3) {
+	if sunDirection.Len() > 0 {
+		sunDirection = sunDirection.Normalize()
+	}
+	shader.SetVec3("sunDirection", sunDirection)
+	shader.SetVec3("rayleighCoefficient", a.RayleighCoefficient)
+	shader.SetFloat("mieCoefficient", a.MieCoefficient)
+	shader.SetFloat("rayleighScaleHeight", a.RayleighScaleHeight)
+	shader.SetFloat("mieScaleHeight", a.MieScaleHeight)
+	shader.SetFloat("mieDirectionality", a.MieDirectionality)
+	shader.SetFloat("sunIntensity", a.SunIntensity)
+	shader.SetFloat("planetRadius", a.PlanetRadius)
+	shader.SetFloat("atmosphereRadius", a.AtmosphereRadius)
+	shader.SetFloat("skyExposure", a.Exposure)
+	shader.SetVec3("nightColor", a.NightColor)
+}
+
+const (
+	atmospherePrimarySteps   = 16
+	atmosphereSecondarySteps = 8
+)
+
+// raySphere returns the near and far hits of a ray against a sphere at the origin.
+// near > far means the ray misses.
+func raySphere(ro, rd [3]float64, radius float64) (float64, float64) {
+	b := ro[0]*rd[0] + ro[1]*rd[1] + ro[2]*rd[2]
+	c := ro[0]*ro[0] + ro[1]*ro[1] + ro[2]*ro[2] - radius*radius
+	d := b*b - c
+	if d < 0 {
+		return 1e5, -1e5
+	}
+	d = math.Sqrt(d)
+	return -b - d, -b + d
+}
+
+// Scatter returns the untonemapped in-scattered sky radiance for a view direction.
+// It mirrors atmosphere() in proceduralSkyFragmentShaderSource.
+func (a AtmosphereSettings) Scatter(viewDir, sunDir mgl32.Vec3) mgl32.Vec3 {
+	rd := vec3To64(viewDir.Normalize())
+	sun := vec3To64(sunDir.Normalize())
+	ro := [3]float64{0, float64(a.PlanetRadius) + 1.0, 0}
+
+	near, far := raySphere(ro, rd, float64(a.AtmosphereRadius))
+	if near > far {
+		return mgl32.Vec3{}
+	}
+	tEnd := far
+	if gNear, gFar := raySphere(ro, rd, float64(a.PlanetRadius)); gNear < gFar && gNear > 0 {
+		tEnd = gNear
+	}
+	tStart := math.Max(near, 0)
+	stepSize := (tEnd - tStart) / atmospherePrimarySteps
+
+	kRlh := vec3To64(a.RayleighCoefficient)
+	kMie := float64(a.MieCoefficient)
+	rsh := float64(a.RayleighScaleHeight)
+	msh := float64(a.MieScaleHeight)
+	g := float64(a.MieDirectionality)
+
+	mu := rd[0]*sun[0] + rd[1]*sun[1] + rd[2]*sun[2]
+	mumu := mu * mu
+	gg := g * g
+	pRlh := 3.0 / (16.0 * math.Pi) * (1.0 + mumu)
+	pMie := 3.0 / (8.0 * math.Pi) * ((1.0 - gg) * (mumu + 1.0)) / (math.Pow(1.0+gg-2.0*mu*g, 1.5) * (2.0 + gg))
+
+	var totalRlh, totalMie [3]float64
+	iOdRlh, iOdMie := 0.0, 0.0
+
+	for i := 0; i < atmospherePrimarySteps; i++ {
+		t := tStart + (float64(i)+0.5)*stepSize
+		pos := [3]float64{ro[0] + rd[0]*t, ro[1] + rd[1]*t, ro[2] + rd[2]*t}
+		height := length64(pos) - float64(a.PlanetRadius)
+
+		odStepRlh := math.Exp(-height/rsh) * stepSize
+		odStepMie := math.Exp(-height/msh) * stepSize
+		iOdRlh += odStepRlh
+		iOdMie += odStepMie
+
+		_, jFar := raySphere(pos, sun, float64(a.AtmosphereRadius))
+		jStepSize := jFar / atmosphereSecondarySteps
+		jOdRlh, jOdMie := 0.0, 0.0
+		for j := 0; j < atmosphereSecondarySteps; j++ {
+			jt := (float64(j) + 0.5) * jStepSize
+			jPos := [3]float64{pos[0] + sun[0]*jt, pos[1] + sun[1]*jt, pos[2] + sun[2]*jt}
+			jHeight := length64(jPos) - float64(a.PlanetRadius)
+			jOdRlh += math.Exp(-jHeight/rsh) * jStepSize
+			jOdMie += math.Exp(-jHeight/msh) * jStepSize
+		}
+
+		for c := 0; c < 3; c++ {
+			attn := math.Exp(-(kRlh[c]*(iOdRlh+jOdRlh) + kMie*(iOdMie+jOdMie)))
+			totalRlh[c] += odStepRlh * attn
+			totalMie[c] += odStepMie * attn
+		}
+	}
+
+	var out mgl32.Vec3
+	for c := 0; c < 3; c++ {
+		out[c] = float32(float64(a.SunIntensity) * (pRlh*kRlh[c]*totalRlh[c] + pMie*kMie*totalMie[c]))
+	}
+	return out
+}
+
+// SkyColor returns the displayed sky color for a view direction, matching the shader output
+func (a AtmosphereSettings) SkyColor(viewDir, sunDir mgl32.Vec3) mgl32.Vec3 {
+	radiance := a.Scatter(viewDir, sunDir)
+	var out mgl32.Vec3
+	for c := 0; c < 3; c++ {
+		out[c] = float32(math.Max(1.0-math.Exp(-float64(a.Exposure*radiance[c])), float64(a.NightColor[c])))
+	}
+	return out
+}
+
+// SunTransmittance returns how much sunlight reaches the ground per channel (0-1).
+// Near the horizon blue is scattered away, leaving the sun orange-red.
+func (a AtmosphereSettings) SunTransmittance(sunDir mgl32.Vec3) mgl32.Vec3 {
+	sun := vec3To64(sunDir.Normalize())
+	ro := [3]float64{0, float64(a.PlanetRadius) + 1.0, 0}
+
+	if gNear, gFar := raySphere(ro, sun, float64(a.PlanetRadius)); gNear < gFar && gNear > 0 {
+		return mgl32.Vec3{} // Sun is below the horizon
+	}
+
+	_, far := raySphere(ro, sun, float64(a.AtmosphereRadius))
+	stepSize := far / atmospherePrimarySteps
+	odRlh, odMie := 0.0, 0.0
+	for i := 0; i < atmospherePrimarySteps; i++ {
+		t := (float64(i) + 0.5) * stepSize
+		pos := [3]float64{ro[0] + sun[0]*t, ro[1] + sun[1]*t, ro[2] + sun[2]*t}
+		height := length64(pos) - float64(a.PlanetRadius)
+		odRlh += math.Exp(-height/float64(a.RayleighScaleHeight)) * stepSize
+		odMie += math.Exp(-height/float64(a.MieScaleHeight)) * stepSize
+	}
+
+	var out mgl32.Vec3
+	for c := 0; c < 3; c++ {
+		out[c] = float32(math.Exp(-(float64(a.RayleighCoefficient[c])*odRlh + float64(a.MieCoefficient)*1.1*odMie)))
+	}
+	return out
+}
+
+func vec3To64(v mgl32.Vec3) [3]float64 {
+	return [3]float64{float64(v[0]), float64(v[1]), float64(v[2])}
+}
+
+func length64(v [3]float64) float64 {
+	return math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+}
+
+// Procedural sky fragment shader: single-scattering atmosphere raymarch.
+// Shares the skybox vertex shader, so TexCoords is the view direction.
+var proceduralSkyFragmentShaderSource = `#version 330 core
+out vec4 FragColor;
+
+in vec3 TexCoords;
+
+uniform vec3 viewPos;
+uniform vec3 sunDirection;        // Direction toward the sun
+uniform vec3 rayleighCoefficient;
+uniform float mieCoefficient;
+uniform float rayleighScaleHeight;
+uniform float mieScaleHeight;
+uniform float mieDirectionality;
+uniform float sunIntensity;
+uniform float planetRadius;
+uniform float atmosphereRadius;
+uniform float skyExposure;
+uniform vec3 nightColor;
+
+#define PRIMARY_STEPS 16
+#define SECONDARY_STEPS 8
+const float PI = 3.14159265359;
+#include "fog.glsl"
+// Returns near/far hit distances; near > far means no hit
+vec2 raySphere(vec3 ro, vec3 rd, float radius) {
+    float b = dot(ro, rd);
+    float c = dot(ro, ro) - radius * radius;
+    float d = b * b - c;
+    if (d < 0.0) {
+        return vec2(1e5, -1e5);
+    }
+    d = sqrt(d);
+    return vec2(-b - d, -b + d);
+}
+
+vec3 atmosphere(vec3 rd, vec3 ro, vec3 sun) {
+    vec2 p = raySphere(ro, rd, atmosphereRadius);
+    if (p.x > p.y) {
+        return vec3(0.0);
+    }
+    float tEnd = p.y;
+    vec2 ground = raySphere(ro, rd, planetRadius);
+    if (ground.x < ground.y && ground.x > 0.0) {
+        tEnd = ground.x;
+    }
+    float tStart = max(p.x, 0.0);
+    float stepSize = (tEnd - tStart) / float(PRIMARY_STEPS);
+
+    float mu = dot(rd, sun);
+    float mumu = mu * mu;
+    float g = mieDirectionality;
+    float gg = g * g;
+    float pRlh = 3.0 / (16.0 * PI) * (1.0 + mumu);
+    float pMie = 3.0 / (8.0 * PI) * ((1.0 - gg) * (mumu + 1.0)) / (pow(1.0 + gg - 2.0 * mu * g, 1.5) * (2.0 + gg));
+
+    vec3 totalRlh = vec3(0.0);
+    vec3 totalMie = vec3(0.0);
+    float iOdRlh = 0.0;
+    float iOdMie = 0.0;
+
+    for (int i = 0; i < PRIMARY_STEPS; i++) {
+        vec3 pos = ro + rd * (tStart + (float(i) + 0.5) * stepSize);
+        float height = length(pos) - planetRadius;
+
+        float odStepRlh = exp(-height / rayleighScaleHeight) * stepSize;
+        float odStepMie = exp(-height / mieScaleHeight) * stepSize;
+        iOdRlh += odStepRlh;
+        iOdMie += odStepMie;
+
+        float jStepSize = raySphere(pos, sun, atmosphereRadius).y / float(SECONDARY_STEPS);
+        float jOdRlh = 0.0;
+        float jOdMie = 0.0;
+        for (int j = 0; j < SECONDARY_STEPS; j++) {
+            vec3 jPos = pos + sun * ((float(j) + 0.5) * jStepSize);
+            float jHeight = length(jPos) - planetRadius;
+            jOdRlh += exp(-jHeight / rayleighScaleHeight) * jStepSize;
+            jOdMie += exp(-jHeight / mieScaleHeight) * jStepSize;
+        }
+
+        vec3 attn = exp(-(rayleighCoefficient * (iOdRlh + jOdRlh) + mieCoefficient * (iOdMie + jOdMie)));
+        totalRlh += odStepRlh * attn;
+        totalMie += odStepMie * attn;
+    }
+
+    return sunIntensity * (pRlh * rayleighCoefficient * totalRlh + pMie * mieCoefficient * totalMie);
+}
+
+void main() {
+    vec3 dir = normalize(TexCoords);
+    vec3 sun = normalize(sunDirection);
+    vec3 ro = vec3(0.0, planetRadius + 1.0, 0.0);
+
+    vec3 color = atmosphere(dir, ro, sun);
+
+    // Sun disc, hidden once it drops below the horizon
+    float sunDisc = smoothstep(0.9995, 0.9998, dot(dir, sun)) * step(0.0, dir.y);
+    color += sunDisc * sunIntensity * 0.5 * exp(-rayleighCoefficient * rayleighScaleHeight / max(sun.y, 0.02));
+
+    color = 1.0 - exp(-skyExposure * color);
+    color = max(color, nightColor);
+
+    FragColor = vec4(mix(color, sceneFog.color, computeSceneSkyFog(dir, viewPos)), 1.0);
+}
+` + "\x00"
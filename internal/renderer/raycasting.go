@@ -110,6 +110,18 @@ func RayIntersectTriangle(ray Ray, v0, v1, v2 mgl32.Vec3) (bool, float32, mgl32.
 
 // ScreenToRay converts a screen position to a world space ray
 func ScreenToRay(camera Camera, screenX, screenY float32, windowWidth, windowHeight int) Ray {
+	if camera.ProjectionMode == ProjectionOrthographic {
+		// Parallel rays, starting where the pixel sits on the camera plane
+		h := camera.orthoHalfHeight()
+		x := (2.0*screenX/float32(windowWidth) - 1.0) * h * camera.AspectRatio
+		y := (1.0 - 2.0*screenY/float32(windowHeight)) * h
+		right := camera.Front.Cross(camera.Up).Normalize()
+		return Ray{
+			Origin:    camera.Position.Add(right.Mul(x)).Add(camera.Up.Mul(y)),
+			Direction: camera.Front.Normalize(),
+		}
+	}
+
 	// Normalize screen coordinates to NDC (-1 to 1)
 	ndcX := (2.0*screenX/float32(windowWidth) - 1.0) * camera.AspectRatio
 	ndcY := 1.0 - 2.0*screenY/float32(windowHeight)
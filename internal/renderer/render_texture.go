@@ -0,0 +1,249 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.uber.org/zap"
+)
+
+// RenderTexturePrefix marks texture paths that name a render texture instead of a file
+const RenderTexturePrefix = "rendertexture:"
+
+// RenderTexturePath returns the texture path that binds the named render texture
+// to a material slot
+func RenderTexturePath(name string) string {
+	return RenderTexturePrefix + name
+}
+
+// RenderTextureName returns the render texture a texture path refers to
+func RenderTextureName(path string) (string, bool) {
+	name, ok := strings.CutPrefix(path, RenderTexturePrefix)
+	return name, ok && name != ""
+}
+
+// RenderTexture is a texture a camera renders the scene into, for monitors,
+// mirrors, portals and minimaps. Materials use it through RenderTexturePath(Name).
+type RenderTexture struct {
+	Name       string
+	Width      int32   // Resolution, applied on the next update
+	Height     int32   // Resolution, applied on the next update
+	UpdateRate float32 // Updates per second; 0 updates every frame
+	Camera     *Camera // Nothing is drawn until a camera is set
+
+	TextureID uint32 // Color texture; kept across resizes so materials keep working
+
+	fbo, depth     uint32
+	allocW, allocH int32
+	lastUpdate     time.Time
+}
+
+// Default render texture resolution
+const (
+	DefaultRenderTextureWidth  = 512
+	DefaultRenderTextureHeight = 512
+)
+
+// Render textures have no mipmaps and shouldn't tile their edges
+var renderTextureSampler = SamplerSettings{Wrap: WrapClamp, Filter: FilterBilinear}
+
+// due returns true if the texture should be redrawn at now
+func (rt *RenderTexture) due(now time.Time) bool {
+	if rt.Camera == nil {
+		return false
+	}
+	if rt.UpdateRate <= 0 || rt.lastUpdate.IsZero() {
+		return true
+	}
+	return now.Sub(rt.lastUpdate) >= time.Duration(float64(time.Second)/float64(rt.UpdateRate))
+}
+
+// size returns the resolution clamped to what the GPU can render
+func (rt *RenderTexture) size() (int32, int32) {
+	return postProcessSize(rt.Width), postProcessSize(rt.Height)
+}
+
+// allocate (re)creates the framebuffer storage for the current resolution
+func (rt *RenderTexture) allocate() {
+	width, height := rt.size()
+	if rt.fbo != 0 && width == rt.allocW && height == rt.allocH {
+		return
+	}
+	if rt.fbo == 0 {
+		gl.GenFramebuffers(1, &rt.fbo)
+		gl.GenRenderbuffers(1, &rt.depth)
+	}
+
+	// Respecify the existing texture so its ID stays valid in materials
+	gl.BindTexture(gl.TEXTURE_2D, rt.TextureID)
+	// The shaded output written here is already display-encoded, so store it as
+	// sRGB and let materials sample it back as linear
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.SRGB8_ALPHA8, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	applySampler(gl.TEXTURE_2D, renderTextureSampler, false)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, rt.depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, rt.TextureID, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, rt.depth)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		logger.Log.Error("Render texture framebuffer incomplete",
+			zap.String("name", rt.Name), zap.String("status", fmt.Sprintf("0x%X", status)))
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	rt.allocW, rt.allocH = width, height
+}
+
+// RenderTexture returns the named render texture, creating it at the default
+// resolution if needed. Materials may reference it before it is configured.
+func (tm *TextureManager) RenderTexture(name string) *RenderTexture {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.renderTextureLocked(name)
+}
+
+func (tm *TextureManager) renderTextureLocked(name string) *RenderTexture {
+	if rt, ok := tm.renderTextures[name]; ok {
+		return rt
+	}
+	rt := &RenderTexture{Name: name, Width: DefaultRenderTextureWidth, Height: DefaultRenderTextureHeight}
+	gl.GenTextures(1, &rt.TextureID)
+	// Black until the first update so materials never sample undefined memory
+	black := []uint8{0, 0, 0, 255}
+	gl.BindTexture(gl.TEXTURE_2D, rt.TextureID)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.SRGB8_ALPHA8, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(black))
+	applySampler(gl.TEXTURE_2D, renderTextureSampler, false)
+	// The registry holds one reference, so materials releasing theirs never free it
+	tm.addToCache(RenderTexturePath(name), rt.TextureID, int64(rt.Width)*int64(rt.Height)*4)
+	tm.renderTextures[name] = rt
+	return rt
+}
+
+// renderTextureReference returns the named render texture's ID with a new reference
+func (tm *TextureManager) renderTextureReference(name string) uint32 {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	rt := tm.renderTextureLocked(name)
+	tm.textureRefCount[rt.TextureID]++
+	return rt.TextureID
+}
+
+// RenderTextures returns every render texture, sorted by name
+func (rend *OpenGLRenderer) RenderTextures() []*RenderTexture {
+	tm := rend.textureManager
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	list := make([]*RenderTexture, 0, len(tm.renderTextures))
+	for _, rt := range tm.renderTextures {
+		list = append(list, rt)
+	}
+	slices.SortFunc(list, func(a, b *RenderTexture) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+// RenderTexture returns the named render texture, creating it if needed
+func (rend *OpenGLRenderer) RenderTexture(name string) *RenderTexture {
+	return rend.textureManager.RenderTexture(name)
+}
+
+// RemoveRenderTexture stops updating a render texture and frees it once no
+// material uses it
+func (rend *OpenGLRenderer) RemoveRenderTexture(name string) {
+	tm := rend.textureManager
+	tm.mu.Lock()
+	rt, ok := tm.renderTextures[name]
+	delete(tm.renderTextures, name)
+	tm.mu.Unlock()
+	if !ok {
+		return
+	}
+	if rt.fbo != 0 {
+		gl.DeleteFramebuffers(1, &rt.fbo)
+		gl.DeleteRenderbuffers(1, &rt.depth)
+	}
+	tm.ReleaseTexture(rt.TextureID)
+}
+
+// updateRenderTextures redraws the render textures that are due this frame
+func (rend *OpenGLRenderer) updateRenderTextures(light *Light) {
+	if rend.renderingTexture != nil || rend.capturing {
+		return
+	}
+	now := Clock.Now()
+	for _, rt := range rend.RenderTextures() {
+		if rt.due(now) {
+			rt.lastUpdate = now
+			rend.drawRenderTexture(rt, rt.fitCamera(*rt.Camera), light)
+		}
+	}
+}
+
+// fitCamera returns a copy of cam with the texture's aspect ratio
+func (rt *RenderTexture) fitCamera(cam Camera) Camera {
+	width, height := rt.size()
+	if aspect := float32(width) / float32(height); cam.AspectRatio != aspect {
+		cam.SetAspectRatio(aspect)
+	}
+	return cam
+}
+
+// drawRenderTexture renders view into rt's texture. Post-processing is skipped,
+// and models showing rt are left out to avoid sampling the target.
+func (rend *OpenGLRenderer) drawRenderTexture(rt *RenderTexture, view Camera, light *Light) {
+	endPass := rend.profilePass("Render Texture " + rt.Name)
+	defer endPass()
+
+	rt.allocate()
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	scissor := gl.IsEnabled(gl.SCISSOR_TEST)
+	previousOutput, previousTexture := rend.outputFBO, rend.renderingTexture
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+	gl.Viewport(0, 0, rt.allocW, rt.allocH)
+	gl.Disable(gl.SCISSOR_TEST)
+	rend.outputFBO = rt.fbo
+	rend.renderingTexture = rt
+
+	drawCalls := rend.lastDrawCalls
+	rend.Render(view, light)
+	rend.lastDrawCalls += drawCalls
+
+	rend.renderingTexture = previousTexture
+	rend.outputFBO = previousOutput
+	gl.BindFramebuffer(gl.FRAMEBUFFER, previousOutput)
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	if scissor {
+		gl.Enable(gl.SCISSOR_TEST)
+	}
+}
+
+// usesTexture returns true if any of the model's materials or custom uniforms samples rt
+func (m *Model) usesTexture(rt *RenderTexture) bool {
+	textureID := rt.TextureID
+	if m.Material != nil && m.Material.TextureID == textureID {
+		return true
+	}
+	for _, group := range m.MaterialGroups {
+		if group.Material != nil && group.Material.TextureID == textureID {
+			return true
+		}
+	}
+	if m.CustomMaterial != nil {
+		for _, t := range m.CustomMaterial.Textures {
+			if t.textureID == textureID || t.Path == RenderTexturePath(rt.Name) {
+				return true
+			}
+		}
+	}
+	for _, value := range m.CustomUniforms {
+		if v, ok := value.(*RenderTexture); ok && v == rt {
+			return true
+		}
+	}
+	return false
+}
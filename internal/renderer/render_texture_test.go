@@ -0,0 +1,43 @@
+package renderer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderTexturePaths(t *testing.T) {
+	path := RenderTexturePath("security cam")
+	if name, ok := RenderTextureName(path); !ok || name != "security cam" {
+		t.Errorf("RenderTextureName(%q) = %q, %v", path, name, ok)
+	}
+	for _, path := range []string{"textures/monitor.png", RenderTexturePrefix, ""} {
+		if name, ok := RenderTextureName(path); ok {
+			t.Errorf("RenderTextureName(%q) = %q, want no render texture", path, name)
+		}
+	}
+}
+
+func TestRenderTextureUpdateRate(t *testing.T) {
+	rt := &RenderTexture{Name: "minimap", UpdateRate: 10}
+	now := time.Unix(100, 0)
+	if rt.due(now) {
+		t.Error("a render texture without a camera should never update")
+	}
+
+	rt.Camera = &Camera{}
+	if !rt.due(now) {
+		t.Fatal("first update should happen immediately")
+	}
+	rt.lastUpdate = now
+	if rt.due(now.Add(50 * time.Millisecond)) {
+		t.Error("10 Hz texture updated after 50ms")
+	}
+	if !rt.due(now.Add(100 * time.Millisecond)) {
+		t.Error("10 Hz texture not updated after 100ms")
+	}
+
+	rt.UpdateRate = 0
+	if !rt.due(now) {
+		t.Error("rate 0 should update every frame")
+	}
+}
@@ -33,12 +33,15 @@ type Light struct {
 	ConstantAtten   float32    // Constant attenuation factor (usually 1.0)
 	LinearAtten     float32    // Linear attenuation factor
 	QuadraticAtten  float32    // Quadratic attenuation factor
+	AreaWidth       float32    // Surface width of rect and disk lights, which emit along Direction
+	AreaHeight      float32    // Surface height of rect and disk lights
 
 	// COLD DATA - Configuration, rarely changes during runtime
-	Name       string    // Light name for editor identification
-	Type       LightType // "static", "dynamic"
-	Mode       string    // "directional", "point", "spot"
-	Calculated bool      // Pre-calculation flag
+	Name       string      // Light name for editor identification
+	Type       LightType   // "static", "dynamic"
+	Mode       string      // "directional", "point", "spot", "rect", "disk"
+	Calculated bool        // Pre-calculation flag
+	IES        *IESProfile // Photometric distribution of point and spot lights around Direction; nil is uniform
 }
 
 type Render interface {
@@ -52,3 +55,9 @@ type Render interface {
 	Cleanup()
 	UpdateViewport(width, height int32)
 }
+
+// MultiViewRenderer is implemented by renderers that can draw several cameras
+// into their viewport rects in one frame
+type MultiViewRenderer interface {
+	RenderViews(cameras []*Camera, light *Light)
+}
@@ -0,0 +1,532 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"go.uber.org/zap"
+)
+
+// =============================================================
+//
+//	Shader Library
+//  Resolves shader sources by file name, expands #include and
+//  watches the shader directory for live edits
+//
+// =============================================================
+
+// embeddedShaderFiles are the built-in sources, addressed by the file name they
+// would have on disk. Files in the shader directory override these.
+var embeddedShaderFiles = map[string]string{
+	"default.vert":        vertexShaderSource,
+	"default.frag":        fragmentShaderSource,
+	"water.vert":          waterVertexShaderSource,
+	"water.frag":          waterFragmentShaderSource,
+	"skybox.vert":         skyboxVertexShaderSource,
+	"skybox.frag":         skyboxFragmentShaderSource,
+	"skybox_solid.frag":   solidColorSkyboxFragmentShaderSource,
+	"procedural_sky.frag": proceduralSkyFragmentShaderSource,
+	"shadow_volume.vert":  shadowVolumeVertexShaderSource,
+	"shadow_volume.frag":  shadowVolumeFragmentShaderSource,
+	"fullscreen.vert":     fxaaVertexShaderSource,
+	"fxaa.frag":           fxaaFragmentShaderSource,
+	"bloom.frag":          bloomFragmentShaderSource,
+	"passthrough.frag":    passthroughFragmentShaderSource,
+	"fog.glsl":            fogShaderSource,
+	"color_space.glsl":    colorSpaceShaderSource,
+	"mirror.vert":         mirrorVertexShaderSource,
+	"mirror.frag":         mirrorFragmentShaderSource,
+	"debug_view.vert":     debugViewVertexShaderSource,
+	"debug_view.frag":     debugViewFragmentShaderSource,
+	"debug_lines.vert":    debugLinesVertexShaderSource,
+	"debug_lines.frag":    debugLinesFragmentShaderSource,
+	"text.vert":           textVertexShaderSource,
+	"text.frag":           textFragmentShaderSource,
+	"ui.vert":             uiVertexShaderSource,
+	"ui.frag":             uiFragmentShaderSource,
+	"gbuffer.frag":        gBufferFragmentShaderSource,
+	"lighting.glsl":       deferredLightingShaderSource,
+	"deferred_light.frag": deferredLightFragmentShaderSource,
+	"light_resolve.frag":  deferredResolveFragmentShaderSource,
+	"ltc.glsl":            ltcShaderSource,
+	"ies.glsl":            iesShaderSource,
+	"hiz.frag":            hiZFragmentShaderSource,
+	"ssr_trace.frag":      ssrTraceFragmentShaderSource,
+	"ssr_composite.frag":  ssrCompositeFragmentShaderSource,
+	"velocity.vert":       velocityVertexShaderSource,
+	"velocity.frag":       velocityFragmentShaderSource,
+	"depth_of_field.frag": depthOfFieldFragmentShaderSource,
+	"motion_blur.frag":    motionBlurFragmentShaderSource,
+}
+
+const shaderPollInterval = 500 * time.Millisecond
+
+// OnShaderReload is called after a shader is rebuilt because one of its files
+// changed (err == nil), and whenever a shader fails to build.
+var OnShaderReload func(shaderName string, err error)
+
+// ShaderError is returned when a shader fails to preprocess, compile or link.
+// Log lines point at the original file and line, not the expanded source.
+type ShaderError struct {
+	Shader string // Shader name
+	Stage  string // "vertex", "fragment", "link" or "preprocess"
+	Log    string
+}
+
+func (e *ShaderError) Error() string {
+	return fmt.Sprintf("shader %s: %s failed:\n%s", e.Shader, e.Stage, e.Log)
+}
+
+// shaderSourceLine records where a line of expanded source came from
+type shaderSourceLine struct {
+	file string
+	line int
+}
+
+// preprocessedShader is a single stage after #include expansion
+type preprocessedShader struct {
+	source   string // NUL-terminated, ready for gl.ShaderSource
+	lines    []shaderSourceLine
+	files    []string // Every file that contributed, in include order
+	fromDisk bool     // At least one file came from the shader directory
+}
+
+type shaderLibrary struct {
+	directory    string
+	hotReload    bool
+	version      uint64            // Bumped whenever any source changes
+	reloadAll    uint64            // Shaders built before this version rebuild regardless of files
+	fileVersions map[string]uint64 // Version at which each file last changed
+	modTimes     map[string]time.Time
+	lastPoll     time.Time
+	programs     map[programKey]cachedProgram // Latest linked program of each shader variant
+}
+
+// programKey identifies a shader variant across rebuilds: its stage files, or
+// hashes of in-memory stage sources, and its features
+type programKey struct {
+	vertex   string
+	fragment string
+	features ShaderFeatures
+}
+
+// cachedProgram is a linked program and the expanded sources it was built from
+type cachedProgram struct {
+	program uint32
+	sum     [sha256.Size]byte
+}
+
+// shaderStageKey names a stage in a programKey
+func shaderStageKey(file, source string) string {
+	if file != "" {
+		return file
+	}
+	h := fnv.New64a()
+	h.Write([]byte(source))
+	return fmt.Sprintf("source:%016x", h.Sum64())
+}
+
+var shaderLib = newShaderLibrary()
+
+func newShaderLibrary() *shaderLibrary {
+	return &shaderLibrary{
+		fileVersions: make(map[string]uint64),
+		modTimes:     make(map[string]time.Time),
+		programs:     make(map[programKey]cachedProgram),
+	}
+}
+
+// SetShaderDirectory loads shader files from dir, falling back to the embedded
+// sources for any file that isn't there. An empty dir disables file loading.
+func SetShaderDirectory(dir string) {
+	shaderLib.directory = dir
+	shaderLib.modTimes = shaderLib.scan()
+	shaderLib.lastPoll = time.Now()
+	ReloadShaders()
+	logger.Log.Info("Shader directory set", zap.String("dir", dir), zap.Int("files", len(shaderLib.modTimes)))
+}
+
+// GetShaderDirectory returns the directory shaders are loaded from ("" when embedded only)
+func GetShaderDirectory() string {
+	return shaderLib.directory
+}
+
+// SetShaderHotReload enables watching the shader directory for changes
+func SetShaderHotReload(enabled bool) {
+	shaderLib.hotReload = enabled
+}
+
+// IsShaderHotReloadEnabled returns true if shader files are being watched
+func IsShaderHotReloadEnabled() bool {
+	return shaderLib.hotReload
+}
+
+// ReloadShaders rebuilds every shader from its current sources on next use
+func ReloadShaders() {
+	shaderLib.version++
+	shaderLib.reloadAll = shaderLib.version
+}
+
+// EmbeddedShaderFiles returns the names of the built-in shader files
+func EmbeddedShaderFiles() []string {
+	names := make([]string, 0, len(embeddedShaderFiles))
+	for name := range embeddedShaderFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExportEmbeddedShaders writes the built-in sources into dir as a starting point
+// for editing. Existing files are left untouched. Returns the number written.
+func ExportEmbeddedShaders(dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, name := range EmbeddedShaderFiles() {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		source := strings.TrimLeft(strings.TrimRight(embeddedShaderFiles[name], "\x00"), "\n")
+		if err := os.WriteFile(target, []byte(source), 0644); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// pollShaderChanges checks the shader directory for edits at most every
+// shaderPollInterval. Shaders using a changed file rebuild on their next use.
+func pollShaderChanges() {
+	if !shaderLib.hotReload || shaderLib.directory == "" {
+		return
+	}
+	now := time.Now()
+	if now.Sub(shaderLib.lastPoll) < shaderPollInterval {
+		return
+	}
+	shaderLib.lastPoll = now
+
+	if changed := shaderLib.poll(); len(changed) > 0 {
+		logger.Log.Info("Shader files changed", zap.Strings("files", changed))
+	}
+}
+
+// poll rescans the directory and bumps the version of every added, modified or removed file
+func (lib *shaderLibrary) poll() []string {
+	current := lib.scan()
+	var changed []string
+	for name, modTime := range current {
+		if previous, ok := lib.modTimes[name]; !ok || !previous.Equal(modTime) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range lib.modTimes {
+		if _, ok := current[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	lib.modTimes = current
+
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		lib.version++
+		for _, name := range changed {
+			lib.fileVersions[name] = lib.version
+		}
+	}
+	return changed
+}
+
+// scan returns the modification time of every file under the shader directory
+func (lib *shaderLibrary) scan() map[string]time.Time {
+	files := make(map[string]time.Time)
+	if lib.directory == "" {
+		return files
+	}
+	filepath.WalkDir(lib.directory, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if rel, err := filepath.Rel(lib.directory, p); err == nil {
+			files[filepath.ToSlash(rel)] = info.ModTime()
+		}
+		return nil
+	})
+	return files
+}
+
+// isStale reports whether a shader built at builtVersion from files needs rebuilding
+func (lib *shaderLibrary) isStale(builtVersion uint64, files []string) bool {
+	if builtVersion == lib.version {
+		return false
+	}
+	if lib.reloadAll > builtVersion {
+		return true
+	}
+	for _, name := range files {
+		if lib.fileVersions[name] > builtVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// load returns the source for a file name, preferring the shader directory
+func (lib *shaderLibrary) load(name string, useDisk bool) (string, bool, error) {
+	if useDisk && lib.directory != "" {
+		if data, err := os.ReadFile(filepath.Join(lib.directory, filepath.FromSlash(name))); err == nil {
+			return string(data), true, nil
+		}
+	}
+	if source, ok := embeddedShaderFiles[name]; ok {
+		return source, false, nil
+	}
+	return "", false, fmt.Errorf("shader file %q not found", name)
+}
+
+// preprocessFile loads a shader file, expands its includes and inserts defines after #version
+func (lib *shaderLibrary) preprocessFile(name string, useDisk bool, defines []string) (*preprocessedShader, error) {
+	result := &preprocessedShader{}
+	source, fromDisk, err := lib.load(name, useDisk)
+	if err != nil {
+		result.files = append(result.files, name)
+		return result, err
+	}
+	result.fromDisk = fromDisk
+	return lib.expand(result, name, source, useDisk, defines)
+}
+
+// preprocessSource is preprocessFile for an in-memory source labelled name
+func (lib *shaderLibrary) preprocessSource(name, source string, useDisk bool, defines []string) (*preprocessedShader, error) {
+	return lib.expand(&preprocessedShader{}, name, source, useDisk, defines)
+}
+
+func (lib *shaderLibrary) expand(result *preprocessedShader, name, source string, useDisk bool, defines []string) (*preprocessedShader, error) {
+	p := &shaderPreprocessor{lib: lib, useDisk: useDisk, result: result, included: make(map[string]bool), defines: defines}
+	if !strings.Contains(source, "#version") {
+		p.emitDefines()
+	}
+	err := p.process(name, source, 0)
+	result.source = p.out.String() + "\x00"
+	return result, err
+}
+
+type shaderPreprocessor struct {
+	lib      *shaderLibrary
+	useDisk  bool
+	result   *preprocessedShader
+	included map[string]bool
+	defines  []string // Emitted right after the top-level #version
+	out      strings.Builder
+}
+
+// process copies source to the output, splicing #include "file" directives in place.
+// Each file is included at most once per stage, which also breaks include cycles.
+func (p *shaderPreprocessor) process(name, source string, depth int) error {
+	p.included[name] = true
+	p.result.files = append(p.result.files, name)
+
+	lines := strings.Split(strings.TrimRight(source, "\x00"), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "#include") {
+			target, err := parseIncludeDirective(trimmed)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %v", name, i+1, err)
+			}
+			if err := p.include(name, target, depth); err != nil {
+				return fmt.Errorf("%s:%d: %v", name, i+1, err)
+			}
+			continue
+		}
+
+		// Only the top-level file may declare the version
+		if depth > 0 && strings.HasPrefix(trimmed, "#version") {
+			continue
+		}
+
+		p.emit(line, name, i+1)
+		if depth == 0 && strings.HasPrefix(trimmed, "#version") {
+			p.emitDefines()
+		}
+	}
+	return nil
+}
+
+func (p *shaderPreprocessor) emit(line, file string, lineNumber int) {
+	p.out.WriteString(line)
+	p.out.WriteByte('\n')
+	p.result.lines = append(p.result.lines, shaderSourceLine{file: file, line: lineNumber})
+}
+
+func (p *shaderPreprocessor) emitDefines() {
+	for i, define := range p.defines {
+		p.emit(define, "<defines>", i+1)
+	}
+	p.defines = nil
+}
+
+func (p *shaderPreprocessor) include(from, target string, depth int) error {
+	// Resolve relative to the including file first, then from the library root
+	candidates := []string{path.Join(path.Dir(from), target), path.Clean(target)}
+	for _, candidate := range candidates {
+		if p.included[candidate] {
+			return nil
+		}
+		source, fromDisk, err := p.lib.load(candidate, p.useDisk)
+		if err != nil {
+			continue
+		}
+		if fromDisk {
+			p.result.fromDisk = true
+		}
+		return p.process(candidate, source, depth+1)
+	}
+	// Watch the missing file so creating it triggers a rebuild
+	p.result.files = append(p.result.files, path.Clean(target))
+	return fmt.Errorf("cannot include %q: file not found", target)
+}
+
+// parseIncludeDirective extracts the file name from #include "file" or #include <file>
+func parseIncludeDirective(line string) (string, error) {
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "#include"))
+	if len(rest) >= 2 {
+		first, last := rest[0], rest[len(rest)-1]
+		if (first == '"' && last == '"') || (first == '<' && last == '>') {
+			if target := strings.TrimSpace(rest[1 : len(rest)-1]); target != "" {
+				return target, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("malformed #include, expected #include \"file\"")
+}
+
+// shaderLogLocation matches the "0:12(5):" (Mesa), "0(12) :" (NVIDIA) and
+// "ERROR: 0:12:" (AMD/Apple) location prefixes of GLSL compiler logs
+var shaderLogLocation = regexp.MustCompile(`^(\s*(?:ERROR:|WARNING:)?\s*)(\d+)(?::(\d+)|\((\d+)\))`)
+
+// mapShaderLog rewrites driver line numbers in a compiler log to file:line of the original sources
+func mapShaderLog(log string, lines []shaderSourceLine) string {
+	log = strings.TrimRight(strings.ReplaceAll(log, "\x00", ""), "\n\r\t ")
+	logLines := strings.Split(log, "\n")
+	for i, logLine := range logLines {
+		match := shaderLogLocation.FindStringSubmatchIndex(logLine)
+		if match == nil {
+			continue
+		}
+		lineText := ""
+		if match[6] >= 0 {
+			lineText = logLine[match[6]:match[7]]
+		} else if match[8] >= 0 {
+			lineText = logLine[match[8]:match[9]]
+		}
+		n, err := strconv.Atoi(lineText)
+		if err != nil || n < 1 || n > len(lines) {
+			continue
+		}
+		origin := lines[n-1]
+		prefix := logLine[match[2]:match[3]]
+		logLines[i] = fmt.Sprintf("%s%s:%d%s", prefix, origin.file, origin.line, logLine[match[1]:])
+	}
+	return strings.Join(logLines, "\n")
+}
+
+// buildProgram compiles and links two preprocessed stages. Shader copies share
+// the program of their variant until its sources change; a successful rebuild
+// then deletes the old program, and a failed one leaves it cached.
+func (lib *shaderLibrary) buildProgram(name string, key programKey, vertex, fragment *preprocessedShader) (uint32, error) {
+	sum := sha256.Sum256([]byte(vertex.source + fragment.source))
+	cached, exists := lib.programs[key]
+	if exists && cached.sum == sum {
+		return cached.program, nil
+	}
+
+	vertexShader, err := compileShaderStage(name, "vertex", vertex, gl.VERTEX_SHADER)
+	if err != nil {
+		return 0, err
+	}
+	fragmentShader, err := compileShaderStage(name, "fragment", fragment, gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertexShader)
+		return 0, err
+	}
+
+	program := gl.CreateProgram()
+	gl.AttachShader(program, vertexShader)
+	gl.AttachShader(program, fragmentShader)
+	gl.LinkProgram(program)
+	gl.DetachShader(program, vertexShader)
+	gl.DeleteShader(vertexShader)
+	gl.DetachShader(program, fragmentShader)
+	gl.DeleteShader(fragmentShader)
+
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program)
+		return 0, &ShaderError{Shader: name, Stage: "link", Log: strings.TrimRight(log, "\x00\n")}
+	}
+
+	if exists {
+		gl.DeleteProgram(cached.program)
+	}
+	lib.programs[key] = cachedProgram{program: program, sum: sum}
+	return program, nil
+}
+
+func compileShaderStage(name, stage string, source *preprocessedShader, shaderType uint32) (uint32, error) {
+	shader := gl.CreateShader(shaderType)
+	cSources, free := gl.Strs(source.source)
+	gl.ShaderSource(shader, 1, cSources, nil)
+	free()
+	gl.CompileShader(shader)
+
+	var status int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
+		return 0, &ShaderError{Shader: name, Stage: stage, Log: mapShaderLog(log, source.lines)}
+	}
+	return shader, nil
+}
+
+// reportShaderResult logs a build result and forwards it to OnShaderReload
+func reportShaderResult(name string, err error) {
+	if err != nil {
+		logger.Log.Error("Shader build failed", zap.String("shader", name), zap.Error(err))
+	} else {
+		logger.Log.Info("Shader reloaded", zap.String("shader", name))
+	}
+	if OnShaderReload != nil {
+		OnShaderReload(name, err)
+	}
+}
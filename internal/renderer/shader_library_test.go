@@ -0,0 +1,232 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestShaderLibrary(t *testing.T, files map[string]string) *shaderLibrary {
+	t.Helper()
+	lib := newShaderLibrary()
+	lib.directory = t.TempDir()
+	for name, source := range files {
+		target := filepath.Join(lib.directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lib.modTimes = lib.scan()
+	return lib
+}
+
+func TestShaderIncludeExpansion(t *testing.T) {
+	lib := newTestShaderLibrary(t, map[string]string{
+		"main.frag":        "#version 330 core\n#include \"lib/common.glsl\"\nvoid main() {}\n",
+		"lib/common.glsl":  "#version 330 core\n#include \"helpers.glsl\"\nfloat common() { return 1.0; }\n",
+		"lib/helpers.glsl": "float helper() { return 2.0; }\n",
+	})
+
+	result, err := lib.preprocessFile("main.frag", true, nil)
+	if err != nil {
+		t.Fatalf("Unexpected preprocess error: %v", err)
+	}
+	if !result.fromDisk {
+		t.Error("Expected sources to be read from the shader directory")
+	}
+	if strings.Count(result.source, "#version") != 1 {
+		t.Errorf("Expected only the top-level #version, got:\n%s", result.source)
+	}
+	if strings.Contains(result.source, "#include") {
+		t.Errorf("Expected includes to be expanded, got:\n%s", result.source)
+	}
+	if !strings.HasSuffix(result.source, "\x00") {
+		t.Error("Expected NUL-terminated source")
+	}
+
+	// Relative include resolves next to the including file
+	want := []string{"main.frag", "lib/common.glsl", "lib/helpers.glsl"}
+	if strings.Join(result.files, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected files %v, got %v", want, result.files)
+	}
+
+	// helper() is line 1 of helpers.glsl and the third expanded line
+	if got := result.lines[1]; got.file != "lib/helpers.glsl" || got.line != 1 {
+		t.Errorf("Expected line 2 to map to lib/helpers.glsl:1, got %s:%d", got.file, got.line)
+	}
+}
+
+func TestShaderIncludeOnce(t *testing.T) {
+	lib := newTestShaderLibrary(t, map[string]string{
+		"a.glsl": "#include \"b.glsl\"\nfloat a;\n",
+		"b.glsl": "#include \"a.glsl\"\nfloat b;\n",
+	})
+
+	result, err := lib.preprocessSource("test.frag", "#include \"a.glsl\"\n#include \"b.glsl\"\n", true, nil)
+	if err != nil {
+		t.Fatalf("Include cycle should be broken, got error: %v", err)
+	}
+	if strings.Count(result.source, "float a;") != 1 || strings.Count(result.source, "float b;") != 1 {
+		t.Errorf("Expected each file exactly once, got:\n%s", result.source)
+	}
+}
+
+func TestShaderIncludeErrors(t *testing.T) {
+	lib := newTestShaderLibrary(t, nil)
+
+	result, err := lib.preprocessSource("test.frag", "void f();\n#include \"missing.glsl\"\n", true, nil)
+	if err == nil || !strings.Contains(err.Error(), "test.frag:2") {
+		t.Errorf("Expected missing include error at test.frag:2, got %v", err)
+	}
+	found := false
+	for _, f := range result.files {
+		found = found || f == "missing.glsl"
+	}
+	if !found {
+		t.Error("Missing include should still be tracked so creating it triggers a rebuild")
+	}
+
+	if _, err := lib.preprocessSource("test.frag", "#include missing.glsl\n", true, nil); err == nil {
+		t.Error("Expected error for malformed #include")
+	}
+}
+
+func TestShaderDirectoryOverridesEmbedded(t *testing.T) {
+	lib := newTestShaderLibrary(t, map[string]string{
+		"fog.glsl": "// overridden fog\n",
+	})
+
+	source, fromDisk, err := lib.load("fog.glsl", true)
+	if err != nil || !fromDisk || source != "// overridden fog\n" {
+		t.Errorf("Expected fog.glsl from disk, got fromDisk=%v err=%v", fromDisk, err)
+	}
+
+	source, fromDisk, err = lib.load("fog.glsl", false)
+	if err != nil || fromDisk || source != fogShaderSource {
+		t.Errorf("Expected embedded fog.glsl when disk is disabled, got fromDisk=%v err=%v", fromDisk, err)
+	}
+
+	// Files missing on disk fall back to the embedded copy
+	if _, fromDisk, err := lib.load("default.frag", true); err != nil || fromDisk {
+		t.Errorf("Expected embedded default.frag, got fromDisk=%v err=%v", fromDisk, err)
+	}
+}
+
+func TestEmbeddedShadersPreprocess(t *testing.T) {
+	lib := newShaderLibrary()
+	for _, name := range EmbeddedShaderFiles() {
+		result, err := lib.preprocessFile(name, false, nil)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if strings.Contains(result.source, "#include") {
+			t.Errorf("%s: unexpanded #include", name)
+		}
+	}
+}
+
+func TestMapShaderLog(t *testing.T) {
+	lines := []shaderSourceLine{
+		{file: "default.frag", line: 1},
+		{file: "fog.glsl", line: 7},
+		{file: "default.frag", line: 3},
+	}
+
+	tests := []struct {
+		log  string
+		want string
+	}{
+		{"0:2(5): error: syntax error", "fog.glsl:7(5): error: syntax error"},
+		{"0(3) : error C0000: syntax error", "default.frag:3 : error C0000: syntax error"},
+		{"ERROR: 0:2: 'x' : undeclared identifier", "ERROR: fog.glsl:7: 'x' : undeclared identifier"},
+		{"0:99(1): error: out of range", "0:99(1): error: out of range"},
+		{"link error: no main", "link error: no main"},
+	}
+	for _, tt := range tests {
+		if got := mapShaderLog(tt.log+"\x00", lines); got != tt.want {
+			t.Errorf("mapShaderLog(%q) = %q, want %q", tt.log, got, tt.want)
+		}
+	}
+}
+
+func TestShaderLibraryPollDetectsChanges(t *testing.T) {
+	lib := newTestShaderLibrary(t, map[string]string{
+		"water.frag": "// v1\n",
+	})
+
+	built := lib.version
+	files := []string{"water.frag", "fog.glsl"}
+	if lib.isStale(built, files) {
+		t.Error("Shader should not be stale before any change")
+	}
+
+	// Push the mod time forward so coarse filesystem clocks still see a change
+	target := filepath.Join(lib.directory, "water.frag")
+	os.WriteFile(target, []byte("// v2\n"), 0644)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(target, future, future)
+
+	changed := lib.poll()
+	if len(changed) != 1 || changed[0] != "water.frag" {
+		t.Errorf("Expected water.frag to change, got %v", changed)
+	}
+	if !lib.isStale(built, files) {
+		t.Error("Shader using water.frag should be stale")
+	}
+	if lib.isStale(built, []string{"default.frag"}) {
+		t.Error("Shader not using water.frag should not be stale")
+	}
+
+	// Creating an override for an embedded file counts as a change
+	os.WriteFile(filepath.Join(lib.directory, "fog.glsl"), []byte("// fog\n"), 0644)
+	if changed := lib.poll(); len(changed) != 1 || changed[0] != "fog.glsl" {
+		t.Errorf("Expected fog.glsl to be picked up, got %v", changed)
+	}
+}
+
+func TestExportEmbeddedShaders(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "fog.glsl"), []byte("// keep me\n"), 0644)
+
+	written, err := ExportEmbeddedShaders(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != len(embeddedShaderFiles)-1 {
+		t.Errorf("Expected %d files written, got %d", len(embeddedShaderFiles)-1, written)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "fog.glsl")); string(data) != "// keep me\n" {
+		t.Error("Existing files must not be overwritten")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "default.frag")); !strings.Contains(string(data), "#include \"fog.glsl\"") {
+		t.Error("Exported default.frag should include fog.glsl")
+	}
+}
+
+func TestShaderProgramKey(t *testing.T) {
+	a := NewShaderFromFiles("a", "default.vert", "default.frag")
+	b := NewShaderFromFiles("b", "default.vert", "default.frag")
+	if a.programKey() != b.programKey() {
+		t.Error("copies of a shader should share a program")
+	}
+	b.features = FeatureClearcoat
+	if a.programKey() == b.programKey() {
+		t.Error("feature variants need their own program")
+	}
+
+	inline := Shader{Name: "inline", vertexSource: "void main() {}", fragmentSource: "void main() {}"}
+	other := inline
+	other.fragmentSource = "void main() { }"
+	if inline.programKey() == other.programKey() {
+		t.Error("in-memory sources should be told apart by content")
+	}
+	if key := inline.programKey(); len(key.vertex) > 32 {
+		t.Errorf("in-memory sources should be keyed by a hash, got %q", key.vertex)
+	}
+}
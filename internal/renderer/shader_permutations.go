@@ -0,0 +1,213 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ShaderFeatures selects a compile-time variant of a shader. Each set bit becomes
+// a #define, so disabled features cost nothing per fragment instead of being
+// skipped by a uniform bool branch.
+type ShaderFeatures uint32
+
+const (
+	FeatureClearcoat ShaderFeatures = 1 << iota
+	FeatureSheen
+	FeatureTransmission
+	FeatureMultipleScattering
+	FeatureEnergyConservation
+	FeatureSSAO
+	FeatureVolumetricLighting
+	FeatureGlobalIllumination
+	FeaturePerlinNoise
+	FeatureBlockTextures // Voxel faces sample the block texture array
+	FeatureImageBasedLighting
+	FeatureBloom
+	FeatureShadows
+	FeatureHighQualityFiltering
+)
+
+// shaderFeatureDefines lists every feature bit with its preprocessor symbol
+var shaderFeatureDefines = []struct {
+	feature ShaderFeatures
+	define  string
+}{
+	{FeatureClearcoat, "FEATURE_CLEARCOAT"},
+	{FeatureSheen, "FEATURE_SHEEN"},
+	{FeatureTransmission, "FEATURE_TRANSMISSION"},
+	{FeatureMultipleScattering, "FEATURE_MULTIPLE_SCATTERING"},
+	{FeatureEnergyConservation, "FEATURE_ENERGY_CONSERVATION"},
+	{FeatureSSAO, "FEATURE_SSAO"},
+	{FeatureVolumetricLighting, "FEATURE_VOLUMETRIC_LIGHTING"},
+	{FeatureGlobalIllumination, "FEATURE_GLOBAL_ILLUMINATION"},
+	{FeaturePerlinNoise, "FEATURE_PERLIN_NOISE"},
+	{FeatureBlockTextures, "FEATURE_BLOCK_TEXTURES"},
+	{FeatureImageBasedLighting, "FEATURE_IMAGE_BASED_LIGHTING"},
+	{FeatureBloom, "FEATURE_BLOOM"},
+	{FeatureShadows, "FEATURE_SHADOWS"},
+	{FeatureHighQualityFiltering, "FEATURE_HIGH_QUALITY_FILTERING"},
+}
+
+// Has returns true if every bit of feature is set
+func (f ShaderFeatures) Has(feature ShaderFeatures) bool {
+	return f&feature == feature
+}
+
+// With returns f with feature set or cleared
+func (f ShaderFeatures) With(feature ShaderFeatures, enabled bool) ShaderFeatures {
+	if enabled {
+		return f | feature
+	}
+	return f &^ feature
+}
+
+// Defines returns the #define lines for the set features
+func (f ShaderFeatures) Defines() []string {
+	var defines []string
+	for _, d := range shaderFeatureDefines {
+		if f.Has(d.feature) {
+			defines = append(defines, "#define "+d.define)
+		}
+	}
+	return defines
+}
+
+func (f ShaderFeatures) String() string {
+	var names []string
+	for _, d := range shaderFeatureDefines {
+		if f.Has(d.feature) {
+			names = append(names, strings.TrimPrefix(d.define, "FEATURE_"))
+		}
+	}
+	if len(names) == 0 {
+		return "NONE"
+	}
+	return strings.Join(names, "|")
+}
+
+// ShaderFeatures returns the shader variant matching the config's toggles
+func (c AdvancedRenderingConfig) ShaderFeatures() ShaderFeatures {
+	var f ShaderFeatures
+	f = f.With(FeatureClearcoat, c.EnableClearcoat)
+	f = f.With(FeatureSheen, c.EnableSheen)
+	f = f.With(FeatureTransmission, c.EnableTransmission)
+	f = f.With(FeatureMultipleScattering, c.EnableMultipleScattering)
+	f = f.With(FeatureEnergyConservation, c.EnableEnergyConservation)
+	f = f.With(FeatureSSAO, c.EnableSSAO)
+	f = f.With(FeatureVolumetricLighting, c.EnableVolumetricLighting)
+	f = f.With(FeatureGlobalIllumination, c.EnableGlobalIllumination)
+	f = f.With(FeaturePerlinNoise, c.EnablePerlinNoise)
+	f = f.With(FeatureImageBasedLighting, c.EnableImageBasedLighting)
+	f = f.With(FeatureBloom, c.EnableBloom)
+	f = f.With(FeatureShadows, c.EnableAdvancedShadows)
+	f = f.With(FeatureHighQualityFiltering, c.EnableHighQualityFiltering)
+	return f
+}
+
+// SetShaderFeatures sets the config toggles from a feature bitset
+func (c *AdvancedRenderingConfig) SetShaderFeatures(f ShaderFeatures) {
+	c.EnableClearcoat = f.Has(FeatureClearcoat)
+	c.EnableSheen = f.Has(FeatureSheen)
+	c.EnableTransmission = f.Has(FeatureTransmission)
+	c.EnableMultipleScattering = f.Has(FeatureMultipleScattering)
+	c.EnableEnergyConservation = f.Has(FeatureEnergyConservation)
+	c.EnableSSAO = f.Has(FeatureSSAO)
+	c.EnableVolumetricLighting = f.Has(FeatureVolumetricLighting)
+	c.EnableGlobalIllumination = f.Has(FeatureGlobalIllumination)
+	c.EnablePerlinNoise = f.Has(FeaturePerlinNoise)
+	c.EnableImageBasedLighting = f.Has(FeatureImageBasedLighting)
+	c.EnableBloom = f.Has(FeatureBloom)
+	c.EnableAdvancedShadows = f.Has(FeatureShadows)
+	c.EnableHighQualityFiltering = f.Has(FeatureHighQualityFiltering)
+}
+
+// shaderVariantKey identifies a base shader specialised for a feature set
+type shaderVariantKey struct {
+	vertex   string
+	fragment string
+	features ShaderFeatures
+}
+
+// withFeatures returns an uncompiled copy of the shader specialised for features
+func (shader *Shader) withFeatures(features ShaderFeatures) *Shader {
+	return &Shader{
+		vertexSource:   shader.vertexSource,
+		fragmentSource: shader.fragmentSource,
+		vertexFile:     shader.vertexFile,
+		fragmentFile:   shader.fragmentFile,
+		Name:           shader.Name + "[" + features.String() + "]",
+		skyColor:       shader.skyColor,
+		features:       features,
+	}
+}
+
+// shaderVariant returns the cached variant of base for features, creating it on
+// first request. It is compiled lazily on first use unless prewarmed.
+func (rend *OpenGLRenderer) shaderVariant(base *Shader, features ShaderFeatures) *Shader {
+	if features == base.features {
+		return base
+	}
+	key := shaderVariantKey{vertex: base.vertexFile, fragment: base.fragmentFile, features: features}
+	if key.vertex == "" && key.fragment == "" {
+		// In-memory shaders are identified by their sources
+		key.vertex, key.fragment = base.vertexSource, base.fragmentSource
+	}
+	if variant, ok := rend.shaderVariants[key]; ok {
+		return variant
+	}
+	variant := base.withFeatures(features)
+	rend.shaderVariants[key] = variant
+	return variant
+}
+
+// modelShader picks the shader variant used to draw a model
+func (rend *OpenGLRenderer) modelShader(model *Model) *Shader {
+	base := &rend.defaultShader
+	if model.Shader.IsValid() {
+		base = &model.Shader
+	}
+	if model.CustomMaterial != nil {
+		if materialShader := model.CustomMaterial.shaderProgram(); materialShader != nil {
+			base = materialShader
+		}
+	}
+	features := model.ShaderFeatures
+	if model.BlockTextureArray != 0 && DebugView != DebugViewLighting {
+		// Follows the model's data rather than the rendering config
+		features |= FeatureBlockTextures
+	}
+	return rend.shaderVariant(base, features)
+}
+
+// PrewarmShaderVariants compiles the shader variant of every model currently in
+// the scene so toggling between them later doesn't hitch. Returns how many were built.
+func (rend *OpenGLRenderer) PrewarmShaderVariants() int {
+	start := time.Now()
+	built := 0
+	for _, model := range rend.Models {
+		shader := rend.modelShader(model)
+		if !shader.isCompiled {
+			shader.Compile()
+			built++
+		}
+	}
+	if built > 0 {
+		logger.Log.Info("Prewarmed shader variants", zap.Int("count", built), zap.Duration("took", time.Since(start)))
+	}
+	return built
+}
+
+// PrewarmShaderFeatures compiles default shader variants for the given feature sets
+func (rend *OpenGLRenderer) PrewarmShaderFeatures(featureSets ...ShaderFeatures) {
+	for _, features := range featureSets {
+		rend.shaderVariant(&rend.defaultShader, features).Compile()
+	}
+}
+
+// ShaderVariantCount returns how many shader variants have been requested
+func (rend *OpenGLRenderer) ShaderVariantCount() int {
+	return len(rend.shaderVariants)
+}
@@ -0,0 +1,110 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShaderFeaturesDefines(t *testing.T) {
+	f := FeatureClearcoat | FeatureSSAO
+	defines := f.Defines()
+	if len(defines) != 2 || defines[0] != "#define FEATURE_CLEARCOAT" || defines[1] != "#define FEATURE_SSAO" {
+		t.Errorf("Unexpected defines: %v", defines)
+	}
+	if f.String() != "CLEARCOAT|SSAO" {
+		t.Errorf("Expected CLEARCOAT|SSAO, got %s", f.String())
+	}
+	if ShaderFeatures(0).String() != "NONE" || len(ShaderFeatures(0).Defines()) != 0 {
+		t.Error("Empty feature set should have no defines")
+	}
+	if f.With(FeatureSSAO, false) != FeatureClearcoat {
+		t.Error("With(false) should clear the bit")
+	}
+}
+
+func TestAdvancedConfigShaderFeaturesRoundTrip(t *testing.T) {
+	for _, config := range []AdvancedRenderingConfig{
+		DefaultAdvancedRenderingConfig(),
+		HighQualityRenderingConfig(),
+		PerformanceRenderingConfig(),
+	} {
+		features := config.ShaderFeatures()
+		var restored AdvancedRenderingConfig
+		restored.SetShaderFeatures(features)
+		if restored.ShaderFeatures() != features {
+			t.Errorf("Round trip changed features: %s -> %s", features, restored.ShaderFeatures())
+		}
+		if features.Has(FeatureSSAO) != config.EnableSSAO {
+			t.Errorf("FeatureSSAO = %v, EnableSSAO = %v", features.Has(FeatureSSAO), config.EnableSSAO)
+		}
+	}
+}
+
+func TestApplyAdvancedConfigSetsShaderFeatures(t *testing.T) {
+	model := &Model{}
+	config := HighQualityRenderingConfig()
+	ApplyAdvancedRenderingConfig(model, config)
+	if model.ShaderFeatures != config.ShaderFeatures() {
+		t.Errorf("Expected features %s, got %s", config.ShaderFeatures(), model.ShaderFeatures)
+	}
+	for _, name := range []string{"enableSSAO", "enableAdvancedLighting", "enableImageBasedLighting",
+		"enableBloom", "enableShadows", "enableHighQualityFiltering"} {
+		if _, ok := model.CustomUniforms[name]; ok {
+			t.Errorf("Feature toggle %s should no longer be uploaded as a uniform", name)
+		}
+	}
+	if !model.ShaderFeatures.Has(FeatureImageBasedLighting) {
+		t.Errorf("High quality should enable IBL, got %s", model.ShaderFeatures)
+	}
+	if model.ShaderFeatures.Has(FeatureBloom) != config.EnableBloom ||
+		model.ShaderFeatures.Has(FeatureShadows) != config.EnableAdvancedShadows ||
+		model.ShaderFeatures.Has(FeatureHighQualityFiltering) != config.EnableHighQualityFiltering {
+		t.Errorf("Bloom, shadow and filtering toggles should select the variant, got %s", model.ShaderFeatures)
+	}
+}
+
+func TestShaderDefinesInjectedAfterVersion(t *testing.T) {
+	lib := newTestShaderLibrary(t, map[string]string{
+		"main.frag": "#version 330 core\nvoid main() {}\n",
+	})
+	defines := (FeatureSheen | FeaturePerlinNoise).Defines()
+
+	result, err := lib.preprocessFile("main.frag", true, defines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "#version 330 core\n#define FEATURE_SHEEN\n#define FEATURE_PERLIN_NOISE\nvoid main() {}\n"
+	if !strings.HasPrefix(result.source, want) {
+		t.Errorf("Unexpected source:\n%s", result.source)
+	}
+	// Compiler errors on file lines still map back to the file
+	if got := result.lines[3]; got.file != "main.frag" || got.line != 2 {
+		t.Errorf("Expected line 4 to map to main.frag:2, got %s:%d", got.file, got.line)
+	}
+
+	// Sources without #version get the defines first
+	result, err = lib.preprocessSource("snippet", "float x;\n", true, defines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.source, "#define FEATURE_SHEEN\n") {
+		t.Errorf("Expected defines at the start, got:\n%s", result.source)
+	}
+}
+
+func TestEmbeddedShadersPreprocessWithAllFeatures(t *testing.T) {
+	lib := newShaderLibrary()
+	var all ShaderFeatures
+	for _, d := range shaderFeatureDefines {
+		all |= d.feature
+	}
+	result, err := lib.preprocessFile("default.frag", false, all.Defines())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range shaderFeatureDefines {
+		if !strings.Contains(result.source, "#ifdef "+d.define) && !strings.Contains(result.source, "#ifndef "+d.define) {
+			t.Errorf("default.frag never tests %s", d.define)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package renderer
 
 import (
+	"Gopher3D/internal/logger"
+	"fmt"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"go.uber.org/zap"
 )
 
 // =============================================================
@@ -13,35 +17,119 @@ import (
 type Shader struct {
 	vertexSource   string
 	fragmentSource string
+	vertexFile     string // Shader library file names; when set they take
+	fragmentFile   string // precedence over the in-memory sources above
 	program        uint32
 	Name           string `default:"default"`
 	isCompiled     bool
-	skyColor       mgl32.Vec3    // For solid color skybox
-	uniformCache   *UniformCache // Cache for uniform locations
+	skyColor       mgl32.Vec3     // For solid color skybox
+	uniformCache   *UniformCache  // Cache for uniform locations
+	features       ShaderFeatures // Compile-time variant, injected as #defines
+	sourceFiles    []string       // Files the current program was built from
+	libVersion     uint64         // Shader library version the program was built against
 }
 
-func (shader *Shader) Use() {
-	if !shader.isCompiled {
-		shader.Compile()
+// NewShaderFromFiles creates a shader whose stages are loaded by file name from
+// the shader directory (see SetShaderDirectory), with #include support
+func NewShaderFromFiles(name, vertexFile, fragmentFile string) Shader {
+	return Shader{
+		vertexFile:   vertexFile,
+		fragmentFile: fragmentFile,
+		Name:         name,
 	}
+}
+
+func (shader *Shader) Use() {
+	shader.refresh()
 	gl.UseProgram(shader.program)
 }
 
-// Compile compiles the shader sources into an OpenGL program
+// Compile compiles the shader sources into an OpenGL program. If files from the
+// shader directory fail to build, the embedded sources are used instead.
 func (shader *Shader) Compile() error {
 	if shader.isCompiled {
 		return nil
 	}
-
-	vertexShader := GenShader(shader.vertexSource, gl.VERTEX_SHADER)
-	fragmentShader := GenShader(shader.fragmentSource, gl.FRAGMENT_SHADER)
-	shader.program = GenShaderProgram(vertexShader, fragmentShader)
 	shader.isCompiled = true
 
-	// Initialize uniform cache for this shader
-	shader.uniformCache = NewUniformCache(shader.program)
+	fromDisk, err := shader.build(true)
+	if err != nil && fromDisk {
+		reportShaderResult(shader.displayName(), err)
+		logger.Log.Warn("Falling back to embedded shader sources", zap.String("shader", shader.displayName()))
+		_, err = shader.build(false)
+	}
+	if err != nil {
+		reportShaderResult(shader.displayName(), err)
+	}
+	return err
+}
 
-	return nil
+// refresh compiles the shader on first use and rebuilds it after its files change.
+// A failed rebuild keeps the previous program so the frame still renders.
+func (shader *Shader) refresh() {
+	if !shader.isCompiled {
+		shader.Compile()
+		return
+	}
+	if !shaderLib.isStale(shader.libVersion, shader.sourceFiles) {
+		shader.libVersion = shaderLib.version
+		return
+	}
+	_, err := shader.build(true)
+	reportShaderResult(shader.displayName(), err)
+}
+
+// build preprocesses both stages and links them, replacing the current program on success
+func (shader *Shader) build(useDisk bool) (bool, error) {
+	name := shader.displayName()
+	shader.libVersion = shaderLib.version
+
+	vertex, vertexErr := shader.preprocessStage(shader.vertexFile, shader.vertexSource, name+".vert", useDisk)
+	fragment, fragmentErr := shader.preprocessStage(shader.fragmentFile, shader.fragmentSource, name+".frag", useDisk)
+	shader.sourceFiles = append(append([]string{}, vertex.files...), fragment.files...)
+	fromDisk := vertex.fromDisk || fragment.fromDisk
+
+	for _, err := range []error{vertexErr, fragmentErr} {
+		if err != nil {
+			return fromDisk, &ShaderError{Shader: name, Stage: "preprocess", Log: err.Error()}
+		}
+	}
+
+	program, err := shaderLib.buildProgram(name, shader.programKey(), vertex, fragment)
+	if err != nil {
+		return fromDisk, err
+	}
+	shader.program = program
+	shader.uniformCache = NewUniformCache(program)
+	return fromDisk, nil
+}
+
+// programKey identifies the shader's variant in the shader library
+func (shader *Shader) programKey() programKey {
+	return programKey{
+		vertex:   shaderStageKey(shader.vertexFile, shader.vertexSource),
+		fragment: shaderStageKey(shader.fragmentFile, shader.fragmentSource),
+		features: shader.features,
+	}
+}
+
+func (shader *Shader) preprocessStage(file, source, label string, useDisk bool) (*preprocessedShader, error) {
+	defines := shader.features.Defines()
+	if file != "" {
+		return shaderLib.preprocessFile(file, useDisk, defines)
+	}
+	return shaderLib.preprocessSource(label, source, useDisk, defines)
+}
+
+// displayName identifies the shader in logs and error messages
+func (shader *Shader) displayName() string {
+	if shader.Name != "" {
+		return shader.Name
+	}
+	if shader.fragmentFile != "" {
+		return shader.fragmentFile
+	}
+	return fmt.Sprintf("program_%d", shader.program)
 }
 
 func (shader *Shader) SetVec2(name string, value mgl32.Vec2) {
@@ -65,6 +153,15 @@ func (shader *Shader) SetVec3(name string, value mgl32.Vec3) {
 	}
 }
 
+func (shader *Shader) SetVec4(name string, value mgl32.Vec4) {
+	if shader.uniformCache != nil {
+		shader.uniformCache.SetVec4(name, value.X(), value.Y(), value.Z(), value.W())
+	} else {
+		location := gl.GetUniformLocation(shader.program, gl.Str(name+"\x00"))
+		gl.Uniform4f(location, value.X(), value.Y(), value.Z(), value.W())
+	}
+}
+
 func (shader *Shader) SetFloat(name string, value float32) {
 	if shader.uniformCache != nil {
 		shader.uniformCache.SetFloat(name, value)
@@ -104,7 +201,8 @@ func (shader *Shader) SetMat4(name string, value mgl32.Mat4) {
 
 // IsValid returns true if this shader has source code (not default empty shader)
 func (shader *Shader) IsValid() bool {
-	return shader.vertexSource != "" && shader.fragmentSource != ""
+	return (shader.vertexSource != "" || shader.vertexFile != "") &&
+		(shader.fragmentSource != "" || shader.fragmentFile != "")
 }
 
 // IsCompiled returns true if this shader has been compiled
@@ -158,6 +256,10 @@ layout(location = 1) in vec2 inTexCoord; // Texture Coordinate
 layout(location = 2) in vec3 inNormal;   // Vertex normal
 layout(location = 3) in mat4 instanceModel; // Instanced model matrix (locations 3,4,5,6)
 layout(location = 7) in vec3 instanceColor; // Per-instance color (for voxels)
+#ifdef FEATURE_BLOCK_TEXTURES
+layout(location = 8) in vec3 blockLayers;  // Texture array layers for top, side and bottom faces
+layout(location = 9) in vec2 blockSurface; // Block metallic and roughness
+#endif
 
 uniform bool isInstanced; // Flag to differentiate instanced vs non-instanced rendering
 uniform mat4 model;       // Regular model matrix
@@ -167,6 +269,10 @@ out vec2 fragTexCoord;    // Pass to fragment shader
 out vec3 Normal;          // Pass normal to fragment shader
 out vec3 FragPos;         // Pass position to fragment shader
 out vec3 InstanceColor;   // Pass instance color to fragment shader
+#ifdef FEATURE_BLOCK_TEXTURES
+flat out float BlockLayer;
+flat out vec2 BlockSurface;
+#endif
 
 void main() {
     // Decide whether to use instanced or regular model matrix
@@ -188,6 +294,12 @@ void main() {
     // Pass instance color to fragment shader (default white if not instanced)
     InstanceColor = isInstanced ? instanceColor : vec3(1.0, 1.0, 1.0);
 
+#ifdef FEATURE_BLOCK_TEXTURES
+    // Pick the face's layer from the object-space normal
+    BlockLayer = inNormal.y > 0.5 ? blockLayers.x : (inNormal.y < -0.5 ? blockLayers.z : blockLayers.y);
+    BlockSurface = blockSurface;
+#endif
+
     // Final vertex position
     gl_Position = viewProjection * modelMatrix * vec4(inPosition, 1.0);
 }
@@ -214,6 +326,12 @@ uniform struct Light {
     float constantAtten;
     float linearAtten;
     float quadraticAtten;
+    int areaShape;          // 0 = none, 1 = rectangle, 2 = disk
+    vec3 areaRight;         // Half extents of the area light's surface
+    vec3 areaUp;
+    int hasIES;             // 1 when iesProfile shapes a point or spot light
+    vec3 iesRight;          // Horizontal angle 0 and 90 of the profile
+    vec3 iesUp;
 } light;
 uniform vec3 viewPos;
 uniform vec3 diffuseColor;
@@ -225,59 +343,64 @@ uniform float exposure;     // HDR exposure control
 uniform float materialAlpha; // Material transparency (0.0 = transparent, 1.0 = opaque)
 
 // Modern PBR Extensions
-uniform bool enableClearcoat;
 uniform float clearcoatRoughness;
 uniform float clearcoatIntensity;
-uniform bool enableSheen;
 uniform vec3 sheenColor;
 uniform float sheenRoughness;
-uniform bool enableTransmission;
 uniform float transmissionFactor;
 
 // Advanced Lighting Models
-uniform bool enableMultipleScattering;
-uniform bool enableEnergyConservation;
-uniform bool enableImageBasedLighting;
 uniform float iblIntensity;
 
 // Volumetric Lighting
-uniform bool enableVolumetricLighting;
 uniform float volumetricIntensity;
 uniform int volumetricSteps;
 uniform float volumetricScattering;
 
 // SSAO
-uniform bool enableSSAO;
 uniform float ssaoIntensity;
 uniform float ssaoRadius;
 uniform float ssaoBias;
 uniform int ssaoSampleCount;
 
 // Global Illumination
-uniform bool enableGlobalIllumination;
 uniform float giIntensity;
 uniform int giBounces;
 
 // Bloom and HDR
-uniform bool enableBloom;
 uniform float bloomThreshold;
 uniform float bloomIntensity;
 uniform float bloomRadius;
 
 // GPU Gems Chapter 9 & 11: Shadow Volume Support with Antialiasing
-uniform bool enableShadows;  // Enable shadow volume rendering
 uniform float shadowIntensity; // How dark shadows should be (0.0 = black, 1.0 = no shadow)
 uniform float shadowSoftness; // Chapter 11: Shadow edge softness for antialiasing
 
 // GPU Gems Chapter 5: Improved Perlin Noise Support
-uniform bool enablePerlinNoise;
 uniform float noiseScale;
 uniform int noiseOctaves;
 uniform float noiseIntensity;
 
 // Additional advanced rendering uniforms
-uniform bool enableHighQualityFiltering;
+#ifdef FEATURE_HIGH_QUALITY_FILTERING
 uniform int filteringQuality;
+#endif
+
+#ifdef FEATURE_BLOCK_TEXTURES
+flat in float BlockLayer;
+flat in vec2 BlockSurface; // Negative components keep the material's value
+uniform sampler2DArray blockTextures;
+
+float blockMetallic;
+float blockRoughness;
+void resolveBlockSurface() {
+    blockMetallic = BlockSurface.x < 0.0 ? metallic : BlockSurface.x;
+    blockRoughness = BlockSurface.y < 0.0 ? roughness : BlockSurface.y;
+}
+// Everything below reads the block's surface values instead of the uniforms
+#define metallic blockMetallic
+#define roughness blockRoughness
+#endif
 
 out vec4 FragColor;
 
@@ -344,8 +467,9 @@ float geometrySmith(vec3 N, vec3 V, vec3 L, float roughness) {
 
 // Clearcoat BRDF (automotive paint, lacquered surfaces)
 vec3 calculateClearcoat(vec3 N, vec3 V, vec3 L, vec3 H, vec3 baseColor) {
-    if (!enableClearcoat) return vec3(0.0);
-    
+#ifndef FEATURE_CLEARCOAT
+    return vec3(0.0);
+#else
     float clearcoatNDF = distributionGGX(N, H, clearcoatRoughness);
     float clearcoatG = geometrySmith(N, V, L, clearcoatRoughness);
     vec3 clearcoatF = fresnelSchlick(max(dot(H, V), 0.0), vec3(0.04)); // Clear coat F0
@@ -354,22 +478,26 @@ vec3 calculateClearcoat(vec3 N, vec3 V, vec3 L, vec3 H, vec3 baseColor) {
                             (4.0 * max(dot(N, V), 0.0) * max(dot(N, L), 0.0) + 0.001);
     
     return clearcoatSpecular * clearcoatIntensity;
+#endif
 }
 
 // Sheen BRDF (fabric, velvet materials)
 vec3 calculateSheen(vec3 N, vec3 V, vec3 L, vec3 H) {
-    if (!enableSheen) return vec3(0.0);
-    
+#ifndef FEATURE_SHEEN
+    return vec3(0.0);
+#else
     float sheenNdotH = max(dot(N, H), 0.0);
     float sheenD = (2.0 + sheenRoughness) * pow(sheenNdotH, sheenRoughness) / (2.0 * 3.14159265359);
     
     return sheenColor * sheenD * 0.25; // Sheen is typically subtle
+#endif
 }
 
 // Transmission BRDF (glass, translucent materials)
 vec3 calculateTransmission(vec3 N, vec3 V, vec3 L, vec3 baseColor) {
-    if (!enableTransmission) return vec3(0.0);
-    
+#ifndef FEATURE_TRANSMISSION
+    return vec3(0.0);
+#else
     // Proper glass transmission with refraction
     float NdotV = max(dot(N, V), 0.0);
     float NdotL = max(dot(N, L), 0.0);
@@ -386,21 +514,25 @@ vec3 calculateTransmission(vec3 N, vec3 V, vec3 L, vec3 baseColor) {
     vec3 scattering = baseColor * transmission * 0.1;
     
     return transmittedLight + scattering;
+#endif
 }
 
 // Multiple Scattering Energy Compensation
 vec3 compensateEnergyLoss(vec3 color, float NdotV, float roughness) {
-    if (!enableMultipleScattering) return color;
-    
+#ifndef FEATURE_MULTIPLE_SCATTERING
+    return color;
+#else
     // Approximate multiple scattering compensation
     float compensation = 1.0 + roughness * (1.0 - NdotV) * 0.2;
     return color * compensation;
+#endif
 }
 
 // Energy Conservation for layered materials
 vec3 applyEnergyConservation(vec3 diffuse, vec3 specular, vec3 clearcoat, vec3 sheen) {
-    if (!enableEnergyConservation) return diffuse + specular + clearcoat + sheen;
-    
+#ifndef FEATURE_ENERGY_CONSERVATION
+    return diffuse + specular + clearcoat + sheen;
+#else
     // Ensure total energy doesn't exceed 1.0
     vec3 totalEnergy = diffuse + specular + clearcoat + sheen;
     float maxEnergy = max(max(totalEnergy.r, totalEnergy.g), totalEnergy.b);
@@ -410,13 +542,15 @@ vec3 applyEnergyConservation(vec3 diffuse, vec3 specular, vec3 clearcoat, vec3 s
     }
     
     return totalEnergy;
+#endif
 }
 
 // Improved Screen Space Ambient Occlusion approximation
 // Note: True SSAO requires depth buffer, this is a world-space approximation with hemisphere sampling
 float calculateSSAO(vec3 position, vec3 normal, float distanceToCamera) {
-    if (!enableSSAO) return 1.0;
-    
+#ifndef FEATURE_SSAO
+    return 1.0;
+#else
     // Distance-based LOD: reduce samples for close objects (voxel performance)
     int adaptiveSamples = ssaoSampleCount;
     if (distanceToCamera < 5000.0) {
@@ -473,12 +607,14 @@ float calculateSSAO(vec3 position, vec3 normal, float distanceToCamera) {
     occlusion = pow(occlusion, 1.0 + ssaoIntensity);
     
     return occlusion;
+#endif
 }
 
 // Volumetric Lighting (light shafts, fog) with distance-based optimization
 vec3 calculateVolumetricLighting(vec3 worldPos, vec3 lightPos, vec3 viewPos) {
-    if (!enableVolumetricLighting) return vec3(0.0);
-    
+#ifndef FEATURE_VOLUMETRIC_LIGHTING
+    return vec3(0.0);
+#else
     float distanceToCamera = length(worldPos - viewPos);
     
     // Skip volumetric for very close objects - too expensive per fragment
@@ -513,12 +649,14 @@ vec3 calculateVolumetricLighting(vec3 worldPos, vec3 lightPos, vec3 viewPos) {
     
     volumetricColor /= float(adaptiveSteps);
     return volumetricColor * volumetricIntensity * 0.1;
+#endif
 }
 
 // Global Illumination approximation with distance-based optimization
 vec3 calculateGlobalIllumination(vec3 position, vec3 normal, vec3 albedo, float distanceToCamera) {
-    if (!enableGlobalIllumination) return vec3(0.0);
-    
+#ifndef FEATURE_GLOBAL_ILLUMINATION
+    return vec3(0.0);
+#else
     // Adaptive sample count based on distance (CRITICAL for voxel performance)
     int baseSamples = giBounces * 4;
     int samples = baseSamples;
@@ -548,6 +686,7 @@ vec3 calculateGlobalIllumination(vec3 position, vec3 normal, vec3 albedo, float
     }
     
     return gi * giIntensity / float(samples);
+#endif
 }
 
 // Environment reflections (skybox-based) - simplified to avoid artifacts
@@ -706,9 +845,17 @@ float turbulence(vec3 p, int octaves) {
     
     return value / maxValue;
 }
-
+#include "fog.glsl"
+#include "ltc.glsl"
+#include "ies.glsl"
 void main() {
     vec4 texColor = texture(textureSampler, fragTexCoord);
+#ifdef FEATURE_BLOCK_TEXTURES
+    resolveBlockSurface();
+    if (BlockLayer >= 0.0) {
+        texColor = texture(blockTextures, vec3(fragTexCoord, BlockLayer));
+    }
+#endif
     
     // Check for emissive objects first - bypass all lighting for sun-like objects
     if (exposure > 10.0) {
@@ -737,6 +884,9 @@ void main() {
         float distance = length(lightVec);
         lightDir = lightVec / distance; // More precise than normalize()
         attenuation = 1.0 / (light.constantAtten + light.linearAtten * distance + light.quadraticAtten * distance * distance);
+        if (light.hasIES == 1) {
+            attenuation *= iesFactor(-lightDir, light.direction, light.iesRight, light.iesUp);
+        }
     }
     
     vec3 halfwayDir = normalize(lightDir + viewDir);
@@ -834,15 +984,22 @@ void main() {
     // Reduced base ambient, add fill light for back faces
     vec3 ambient = light.ambientStrength * tempAdjustedLightColor * albedo * 0.8;
     vec3 fillLightContrib = fillLight * tempAdjustedLightColor * albedo * 0.2;
+
+    // Rectangle and disk lights replace the point terms with their LTC integral
+    if (light.areaShape != 0) {
+        Lo = areaLight(light.areaShape, light.position, light.areaRight, light.areaUp, norm, viewDir, FragPos,
+                       albedo, F0, metallic, roughness, tempAdjustedLightColor * light.intensity);
+        fillLightContrib = vec3(0.0);
+    }
     
     // GPU Gems Chapter 5: Apply Perlin noise for surface detail if enabled
-    if (enablePerlinNoise) {
-        vec3 noiseCoord = FragPos * noiseScale;
-        float noiseValue = turbulence(noiseCoord, noiseOctaves);
-        
-		// Apply noise directly to albedo for visible surface detail
-		albedo = mix(albedo, albedo * (1.0 + noiseValue * 0.3), noiseIntensity);
-    }
+#ifdef FEATURE_PERLIN_NOISE
+    vec3 noiseCoord = FragPos * noiseScale;
+    float noiseValue = turbulence(noiseCoord, noiseOctaves);
+    
+    // Apply noise directly to albedo for visible surface detail
+    albedo = mix(albedo, albedo * (1.0 + noiseValue * 0.3), noiseIntensity);
+#endif
     
     // Use the properly calculated Lo from energy conservation with fill light
     vec3 color = ambient + fillLightContrib + Lo;
@@ -866,7 +1023,11 @@ void main() {
     
 	// Environment reflections (skybox-based)
     vec3 envReflection = calculateEnvironmentReflection(norm, viewDir, roughness, metallic);
+#ifdef FEATURE_IMAGE_BASED_LIGHTING
+	color += envReflection * 0.3 * iblIntensity;
+#else
 	color += envReflection * 0.3; // More visible reflections
+#endif
     
     // GPU Gems Chapter 2: Caustics are handled in water shader for now
     // Future: Add caustics support to default shader with proper uniform checking
@@ -874,55 +1035,58 @@ void main() {
     // HDR exposure and tone mapping for normal objects
     color = color * exposure;
     // GPU Gems Chapter 9 & 11: Apply shadows with proper sun behavior
-    if (enableShadows) {
-        float shadowFactor = 1.0;
+#ifdef FEATURE_SHADOWS
+    float shadowFactor = 1.0;
+    
+    // For directional lights (like sun): use uniform shadow based on position
+    if (light.isDirectional == 1) {
+        // Sun shadows: uniform illumination, no distance falloff
+        // Only apply shadows in specific areas (like under objects)
+        vec3 worldPos = FragPos;
+        float shadowNoise = sin(worldPos.x * 0.0001) * sin(worldPos.z * 0.0001);
         
-        // For directional lights (like sun): use uniform shadow based on position
-        if (light.isDirectional == 1) {
-            // Sun shadows: uniform illumination, no distance falloff
-            // Only apply shadows in specific areas (like under objects)
-            vec3 worldPos = FragPos;
-            float shadowNoise = sin(worldPos.x * 0.0001) * sin(worldPos.z * 0.0001);
+        // Very subtle shadow variation for realism, not distance-based darkening
+        shadowFactor = 1.0 - shadowIntensity * 0.1 * shadowNoise;
+    } else {
+        // Point light shadows: distance-based (for torches, lamps, etc.)
+        float lightDistance = length(light.position - FragPos);
+        if (lightDistance > 50000.0) {
+            float distanceFactor = smoothstep(50000.0, 150000.0, lightDistance);
+            shadowFactor = mix(1.0, shadowIntensity, distanceFactor);
             
-            // Very subtle shadow variation for realism, not distance-based darkening
-            shadowFactor = 1.0 - shadowIntensity * 0.1 * shadowNoise;
-        } else {
-            // Point light shadows: distance-based (for torches, lamps, etc.)
-            float lightDistance = length(light.position - FragPos);
-            if (lightDistance > 50000.0) {
-                float distanceFactor = smoothstep(50000.0, 150000.0, lightDistance);
-                shadowFactor = mix(1.0, shadowIntensity, distanceFactor);
-                
-                // Chapter 11: Add shadow edge softness
-                float shadowEdge = fract(lightDistance * 0.00001);
-                shadowFactor = mix(shadowFactor, 1.0, shadowEdge * shadowSoftness);
-            }
+            // Chapter 11: Add shadow edge softness
+            float shadowEdge = fract(lightDistance * 0.00001);
+            shadowFactor = mix(shadowFactor, 1.0, shadowEdge * shadowSoftness);
         }
-        
-        // Apply shadow to lighting components (preserve ambient)
-        vec3 lightContrib = color - ambient;
-        lightContrib *= shadowFactor;
-        color = ambient + lightContrib;
     }
     
+    // Apply shadow to lighting components (preserve ambient)
+    vec3 lightContrib = color - ambient;
+    lightContrib *= shadowFactor;
+    color = ambient + lightContrib;
+#endif
+    
     // Apply bloom effect
-    if (enableBloom) {
-        // Extract bright areas for bloom
-        vec3 brightColor = max(color - bloomThreshold, vec3(0.0));
-        float brightness = dot(brightColor, vec3(0.2126, 0.7152, 0.0722));
-        
-        if (brightness > 0.0) {
-            // Simple bloom approximation
-            vec3 bloom = brightColor * bloomIntensity;
-            color += bloom * 0.3; // Blend bloom back into the image
-        }
+#ifdef FEATURE_BLOOM
+    // Extract bright areas for bloom
+    vec3 brightColor = max(color - bloomThreshold, vec3(0.0));
+    float brightness = dot(brightColor, vec3(0.2126, 0.7152, 0.0722));
+    
+    if (brightness > 0.0) {
+        // Simple bloom approximation
+        vec3 bloom = brightColor * bloomIntensity;
+        color += bloom * 0.3; // Blend bloom back into the image
     }
+#endif
     
     color = ACESFilm(color);
     
     // Gamma correction (sRGB)
     color = pow(color, vec3(1.0/2.2));
     
+    // Scene fog is blended in display space so it matches the clear color and sky
+    color = applySceneFog(color, FragPos, viewPos);
+    
     // Use material alpha for transparency
     float finalAlpha = texColor.a * materialAlpha;
     
@@ -944,6 +1108,7 @@ layout (location = 2) in vec3 aNormal;
 out vec2 fragTexCoord;
 out vec3 fragNormal;
 out vec3 fragPosition;
+out vec4 fragClipPosition; // Screen position, for sampling the planar reflection
 
 uniform mat4 model;
 uniform mat4 viewProjection;
@@ -1094,6 +1259,7 @@ void main() {
     fragNormal = totalNormal;
     
     gl_Position = viewProjection * vec4(worldPos, 1.0);
+    fragClipPosition = gl_Position;
 }
 ` + "\x00"
 
@@ -1102,6 +1268,7 @@ var waterFragmentShaderSource = `#version 330 core
 in vec2 fragTexCoord;
 in vec3 fragNormal;
 in vec3 fragPosition;
+in vec4 fragClipPosition;
 
 // Enhanced water shader uniforms
 uniform vec3 lightPos;
@@ -1151,6 +1318,10 @@ uniform float waterDistortionIntensity;
 uniform bool enableWaterNormalMapping;
 uniform float waterNormalIntensity;
 
+// Planar reflection of the scene, drawn flipped left to right
+uniform bool enablePlanarReflection;
+uniform sampler2D reflectionTexture;
+
 // Custom transparency control
 uniform float baseAlpha;
 uniform float transparencyBoost;
@@ -1301,7 +1472,7 @@ float generateCaustics(vec3 worldPos, float time) {
     
     return combined * causticsIntensity * distanceAttenuation;
 }
-
+#include "fog.glsl"
 void main() {
     vec3 norm = normalize(fragNormal);
     
@@ -1608,6 +1779,15 @@ void main() {
     float waveLighting = 1.0 + waveFacing * 0.2 + waveHighlight; // Increased wave lighting
     finalColor *= waveLighting;
     
+    // Planar reflection, rippled by the surface normal
+    if (enablePlanarReflection) {
+        vec2 screenUV = fragClipPosition.xy / fragClipPosition.w * 0.5 + 0.5;
+        vec2 reflectionUV = vec2(1.0 - screenUV.x, screenUV.y) + norm.xz * waterDistortionIntensity * 0.05;
+        vec3 planarReflection = texture(reflectionTexture, clamp(reflectionUV, 0.001, 0.999)).rgb;
+        float reflectance = 0.02 + 0.98 * pow(1.0 - NdotV, 5.0); // Schlick, water F0
+        finalColor = mix(finalColor, planarReflection, clamp(reflectance * waterReflectionIntensity, 0.0, 1.0));
+    }
+    
     // Very subtle, natural foam
     if (totalFoam > 0.05) {
         vec3 foamColor = vec3(0.6, 0.7, 0.8); // Brighter foam
@@ -1672,6 +1852,9 @@ void main() {
         finalColor += lightColor * godRayStrength * (1.0 - tintStrength);
     }
     
+    // Scene fog (distance + height), shared with the default and skybox shaders
+    finalColor = applySceneFog(finalColor, fragPosition, viewPos);
+    
     alpha = clamp(alpha, 0.001, 0.98); // Allow almost complete transparency
     
     FragColor = vec4(finalColor, alpha);
@@ -1682,6 +1865,9 @@ func InitShader() Shader {
 	return Shader{
 		vertexSource:   vertexShaderSource,
 		fragmentSource: fragmentShaderSource,
+		vertexFile:     "default.vert",
+		fragmentFile:   "default.frag",
+		Name:           "default",
 	}
 }
 
@@ -1724,6 +1910,9 @@ func InitWaterShader() Shader {
 	return Shader{
 		vertexSource:   waterVertexShaderSource,
 		fragmentSource: waterFragmentShaderSource,
+		vertexFile:     "water.vert",
+		fragmentFile:   "water.frag",
+		Name:           "water",
 	}
 }
 
@@ -1937,8 +2126,10 @@ out vec4 FragColor;
 
 in vec3 TexCoords;
 
-uniform sampler2D skybox;
-
+uniform sampler2D skybox; // sRGB, decoded to linear when sampled
+uniform vec3 viewPos;
+#include "fog.glsl"
+#include "color_space.glsl"
 void main() {
     vec3 dir = normalize(TexCoords);
     
@@ -1948,17 +2139,22 @@ void main() {
     float u = (theta + 3.14159265) / 6.28318531;
     float v = (phi + 1.57079633) / 3.14159265;
     
-    FragColor = texture(skybox, vec2(u, v));
+    vec4 sky = texture(skybox, vec2(u, v));
+    FragColor = vec4(mix(linearToSRGB(sky.rgb), sceneFog.color, computeSceneSkyFog(dir, viewPos)), sky.a);
 }
 ` + "\x00"
 
 var solidColorSkyboxFragmentShaderSource = `#version 330 core
 out vec4 FragColor;
 
-uniform vec3 skyColor;
+in vec3 TexCoords;
 
+uniform vec3 skyColor;
+uniform vec3 viewPos;
+#include "fog.glsl"
 void main() {
-    FragColor = vec4(skyColor, 1.0);
+    vec3 dir = normalize(TexCoords);
+    FragColor = vec4(mix(skyColor, sceneFog.color, computeSceneSkyFog(dir, viewPos)), 1.0);
 }
 ` + "\x00"
 
@@ -1967,6 +2163,9 @@ func InitSkyboxShader() Shader {
 	return Shader{
 		vertexSource:   skyboxVertexShaderSource,
 		fragmentSource: skyboxFragmentShaderSource,
+		vertexFile:     "skybox.vert",
+		fragmentFile:   "skybox.frag",
+		Name:           "skybox",
 	}
 }
 
@@ -1975,6 +2174,8 @@ func InitShadowVolumeShader() Shader {
 	return Shader{
 		vertexSource:   shadowVolumeVertexShaderSource,
 		fragmentSource: shadowVolumeFragmentShaderSource,
+		vertexFile:     "shadow_volume.vert",
+		fragmentFile:   "shadow_volume.frag",
 		Name:           "shadow_volume",
 	}
 }
@@ -1984,6 +2185,9 @@ func InitSolidColorSkyboxShader(r, g, b float32) Shader {
 	shader := Shader{
 		vertexSource:   skyboxVertexShaderSource, // Same vertex shader
 		fragmentSource: solidColorSkyboxFragmentShaderSource,
+		vertexFile:     "skybox.vert",
+		fragmentFile:   "skybox_solid.frag",
+		Name:           "skybox_solid",
 	}
 	// Store color in shader for later use
 	shader.skyColor = mgl32.Vec3{r, g, b}
@@ -2115,6 +2319,8 @@ func InitFXAAShader() Shader {
 	shader := Shader{
 		vertexSource:   fxaaVertexShaderSource,
 		fragmentSource: fxaaFragmentShaderSource,
+		vertexFile:     "fullscreen.vert",
+		fragmentFile:   "fxaa.frag",
 		Name:           "fxaa",
 	}
 	return shader
@@ -2170,6 +2376,8 @@ func InitBloomShader() Shader {
 	shader := Shader{
 		vertexSource:   fxaaVertexShaderSource, // Same vertex shader as FXAA
 		fragmentSource: bloomFragmentShaderSource,
+		vertexFile:     "fullscreen.vert",
+		fragmentFile:   "bloom.frag",
 		Name:           "bloom",
 	}
 	return shader
@@ -2193,6 +2401,8 @@ func InitPassthroughShader() Shader {
 	shader := Shader{
 		vertexSource:   fxaaVertexShaderSource, // Same vertex shader
 		fragmentSource: passthroughFragmentShaderSource,
+		vertexFile:     "fullscreen.vert",
+		fragmentFile:   "passthrough.frag",
 		Name:           "passthrough",
 	}
 	return shader
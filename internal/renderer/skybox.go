@@ -24,19 +24,106 @@ type Skybox struct {
 	VBO       uint32
 	TextureID uint32
 	Shader    Shader
+
+	// Procedural sky (nil for textured and solid color skyboxes)
+	Atmosphere   *AtmosphereSettings
+	SunDirection mgl32.Vec3 // Direction toward the sun, kept in sync with the primary directional light
 }
 
+// ProceduralSkyboxPath is the special CreateSkybox path for a procedural atmospheric sky
+const ProceduralSkyboxPath = "procedural"
+
 // CreateSkybox creates a skybox with the specified texture
 func CreateSkybox(texturePath string) (*Skybox, error) {
-	skybox := &Skybox{}
-
 	// Handle special case for solid color skybox
 	if texturePath == "dark_sky" || texturePath == "" {
 		// Creating solid color skybox with current RGB values
 		return CreateSolidColorSkybox(SkyboxR, SkyboxG, SkyboxB) // Use public variables!
 	}
+	if texturePath == ProceduralSkyboxPath {
+		return CreateProceduralSkybox(DefaultAtmosphereSettings())
+	}
+
+	// Load texture directly for skybox
+	textureID, err := loadSkyboxTexture(texturePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load skybox texture %s: %v", texturePath, err)
+	}
+	return newImageSkybox(textureID), nil
+}
+
+// newImageSkybox builds the geometry and shader around an uploaded skybox texture
+func newImageSkybox(textureID uint32) *Skybox {
+	skybox := &Skybox{TextureID: textureID}
+	initSkyboxGeometry(skybox)
+
+	// Initialize skybox shader
+	skybox.Shader = InitSkyboxShader()
+	skybox.Shader.Compile()
+
+	return skybox
+}
+
+// Render renders the skybox
+func (s *Skybox) Render(camera Camera) {
+	// Only render if it's a textured or procedural skybox
+	// Solid color skyboxes are handled by gl.ClearColor in the renderer
+	if s.TextureID == 0 && !s.IsProcedural() {
+		return
+	}
+	s.RenderWithFog(camera, FogSettings{})
+}
+
+// RenderWithFog renders the skybox blended toward the scene fog near the horizon.
+// Unlike Render it also draws solid color skyboxes, which the renderer uses as a
+// fog backdrop when no skybox image is set.
+func (s *Skybox) RenderWithFog(camera Camera, fog FogSettings) {
+	// Use shader
+	s.Shader.Use()
+
+	// Remove translation from view matrix (skybox should appear infinite)
+	view := camera.GetViewMatrix()
+	// Zero out the translation components
+	view[12] = 0
+	view[13] = 0
+	view[14] = 0
+
+	projection := camera.perspectiveProjection()
+
+	// Set uniforms
+	s.Shader.SetMat4("view", view)
+	s.Shader.SetMat4("projection", projection)
+	s.Shader.SetVec3("viewPos", camera.Position)
+	if s.IsProcedural() {
+		s.Atmosphere.setUniforms(&s.Shader, s.SunDirection)
+	} else if s.TextureID == 0 {
+		s.Shader.SetVec3("skyColor", s.Shader.skyColor)
+	}
+	if s.Shader.uniformCache != nil {
+		setFogUniforms(s.Shader.uniformCache, fog, camera.Far)
+	}
 
-	// Create skybox geometry (configurable size)
+	gl.DepthMask(false)
+	gl.DepthFunc(gl.LEQUAL)
+
+	// Bind texture
+	if s.TextureID != 0 {
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D, s.TextureID)
+	}
+
+	// Draw skybox
+	gl.BindVertexArray(s.VAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	gl.BindVertexArray(0)
+
+	// Restore OpenGL state
+	gl.DepthMask(true)
+	gl.DepthFunc(gl.LESS)
+}
+
+// initSkyboxGeometry uploads the skybox cube (SkyboxSize half-extent) into skybox.VAO/VBO
+func initSkyboxGeometry(skybox *Skybox) {
 	size := SkyboxSize
 	vertices := []float32{
 		// Positions (cube centered at origin)
@@ -96,74 +183,29 @@ func CreateSkybox(texturePath string) (*Skybox, error) {
 	gl.EnableVertexAttribArray(0)
 
 	gl.BindVertexArray(0)
+}
 
-	// Load texture directly for skybox
-	textureID, err := loadSkyboxTexture(texturePath)
+func loadSkyboxTexture(filePath string) (uint32, error) {
+	pixels, width, height, err := decodeSkyboxImage(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load skybox texture %s: %v", texturePath, err)
+		return 0, err
 	}
-	skybox.TextureID = textureID
-
-	// Initialize skybox shader
-	skybox.Shader = InitSkyboxShader()
-	skybox.Shader.Compile()
-
-	return skybox, nil
+	return uploadSkyboxTexture(pixels, width, height), nil
 }
 
-// Render renders the skybox
-func (s *Skybox) Render(camera Camera) {
-	// Only render if it's a textured skybox (TextureID != 0)
-	// Solid color skyboxes are handled by gl.ClearColor in the renderer
-	if s.TextureID == 0 {
-		return
-	}
-
-	// Use shader
-	s.Shader.Use()
-
-	// Remove translation from view matrix (skybox should appear infinite)
-	view := camera.GetViewMatrix()
-	// Zero out the translation components
-	view[12] = 0
-	view[13] = 0
-	view[14] = 0
-
-	projection := camera.GetProjectionMatrix()
-
-	// Set uniforms
-	s.Shader.SetMat4("view", view)
-	s.Shader.SetMat4("projection", projection)
-
-	gl.DepthMask(false)
-	gl.DepthFunc(gl.LEQUAL)
-
-	// Bind texture
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, s.TextureID)
-
-	// Draw skybox
-	gl.BindVertexArray(s.VAO)
-	gl.DrawArrays(gl.TRIANGLES, 0, 36)
-	gl.BindVertexArray(0)
-
-	// Restore OpenGL state
-	gl.DepthMask(true)
-	gl.DepthFunc(gl.LESS)
-}
-
-func loadSkyboxTexture(filePath string) (uint32, error) {
+// decodeSkyboxImage reads a skybox image into bottom-up RGBA rows. It makes no GL calls.
+func decodeSkyboxImage(filePath string) ([]byte, int, int, error) {
 	fmt.Printf("Loading skybox texture: %s\n", filePath)
 
 	imgFile, err := os.Open(filePath)
 	if err != nil {
-		return 0, err
+		return nil, 0, 0, err
 	}
 	defer imgFile.Close()
 
 	img, format, err := image.Decode(imgFile)
 	if err != nil {
-		return 0, err
+		return nil, 0, 0, err
 	}
 
 	fmt.Printf("Decoded %s image successfully\n", format)
@@ -175,7 +217,7 @@ func loadSkyboxTexture(filePath string) (uint32, error) {
 	maxSize := 512
 	if width > maxSize || height > maxSize {
 		fmt.Printf("Skybox %dx%d exceeds max %d, rejecting\n", width, height, maxSize)
-		return 0, fmt.Errorf("skybox image too large (max %dx%d)", maxSize, maxSize)
+		return nil, 0, 0, fmt.Errorf("skybox image too large (max %dx%d)", maxSize, maxSize)
 	}
 
 	var srcPix []uint8
@@ -208,6 +250,11 @@ func loadSkyboxTexture(filePath string) (uint32, error) {
 		copy(result[dstStart:dstStart+rowSize], srcPix[srcStart:srcStart+rowSize])
 	}
 
+	return result, width, height, nil
+}
+
+// uploadSkyboxTexture creates the GL texture for decoded skybox pixels
+func uploadSkyboxTexture(result []byte, width, height int) uint32 {
 	fmt.Printf("Uploading texture to GPU (%dx%d)...\n", width, height)
 
 	var textureID uint32
@@ -219,10 +266,11 @@ func loadSkyboxTexture(filePath string) (uint32, error) {
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
 
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(result))
+	// Sky images are colors, so sample them as linear like albedo
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.SRGB8_ALPHA8, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(result))
 
 	fmt.Printf("Skybox texture loaded successfully! (ID: %d)\n", textureID)
-	return textureID, nil
+	return textureID
 }
 
 // CreateSolidColorSkybox creates a skybox with a solid color (no texture needed)
@@ -230,66 +278,7 @@ func CreateSolidColorSkybox(r, g, b float32) (*Skybox, error) {
 	skybox := &Skybox{}
 	// Creating solid color skybox
 
-	// Create skybox geometry (configurable size)
-	size := SkyboxSize
-	vertices := []float32{
-		// Positions (cube centered at origin)
-		-size, size, -size,
-		-size, -size, -size,
-		size, -size, -size,
-		size, -size, -size,
-		size, size, -size,
-		-size, size, -size,
-
-		-size, -size, size,
-		-size, -size, -size,
-		-size, size, -size,
-		-size, size, -size,
-		-size, size, size,
-		-size, -size, size,
-
-		size, -size, -size,
-		size, -size, size,
-		size, size, size,
-		size, size, size,
-		size, size, -size,
-		size, -size, -size,
-
-		-size, -size, size,
-		-size, size, size,
-		size, size, size,
-		size, size, size,
-		size, -size, size,
-		-size, -size, size,
-
-		-size, size, -size,
-		size, size, -size,
-		size, size, size,
-		size, size, size,
-		-size, size, size,
-		-size, size, -size,
-
-		-size, -size, -size,
-		-size, -size, size,
-		size, -size, -size,
-		size, -size, -size,
-		-size, -size, size,
-		size, -size, size,
-	}
-
-	// Create VAO and VBO
-	gl.GenVertexArrays(1, &skybox.VAO)
-	gl.GenBuffers(1, &skybox.VBO)
-
-	gl.BindVertexArray(skybox.VAO)
-	gl.BindBuffer(gl.ARRAY_BUFFER, skybox.VBO)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-
-	// Position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-
-	gl.BindVertexArray(0)
+	initSkyboxGeometry(skybox)
 
 	// No texture needed for solid color
 	skybox.TextureID = 0
@@ -334,6 +323,11 @@ func UpdateCurrentSkyboxColor(skybox *Skybox, r, g, b float32) {
 	}
 }
 
+// IsProcedural reports whether the skybox is a procedural atmospheric sky
+func (s *Skybox) IsProcedural() bool {
+	return s.Atmosphere != nil
+}
+
 // UpdateColor dynamically updates the skybox color (for solid color skyboxes only)
 func (s *Skybox) UpdateColor(r, g, b float32) {
 	if s.TextureID == 0 { // Only for solid color skyboxes
@@ -0,0 +1,536 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+	"fmt"
+	"math/bits"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Screen-space reflection inputs sit after the G-buffer's units 0 to 3
+const (
+	ssrSceneUnit      = 4
+	ssrHiZUnit        = 5
+	ssrReflectionUnit = 6
+	ssrSkyUnit        = 7
+)
+
+// ssrMaxBlurLevels caps how many reflection mips the roughest surfaces blur across
+const ssrMaxBlurLevels = 6
+
+// SSRQuality trades reflection accuracy for speed by bounding the trace steps
+type SSRQuality string
+
+const (
+	SSRQualityLow    SSRQuality = "low"
+	SSRQualityMedium SSRQuality = "medium"
+	SSRQualityHigh   SSRQuality = "high"
+)
+
+// maxSteps is the trace iteration budget for the quality level; unknown values use medium
+func (q SSRQuality) maxSteps() int32 {
+	switch q {
+	case SSRQualityLow:
+		return 32
+	case SSRQualityHigh:
+		return 128
+	default:
+		return 64
+	}
+}
+
+// SSRSettings configures screen-space reflections. Reflections come from the
+// drawn opaque scene and fall back to the sky where the trace finds nothing.
+type SSRSettings struct {
+	Enabled      bool       `json:"enabled"`
+	Quality      SSRQuality `json:"quality"`
+	MaxDistance  float32    `json:"max_distance"`  // Longest reflected ray in world units
+	Thickness    float32    `json:"thickness"`     // How far behind its depth a surface counts as hit, in world units
+	Intensity    float32    `json:"intensity"`     // Scales the reflected light
+	MaxRoughness float32    `json:"max_roughness"` // Rougher surfaces get no reflections
+}
+
+// DefaultSSRSettings returns disabled reflections with values ready to be switched on
+func DefaultSSRSettings() SSRSettings {
+	return SSRSettings{
+		Enabled:      false,
+		Quality:      SSRQualityMedium,
+		MaxDistance:  50.0,
+		Thickness:    0.5,
+		Intensity:    1.0,
+		MaxRoughness: 0.6,
+	}
+}
+
+// hiZLevels is the mip count of a depth pyramid halved down to a single texel
+func hiZLevels(width, height int32) int32 {
+	return int32(bits.Len32(uint32(max(width, height, 1))))
+}
+
+// ssrBuffers holds a view's depth pyramid and traced reflections
+type ssrBuffers struct {
+	hiZ           uint32   // Nearest depth per texel, R32F, one mip per halving
+	hiZFBOs       []uint32 // One per hiZ mip
+	reflection    uint32   // Reflected color times confidence, and confidence, mipmapped for blur
+	reflectionFBO uint32
+	levels        int32 // Mips of hiZ
+	width         int32
+	height        int32
+}
+
+// ssrBuffers returns the reflection buffers of the view being drawn
+func (rend *OpenGLRenderer) ssrBuffers() *ssrBuffers {
+	for len(rend.ssrTargets) <= rend.activeView {
+		s := &ssrBuffers{}
+		gl.GenFramebuffers(1, &s.reflectionFBO)
+		rend.ssrTargets = append(rend.ssrTargets, s)
+	}
+	return rend.ssrTargets[rend.activeView]
+}
+
+// mipTexture allocates every level of a texture down to one texel
+func mipTexture(internalFormat int32, format uint32, width, height, levels int32, minFilter int32) uint32 {
+	var id uint32
+	gl.GenTextures(1, &id)
+	gl.BindTexture(gl.TEXTURE_2D, id)
+	for level := int32(0); level < levels; level++ {
+		gl.TexImage2D(gl.TEXTURE_2D, level, internalFormat, max(width>>level, 1), max(height>>level, 1), 0, format, gl.FLOAT, nil)
+	}
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_BASE_LEVEL, 0)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, levels-1)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	return id
+}
+
+// resize recreates the pyramid and reflection texture at a new size
+func (s *ssrBuffers) resize(width, height int32) {
+	s.deleteAttachments()
+	s.levels = hiZLevels(width, height)
+	s.hiZ = mipTexture(gl.R32F, gl.RED, width, height, s.levels, gl.NEAREST_MIPMAP_NEAREST)
+	s.hiZFBOs = make([]uint32, s.levels)
+	gl.GenFramebuffers(s.levels, &s.hiZFBOs[0])
+	for level, fbo := range s.hiZFBOs {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, s.hiZ, int32(level))
+	}
+
+	blurLevels := min(s.levels, ssrMaxBlurLevels+1)
+	s.reflection = mipTexture(gl.RGBA16F, gl.RGBA, width, height, blurLevels, gl.LINEAR_MIPMAP_LINEAR)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, s.reflectionFBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, s.reflection, 0)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		logger.Log.Error(fmt.Sprintf("Reflection framebuffer incomplete after resize! Status: 0x%X", status))
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	s.width, s.height = width, height
+	logger.Log.Info(fmt.Sprintf("Resized reflection buffers (%dx%d, %d depth levels)", width, height, s.levels))
+}
+
+func (s *ssrBuffers) deleteAttachments() {
+	if len(s.hiZFBOs) > 0 {
+		gl.DeleteFramebuffers(int32(len(s.hiZFBOs)), &s.hiZFBOs[0])
+		s.hiZFBOs = nil
+	}
+	for _, id := range []*uint32{&s.hiZ, &s.reflection} {
+		if *id != 0 {
+			gl.DeleteTextures(1, id)
+			*id = 0
+		}
+	}
+}
+
+// delete releases the framebuffers and their attachments
+func (s *ssrBuffers) delete() {
+	s.deleteAttachments()
+	gl.DeleteFramebuffers(1, &s.reflectionFBO)
+	s.reflectionFBO = 0
+}
+
+// renderSSR adds screen-space reflections to the opaque scene drawn into
+// target. The G-buffer serves as the depth and normal prepass; the deferred
+// path has filled it already. Only models the G-buffer holds receive
+// reflections, while everything drawn so far can be reflected.
+func (rend *OpenGLRenderer) renderSSR(target *postProcessTarget, viewProjection mgl32.Mat4, camera Camera, deferred bool, backgroundColor mgl32.Vec3) {
+	g := rend.gBuffer()
+	if !deferred {
+		if g.width != target.width || g.height != target.height {
+			g.resize(target.width, target.height)
+		}
+		gl.BindFramebuffer(gl.FRAMEBUFFER, g.fbo)
+		if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, target.fbo)
+			return
+		}
+		endPrepass := rend.profilePass("Reflection Prepass")
+		rend.renderGBuffer(viewProjection)
+		endPrepass()
+	}
+
+	s := rend.ssrBuffers()
+	if s.width != g.width || s.height != g.height {
+		s.resize(g.width, g.height)
+	}
+	if rend.hiZShader.Name == "" {
+		rend.hiZShader = NewShaderFromFiles("hiz", "fullscreen.vert", "hiz.frag")
+		rend.ssrTraceShader = NewShaderFromFiles("ssr_trace", "fullscreen.vert", "ssr_trace.frag")
+		rend.ssrCompositeShader = NewShaderFromFiles("ssr_composite", "fullscreen.vert", "ssr_composite.frag")
+	}
+
+	var depthFunc int32
+	gl.GetIntegerv(gl.DEPTH_FUNC, &depthFunc)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+	gl.Disable(gl.BLEND)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+	gl.BindVertexArray(rend.screenQuadVAO)
+
+	inverseViewProjection := viewProjection.Inv()
+	if rend.buildHiZ(s, g) {
+		rend.traceSSR(s, g, target, viewProjection, inverseViewProjection, camera)
+
+		// Each mip averages the hits below it, widening the blur for rough surfaces
+		gl.BindTexture(gl.TEXTURE_2D, s.reflection)
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+
+		gl.BindFramebuffer(gl.FRAMEBUFFER, target.fbo)
+		gl.Viewport(0, 0, target.width, target.height)
+		rend.compositeSSR(s, g, inverseViewProjection, camera, backgroundColor)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.fbo)
+	gl.Viewport(0, 0, target.width, target.height)
+
+	gl.BindVertexArray(0)
+	gl.DepthFunc(uint32(depthFunc))
+	gl.DepthMask(true)
+	if rend.depthTestState {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.ClearColor(backgroundColor.X(), backgroundColor.Y(), backgroundColor.Z(), 1.0)
+	if Debug {
+		gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+	}
+	// The reflection passes bind their own programs, so force the next model to rebind
+	rend.currentShaderProgram = 0
+}
+
+// buildHiZ copies the G-buffer depth into the pyramid's first level and keeps
+// the nearest depth of each texel's footprint in every level after it
+func (rend *OpenGLRenderer) buildHiZ(s *ssrBuffers, g *gBuffer) bool {
+	shader := &rend.hiZShader
+	shader.Use()
+	if shader.program == 0 {
+		return false
+	}
+	shader.SetInt("source", 0)
+	gl.ActiveTexture(gl.TEXTURE0)
+	for level := int32(0); level < s.levels; level++ {
+		width, height := max(s.width>>level, 1), max(s.height>>level, 1)
+		if level == 0 {
+			gl.BindTexture(gl.TEXTURE_2D, g.depth)
+		} else {
+			// Reading only the level above keeps the written level out of the sampled range
+			gl.BindTexture(gl.TEXTURE_2D, s.hiZ)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_BASE_LEVEL, level-1)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, level-1)
+		}
+		gl.BindFramebuffer(gl.FRAMEBUFFER, s.hiZFBOs[level])
+		gl.Viewport(0, 0, width, height)
+		shader.SetBool("firstLevel", level == 0)
+		gl.DrawArrays(gl.TRIANGLES, 0, 6)
+		rend.lastDrawCalls++
+	}
+	gl.BindTexture(gl.TEXTURE_2D, s.hiZ)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_BASE_LEVEL, 0)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, s.levels-1)
+	return true
+}
+
+// traceSSR marches each reflective pixel's reflected ray through the depth
+// pyramid and stores the scene color it hits
+func (rend *OpenGLRenderer) traceSSR(s *ssrBuffers, g *gBuffer, target *postProcessTarget, viewProjection, inverseViewProjection mgl32.Mat4, camera Camera) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, s.reflectionFBO)
+	gl.Viewport(0, 0, s.width, s.height)
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	shader := &rend.ssrTraceShader
+	shader.Use()
+	if shader.program == 0 {
+		return
+	}
+	g.bind(shader)
+	gl.ActiveTexture(gl.TEXTURE0 + ssrSceneUnit)
+	gl.BindTexture(gl.TEXTURE_2D, target.texture)
+	gl.ActiveTexture(gl.TEXTURE0 + ssrHiZUnit)
+	gl.BindTexture(gl.TEXTURE_2D, s.hiZ)
+	gl.ActiveTexture(gl.TEXTURE0)
+	shader.SetInt("sceneColor", ssrSceneUnit)
+	shader.SetInt("hiZ", ssrHiZUnit)
+	shader.SetMat4("viewProjection", viewProjection)
+	shader.SetMat4("inverseViewProjection", inverseViewProjection)
+	shader.SetVec3("viewPos", camera.Position)
+	shader.SetInt("maxSteps", rend.SSR.Quality.maxSteps())
+	shader.SetInt("maxLevel", s.levels-1)
+	shader.SetFloat("maxDistance", rend.SSR.MaxDistance)
+	shader.SetFloat("thickness", rend.SSR.Thickness)
+	shader.SetFloat("maxRoughness", rend.SSR.MaxRoughness)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	rend.lastDrawCalls++
+}
+
+// compositeSSR adds the blurred reflections, or the sky where the trace missed,
+// to the bound scene target, weighted by each surface's Fresnel term
+func (rend *OpenGLRenderer) compositeSSR(s *ssrBuffers, g *gBuffer, inverseViewProjection mgl32.Mat4, camera Camera, backgroundColor mgl32.Vec3) {
+	shader := &rend.ssrCompositeShader
+	shader.Use()
+	if shader.program == 0 {
+		return
+	}
+	g.bind(shader)
+	gl.ActiveTexture(gl.TEXTURE0 + ssrReflectionUnit)
+	gl.BindTexture(gl.TEXTURE_2D, s.reflection)
+	shader.SetInt("reflections", ssrReflectionUnit)
+	shader.SetInt("skyTexture", ssrSkyUnit)
+
+	horizon, zenith := backgroundColor, backgroundColor
+	if sky := rend.skybox; sky != nil && sky.TextureID != 0 {
+		gl.ActiveTexture(gl.TEXTURE0 + ssrSkyUnit)
+		gl.BindTexture(gl.TEXTURE_2D, sky.TextureID)
+		shader.SetInt("hasSkyTexture", 1)
+	} else {
+		shader.SetInt("hasSkyTexture", 0)
+		if sky != nil && sky.IsProcedural() {
+			horizon = sky.Atmosphere.SkyColor(mgl32.Vec3{0, 0.05, 1}.Normalize(), sky.SunDirection)
+			zenith = sky.Atmosphere.SkyColor(mgl32.Vec3{0, 1, 0}, sky.SunDirection)
+		}
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+	shader.SetVec3("skyHorizon", horizon)
+	shader.SetVec3("skyZenith", zenith)
+	shader.SetMat4("inverseViewProjection", inverseViewProjection)
+	shader.SetVec3("viewPos", camera.Position)
+	shader.SetFloat("intensity", rend.SSR.Intensity)
+	shader.SetFloat("maxRoughness", rend.SSR.MaxRoughness)
+	shader.SetFloat("blurLevels", float32(min(s.levels-1, ssrMaxBlurLevels)))
+
+	// Only surfaces left visible by the opaque pass take reflections
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthFunc(gl.LEQUAL)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	rend.lastDrawCalls++
+}
+
+// hiZFragmentShaderSource writes one pyramid level from the level above,
+// keeping the nearest depth. Odd sizes fold a third row or column in.
+const hiZFragmentShaderSource = `#version 410 core
+out float nearestDepth;
+
+uniform sampler2D source; // Only the level above is in range
+uniform bool firstLevel;  // Copying the full-size depth
+
+void main() {
+    ivec2 sourceSize = textureSize(source, 0);
+    ivec2 destSize = firstLevel ? sourceSize : max(sourceSize / 2, ivec2(1));
+    ivec2 texel = ivec2(gl_FragCoord.xy);
+    ivec2 lo = texel * sourceSize / destSize;
+    ivec2 hi = min(((texel + 1) * sourceSize + destSize - 1) / destSize, sourceSize);
+    float depth = 1.0;
+    for (int y = lo.y; y < hi.y; y++) {
+        for (int x = lo.x; x < hi.x; x++) {
+            depth = min(depth, texelFetch(source, ivec2(x, y), 0).r);
+        }
+    }
+    nearestDepth = depth;
+}
+`
+
+// ssrTraceFragmentShaderSource follows reflected rays in screen space, where
+// depth changes linearly along a ray. Empty pyramid cells are skipped whole
+// and the ray climbs a level; cells it may hit are refined a level at a time.
+const ssrTraceFragmentShaderSource = `#version 410 core
+in vec2 TexCoords;
+out vec4 FragColor; // Hit color times confidence, and confidence
+
+uniform sampler2D gNormal;
+uniform sampler2D gSurface;
+uniform sampler2D gDepth;
+uniform sampler2D hiZ;
+uniform sampler2D sceneColor;
+uniform mat4 viewProjection;
+uniform mat4 inverseViewProjection;
+uniform vec3 viewPos;
+uniform int maxSteps;
+uniform int maxLevel;
+uniform float maxDistance;
+uniform float thickness;
+uniform float maxRoughness;
+
+#include "lighting.glsl"
+
+vec2 cellCount(int level) {
+    return vec2(textureSize(hiZ, level));
+}
+
+vec2 cellOf(vec2 uv, vec2 count) {
+    return floor(uv * count);
+}
+
+// crossCell moves the ray to where it leaves its cell, nudged into the next one
+vec3 crossCell(vec3 ray, vec3 dir, vec2 cell, vec2 count, vec2 crossStep, vec2 crossOffset) {
+    vec2 t = ((cell + crossStep) / count - ray.xy) / dir.xy;
+    vec3 next = ray + dir * min(t.x, t.y);
+    next.xy += t.x < t.y ? vec2(crossOffset.x, 0.0) : vec2(0.0, crossOffset.y);
+    return next;
+}
+
+void main() {
+    FragColor = vec4(0.0);
+    float depth = texture(gDepth, TexCoords).r;
+    vec4 surface = texture(gSurface, TexCoords);
+    if (depth >= 1.0 || surface.b > 0.5 || surface.g > maxRoughness) {
+        return;
+    }
+    vec3 P = worldPosition(TexCoords, depth, inverseViewProjection);
+    vec3 N = normalize(texture(gNormal, TexCoords).xyz);
+    vec3 V = normalize(P - viewPos);
+    vec3 R = reflect(V, N);
+
+    // Screen-space ray from the pixel to the far end, cut short before the camera plane
+    vec4 startClip = viewProjection * vec4(P, 1.0);
+    vec4 endClip = viewProjection * vec4(P + R * maxDistance, 1.0);
+    if (endClip.w < 0.01) {
+        endClip = mix(startClip, endClip, (startClip.w - 0.01) / (startClip.w - endClip.w));
+    }
+    vec3 start = vec3(TexCoords, depth);
+    vec3 dir = endClip.xyz / endClip.w * 0.5 + 0.5 - start;
+    float lengthSquared = dot(dir.xy, dir.xy);
+    if (lengthSquared < 1e-10) {
+        return;
+    }
+    dir.xy = mix(dir.xy, vec2(1e-7), lessThan(abs(dir.xy), vec2(1e-7))); // Keep cell crossings finite
+
+    vec2 crossStep = step(0.0, dir.xy);
+    vec2 crossOffset = sign(dir.xy) * 0.1 / cellCount(0);
+    vec3 ray = crossCell(start, dir, cellOf(start.xy, cellCount(0)), cellCount(0), crossStep, crossOffset);
+    float travel = 0.0;
+    int level = 0;
+    for (int i = 0; i < maxSteps && level >= 0; i++) {
+        travel = dot(ray.xy - start.xy, dir.xy) / lengthSquared;
+        if (any(lessThan(ray.xy, vec2(0.0))) || any(greaterThanEqual(ray.xy, vec2(1.0))) || travel > 1.0) {
+            return;
+        }
+        vec2 count = cellCount(level);
+        vec2 cell = cellOf(ray.xy, count);
+        float nearest = texelFetch(hiZ, ivec2(cell), level).r;
+        vec3 next = ray;
+        if (dir.z > 0.0) {
+            // Moving away: jump to the cell's nearest depth unless the cell ends first
+            if (ray.z < nearest) {
+                next = ray + dir * ((nearest - ray.z) / dir.z);
+            }
+            if (cellOf(next.xy, count) != cell) {
+                next = crossCell(ray, dir, cell, count, crossStep, crossOffset);
+                level = min(maxLevel, level + 2);
+            }
+        } else if (ray.z < nearest) {
+            // Moving closer while in front of everything in the cell
+            next = crossCell(ray, dir, cell, count, crossStep, crossOffset);
+            level = min(maxLevel, level + 2);
+        }
+        ray = next;
+        level--;
+    }
+    if (level >= 0) {
+        return; // Out of steps
+    }
+
+    // The ray is behind the depth it reached; call it a hit only within the thickness
+    float hitDepth = texelFetch(hiZ, ivec2(cellOf(ray.xy, cellCount(0))), 0).r;
+    if (hitDepth >= 1.0) {
+        return;
+    }
+    vec3 hitPos = worldPosition(ray.xy, hitDepth, inverseViewProjection);
+    if (distance(hitPos, worldPosition(ray.xy, ray.z, inverseViewProjection)) > thickness) {
+        return;
+    }
+    if (dot(texture(gNormal, ray.xy).xyz, R) > 0.0) {
+        return; // Back of a surface
+    }
+
+    // Fade toward the screen edges, the end of the ray and rays turning back at the camera
+    vec2 edge = smoothstep(0.0, 0.1, ray.xy) * (1.0 - smoothstep(0.9, 1.0, ray.xy));
+    float confidence = edge.x * edge.y * (1.0 - smoothstep(0.6, 1.0, travel));
+    confidence *= 1.0 - smoothstep(0.25, 0.75, dot(R, -V));
+    FragColor = vec4(texture(sceneColor, ray.xy).rgb * confidence, confidence);
+}
+`
+
+// ssrCompositeFragmentShaderSource blends traced reflections over the sky by
+// their confidence and adds them to the scene. Rougher surfaces read blurrier
+// mips and fade out toward maxRoughness.
+const ssrCompositeFragmentShaderSource = `#version 410 core
+in vec2 TexCoords;
+out vec4 FragColor;
+
+uniform sampler2D gAlbedo;
+uniform sampler2D gNormal;
+uniform sampler2D gSurface;
+uniform sampler2D gDepth;
+uniform sampler2D reflections;
+uniform sampler2D skyTexture; // Equirectangular, as the skybox draws it
+uniform int hasSkyTexture;
+uniform vec3 skyHorizon;      // Sky colors used without a sky texture
+uniform vec3 skyZenith;
+uniform mat4 inverseViewProjection;
+uniform vec3 viewPos;
+uniform float intensity;
+uniform float maxRoughness;
+uniform float blurLevels;
+
+#include "lighting.glsl"
+#include "color_space.glsl"
+
+vec3 environment(vec3 dir, float lod) {
+    if (hasSkyTexture == 1) {
+        vec2 uv = vec2((atan(dir.z, dir.x) + PI) / (2.0 * PI), (asin(clamp(dir.y, -1.0, 1.0)) + 0.5 * PI) / PI);
+        return linearToSRGB(textureLod(skyTexture, uv, lod).rgb);
+    }
+    return mix(skyHorizon, skyZenith, sqrt(clamp(dir.y, 0.0, 1.0)));
+}
+
+void main() {
+    float depth = texture(gDepth, TexCoords).r;
+    vec4 surface = texture(gSurface, TexCoords);
+    if (depth >= 1.0 || surface.b > 0.5 || surface.g > maxRoughness) {
+        discard;
+    }
+    // Slightly nearer than the surface so the opaque pass's depth lets it through
+    gl_FragDepth = max(depth - 1e-6, 0.0);
+
+    float roughness = surface.g;
+    vec3 P = worldPosition(TexCoords, depth, inverseViewProjection);
+    vec3 N = normalize(texture(gNormal, TexCoords).xyz);
+    vec3 V = normalize(viewPos - P);
+    vec3 R = reflect(-V, N);
+
+    float lod = roughness / max(maxRoughness, 0.001) * blurLevels;
+    vec4 traced = textureLod(reflections, TexCoords, lod);
+    vec3 hitColor = traced.a > 0.0001 ? traced.rgb / traced.a : vec3(0.0);
+    vec3 reflected = mix(environment(R, lod), hitColor, clamp(traced.a, 0.0, 1.0));
+
+    vec3 albedo = texture(gAlbedo, TexCoords).rgb;
+    vec3 F = fresnelSchlick(max(dot(N, V), 0.0), surfaceF0(albedo, surface.r));
+    float fade = (1.0 - smoothstep(0.5 * maxRoughness, maxRoughness, roughness)) * (1.0 - roughness);
+    FragColor = vec4(reflected * F * fade * intensity, 1.0);
+}
+`
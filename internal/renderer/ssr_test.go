@@ -0,0 +1,45 @@
+package renderer
+
+import "testing"
+
+func TestHiZLevels(t *testing.T) {
+	for _, c := range []struct{ width, height, want int32 }{
+		{1, 1, 1},
+		{2, 1, 2},
+		{1920, 1080, 11},
+		{1024, 1024, 11},
+		{5, 600, 10},
+	} {
+		if got := hiZLevels(c.width, c.height); got != c.want {
+			t.Errorf("hiZLevels(%d, %d) = %d, want %d", c.width, c.height, got, c.want)
+		}
+		// The last level must be a single texel
+		last := hiZLevels(c.width, c.height) - 1
+		if max(c.width>>last, 1) != 1 || max(c.height>>last, 1) != 1 {
+			t.Errorf("%dx%d pyramid doesn't end at one texel", c.width, c.height)
+		}
+	}
+}
+
+func TestSSRQualitySteps(t *testing.T) {
+	low, medium, high := SSRQualityLow.maxSteps(), SSRQualityMedium.maxSteps(), SSRQualityHigh.maxSteps()
+	if low >= medium || medium >= high {
+		t.Errorf("steps should grow with quality, got %d, %d, %d", low, medium, high)
+	}
+	if got := SSRQuality("").maxSteps(); got != medium {
+		t.Errorf("unknown quality should fall back to medium, got %d", got)
+	}
+}
+
+func TestAdvancedRenderingConfigSSR(t *testing.T) {
+	settings := DefaultAdvancedRenderingConfig().SSRSettings()
+	if settings != DefaultSSRSettings() {
+		t.Errorf("default config should match DefaultSSRSettings, got %+v", settings)
+	}
+	if high := HighQualityRenderingConfig(); !high.EnableSSR || high.SSRQuality != SSRQualityHigh {
+		t.Errorf("high quality should trace reflections at high quality, got %v %q", high.EnableSSR, high.SSRQuality)
+	}
+	if PerformanceRenderingConfig().EnableSSR {
+		t.Error("performance config should leave reflections off")
+	}
+}
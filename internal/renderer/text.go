@@ -0,0 +1,325 @@
+package renderer
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// TextSpace says whether a text is a screen label or lives in the world
+type TextSpace string
+
+const (
+	TextScreen TextSpace = "screen" // Position is in pixels from the viewport's top-left
+	TextWorld  TextSpace = "world"  // Position is a world point, Size is in world units
+)
+
+// Text is a block of text drawn by the renderer. Its top edge sits on Position;
+// Align places each line horizontally around it.
+type Text struct {
+	Name    string
+	Content string
+	Font    *Font // Nil uses DefaultFont
+	Size    float32
+	Color   [4]float32
+
+	Align       TextAlign
+	LineSpacing float32
+	MaxWidth    float32
+
+	Space     TextSpace
+	Position  mgl32.Vec3
+	Rotation  mgl32.Quat // Orientation of world text that is not a billboard
+	Billboard bool       // World text turns to face the camera
+	Hidden    bool
+
+	vao, vbo    uint32
+	vertexCount int32
+	laidOut     textLayoutKey
+	width       float32
+	height      float32
+}
+
+// textLayoutKey holds everything a text's vertices depend on
+type textLayoutKey struct {
+	content     string
+	font        *Font
+	layout      TextLayout
+	atlasHeight int // Glyphs never move, but UVs change when the atlas grows
+}
+
+// NewText creates a white screen label in the default font
+func NewText(content string) *Text {
+	return &Text{
+		Content:  content,
+		Size:     32,
+		Color:    [4]float32{1, 1, 1, 1},
+		Align:    AlignLeft,
+		Space:    TextScreen,
+		Rotation: mgl32.QuatIdent(),
+	}
+}
+
+// font returns the text's font, falling back to the built-in one
+func (t *Text) font() *Font {
+	if t.Font != nil {
+		return t.Font
+	}
+	return DefaultFont()
+}
+
+// Bounds returns the width and height of the text as last drawn
+func (t *Text) Bounds() (float32, float32) {
+	return t.width, t.height
+}
+
+// AddText starts drawing a text every frame
+func (rend *OpenGLRenderer) AddText(t *Text) {
+	rend.texts = append(rend.texts, t)
+}
+
+// RemoveText stops drawing a text and frees its buffers
+func (rend *OpenGLRenderer) RemoveText(t *Text) {
+	for i, existing := range rend.texts {
+		if existing == t {
+			rend.texts = append(rend.texts[:i], rend.texts[i+1:]...)
+			break
+		}
+	}
+	t.deleteBuffers()
+}
+
+// FindText returns the first text with the given name, or nil
+func (rend *OpenGLRenderer) FindText(name string) *Text {
+	for _, t := range rend.texts {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Texts returns the texts being drawn
+func (rend *OpenGLRenderer) Texts() []*Text {
+	return rend.texts
+}
+
+// update lays the text out again if anything it depends on changed
+func (t *Text) update() {
+	f := t.font()
+	key := textLayoutKey{
+		content: t.Content,
+		font:    f,
+		layout:  TextLayout{Size: t.Size, Align: t.Align, LineSpacing: t.LineSpacing, MaxWidth: t.MaxWidth},
+	}
+	key.atlasHeight = f.atlas.height
+	if t.vao != 0 && key == t.laidOut {
+		return
+	}
+
+	quads, width, height := f.Layout(t.Content, key.layout)
+	key.atlasHeight = f.atlas.height // Layout may have grown the atlas
+	t.laidOut, t.width, t.height = key, width, height
+
+	vertices := make([]float32, 0, len(quads)*6*4)
+	for _, q := range quads {
+		vertices = append(vertices,
+			q.X0, q.Y0, q.U0, q.V0,
+			q.X1, q.Y0, q.U1, q.V0,
+			q.X1, q.Y1, q.U1, q.V1,
+			q.X0, q.Y0, q.U0, q.V0,
+			q.X1, q.Y1, q.U1, q.V1,
+			q.X0, q.Y1, q.U0, q.V1)
+	}
+	if t.vao == 0 {
+		gl.GenVertexArrays(1, &t.vao)
+		gl.GenBuffers(1, &t.vbo)
+		gl.BindVertexArray(t.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+		gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(0))
+		gl.EnableVertexAttribArray(0)
+		gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(2*4))
+		gl.EnableVertexAttribArray(1)
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	if len(vertices) > 0 {
+		gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+	}
+	t.vertexCount = int32(len(vertices) / 4)
+}
+
+func (t *Text) deleteBuffers() {
+	if t.vao != 0 {
+		gl.DeleteVertexArrays(1, &t.vao)
+		gl.DeleteBuffers(1, &t.vbo)
+		t.vao, t.vbo = 0, 0
+	}
+}
+
+// texture uploads the atlas if glyphs were added since the last upload
+func (a *glyphAtlas) texture() uint32 {
+	if a.textureID != 0 && a.textureVersion == a.version {
+		return a.textureID
+	}
+	if a.textureID == 0 {
+		gl.GenTextures(1, &a.textureID)
+		gl.BindTexture(gl.TEXTURE_2D, a.textureID)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, a.textureID)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, fontAtlasWidth, int32(a.height), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(a.pixels))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	a.textureVersion = a.version
+	return a.textureID
+}
+
+func (a *glyphAtlas) deleteTexture() {
+	if a.textureID != 0 {
+		gl.DeleteTextures(1, &a.textureID)
+		a.textureID = 0
+	}
+}
+
+// deleteFontTextures frees the atlases of every loaded font
+func deleteFontTextures() {
+	fontCacheMu.Lock()
+	defer fontCacheMu.Unlock()
+	for _, f := range fontCache {
+		f.atlas.deleteTexture()
+	}
+	if defaultFont != nil {
+		defaultFont.atlas.deleteTexture()
+	}
+}
+
+// renderTexts draws the texts in one space. World text is depth tested against
+// the scene; screen labels go on top of everything in the viewport.
+func (rend *OpenGLRenderer) renderTexts(space TextSpace, camera Camera, viewport [4]int32) {
+	if len(rend.texts) == 0 {
+		return
+	}
+	shader := rend.textShaderProgram()
+
+	var viewProjection mgl32.Mat4
+	if space == TextScreen {
+		viewProjection = mgl32.Ortho(0, float32(viewport[2]), float32(viewport[3]), 0, -1, 1)
+	} else {
+		viewProjection = camera.GetViewProjection()
+	}
+	view := camera.GetViewMatrix()
+	cameraRight := mgl32.Vec3{view.At(0, 0), view.At(0, 1), view.At(0, 2)}
+	cameraUp := mgl32.Vec3{view.At(1, 0), view.At(1, 1), view.At(1, 2)}
+
+	bound := false
+	for _, t := range rend.texts {
+		if t.Hidden || t.Space != space || t.Content == "" {
+			continue
+		}
+		t.update()
+		if t.vertexCount == 0 {
+			continue
+		}
+		if !bound {
+			shader.Use()
+			if shader.program == 0 {
+				return
+			}
+			rend.currentShaderProgram = shader.program
+			shader.SetMat4("viewProjection", viewProjection)
+			shader.SetInt("atlas", 0)
+			gl.Enable(gl.BLEND)
+			gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+			rend.setFaceCulling(false)
+			if space == TextScreen {
+				rend.setDepthTest(false)
+			}
+			gl.DepthMask(false)
+			bound = true
+		}
+
+		// Layouts are y up; the screen's y axis points down
+		axisX, axisY := mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, -1, 0}
+		if space == TextWorld {
+			if t.Billboard {
+				axisX, axisY = cameraRight, cameraUp
+			} else if t.Rotation != (mgl32.Quat{}) {
+				axisX, axisY = t.Rotation.Rotate(mgl32.Vec3{1, 0, 0}), t.Rotation.Rotate(mgl32.Vec3{0, 1, 0})
+			} else {
+				axisY = mgl32.Vec3{0, 1, 0}
+			}
+		}
+		t.draw(shader, axisX, axisY)
+		rend.lastDrawCalls++
+	}
+	if !bound {
+		return
+	}
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	rend.setDepthTest(DepthTestEnabled)
+	rend.setFaceCulling(FaceCullingEnabled)
+}
+
+// draw issues the text's draw call; the text shader must be bound
+func (t *Text) draw(shader *Shader, axisX, axisY mgl32.Vec3) {
+	shader.SetVec3("origin", t.Position)
+	shader.SetVec3("axisX", axisX)
+	shader.SetVec3("axisY", axisY)
+	shader.SetVec4("textColor", mgl32.Vec4(t.Color))
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, t.font().atlas.texture())
+	gl.BindVertexArray(t.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, t.vertexCount)
+}
+
+// textShaderProgram builds the text shader on first use
+func (rend *OpenGLRenderer) textShaderProgram() *Shader {
+	if rend.textShader.Name == "" {
+		rend.textShader = NewShaderFromFiles("text", "text.vert", "text.frag")
+	}
+	return &rend.textShader
+}
+
+const textVertexShaderSource = `#version 330 core
+
+layout(location = 0) in vec2 inPosition; // Layout space, y up
+layout(location = 1) in vec2 inTexCoord;
+
+uniform mat4 viewProjection;
+uniform vec3 origin;
+uniform vec3 axisX;
+uniform vec3 axisY;
+
+out vec2 fragTexCoord;
+
+void main() {
+    fragTexCoord = inTexCoord;
+    vec3 position = origin + axisX * inPosition.x + axisY * inPosition.y;
+    gl_Position = viewProjection * vec4(position, 1.0);
+}
+`
+
+const textFragmentShaderSource = `#version 330 core
+
+in vec2 fragTexCoord;
+
+uniform sampler2D atlas;
+uniform vec4 textColor;
+
+out vec4 FragColor;
+
+void main() {
+    // The outline sits at 0.5; fwidth keeps the edge one pixel wide at any scale
+    float distance = texture(atlas, fragTexCoord).r;
+    float edge = max(fwidth(distance), 1e-4);
+    float coverage = smoothstep(0.5 - edge, 0.5 + edge, distance);
+    if (coverage <= 0.0) {
+        discard;
+    }
+    FragColor = vec4(textColor.rgb, textColor.a * coverage);
+}
+`
@@ -0,0 +1,385 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+)
+
+// TextureFormat is the pixel format of a texture stored in a KTX2 or DDS file
+type TextureFormat int
+
+const (
+	FormatRGBA8 TextureFormat = iota // Uncompressed 8-bit RGBA
+	FormatBC1                        // DXT1, opaque
+	FormatBC1A                       // DXT1 with 1-bit alpha
+	FormatBC2                        // DXT3
+	FormatBC3                        // DXT5
+	FormatBC4                        // RGTC1, single channel
+	FormatBC4Signed
+	FormatBC5 // RGTC2, two channels (normal maps)
+	FormatBC5Signed
+	FormatBC6H // BPTC HDR
+	FormatBC6HSigned
+	FormatBC7 // BPTC
+)
+
+var textureFormatNames = map[TextureFormat]string{
+	FormatRGBA8: "RGBA8", FormatBC1: "BC1", FormatBC1A: "BC1A", FormatBC2: "BC2", FormatBC3: "BC3",
+	FormatBC4: "BC4", FormatBC4Signed: "BC4_SNORM", FormatBC5: "BC5", FormatBC5Signed: "BC5_SNORM",
+	FormatBC6H: "BC6H", FormatBC6HSigned: "BC6H_SF16", FormatBC7: "BC7",
+}
+
+func (f TextureFormat) String() string {
+	if name, ok := textureFormatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("TextureFormat(%d)", int(f))
+}
+
+// blockBytes returns the size of a 4x4 block, or 0 for uncompressed formats
+func (f TextureFormat) blockBytes() int {
+	switch f {
+	case FormatBC1, FormatBC1A, FormatBC4, FormatBC4Signed:
+		return 8
+	case FormatRGBA8:
+		return 0
+	}
+	return 16
+}
+
+// levelSize returns the byte size of a width x height image in this format
+func (f TextureFormat) levelSize(width, height int) int {
+	if f == FormatRGBA8 {
+		return width * height * 4
+	}
+	return ((width + 3) / 4) * ((height + 3) / 4) * f.blockBytes()
+}
+
+// cpuDecodable returns true if decodeTextureLevel can expand the format to RGBA
+func (f TextureFormat) cpuDecodable() bool {
+	switch f {
+	case FormatRGBA8, FormatBC1, FormatBC1A, FormatBC2, FormatBC3, FormatBC4, FormatBC5:
+		return true
+	}
+	return false
+}
+
+// textureLevel is one mip level of a texture file
+type textureLevel struct {
+	width, height int
+	data          []byte
+}
+
+// textureFile is a texture read from a KTX2 or DDS container, largest level first
+type textureFile struct {
+	format       TextureFormat
+	srgb         bool // Color data is sRGB encoded
+	levels       []textureLevel
+	generateMips bool // The file asks for mipmaps to be generated at load
+}
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// ktx2Formats maps the VkFormat values we can load
+var ktx2Formats = map[uint32]struct {
+	format TextureFormat
+	srgb   bool
+}{
+	37: {FormatRGBA8, false}, 43: {FormatRGBA8, true},
+	131: {FormatBC1, false}, 132: {FormatBC1, true},
+	133: {FormatBC1A, false}, 134: {FormatBC1A, true},
+	135: {FormatBC2, false}, 136: {FormatBC2, true},
+	137: {FormatBC3, false}, 138: {FormatBC3, true},
+	139: {FormatBC4, false}, 140: {FormatBC4Signed, false},
+	141: {FormatBC5, false}, 142: {FormatBC5Signed, false},
+	143: {FormatBC6H, false}, 144: {FormatBC6HSigned, false},
+	145: {FormatBC7, false}, 146: {FormatBC7, true},
+}
+
+// parseKTX2 reads a 2D, non-supercompressed KTX2 file
+func parseKTX2(data []byte) (*textureFile, error) {
+	const headerSize = 80
+	if len(data) < headerSize || !bytes.Equal(data[:12], ktx2Identifier) {
+		return nil, fmt.Errorf("not a KTX2 file")
+	}
+	u32 := func(offset int) uint32 { return binary.LittleEndian.Uint32(data[offset:]) }
+
+	vkFormat := u32(12)
+	width, height, depth := int(u32(20)), int(u32(24)), u32(28)
+	layers, faces, levelCount := u32(32), u32(36), int(u32(40))
+	if scheme := u32(44); scheme != 0 {
+		return nil, fmt.Errorf("KTX2 supercompression scheme %d is not supported", scheme)
+	}
+	if depth > 1 || layers > 1 || faces != 1 {
+		return nil, fmt.Errorf("only 2D KTX2 textures are supported")
+	}
+	format, ok := ktx2Formats[vkFormat]
+	if !ok {
+		return nil, fmt.Errorf("unsupported KTX2 format (VkFormat %d)", vkFormat)
+	}
+
+	file := &textureFile{format: format.format, srgb: format.srgb}
+	if levelCount == 0 {
+		levelCount = 1
+		file.generateMips = true
+	}
+	if len(data) < headerSize+levelCount*24 {
+		return nil, fmt.Errorf("truncated KTX2 level index")
+	}
+
+	for i := 0; i < levelCount; i++ {
+		entry := headerSize + i*24
+		offset := binary.LittleEndian.Uint64(data[entry:])
+		length := binary.LittleEndian.Uint64(data[entry+8:])
+		w, h := mipDimension(width, i), mipDimension(height, i)
+		if offset+length > uint64(len(data)) || length < uint64(format.format.levelSize(w, h)) {
+			return nil, fmt.Errorf("KTX2 level %d is truncated", i)
+		}
+		file.levels = append(file.levels, textureLevel{width: w, height: h, data: data[offset : offset+length]})
+	}
+	return file, nil
+}
+
+// DDS header flags
+const (
+	ddsMipmapCount = 0x20000
+	ddsAlphaPixels = 0x1
+	ddsFourCC      = 0x4
+	ddsRGB         = 0x40
+	ddsCubemap     = 0x200
+)
+
+var ddsFourCCFormats = map[string]TextureFormat{
+	"DXT1": FormatBC1, "DXT2": FormatBC2, "DXT3": FormatBC2, "DXT4": FormatBC3, "DXT5": FormatBC3,
+	"ATI1": FormatBC4, "BC4U": FormatBC4, "BC4S": FormatBC4Signed,
+	"ATI2": FormatBC5, "BC5U": FormatBC5, "BC5S": FormatBC5Signed,
+}
+
+var ddsDXGIFormats = map[uint32]struct {
+	format TextureFormat
+	srgb   bool
+}{
+	28: {FormatRGBA8, false}, 29: {FormatRGBA8, true},
+	71: {FormatBC1, false}, 72: {FormatBC1, true},
+	74: {FormatBC2, false}, 75: {FormatBC2, true},
+	77: {FormatBC3, false}, 78: {FormatBC3, true},
+	80: {FormatBC4, false}, 81: {FormatBC4Signed, false},
+	83: {FormatBC5, false}, 84: {FormatBC5Signed, false},
+	95: {FormatBC6H, false}, 96: {FormatBC6HSigned, false},
+	98: {FormatBC7, false}, 99: {FormatBC7, true},
+}
+
+// parseDDS reads a 2D DDS file with BCn or 32-bit RGBA data, including DX10 headers
+func parseDDS(data []byte) (*textureFile, error) {
+	if len(data) < 128 || string(data[:4]) != "DDS " || binary.LittleEndian.Uint32(data[4:]) != 124 {
+		return nil, fmt.Errorf("not a DDS file")
+	}
+	u32 := func(offset int) uint32 { return binary.LittleEndian.Uint32(data[offset:]) }
+
+	flags, height, width := u32(8), int(u32(12)), int(u32(16))
+	levelCount := 1
+	if flags&ddsMipmapCount != 0 && u32(28) > 0 {
+		levelCount = int(u32(28))
+	}
+	if u32(112)&ddsCubemap != 0 {
+		return nil, fmt.Errorf("DDS cubemaps are not supported")
+	}
+
+	file := &textureFile{}
+	offset := 128
+	pixelFlags := u32(80)
+	switch {
+	case pixelFlags&ddsFourCC != 0 && string(data[84:88]) == "DX10":
+		if len(data) < 148 {
+			return nil, fmt.Errorf("truncated DDS DX10 header")
+		}
+		format, ok := ddsDXGIFormats[u32(128)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported DDS DXGI format %d", u32(128))
+		}
+		if u32(132) != 3 || u32(140) > 1 {
+			return nil, fmt.Errorf("only single 2D DDS textures are supported")
+		}
+		file.format, file.srgb = format.format, format.srgb
+		offset = 148
+	case pixelFlags&ddsFourCC != 0:
+		format, ok := ddsFourCCFormats[string(data[84:88])]
+		if !ok {
+			return nil, fmt.Errorf("unsupported DDS FourCC %q", data[84:88])
+		}
+		if format == FormatBC1 && pixelFlags&ddsAlphaPixels != 0 {
+			format = FormatBC1A
+		}
+		file.format = format
+	case pixelFlags&ddsRGB != 0 && u32(88) == 32:
+		file.format = FormatRGBA8
+	default:
+		return nil, fmt.Errorf("unsupported DDS pixel format")
+	}
+
+	// Legacy uncompressed DDS is usually BGRA
+	bgra := file.format == FormatRGBA8 && offset == 128 && u32(92) == 0x00FF0000
+
+	for i := 0; i < levelCount; i++ {
+		w, h := mipDimension(width, i), mipDimension(height, i)
+		size := file.format.levelSize(w, h)
+		if offset+size > len(data) {
+			return nil, fmt.Errorf("DDS level %d is truncated", i)
+		}
+		level := data[offset : offset+size]
+		if bgra {
+			level = swizzleBGRA(level)
+		}
+		file.levels = append(file.levels, textureLevel{width: w, height: h, data: level})
+		offset += size
+	}
+	file.generateMips = levelCount == 1
+	return file, nil
+}
+
+// mipDimension returns the size of mip level of a base dimension
+func mipDimension(base, level int) int {
+	if d := base >> level; d > 0 {
+		return d
+	}
+	return 1
+}
+
+func swizzleBGRA(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i := 0; i+3 < len(data); i += 4 {
+		out[i], out[i+1], out[i+2], out[i+3] = data[i+2], data[i+1], data[i], data[i+3]
+	}
+	return out
+}
+
+// decodeTextureLevel expands a level to RGBA on the CPU, for formats the GPU can't sample
+func decodeTextureLevel(format TextureFormat, level textureLevel) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, level.width, level.height))
+	if format == FormatRGBA8 {
+		copy(img.Pix, level.data)
+		return img, nil
+	}
+	if !format.cpuDecodable() {
+		return nil, fmt.Errorf("no CPU decoder for %s", format)
+	}
+
+	blockBytes := format.blockBytes()
+	blocksX := (level.width + 3) / 4
+	var texels [16][4]uint8
+	for by := 0; by < (level.height+3)/4; by++ {
+		for bx := 0; bx < blocksX; bx++ {
+			offset := (by*blocksX + bx) * blockBytes
+			decodeBlock(format, level.data[offset:offset+blockBytes], &texels)
+
+			for i, texel := range texels {
+				x, y := bx*4+i%4, by*4+i/4
+				if x < level.width && y < level.height {
+					copy(img.Pix[img.PixOffset(x, y):], texel[:])
+				}
+			}
+		}
+	}
+	return img, nil
+}
+
+// decodeBlock decodes a single 4x4 block into RGBA texels
+func decodeBlock(format TextureFormat, block []byte, texels *[16][4]uint8) {
+	switch format {
+	case FormatBC1, FormatBC1A:
+		decodeColorBlock(block, texels, true, format == FormatBC1A)
+	case FormatBC2:
+		decodeColorBlock(block[8:], texels, false, false)
+		for i := range texels {
+			nibble := block[i/2] >> (4 * uint(i%2)) & 0x0F
+			texels[i][3] = nibble * 17
+		}
+	case FormatBC3:
+		decodeColorBlock(block[8:], texels, false, false)
+		alpha := decodeChannelBlock(block)
+		for i := range texels {
+			texels[i][3] = alpha[i]
+		}
+	case FormatBC4:
+		red := decodeChannelBlock(block)
+		for i := range texels {
+			texels[i] = [4]uint8{red[i], 0, 0, 255}
+		}
+	case FormatBC5:
+		red, green := decodeChannelBlock(block), decodeChannelBlock(block[8:])
+		for i := range texels {
+			texels[i] = [4]uint8{red[i], green[i], 0, 255}
+		}
+	}
+}
+
+// decodeColorBlock decodes the 8-byte BC1 color block shared by BC1-BC3. Only BC1
+// uses the three-color mode; punchThrough makes its fourth color transparent.
+func decodeColorBlock(block []byte, texels *[16][4]uint8, allowThreeColor, punchThrough bool) {
+	c0 := binary.LittleEndian.Uint16(block[0:])
+	c1 := binary.LittleEndian.Uint16(block[2:])
+	indices := binary.LittleEndian.Uint32(block[4:])
+
+	var palette [4][4]uint8
+	palette[0] = rgb565(c0)
+	palette[1] = rgb565(c1)
+	if c0 > c1 || !allowThreeColor {
+		for ch := 0; ch < 3; ch++ {
+			a, b := int(palette[0][ch]), int(palette[1][ch])
+			palette[2][ch] = uint8((2*a + b) / 3)
+			palette[3][ch] = uint8((a + 2*b) / 3)
+		}
+		palette[2][3], palette[3][3] = 255, 255
+	} else {
+		for ch := 0; ch < 3; ch++ {
+			palette[2][ch] = uint8((int(palette[0][ch]) + int(palette[1][ch])) / 2)
+		}
+		palette[2][3] = 255
+		palette[3] = [4]uint8{0, 0, 0, 255}
+		if punchThrough {
+			palette[3][3] = 0
+		}
+	}
+
+	for i := range texels {
+		texels[i] = palette[indices>>(2*uint(i))&3]
+	}
+}
+
+// decodeChannelBlock decodes an 8-byte BC4 block (also BC3 alpha and BC5 channels)
+func decodeChannelBlock(block []byte) [16]uint8 {
+	r0, r1 := int(block[0]), int(block[1])
+	var palette [8]uint8
+	palette[0], palette[1] = uint8(r0), uint8(r1)
+	if r0 > r1 {
+		for i := 1; i <= 6; i++ {
+			palette[i+1] = uint8(((7-i)*r0 + i*r1) / 7)
+		}
+	} else {
+		for i := 1; i <= 4; i++ {
+			palette[i+1] = uint8(((5-i)*r0 + i*r1) / 5)
+		}
+		palette[6], palette[7] = 0, 255
+	}
+
+	// 16 3-bit indices packed little-endian into 48 bits
+	var bits uint64
+	for i := 0; i < 6; i++ {
+		bits |= uint64(block[2+i]) << (8 * uint(i))
+	}
+	var out [16]uint8
+	for i := range out {
+		out[i] = palette[bits>>(3*uint(i))&7]
+	}
+	return out
+}
+
+// rgb565 expands a packed 5:6:5 color to 8 bits per channel
+func rgb565(c uint16) [4]uint8 {
+	r := uint8(c >> 11 & 0x1F)
+	g := uint8(c >> 5 & 0x3F)
+	b := uint8(c & 0x1F)
+	return [4]uint8{r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2, 255}
+}
@@ -0,0 +1,214 @@
+package renderer
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// bc1Block builds a BC1 block from two 5:6:5 endpoints and 16 2-bit indices
+func bc1Block(c0, c1 uint16, indices [16]uint32) []byte {
+	block := make([]byte, 8)
+	binary.LittleEndian.PutUint16(block[0:], c0)
+	binary.LittleEndian.PutUint16(block[2:], c1)
+	var packed uint32
+	for i, idx := range indices {
+		packed |= idx << (2 * uint(i))
+	}
+	binary.LittleEndian.PutUint32(block[4:], packed)
+	return block
+}
+
+func TestDecodeBC1(t *testing.T) {
+	// Red and blue endpoints, c0 > c1 selects four-color mode
+	block := bc1Block(0xF800, 0x001F, [16]uint32{0, 1, 2, 3})
+	img, err := decodeTextureLevel(FormatBC1, textureLevel{width: 4, height: 4, data: block})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][4]uint8{{255, 0, 0, 255}, {0, 0, 255, 255}, {170, 0, 85, 255}, {85, 0, 170, 255}}
+	for x, w := range want {
+		c := img.RGBAAt(x, 0)
+		if got := [4]uint8{c.R, c.G, c.B, c.A}; got != w {
+			t.Errorf("Texel %d: expected %v, got %v", x, w, got)
+		}
+	}
+}
+
+func TestDecodeBC1PunchThrough(t *testing.T) {
+	// c0 <= c1 selects three-color mode; index 3 is transparent black in BC1A
+	block := bc1Block(0x001F, 0xF800, [16]uint32{3})
+	img, err := decodeTextureLevel(FormatBC1A, textureLevel{width: 4, height: 4, data: block})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := img.RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("Expected a transparent texel, got alpha %d", a)
+	}
+	if a := img.RGBAAt(1, 0).A; a != 255 {
+		t.Errorf("Expected an opaque texel, got alpha %d", a)
+	}
+}
+
+func TestDecodeBC4Palette(t *testing.T) {
+	block := []byte{255, 0, 0, 0, 0, 0, 0, 0}
+	// Index 1 selects r1, index 2 the first interpolated value
+	block[2] = 1 | 2<<3
+	red := decodeChannelBlock(block)
+	if red[0] != 0 || red[1] != 218 || red[2] != 255 {
+		t.Errorf("Expected 0, 218, 255, got %d, %d, %d", red[0], red[1], red[2])
+	}
+
+	// r0 <= r1 has explicit 0 and 255 entries
+	block = []byte{10, 20, 6 | 7<<3, 0, 0, 0, 0, 0}
+	red = decodeChannelBlock(block)
+	if red[0] != 0 || red[1] != 255 {
+		t.Errorf("Expected 0 and 255, got %d and %d", red[0], red[1])
+	}
+}
+
+func TestDecodeOddSizedLevel(t *testing.T) {
+	// A 2x2 level still occupies a full 4x4 block
+	block := bc1Block(0xFFFF, 0xFFFF, [16]uint32{})
+	img, err := decodeTextureLevel(FormatBC1, textureLevel{width: 2, height: 2, data: block})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 2 || img.RGBAAt(1, 1).R != 255 {
+		t.Errorf("Expected a white 2x2 image, got %v", img.Bounds())
+	}
+	if _, err := decodeTextureLevel(FormatBC7, textureLevel{width: 4, height: 4, data: make([]byte, 16)}); err == nil {
+		t.Error("BC7 has no CPU decoder and should return an error")
+	}
+}
+
+// buildKTX2 writes a minimal KTX2 file with a level index and tightly packed levels
+func buildKTX2(vkFormat uint32, width, height int, levels [][]byte) []byte {
+	header := make([]byte, 80+24*len(levels))
+	copy(header, ktx2Identifier)
+	binary.LittleEndian.PutUint32(header[12:], vkFormat)
+	binary.LittleEndian.PutUint32(header[20:], uint32(width))
+	binary.LittleEndian.PutUint32(header[24:], uint32(height))
+	binary.LittleEndian.PutUint32(header[36:], 1) // faces
+	binary.LittleEndian.PutUint32(header[40:], uint32(len(levels)))
+
+	data := header
+	for i, level := range levels {
+		entry := 80 + 24*i
+		binary.LittleEndian.PutUint64(data[entry:], uint64(len(data)))
+		binary.LittleEndian.PutUint64(data[entry+8:], uint64(len(level)))
+		data = append(data, level...)
+	}
+	return data
+}
+
+func TestParseKTX2(t *testing.T) {
+	// 8x8 BC1 has 4 blocks at level 0, then one block each for 4x4, 2x2 and 1x1
+	levels := [][]byte{make([]byte, 32), make([]byte, 8), make([]byte, 8), make([]byte, 8)}
+	file, err := parseKTX2(buildKTX2(132, 8, 8, levels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.format != FormatBC1 || !file.srgb {
+		t.Errorf("Expected sRGB BC1, got %s (srgb=%v)", file.format, file.srgb)
+	}
+	if len(file.levels) != 4 || file.levels[3].width != 1 || file.generateMips {
+		t.Errorf("Expected a complete 4-level chain, got %d levels", len(file.levels))
+	}
+
+	truncated := buildKTX2(131, 8, 8, [][]byte{make([]byte, 16)})
+	if _, err := parseKTX2(truncated); err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Errorf("Expected a truncated level error, got %v", err)
+	}
+	if _, err := parseKTX2(buildKTX2(9999, 4, 4, [][]byte{make([]byte, 8)})); err == nil {
+		t.Error("Expected an error for an unknown VkFormat")
+	}
+}
+
+// buildDDS writes a DDS header with a FourCC pixel format followed by data
+func buildDDS(fourCC string, width, height, mipCount int, data []byte) []byte {
+	header := make([]byte, 128)
+	copy(header, "DDS ")
+	binary.LittleEndian.PutUint32(header[4:], 124)
+	flags := uint32(0x1007)
+	if mipCount > 1 {
+		flags |= ddsMipmapCount
+	}
+	binary.LittleEndian.PutUint32(header[8:], flags)
+	binary.LittleEndian.PutUint32(header[12:], uint32(height))
+	binary.LittleEndian.PutUint32(header[16:], uint32(width))
+	binary.LittleEndian.PutUint32(header[28:], uint32(mipCount))
+	binary.LittleEndian.PutUint32(header[76:], 32)
+	binary.LittleEndian.PutUint32(header[80:], ddsFourCC)
+	copy(header[84:], fourCC)
+	return append(header, data...)
+}
+
+func TestParseDDS(t *testing.T) {
+	// 4x4 BC3 with its 2x2 and 1x1 levels, one 16-byte block each
+	file, err := parseDDS(buildDDS("DXT5", 4, 4, 3, make([]byte, 48)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.format != FormatBC3 || len(file.levels) != 3 || file.generateMips {
+		t.Errorf("Expected 3 BC3 levels, got %s with %d levels", file.format, len(file.levels))
+	}
+
+	file, err = parseDDS(buildDDS("ATI2", 8, 4, 1, make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.format != FormatBC5 || !file.generateMips {
+		t.Errorf("Expected single-level BC5 to request mip generation, got %s", file.format)
+	}
+
+	if _, err := parseDDS(buildDDS("DXT1", 8, 8, 1, make([]byte, 8))); err == nil {
+		t.Error("Expected an error for truncated DDS data")
+	}
+	if _, err := parseDDS(buildDDS("ETC2", 4, 4, 1, make([]byte, 8))); err == nil {
+		t.Error("Expected an error for an unsupported FourCC")
+	}
+}
+
+func TestSamplerSettings(t *testing.T) {
+	if got := (SamplerSettings{}).Resolved(); got != DefaultSampler {
+		t.Errorf("Zero settings should resolve to the default sampler, got %+v", got)
+	}
+	clamp := SamplerSettings{Wrap: WrapClamp}
+	if got := clamp.Resolved(); got.Wrap != WrapClamp || got.Filter != DefaultSampler.Filter {
+		t.Errorf("Expected clamp with default filtering, got %+v", got)
+	}
+
+	if key := (SamplerSettings{}).cacheKey("a.png"); key != "a.png" {
+		t.Errorf("Default settings should share the plain path cache key, got %q", key)
+	}
+	if clamp.cacheKey("a.png") == (SamplerSettings{Filter: FilterNearest}).cacheKey("a.png") {
+		t.Error("Different sampler settings must not share a cache entry")
+	}
+
+	if err := (SamplerSettings{Filter: "cubic"}).validate(); err == nil {
+		t.Error("Expected an error for an unknown filter")
+	}
+	if _, err := ParseMaterialAsset([]byte(`{"textures": [{"name": "t", "path": "t.png", "sampler": {"wrap": "border"}}]}`)); err == nil {
+		t.Error("Materials should reject unknown wrap modes")
+	}
+}
+
+func TestCompressedFormatUsesCapsSnapshot(t *testing.T) {
+	defer glCaps.Store(glCaps.Load())
+
+	// Loader goroutines must not query GL, so no snapshot means no GPU formats
+	glCaps.Store(nil)
+	if _, ok := glCompressedFormat(FormatBC1, false); ok {
+		t.Error("BC1 should be unsupported before the renderer takes the caps snapshot")
+	}
+
+	glCaps.Store(&glCapabilities{extensions: map[string]bool{"GL_EXT_texture_compression_s3tc": true}})
+	if _, ok := glCompressedFormat(FormatBC1, false); !ok {
+		t.Error("BC1 should be supported once the snapshot has S3TC")
+	}
+	if _, ok := glCompressedFormat(FormatBC1, true); ok {
+		t.Error("sRGB BC1 needs EXT_texture_sRGB as well")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"image"
 	"image/draw"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -23,10 +25,13 @@ type TextureStats struct {
 
 // TextureManager manages texture loading, caching, and lifecycle
 type TextureManager struct {
-	textureCache    map[string]uint32 // path -> OpenGL texture ID
-	textureRefCount map[uint32]int    // texture ID -> reference count
-	texturePaths    map[uint32]string // texture ID -> path (for debugging)
-	mu              sync.RWMutex      // Thread-safe operations
+	textureCache    map[string]uint32               // path -> OpenGL texture ID
+	textureRefCount map[uint32]int                  // texture ID -> reference count
+	texturePaths    map[uint32]string               // texture ID -> path (for debugging)
+	textureSizes    map[uint32]int64                // texture ID -> GPU bytes, for stats
+	pending         map[string]*AssetHandle[uint32] // cache key -> in-flight async load
+	renderTextures  map[string]*RenderTexture       // name -> camera render target
+	mu              sync.RWMutex                    // Thread-safe operations
 	stats           TextureStats
 }
 
@@ -36,17 +41,32 @@ func NewTextureManager() *TextureManager {
 		textureCache:    make(map[string]uint32),
 		textureRefCount: make(map[uint32]int),
 		texturePaths:    make(map[uint32]string),
+		textureSizes:    make(map[uint32]int64),
+		pending:         make(map[string]*AssetHandle[uint32]),
+		renderTextures:  make(map[string]*RenderTexture),
 	}
 }
 
 // LoadTexture loads a texture from file or returns cached texture ID
 // Automatically increments reference count
 func (tm *TextureManager) LoadTexture(filePath string) (uint32, error) {
+	return tm.LoadTextureWithSampler(filePath, SamplerSettings{})
+}
+
+// LoadTextureWithSampler loads a texture with explicit wrap/filter settings.
+// The same file loaded with different settings gets its own texture object.
+func (tm *TextureManager) LoadTextureWithSampler(filePath string, sampler SamplerSettings) (uint32, error) {
+	if name, ok := RenderTextureName(filePath); ok {
+		return tm.renderTextureReference(name), nil
+	}
+
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
+	cacheKey := sampler.cacheKey(filePath)
+
 	// Check if texture is already cached
-	if textureID, exists := tm.textureCache[filePath]; exists {
+	if textureID, exists := tm.textureCache[cacheKey]; exists {
 		// Increment reference count
 		tm.textureRefCount[textureID]++
 		tm.stats.CacheHits++
@@ -62,50 +82,228 @@ func (tm *TextureManager) LoadTexture(filePath string) (uint32, error) {
 	// Cache miss - load texture from disk
 	tm.stats.CacheMisses++
 
-	imgFile, err := os.Open(filePath)
+	pending, err := readTexture(filePath, sampler)
 	if err != nil {
 		return 0, err
 	}
+	textureID, size := pending.upload(sampler)
+	tm.addToCache(cacheKey, textureID, size)
+
+	logger.Log.Info("Texture loaded and cached",
+		zap.String("path", filePath),
+		zap.Uint32("textureID", textureID),
+		zap.Int64("bytes", size))
+
+	return textureID, nil
+}
+
+// LoadTextureAsync reads and decodes a texture on the asset pool and uploads it
+// through uploads. The handle resolves to a texture ID holding one reference.
+func (tm *TextureManager) LoadTextureAsync(filePath string, sampler SamplerSettings, uploads *UploadQueue) *AssetHandle[uint32] {
+	if name, ok := RenderTextureName(filePath); ok {
+		return ReadyAssetHandle(filePath, tm.renderTextureReference(name))
+	}
+	cacheKey := sampler.cacheKey(filePath)
+
+	tm.mu.Lock()
+	if textureID, exists := tm.textureCache[cacheKey]; exists {
+		tm.textureRefCount[textureID]++
+		tm.stats.CacheHits++
+		tm.mu.Unlock()
+		return ReadyAssetHandle(filePath, textureID)
+	}
+	if inFlight, loading := tm.pending[cacheKey]; loading {
+		tm.mu.Unlock()
+		// Share the decode, but give this caller its own reference
+		handle := NewAssetHandle[uint32](filePath, uploads)
+		inFlight.OnReady(func(textureID uint32, err error) {
+			if err == nil {
+				tm.AddReference(textureID)
+			}
+			handle.Complete(textureID, err)
+		})
+		return handle
+	}
+	handle := NewAssetHandle[uint32](filePath, uploads)
+	tm.pending[cacheKey] = handle
+	tm.stats.CacheMisses++
+	tm.mu.Unlock()
+
+	finish := func(textureID uint32, err error) {
+		tm.mu.Lock()
+		delete(tm.pending, cacheKey)
+		tm.mu.Unlock()
+		handle.Complete(textureID, err)
+	}
+
+	AssetPool().Submit(func() {
+		pending, err := readTexture(filePath, sampler)
+		if err != nil {
+			uploads.Push(func() { finish(0, err) })
+			return
+		}
+		handle.setState(AssetUploading)
+		handle.SetProgress(0.8)
+		uploads.Push(func() {
+			textureID, size := pending.upload(sampler)
+			tm.mu.Lock()
+			// A synchronous load of the same key may have finished meanwhile
+			textureID = tm.addToCache(cacheKey, textureID, size)
+			tm.mu.Unlock()
+			finish(textureID, nil)
+		})
+	})
+	return handle
+}
+
+// addToCache registers a freshly uploaded texture with one reference and returns
+// its ID. If the key is already cached, the duplicate is deleted and the cached
+// texture gains the reference instead. Caller holds tm.mu.
+func (tm *TextureManager) addToCache(cacheKey string, textureID uint32, size int64) uint32 {
+	if existing, ok := tm.textureCache[cacheKey]; ok && existing != textureID {
+		gl.DeleteTextures(1, &textureID)
+		tm.textureRefCount[existing]++
+		return existing
+	}
+	tm.textureCache[cacheKey] = textureID
+	tm.textureRefCount[textureID] = 1
+	tm.texturePaths[textureID] = cacheKey
+	tm.textureSizes[textureID] = size
+	tm.stats.TotalTextures++
+	tm.stats.ActiveTextures++
+	return textureID
+}
+
+// decodedTexture is texture data prepared on the CPU, ready for a GL upload
+type decodedTexture struct {
+	rgba     *image.RGBA   // Plain images
+	file     *textureFile  // KTX2/DDS containers
+	levels   []*image.RGBA // Container levels decoded on the CPU when the GPU can't sample them
+	glFormat uint32        // Compressed internal format when uploading file levels directly
+}
+
+// readTexture loads and decodes a texture file without touching GL, so it can run
+// on a worker. PNG/JPEG get a generated mip chain at upload; KTX2/DDS keep their own.
+func readTexture(filePath string, sampler SamplerSettings) (*decodedTexture, error) {
+	if ext := strings.ToLower(filepath.Ext(filePath)); ext != ".ktx2" && ext != ".dds" {
+		rgba, err := decodeImageFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		return &decodedTexture{rgba: rgba}, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var file *textureFile
+	if strings.EqualFold(filepath.Ext(filePath), ".dds") {
+		file, err = parseDDS(data)
+	} else {
+		file, err = parseKTX2(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(filePath), err)
+	}
+	if sampler.NoMipmaps {
+		file.levels = file.levels[:1]
+	}
+
+	decoded := &decodedTexture{file: file}
+	if glFormat, gpuSupported := glCompressedFormat(file.format, sampler.isSRGB()); gpuSupported && file.format != FormatRGBA8 {
+		decoded.glFormat = glFormat
+		return decoded, nil
+	}
+	if !file.format.cpuDecodable() {
+		return nil, fmt.Errorf("%s: %s textures are not supported by this GPU", filepath.Base(filePath), file.format)
+	}
+	for _, level := range file.levels {
+		rgba, err := decodeTextureLevel(file.format, level)
+		if err != nil {
+			return nil, err
+		}
+		decoded.levels = append(decoded.levels, rgba)
+	}
+	return decoded, nil
+}
+
+// decodeImageFile decodes a PNG/JPEG into tightly packed RGBA
+func decodeImageFile(filePath string) (*image.RGBA, error) {
+	imgFile, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
 	defer imgFile.Close()
 
 	img, _, err := image.Decode(imgFile)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	rgba := image.NewRGBA(img.Bounds())
 	if rgba.Stride != rgba.Rect.Size().X*4 {
-		return 0, fmt.Errorf("unsupported stride")
+		return nil, fmt.Errorf("unsupported stride")
 	}
 	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
+	return rgba, nil
+}
+
+// upload creates the GL texture and returns its ID and approximate GPU size
+func (d *decodedTexture) upload(sampler SamplerSettings) (uint32, int64) {
+	if d.rgba != nil {
+		return uploadRGBATexture(d.rgba, sampler)
+	}
 
 	var textureID uint32
 	gl.GenTextures(1, &textureID)
 	gl.BindTexture(gl.TEXTURE_2D, textureID)
-	gl.TexImage2D(
-		gl.TEXTURE_2D, 0, gl.RGBA,
-		int32(rgba.Rect.Size().X), int32(rgba.Rect.Size().Y),
-		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
 
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
+	var size int64
+	for i, level := range d.file.levels {
+		if d.levels == nil {
+			gl.CompressedTexImage2D(gl.TEXTURE_2D, int32(i), d.glFormat,
+				int32(level.width), int32(level.height), 0, int32(len(level.data)), gl.Ptr(level.data))
+			size += int64(len(level.data))
+			continue
+		}
+		rgba := d.levels[i]
+		gl.TexImage2D(gl.TEXTURE_2D, int32(i), sampler.glInternalFormat(),
+			int32(level.width), int32(level.height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+		size += int64(len(rgba.Pix))
+	}
 
-	// Cache the texture
-	tm.textureCache[filePath] = textureID
-	tm.textureRefCount[textureID] = 1
-	tm.texturePaths[textureID] = filePath
-	tm.stats.TotalTextures++
-	tm.stats.ActiveTextures++
+	// Single-level files get a generated chain, unless the data is still compressed on the GPU
+	hasMips := len(d.file.levels) > 1
+	if !hasMips && !sampler.NoMipmaps && d.levels != nil {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+		size = size * 4 / 3
+		hasMips = true
+	} else {
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, int32(len(d.file.levels)-1))
+	}
+	applySampler(gl.TEXTURE_2D, sampler, hasMips)
+	return textureID, size
+}
 
-	logger.Log.Info("Texture loaded and cached",
-		zap.String("path", filePath),
-		zap.Uint32("textureID", textureID),
-		zap.Int("width", rgba.Rect.Size().X),
-		zap.Int("height", rgba.Rect.Size().Y))
+// uploadRGBATexture uploads a single RGBA image, generating mipmaps unless disabled
+func uploadRGBATexture(rgba *image.RGBA, sampler SamplerSettings) (uint32, int64) {
+	var textureID uint32
+	gl.GenTextures(1, &textureID)
+	gl.BindTexture(gl.TEXTURE_2D, textureID)
+	gl.TexImage2D(
+		gl.TEXTURE_2D, 0, sampler.glInternalFormat(),
+		int32(rgba.Rect.Size().X), int32(rgba.Rect.Size().Y),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
 
-	return textureID, nil
+	size := int64(len(rgba.Pix))
+	hasMips := !sampler.NoMipmaps
+	if hasMips {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+		size = size * 4 / 3 // A full mip chain adds a third
+	}
+	applySampler(gl.TEXTURE_2D, sampler, hasMips)
+	return textureID, size
 }
 
 // CreateTextureFromImage creates a texture from an image.Image
@@ -129,19 +327,10 @@ func (tm *TextureManager) CreateTextureFromImage(img image.Image, name string) (
 		draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
 	}
 
-	var textureID uint32
-	gl.GenTextures(1, &textureID)
-	gl.BindTexture(gl.TEXTURE_2D, textureID)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(rgba.Rect.Size().X), int32(rgba.Rect.Size().Y), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	textureID, size := uploadRGBATexture(rgba, SamplerSettings{})
 
 	// Cache with name
-	tm.textureCache[name] = textureID
-	tm.textureRefCount[textureID] = 1
-	tm.texturePaths[textureID] = name
-	tm.stats.TotalTextures++
-	tm.stats.ActiveTextures++
+	tm.addToCache(name, textureID, size)
 
 	logger.Log.Info("Texture created from image",
 		zap.String("name", name),
@@ -198,6 +387,7 @@ func (tm *TextureManager) ReleaseTexture(textureID uint32) {
 		delete(tm.textureCache, path)
 		delete(tm.textureRefCount, textureID)
 		delete(tm.texturePaths, textureID)
+		delete(tm.textureSizes, textureID)
 		tm.stats.ActiveTextures--
 
 		logger.Log.Info("Texture freed",
@@ -213,6 +403,11 @@ func (tm *TextureManager) GetStats() TextureStats {
 
 	stats := tm.stats
 	stats.ActiveTextures = len(tm.textureRefCount)
+	var bytes int64
+	for _, size := range tm.textureSizes {
+		bytes += size
+	}
+	stats.TotalMemoryMB = float64(bytes) / (1024 * 1024)
 	return stats
 }
 
@@ -235,10 +430,18 @@ func (tm *TextureManager) Clear() {
 	for textureID := range tm.textureRefCount {
 		gl.DeleteTextures(1, &textureID)
 	}
+	for _, rt := range tm.renderTextures {
+		if rt.fbo != 0 {
+			gl.DeleteFramebuffers(1, &rt.fbo)
+			gl.DeleteRenderbuffers(1, &rt.depth)
+		}
+	}
 
 	tm.textureCache = make(map[string]uint32)
 	tm.textureRefCount = make(map[uint32]int)
 	tm.texturePaths = make(map[uint32]string)
+	tm.textureSizes = make(map[uint32]int64)
+	tm.renderTextures = make(map[string]*RenderTexture)
 	tm.stats.ActiveTextures = 0
 
 	logger.Log.Info("Texture manager cleared")
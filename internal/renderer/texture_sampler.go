@@ -0,0 +1,242 @@
+package renderer
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// TextureWrap selects how texture coordinates outside [0,1] are handled
+type TextureWrap string
+
+const (
+	WrapRepeat TextureWrap = "repeat"
+	WrapClamp  TextureWrap = "clamp"
+	WrapMirror TextureWrap = "mirror"
+)
+
+// TextureFilter selects how texels are filtered
+type TextureFilter string
+
+const (
+	FilterNearest   TextureFilter = "nearest"   // Blocky, for pixel art
+	FilterBilinear  TextureFilter = "bilinear"  // Smooth within a mip level
+	FilterTrilinear TextureFilter = "trilinear" // Smooth across mip levels
+)
+
+// TextureWraps and TextureFilters list the valid sampler values, in display order
+var (
+	TextureWraps   = []TextureWrap{WrapRepeat, WrapClamp, WrapMirror}
+	TextureFilters = []TextureFilter{FilterTrilinear, FilterBilinear, FilterNearest}
+)
+
+// SamplerSettings controls how a texture is sampled. Zero fields use DefaultSampler.
+type SamplerSettings struct {
+	Wrap       TextureWrap   `json:"wrap,omitempty"`
+	Filter     TextureFilter `json:"filter,omitempty"`
+	Anisotropy float32       `json:"anisotropy,omitempty"` // Max anisotropic samples, 1 disables
+	NoMipmaps  bool          `json:"noMipmaps,omitempty"`
+
+	ColorSpace TextureColorSpace `json:"colorSpace,omitempty"` // Unset is sRGB
+}
+
+// DefaultSampler fills in any sampler field a texture leaves unset
+var DefaultSampler = SamplerSettings{Wrap: WrapRepeat, Filter: FilterTrilinear, Anisotropy: 8}
+
+// Resolved returns the settings with unset fields taken from DefaultSampler
+func (s SamplerSettings) Resolved() SamplerSettings {
+	if s.Wrap == "" {
+		s.Wrap = DefaultSampler.Wrap
+	}
+	if s.Filter == "" {
+		s.Filter = DefaultSampler.Filter
+	}
+	if s.Anisotropy == 0 {
+		s.Anisotropy = DefaultSampler.Anisotropy
+	}
+	return s
+}
+
+// validate rejects unknown wrap, filter and color space names
+func (s SamplerSettings) validate() error {
+	switch s.Wrap {
+	case "", WrapRepeat, WrapClamp, WrapMirror:
+	default:
+		return fmt.Errorf("unknown wrap mode %q", s.Wrap)
+	}
+	switch s.Filter {
+	case "", FilterNearest, FilterBilinear, FilterTrilinear:
+	default:
+		return fmt.Errorf("unknown filter %q", s.Filter)
+	}
+	switch s.ColorSpace {
+	case "", ColorSpaceSRGB, ColorSpaceLinear:
+	default:
+		return fmt.Errorf("unknown color space %q", s.ColorSpace)
+	}
+	if s.Anisotropy < 0 {
+		return fmt.Errorf("anisotropy must be positive")
+	}
+	return nil
+}
+
+// cacheKey distinguishes copies of the same image loaded with different settings
+func (s SamplerSettings) cacheKey(path string) string {
+	if s.ColorSpace == ColorSpaceSRGB {
+		s.ColorSpace = ""
+	}
+	if s == (SamplerSettings{}) {
+		return path
+	}
+	return fmt.Sprintf("%s|%s|%s|%g|%v|%v", path, s.Wrap, s.Filter, s.Anisotropy, s.NoMipmaps, s.ColorSpace)
+}
+
+// glFilters returns the min and mag filters, using mip levels only if the texture has them
+func (s SamplerSettings) glFilters(hasMips bool) (int32, int32) {
+	switch s.Filter {
+	case FilterNearest:
+		if hasMips {
+			return gl.NEAREST_MIPMAP_NEAREST, gl.NEAREST
+		}
+		return gl.NEAREST, gl.NEAREST
+	case FilterBilinear:
+		if hasMips {
+			return gl.LINEAR_MIPMAP_NEAREST, gl.LINEAR
+		}
+	default:
+		if hasMips {
+			return gl.LINEAR_MIPMAP_LINEAR, gl.LINEAR
+		}
+	}
+	return gl.LINEAR, gl.LINEAR
+}
+
+func (s SamplerSettings) glWrap() int32 {
+	switch s.Wrap {
+	case WrapClamp:
+		return gl.CLAMP_TO_EDGE
+	case WrapMirror:
+		return gl.MIRRORED_REPEAT
+	}
+	return gl.REPEAT
+}
+
+// applySampler sets the sampling state of the texture bound to target
+func applySampler(target uint32, s SamplerSettings, hasMips bool) {
+	s = s.Resolved()
+	minFilter, magFilter := s.glFilters(hasMips)
+	gl.TexParameteri(target, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(target, gl.TEXTURE_MAG_FILTER, magFilter)
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_S, s.glWrap())
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_T, s.glWrap())
+
+	if maxAniso := maxAnisotropy(); maxAniso > 1 && hasMips {
+		gl.TexParameterf(target, gl.TEXTURE_MAX_ANISOTROPY, min(max(s.Anisotropy, 1), maxAniso))
+	}
+}
+
+// glCapabilities is a snapshot of the context's limits, taken once on the GL thread
+type glCapabilities struct {
+	extensions    map[string]bool
+	maxAnisotropy float32
+}
+
+// glCaps is written once by queryGLCaps and only read afterwards, so loader
+// goroutines can check formats without GL calls
+var glCaps atomic.Pointer[glCapabilities]
+
+// queryGLCaps reads the context's extensions once. Call it on the GL thread;
+// the renderer does so during init.
+func queryGLCaps() {
+	if glCaps.Load() != nil {
+		return
+	}
+	caps := &glCapabilities{extensions: make(map[string]bool)}
+
+	var count int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+	for i := uint32(0); i < uint32(count); i++ {
+		caps.extensions[gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i))] = true
+	}
+
+	if caps.extensions["GL_EXT_texture_filter_anisotropic"] || caps.extensions["GL_ARB_texture_filter_anisotropic"] {
+		gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &caps.maxAnisotropy)
+	}
+	glCaps.Store(caps)
+}
+
+// hasGLExtension returns true if the context exposes an extension. It only reads
+// the snapshot, so it is safe off the GL thread and false before the renderer is up.
+func hasGLExtension(name string) bool {
+	caps := glCaps.Load()
+	return caps != nil && caps.extensions[name]
+}
+
+// maxAnisotropy returns the driver's anisotropy limit, or 0 if unsupported.
+// Call it on the GL thread.
+func maxAnisotropy() float32 {
+	queryGLCaps()
+	return glCaps.Load().maxAnisotropy
+}
+
+// MaxTextureAnisotropy returns the highest anisotropy the GPU supports (0 if none)
+func MaxTextureAnisotropy() float32 {
+	return maxAnisotropy()
+}
+
+// sRGB variants of the S3TC formats, from EXT_texture_sRGB
+const (
+	compressedSRGBS3TCDXT1      = 0x8C4C
+	compressedSRGBAlphaS3TCDXT1 = 0x8C4D
+	compressedSRGBAlphaS3TCDXT3 = 0x8C4E
+	compressedSRGBAlphaS3TCDXT5 = 0x8C4F
+)
+
+// glCompressedFormat returns the GL internal format for a compressed format and
+// whether the context can sample it directly. Color formats use their sRGB
+// variant when srgb is set; single and dual channel formats are always data.
+func glCompressedFormat(format TextureFormat, srgb bool) (uint32, bool) {
+	s3tc := hasGLExtension("GL_EXT_texture_compression_s3tc")
+	bptc := hasGLExtension("GL_ARB_texture_compression_bptc")
+	if srgb {
+		s3tcSRGB := s3tc && hasGLExtension("GL_EXT_texture_sRGB")
+		switch format {
+		case FormatBC1:
+			return compressedSRGBS3TCDXT1, s3tcSRGB
+		case FormatBC1A:
+			return compressedSRGBAlphaS3TCDXT1, s3tcSRGB
+		case FormatBC2:
+			return compressedSRGBAlphaS3TCDXT3, s3tcSRGB
+		case FormatBC3:
+			return compressedSRGBAlphaS3TCDXT5, s3tcSRGB
+		case FormatBC7:
+			return gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB, bptc
+		}
+	}
+	switch format {
+	case FormatBC1:
+		return gl.COMPRESSED_RGB_S3TC_DXT1_EXT, s3tc
+	case FormatBC1A:
+		return gl.COMPRESSED_RGBA_S3TC_DXT1_EXT, s3tc
+	case FormatBC2:
+		return gl.COMPRESSED_RGBA_S3TC_DXT3_EXT, s3tc
+	case FormatBC3:
+		return gl.COMPRESSED_RGBA_S3TC_DXT5_EXT, s3tc
+	case FormatBC4:
+		return gl.COMPRESSED_RED_RGTC1, true // Core since GL 3.0
+	case FormatBC4Signed:
+		return gl.COMPRESSED_SIGNED_RED_RGTC1, true
+	case FormatBC5:
+		return gl.COMPRESSED_RG_RGTC2, true
+	case FormatBC5Signed:
+		return gl.COMPRESSED_SIGNED_RG_RGTC2, true
+	case FormatBC6H:
+		return gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB, bptc
+	case FormatBC6HSigned:
+		return gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB, bptc
+	case FormatBC7:
+		return gl.COMPRESSED_RGBA_BPTC_UNORM_ARB, bptc
+	}
+	return 0, false
+}
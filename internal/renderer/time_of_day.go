@@ -0,0 +1,183 @@
+package renderer
+
+import (
+	"math"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SkyColorTarget receives the current sky color (e.g. water reflections)
+type SkyColorTarget interface {
+	SetSkyColor(color mgl32.Vec3)
+}
+
+// TimeOfDay animates the sun across the sky and keeps the primary directional
+// light, ambient level and sky-dependent materials in sync with it.
+// It satisfies behaviour.PlayerBehaviour, so it can be added to the behaviour manager.
+type TimeOfDay struct {
+	Hour      float32 // Current time in hours (0-24, 6 = sunrise, 12 = noon, 18 = sunset)
+	DayLength float32 // Real seconds for a full 24h cycle (0 = static)
+	Paused    bool
+
+	SunAzimuth float32 // Compass rotation of the sun path in degrees (0 = rises in +X)
+	SunTilt    float32 // Tilt of the sun path away from the zenith in degrees
+
+	DayIntensity      float32 // Sun intensity at noon
+	NightIntensity    float32 // Residual light intensity at night
+	DayAmbient        float32 // Ambient strength at noon
+	NightAmbient      float32 // Ambient strength at night
+	DayTemperature    float32 // Light temperature (K) with the sun high
+	SunsetTemperature float32 // Light temperature (K) with the sun on the horizon
+
+	Light      *Light             // Primary directional light driven by the cycle
+	Atmosphere AtmosphereSettings // Used to derive sky color and sun tint on the CPU
+
+	skyColorTargets []SkyColorTarget
+	skyColor        mgl32.Vec3
+	lastUpdate      time.Time
+}
+
+// NewTimeOfDay creates a controller for the given directional light starting at the given hour
+func NewTimeOfDay(light *Light, hour float32) *TimeOfDay {
+	return &TimeOfDay{
+		Hour:              hour,
+		DayLength:         600.0, // 10 minute day
+		SunTilt:           30.0,
+		DayIntensity:      1.0,
+		NightIntensity:    0.05,
+		DayAmbient:        0.3,
+		NightAmbient:      0.05,
+		DayTemperature:    5500.0,
+		SunsetTemperature: 2500.0,
+		Light:             light,
+		Atmosphere:        DefaultAtmosphereSettings(),
+	}
+}
+
+// AddSkyColorTarget registers a receiver for sky color updates
+func (t *TimeOfDay) AddSkyColorTarget(target SkyColorTarget) {
+	t.skyColorTargets = append(t.skyColorTargets, target)
+	target.SetSkyColor(t.skyColor)
+}
+
+// RemoveSkyColorTarget unregisters a sky color receiver
+func (t *TimeOfDay) RemoveSkyColorTarget(target SkyColorTarget) {
+	for i, existing := range t.skyColorTargets {
+		if existing == target {
+			t.skyColorTargets = append(t.skyColorTargets[:i], t.skyColorTargets[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetHour jumps to a time of day and applies it immediately
+func (t *TimeOfDay) SetHour(hour float32) {
+	t.Hour = wrapHour(hour)
+	t.Apply()
+}
+
+// SkyColor returns the last computed sky color
+func (t *TimeOfDay) SkyColor() mgl32.Vec3 {
+	return t.skyColor
+}
+
+// SunDirection returns the direction toward the sun for the current hour
+func (t *TimeOfDay) SunDirection() mgl32.Vec3 {
+	// 6h on the eastern horizon, 12h at the highest point, 18h on the western horizon
+	angle := float64(t.Hour-6.0) / 24.0 * 2.0 * math.Pi
+	tilt := float64(mgl32.DegToRad(t.SunTilt))
+	azimuth := float64(mgl32.DegToRad(t.SunAzimuth))
+
+	x := math.Cos(angle)
+	up := math.Sin(angle)
+	y := up * math.Cos(tilt)
+	z := up * math.Sin(tilt)
+
+	// Rotate the whole path around the vertical axis
+	rx := x*math.Cos(azimuth) - z*math.Sin(azimuth)
+	rz := x*math.Sin(azimuth) + z*math.Cos(azimuth)
+
+	return mgl32.Vec3{float32(rx), float32(y), float32(rz)}.Normalize()
+}
+
+// Advance moves the clock forward by dt seconds and applies the result
+func (t *TimeOfDay) Advance(dt float32) {
+	if !t.Paused && t.DayLength > 0 {
+		t.Hour = wrapHour(t.Hour + dt/t.DayLength*24.0)
+	}
+	t.Apply()
+}
+
+// Apply pushes the current hour to the light and sky color targets
+func (t *TimeOfDay) Apply() {
+	sunDir := t.SunDirection()
+	elevation := sunDir.Y()
+
+	dayFactor := smoothstep32(-0.1, 0.2, elevation)
+	highSun := smoothstep32(0.0, 0.5, elevation)
+
+	if t.Light != nil {
+		t.Light.Mode = "directional"
+		t.Light.Direction = sunDir
+		t.Light.Intensity = lerp32(t.NightIntensity, t.DayIntensity, dayFactor)
+		t.Light.AmbientStrength = lerp32(t.NightAmbient, t.DayAmbient, dayFactor)
+		t.Light.Temperature = lerp32(t.SunsetTemperature, t.DayTemperature, highSun)
+
+		// Tint by what survives the trip through the atmosphere
+		tint := t.Atmosphere.SunTransmittance(sunDir)
+		if maxComponent := max(tint[0], tint[1], tint[2]); maxComponent > 1e-4 {
+			tint = tint.Mul(1.0 / maxComponent)
+			t.Light.Color = mgl32.Vec3{1, 1, 1}.Mul(0.5).Add(tint.Mul(0.5))
+		}
+	}
+
+	// Sample the sky a little above the horizon, on the side facing the sun
+	horizontal := mgl32.Vec3{sunDir.X(), 0, sunDir.Z()}
+	if horizontal.Len() < 1e-4 {
+		horizontal = mgl32.Vec3{1, 0, 0}
+	}
+	sampleDir := horizontal.Normalize().Add(mgl32.Vec3{0, 0.5, 0}).Normalize()
+	t.skyColor = t.Atmosphere.SkyColor(sampleDir, sunDir)
+
+	for _, target := range t.skyColorTargets {
+		target.SetSkyColor(t.skyColor)
+	}
+}
+
+// Start implements the Behaviour interface
+func (t *TimeOfDay) Start() {
+	t.lastUpdate = Clock.Now()
+	t.Apply()
+}
+
+// Update implements the Behaviour interface, advancing with the frame clock
+func (t *TimeOfDay) Update() {
+	now := Clock.Now()
+	if t.lastUpdate.IsZero() {
+		t.lastUpdate = now
+	}
+	dt := float32(now.Sub(t.lastUpdate).Seconds())
+	t.lastUpdate = now
+	t.Advance(dt)
+}
+
+// UpdateFixed implements the Behaviour interface
+func (t *TimeOfDay) UpdateFixed() {}
+
+func wrapHour(hour float32) float32 {
+	hour = float32(math.Mod(float64(hour), 24.0))
+	if hour < 0 {
+		hour += 24.0
+	}
+	return hour
+}
+
+func lerp32(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
+
+func smoothstep32(edge0, edge1, x float32) float32 {
+	t := mgl32.Clamp((x-edge0)/(edge1-edge0), 0, 1)
+	return t * t * (3 - 2*t)
+}
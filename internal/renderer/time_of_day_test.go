@@ -0,0 +1,120 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type skyColorRecorder struct {
+	color mgl32.Vec3
+	calls int
+}
+
+func (r *skyColorRecorder) SetSkyColor(color mgl32.Vec3) {
+	r.color = color
+	r.calls++
+}
+
+func TestTimeOfDaySunPath(t *testing.T) {
+	tod := NewTimeOfDay(nil, 12)
+	tod.SunTilt = 0
+
+	if y := tod.SunDirection().Y(); math.Abs(float64(y-1)) > 1e-5 {
+		t.Errorf("Expected sun overhead at noon, got elevation %f", y)
+	}
+
+	tod.Hour = 6
+	if y := tod.SunDirection().Y(); math.Abs(float64(y)) > 1e-5 {
+		t.Errorf("Expected sun on the horizon at 6h, got elevation %f", y)
+	}
+
+	tod.Hour = 0
+	if y := tod.SunDirection().Y(); y >= 0 {
+		t.Errorf("Expected sun below the horizon at midnight, got elevation %f", y)
+	}
+}
+
+func TestTimeOfDayDrivesLight(t *testing.T) {
+	light := CreateDirectionalLight(mgl32.Vec3{0, 1, 0}, mgl32.Vec3{1, 1, 1}, 1.0)
+	tod := NewTimeOfDay(light, 12)
+
+	tod.Apply()
+	noonIntensity := light.Intensity
+	noonAmbient := light.AmbientStrength
+	noonTemperature := light.Temperature
+	if light.Direction.Sub(tod.SunDirection()).Len() > 1e-5 {
+		t.Error("Light direction should follow the sun")
+	}
+
+	tod.SetHour(0)
+	if light.Intensity >= noonIntensity {
+		t.Errorf("Expected dimmer light at night (%f) than noon (%f)", light.Intensity, noonIntensity)
+	}
+	if light.AmbientStrength >= noonAmbient {
+		t.Errorf("Expected lower ambient at night (%f) than noon (%f)", light.AmbientStrength, noonAmbient)
+	}
+
+	tod.SetHour(6.3)
+	if light.Temperature >= noonTemperature {
+		t.Errorf("Expected warmer light at sunrise (%f K) than noon (%f K)", light.Temperature, noonTemperature)
+	}
+}
+
+func TestTimeOfDayAdvanceWraps(t *testing.T) {
+	tod := NewTimeOfDay(nil, 23)
+	tod.DayLength = 24 // One hour per second
+
+	tod.Advance(2)
+	if math.Abs(float64(tod.Hour-1)) > 1e-4 {
+		t.Errorf("Expected hour to wrap to 1, got %f", tod.Hour)
+	}
+
+	tod.Paused = true
+	tod.Advance(5)
+	if math.Abs(float64(tod.Hour-1)) > 1e-4 {
+		t.Errorf("Paused clock should not advance, got %f", tod.Hour)
+	}
+}
+
+func TestTimeOfDaySkyColorTargets(t *testing.T) {
+	tod := NewTimeOfDay(nil, 12)
+	recorder := &skyColorRecorder{}
+	tod.AddSkyColorTarget(recorder)
+
+	tod.Apply()
+	noon := recorder.color
+	if noon.Z() <= noon.X() {
+		t.Errorf("Expected a blue sky at noon, got %v", noon)
+	}
+
+	tod.SetHour(0)
+	night := recorder.color
+	if night.Len() >= noon.Len() {
+		t.Errorf("Expected a darker sky at night (%v) than noon (%v)", night, noon)
+	}
+
+	tod.RemoveSkyColorTarget(recorder)
+	calls := recorder.calls
+	tod.Apply()
+	if recorder.calls != calls {
+		t.Error("Removed target should not receive updates")
+	}
+}
+
+func TestAtmosphereSunsetIsRedder(t *testing.T) {
+	atmosphere := DefaultAtmosphereSettings()
+
+	noon := atmosphere.SunTransmittance(mgl32.Vec3{0, 1, 0})
+	sunset := atmosphere.SunTransmittance(mgl32.Vec3{1, 0.02, 0})
+
+	if sunset.X()/sunset.Z() <= noon.X()/noon.Z() {
+		t.Errorf("Expected sunset transmittance %v to be redder than noon %v", sunset, noon)
+	}
+
+	below := atmosphere.SunTransmittance(mgl32.Vec3{1, -0.2, 0})
+	if below.Len() != 0 {
+		t.Errorf("Expected no direct sunlight below the horizon, got %v", below)
+	}
+}
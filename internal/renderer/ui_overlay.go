@@ -0,0 +1,212 @@
+package renderer
+
+import (
+	"Gopher3D/internal/logger"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"go.uber.org/zap"
+)
+
+// UIRect is a rectangle of the in-game UI, in pixels from the window's top-left
+type UIRect struct {
+	X, Y, W, H float32
+}
+
+// Contains reports whether a point lies inside the rectangle
+func (r UIRect) Contains(x, y float32) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H
+}
+
+// UIDrawItem is one element of the UI overlay: a filled or textured rectangle,
+// or a screen text when Text is set
+type UIDrawItem struct {
+	Rect  UIRect
+	Color [4]float32 // Fill, or the tint of Image
+	Image string     // Texture file; empty fills the rectangle with Color
+	Text  *Text      // Drawn at its Position instead of the rectangle
+}
+
+// uiImageSampler loads UI images as stored; the overlay is not tone mapped
+var uiImageSampler = SamplerSettings{Wrap: WrapClamp, Filter: FilterBilinear, NoMipmaps: true, ColorSpace: ColorSpaceLinear}
+
+// uiOverlay holds the GL objects of the UI overlay
+type uiOverlay struct {
+	shader Shader
+	vao    uint32
+	vbo    uint32
+	images map[string]uint32 // Zero for images that failed to load
+	texts  map[*Text]bool    // Texts drawn last frame; the rest have their buffers freed
+}
+
+// DrawUI draws the in-game UI over the finished frame, in item order
+func (rend *OpenGLRenderer) DrawUI(items []UIDrawItem, width, height int32) {
+	o := &rend.ui
+	drawn := make(map[*Text]bool, len(o.texts))
+	defer func() {
+		for t := range o.texts {
+			if !drawn[t] {
+				t.deleteBuffers()
+			}
+		}
+		o.texts = drawn
+	}()
+	if len(items) == 0 {
+		return
+	}
+	if o.vao == 0 {
+		o.init()
+	}
+
+	gl.Viewport(0, 0, width, height)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	rend.setFaceCulling(false)
+	rend.setDepthTest(false)
+	gl.DepthMask(false)
+	projection := mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
+
+	var bound *Shader
+	for _, item := range items {
+		if item.Text != nil {
+			t := item.Text
+			if t.Hidden || t.Content == "" {
+				continue
+			}
+			t.update()
+			drawn[t] = true
+			if t.vertexCount == 0 {
+				continue
+			}
+			shader := rend.textShaderProgram()
+			if bound != shader {
+				if !rend.bindUIShader(shader, projection) {
+					continue
+				}
+				shader.SetInt("atlas", 0)
+				bound = shader
+			}
+			t.draw(shader, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, -1, 0})
+			rend.lastDrawCalls++
+			continue
+		}
+
+		if item.Color[3] <= 0 || item.Rect.W <= 0 || item.Rect.H <= 0 {
+			continue
+		}
+		texture := uint32(0)
+		if item.Image != "" {
+			if texture = o.image(rend.textureManager, item.Image); texture == 0 {
+				continue
+			}
+		}
+		if bound != &o.shader {
+			if !rend.bindUIShader(&o.shader, projection) {
+				continue
+			}
+			o.shader.SetInt("image", 0)
+			bound = &o.shader
+		}
+		o.shader.SetVec4("rect", mgl32.Vec4{item.Rect.X, item.Rect.Y, item.Rect.W, item.Rect.H})
+		o.shader.SetVec4("color", mgl32.Vec4(item.Color))
+		o.shader.SetBool("useImage", texture != 0)
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D, texture)
+		gl.BindVertexArray(o.vao)
+		gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+		rend.lastDrawCalls++
+	}
+
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	rend.setDepthTest(DepthTestEnabled)
+	rend.setFaceCulling(FaceCullingEnabled)
+}
+
+// bindUIShader binds a shader for the overlay, reporting false if it failed to build
+func (rend *OpenGLRenderer) bindUIShader(shader *Shader, projection mgl32.Mat4) bool {
+	shader.Use()
+	if shader.program == 0 {
+		return false
+	}
+	rend.currentShaderProgram = shader.program
+	shader.SetMat4("projection", projection)
+	shader.SetMat4("viewProjection", projection)
+	return true
+}
+
+func (o *uiOverlay) init() {
+	o.shader = NewShaderFromFiles("ui", "ui.vert", "ui.frag")
+	o.images = make(map[string]uint32)
+	corners := []float32{0, 0, 1, 0, 0, 1, 1, 1}
+	gl.GenVertexArrays(1, &o.vao)
+	gl.GenBuffers(1, &o.vbo)
+	gl.BindVertexArray(o.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, o.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(corners)*4, gl.Ptr(corners), gl.STATIC_DRAW)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 2*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.BindVertexArray(0)
+}
+
+// image returns the texture of a UI image, loading it on first use
+func (o *uiOverlay) image(textures *TextureManager, path string) uint32 {
+	if id, ok := o.images[path]; ok {
+		return id
+	}
+	id, err := textures.LoadTextureWithSampler(path, uiImageSampler)
+	if err != nil {
+		logger.Log.Warn("Failed to load UI image", zap.String("path", path), zap.Error(err))
+		id = 0
+	}
+	o.images[path] = id
+	return id
+}
+
+func (o *uiOverlay) delete(textures *TextureManager) {
+	if o.vao != 0 {
+		gl.DeleteVertexArrays(1, &o.vao)
+		gl.DeleteBuffers(1, &o.vbo)
+		o.vao, o.vbo = 0, 0
+	}
+	for _, id := range o.images {
+		if id != 0 {
+			textures.ReleaseTexture(id)
+		}
+	}
+	o.images = nil
+	for t := range o.texts {
+		t.deleteBuffers()
+	}
+	o.texts = nil
+}
+
+const uiVertexShaderSource = `#version 330 core
+
+layout(location = 0) in vec2 inCorner; // 0..1 across the rectangle
+
+uniform mat4 projection;
+uniform vec4 rect; // x, y, width, height in pixels
+
+out vec2 fragTexCoord;
+
+void main() {
+    fragTexCoord = inCorner;
+    gl_Position = projection * vec4(rect.xy + inCorner * rect.zw, 0.0, 1.0);
+}
+`
+
+const uiFragmentShaderSource = `#version 330 core
+
+in vec2 fragTexCoord;
+
+uniform sampler2D image;
+uniform bool useImage;
+uniform vec4 color;
+
+out vec4 FragColor;
+
+void main() {
+    FragColor = useImage ? texture(image, fragTexCoord) * color : color;
+}
+`
@@ -36,6 +36,14 @@ func (uc *UniformCache) SetFloat(name string, value float32) {
 	}
 }
 
+// SetVec2 sets a vec2 uniform using cached location
+func (uc *UniformCache) SetVec2(name string, x, y float32) {
+	loc := uc.GetLocation(name)
+	if loc != -1 {
+		gl.Uniform2f(loc, x, y)
+	}
+}
+
 // SetVec3 sets a vec3 uniform using cached location
 func (uc *UniformCache) SetVec3(name string, x, y, z float32) {
 	loc := uc.GetLocation(name)
@@ -44,6 +52,14 @@ func (uc *UniformCache) SetVec3(name string, x, y, z float32) {
 	}
 }
 
+// SetVec4 sets a vec4 uniform using cached location
+func (uc *UniformCache) SetVec4(name string, x, y, z, w float32) {
+	loc := uc.GetLocation(name)
+	if loc != -1 {
+		gl.Uniform4f(loc, x, y, z, w)
+	}
+}
+
 // SetInt sets an int uniform using cached location
 func (uc *UniformCache) SetInt(name string, value int32) {
 	loc := uc.GetLocation(name)
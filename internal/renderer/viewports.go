@@ -0,0 +1,111 @@
+package renderer
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ViewportRect is the part of the window a camera draws to, as fractions of the
+// window size measured from the top-left corner. The zero value covers the whole window.
+type ViewportRect struct {
+	X, Y, Width, Height float32
+}
+
+// FullViewport covers the whole window
+var FullViewport = ViewportRect{0, 0, 1, 1}
+
+// IsFull returns true if the rect covers the whole window
+func (v ViewportRect) IsFull() bool {
+	return v == ViewportRect{} || v == FullViewport
+}
+
+// Pixels converts the rect to a GL viewport (x, y, width, height) inside area, which
+// is itself a GL viewport. Edges are rounded so neighbouring views share them exactly.
+func (v ViewportRect) Pixels(area [4]int32) [4]int32 {
+	if v.Width <= 0 || v.Height <= 0 {
+		v = FullViewport
+	}
+	edge := func(f float32, size int32) int32 {
+		return min(max(int32(math.Round(float64(f*float32(size)))), 0), size)
+	}
+	left, right := edge(v.X, area[2]), edge(v.X+v.Width, area[2])
+	top, bottom := edge(v.Y, area[3]), edge(v.Y+v.Height, area[3])
+	// GL counts rows from the bottom
+	return [4]int32{area[0] + left, area[1] + area[3] - bottom, right - left, bottom - top}
+}
+
+// SplitViewports divides the window into count equal views: side by side for two,
+// otherwise a grid filled row by row from the top-left
+func SplitViewports(count int) []ViewportRect {
+	if count <= 1 {
+		return []ViewportRect{FullViewport}
+	}
+	cols, rows := 2, 1
+	if count > 2 {
+		cols = int(math.Ceil(math.Sqrt(float64(count))))
+		rows = (count + cols - 1) / cols
+	}
+	w, h := 1/float32(cols), 1/float32(rows)
+	rects := make([]ViewportRect, count)
+	for i := range rects {
+		rects[i] = ViewportRect{X: float32(i%cols) * w, Y: float32(i/cols) * h, Width: w, Height: h}
+	}
+	return rects
+}
+
+// orderViews fills dst with cameras sorted by Depth, keeping the given order for equal depths
+func orderViews(dst, cameras []*Camera) []*Camera {
+	dst = append(dst[:0], cameras...)
+	slices.SortStableFunc(dst, func(a, b *Camera) int { return cmp.Compare(a.Depth, b.Depth) })
+	return dst
+}
+
+// RenderViews draws each camera into its Viewport of the current GL viewport, lowest
+// Depth first so higher depths draw on top. Every view is culled for its own camera
+// and post-processed in its own buffers.
+func (rend *OpenGLRenderer) RenderViews(cameras []*Camera, light *Light) {
+	var area [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &area[0])
+	rend.viewOrder = orderViews(rend.viewOrder, cameras)
+	rend.updateRenderTextures(light)
+	rend.advanceMotionFrame()
+
+	rend.drawingViews = true
+	drawCalls := 0
+	gl.Enable(gl.SCISSOR_TEST)
+	for i, cam := range rend.viewOrder {
+		rect := cam.Viewport.Pixels(area)
+		if rect[2] <= 0 || rect[3] <= 0 {
+			continue
+		}
+		// Match the projection to the view's shape without changing the caller's camera
+		view := *cam
+		if aspect := float32(rect[2]) / float32(rect[3]); view.AspectRatio != aspect {
+			view.SetAspectRatio(aspect)
+		}
+		gl.Viewport(rect[0], rect[1], rect[2], rect[3])
+		gl.Scissor(rect[0], rect[1], rect[2], rect[3])
+		rend.activeView = i
+		rend.Render(view, light)
+		drawCalls += rend.lastDrawCalls
+	}
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Viewport(area[0], area[1], area[2], area[3])
+	rend.activeView = 0
+	rend.drawingViews = false
+	rend.lastDrawCalls = drawCalls
+
+	// Free the buffers of views that went away
+	for len(rend.postTargets) > max(len(rend.viewOrder), 1) {
+		last := len(rend.postTargets) - 1
+		rend.postTargets[last].delete()
+		rend.postTargets = rend.postTargets[:last]
+	}
+
+	if rend.capturePending() {
+		rend.processCaptures(func() { rend.RenderViews(cameras, light) })
+	}
+}
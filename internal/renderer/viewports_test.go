@@ -0,0 +1,73 @@
+package renderer
+
+import "testing"
+
+func TestViewportRectPixels(t *testing.T) {
+	area := [4]int32{0, 0, 1000, 600}
+	tests := []struct {
+		name string
+		rect ViewportRect
+		want [4]int32
+	}{
+		{"zero is full", ViewportRect{}, [4]int32{0, 0, 1000, 600}},
+		{"top half", ViewportRect{0, 0, 1, 0.5}, [4]int32{0, 300, 1000, 300}},
+		{"bottom right quarter", ViewportRect{0.5, 0.5, 0.5, 0.5}, [4]int32{500, 0, 500, 300}},
+		{"clamped to window", ViewportRect{0.75, 0, 0.5, 0.25}, [4]int32{750, 450, 250, 150}},
+	}
+	for _, tt := range tests {
+		if got := tt.rect.Pixels(area); got != tt.want {
+			t.Errorf("%s: Pixels = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Offset areas keep their origin
+	if got := (ViewportRect{0.5, 0, 0.5, 1}).Pixels([4]int32{10, 20, 100, 50}); got != [4]int32{60, 20, 50, 50} {
+		t.Errorf("offset area: Pixels = %v", got)
+	}
+}
+
+func TestSplitViewportsShareEdges(t *testing.T) {
+	area := [4]int32{0, 0, 1001, 601}
+	for _, count := range []int{2, 4} {
+		var covered int32
+		for _, rect := range SplitViewports(count) {
+			px := rect.Pixels(area)
+			covered += px[2] * px[3]
+		}
+		if covered != area[2]*area[3] {
+			t.Errorf("%d views cover %d pixels, want %d", count, covered, area[2]*area[3])
+		}
+	}
+
+	// Three views use a 2x2 grid, leaving the bottom-right cell empty
+	three := SplitViewports(3)
+	if len(three) != 3 || three[2] != (ViewportRect{0, 0.5, 0.5, 0.5}) {
+		t.Errorf("SplitViewports(3) = %v", three)
+	}
+	if got := SplitViewports(1); len(got) != 1 || !got[0].IsFull() {
+		t.Errorf("SplitViewports(1) = %v, want the full window", got)
+	}
+}
+
+func TestOrderViewsByDepth(t *testing.T) {
+	main := &Camera{Name: "main"}
+	minimap := &Camera{Name: "minimap", Depth: 1}
+	second := &Camera{Name: "second"}
+	background := &Camera{Name: "background", Depth: -1}
+
+	got := orderViews(nil, []*Camera{minimap, main, second, background})
+	want := []string{"background", "main", "second", "minimap"}
+	for i, cam := range got {
+		if cam.Name != want[i] {
+			t.Fatalf("order = %v, want %v", names(got), want)
+		}
+	}
+}
+
+func names(cameras []*Camera) []string {
+	out := make([]string, len(cameras))
+	for i, cam := range cameras {
+		out[i] = cam.Name
+	}
+	return out
+}
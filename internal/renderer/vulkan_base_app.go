@@ -41,7 +41,8 @@ type Scene struct {
 	depth             *Depth
 	useStagingBuffers bool
 
-	descPool vk.DescriptorPool
+	descPool   vk.DescriptorPool
+	uploadPool vk.CommandPool // Transient pool for one-time upload commands
 
 	pipelineLayout vk.PipelineLayout
 	descLayout     vk.DescriptorSetLayout
@@ -144,15 +145,12 @@ var texEnabled = []string{
 	"textures/gopher.png",
 }
 
-func (s *Scene) prepareTextureImage(path string, tiling vk.ImageTiling,
+func (s *Scene) prepareTextureImage(img image.Image, tiling vk.ImageTiling,
 	usage vk.ImageUsageFlagBits, memoryProps vk.MemoryPropertyFlagBits) *Texture {
 
 	dev := s.Context().Device()
 	texFormat := vk.FormatR8g8b8a8Unorm
-	_, width, height, err := loadTextureData(path, 0)
-	if err != nil {
-		logger.Log.Error("Failed to load texture data", zap.Error(err))
-	}
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
 	tex := &Texture{
 		texWidth:    int32(width),
 		texHeight:   int32(height),
@@ -216,10 +214,7 @@ func (s *Scene) prepareTextureImage(path string, tiling vk.ImageTiling,
 		}, &layout)
 		layout.Deref()
 
-		data, _, _, err := loadTextureData(path, int(layout.RowPitch))
-		if err != nil {
-			logger.Log.Error("Failed to load texture data", zap.Error(err))
-		}
+		data := rgbaPixels(img, int(layout.RowPitch))
 		if len(data) > 0 {
 			var pData unsafe.Pointer
 			ret = vk.MapMemory(dev, tex.mem, 0, vk.DeviceSize(len(data)), 0, &pData)
@@ -237,16 +232,12 @@ func (s *Scene) prepareTextureImage(path string, tiling vk.ImageTiling,
 	return tex
 }
 
-func (s *Scene) setImageLayout(image vk.Image, aspectMask vk.ImageAspectFlagBits,
+// setImageLayout records a layout transition for image into cmd
+func (s *Scene) setImageLayout(cmd vk.CommandBuffer, image vk.Image, aspectMask vk.ImageAspectFlagBits,
 	oldImageLayout, newImageLayout vk.ImageLayout,
 	srcAccessMask vk.AccessFlagBits,
 	srcStages, dstStages vk.PipelineStageFlagBits) {
 
-	cmd := s.Context().CommandBuffer()
-	if cmd == nil {
-		logger.Log.Error("vulkan: command buffer not initialized")
-	}
-
 	imageMemoryBarrier := vk.ImageMemoryBarrier{
 		SType:         vk.StructureTypeImageMemoryBarrier,
 		SrcAccessMask: vk.AccessFlags(srcAccessMask),
@@ -284,7 +275,90 @@ func (s *Scene) setImageLayout(image vk.Image, aspectMask vk.ImageAspectFlagBits
 		0, 0, nil, 0, nil, 1, []vk.ImageMemoryBarrier{imageMemoryBarrier})
 }
 
+// beginOneTimeCommands allocates a command buffer from the upload pool and
+// begins recording. Finish it with endOneTimeCommands.
+func (s *Scene) beginOneTimeCommands() (vk.CommandBuffer, error) {
+	dev := s.Context().Device()
+	if s.uploadPool == vk.NullCommandPool {
+		var pool vk.CommandPool
+		ret := vk.CreateCommandPool(dev, &vk.CommandPoolCreateInfo{
+			SType:            vk.StructureTypeCommandPoolCreateInfo,
+			Flags:            vk.CommandPoolCreateFlags(vk.CommandPoolCreateTransientBit),
+			QueueFamilyIndex: s.Context().Platform().GraphicsQueueFamilyIndex(),
+		}, nil, &pool)
+		if ret != vk.Success {
+			return nil, fmt.Errorf("failed to create upload command pool: %d", ret)
+		}
+		s.uploadPool = pool
+	}
+
+	cmds := make([]vk.CommandBuffer, 1)
+	ret := vk.AllocateCommandBuffers(dev, &vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        s.uploadPool,
+		Level:              vk.CommandBufferLevelPrimary,
+		CommandBufferCount: 1,
+	}, cmds)
+	if ret != vk.Success {
+		return nil, fmt.Errorf("failed to allocate upload command buffer: %d", ret)
+	}
+	ret = vk.BeginCommandBuffer(cmds[0], &vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit),
+	})
+	if ret != vk.Success {
+		vk.FreeCommandBuffers(dev, s.uploadPool, 1, cmds)
+		return nil, fmt.Errorf("failed to begin upload command buffer: %d", ret)
+	}
+	return cmds[0], nil
+}
+
+// endOneTimeCommands submits cmd to the graphics queue, waits for it to
+// finish and frees it
+func (s *Scene) endOneTimeCommands(cmd vk.CommandBuffer) error {
+	dev := s.Context().Device()
+	cmds := []vk.CommandBuffer{cmd}
+	defer vk.FreeCommandBuffers(dev, s.uploadPool, 1, cmds)
+
+	if ret := vk.EndCommandBuffer(cmd); ret != vk.Success {
+		return fmt.Errorf("failed to end upload command buffer: %d", ret)
+	}
+	var fence vk.Fence
+	if ret := vk.CreateFence(dev, &vk.FenceCreateInfo{SType: vk.StructureTypeFenceCreateInfo}, nil, &fence); ret != vk.Success {
+		return fmt.Errorf("failed to create upload fence: %d", ret)
+	}
+	defer vk.DestroyFence(dev, fence, nil)
+
+	ret := vk.QueueSubmit(s.Context().Platform().GraphicsQueue(), 1, []vk.SubmitInfo{{
+		SType:              vk.StructureTypeSubmitInfo,
+		CommandBufferCount: 1,
+		PCommandBuffers:    cmds,
+	}}, fence)
+	if ret != vk.Success {
+		return fmt.Errorf("failed to submit upload commands: %d", ret)
+	}
+	if ret := vk.WaitForFences(dev, 1, []vk.Fence{fence}, vk.True, vk.MaxUint64); ret != vk.Success {
+		return fmt.Errorf("failed waiting for upload commands: %d", ret)
+	}
+	return nil
+}
+
 func (s *Scene) prepareTextures() {
+	s.textures = make([]*Texture, 0, len(texEnabled))
+	for _, texFile := range texEnabled {
+		img, err := decodeAssetImage(texFile)
+		if err != nil {
+			logger.Log.Error("Failed to load texture data", zap.Error(err))
+			continue
+		}
+		if tex := s.createTexture(img); tex != nil {
+			s.textures = append(s.textures, tex)
+		}
+	}
+}
+
+// createTexture uploads img as a sampled RGBA texture with its view and sampler
+func (s *Scene) createTexture(img image.Image) *Texture {
 	dev := s.Context().Device()
 	texFormat := vk.FormatR8g8b8a8Unorm
 	var props vk.FormatProperties
@@ -292,133 +366,140 @@ func (s *Scene) prepareTextures() {
 	vk.GetPhysicalDeviceFormatProperties(gpu, texFormat, &props)
 	props.Deref()
 
-	prepareTex := func(path string) *Texture {
-		var tex *Texture
-
-		if (props.LinearTilingFeatures&vk.FormatFeatureFlags(vk.FormatFeatureSampledImageBit) != 0) &&
-			!s.useStagingBuffers {
-			// -> device can texture using linear textures
-
-			tex = s.prepareTextureImage(path, vk.ImageTilingLinear, vk.ImageUsageSampledBit,
-				vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
-
-			// Nothing in the pipeline needs to be complete to start, and don't allow fragment
-			// shader to run until layout transition completes
-			s.setImageLayout(tex.image, vk.ImageAspectColorBit,
-				vk.ImageLayoutPreinitialized, tex.imageLayout,
-				vk.AccessHostWriteBit,
-				vk.PipelineStageTopOfPipeBit, vk.PipelineStageFragmentShaderBit)
-
-		} else if props.OptimalTilingFeatures&vk.FormatFeatureFlags(vk.FormatFeatureSampledImageBit) != 0 {
-			//  Must use staging buffer to copy linear texture to optimized
-			log.Println("vulkan warn: using staging buffers")
-
-			staging := s.prepareTextureImage(path, vk.ImageTilingLinear, vk.ImageUsageTransferSrcBit,
-				vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
-			tex = s.prepareTextureImage(path, vk.ImageTilingOptimal,
-				vk.ImageUsageTransferDstBit|vk.ImageUsageSampledBit, vk.MemoryPropertyDeviceLocalBit)
-
-			s.setImageLayout(staging.image, vk.ImageAspectColorBit,
-				vk.ImageLayoutPreinitialized, vk.ImageLayoutTransferSrcOptimal,
-				vk.AccessHostWriteBit,
-				vk.PipelineStageTopOfPipeBit, vk.PipelineStageTransferBit)
-
-			s.setImageLayout(tex.image, vk.ImageAspectColorBit,
-				vk.ImageLayoutPreinitialized, vk.ImageLayoutTransferDstOptimal,
-				vk.AccessHostWriteBit,
-				vk.PipelineStageTopOfPipeBit, vk.PipelineStageTransferBit)
-
-			cmd := s.Context().CommandBuffer()
-			if cmd == nil {
-				logger.Log.Error("vulkan: command buffer not initialized")
-			}
-			vk.CmdCopyImage(cmd, staging.image, vk.ImageLayoutTransferSrcOptimal,
-				tex.image, vk.ImageLayoutTransferDstOptimal,
-				1, []vk.ImageCopy{{
-					SrcSubresource: vk.ImageSubresourceLayers{
-						AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
-						LayerCount: 1,
-					},
-					SrcOffset: vk.Offset3D{
-						X: 0, Y: 0, Z: 0,
-					},
-					DstSubresource: vk.ImageSubresourceLayers{
-						AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
-						LayerCount: 1,
-					},
-					DstOffset: vk.Offset3D{
-						X: 0, Y: 0, Z: 0,
-					},
-					Extent: vk.Extent3D{
-						Width:  uint32(staging.texWidth),
-						Height: uint32(staging.texHeight),
-						Depth:  1,
-					},
-				}})
-			s.setImageLayout(tex.image, vk.ImageAspectColorBit,
-				vk.ImageLayoutTransferDstOptimal, tex.imageLayout,
-				vk.AccessTransferWriteBit,
-				vk.PipelineStageTransferBit, vk.PipelineStageFragmentShaderBit)
-			// cannot destroy until cmd is submitted.. must keep a list somewhere
-			// staging.DestroyImage(dev)
-		} else {
-			logger.Log.Error("No support for R8g8b8a8Unorm as texture image format")
-		}
+	cmd, err := s.beginOneTimeCommands()
+	if err != nil {
+		logger.Log.Error("Failed to begin texture upload", zap.Error(err))
+		return nil
+	}
 
-		var sampler vk.Sampler
-		ret := vk.CreateSampler(dev, &vk.SamplerCreateInfo{
-			SType:                   vk.StructureTypeSamplerCreateInfo,
-			MagFilter:               vk.FilterNearest,
-			MinFilter:               vk.FilterNearest,
-			MipmapMode:              vk.SamplerMipmapModeNearest,
-			AddressModeU:            vk.SamplerAddressModeClampToEdge,
-			AddressModeV:            vk.SamplerAddressModeClampToEdge,
-			AddressModeW:            vk.SamplerAddressModeClampToEdge,
-			AnisotropyEnable:        vk.False,
-			MaxAnisotropy:           1,
-			CompareOp:               vk.CompareOpNever,
-			BorderColor:             vk.BorderColorFloatOpaqueWhite,
-			UnnormalizedCoordinates: vk.False,
-		}, nil, &sampler)
-		tex.sampler = sampler
+	var tex *Texture
+	var staging *Texture
+
+	if (props.LinearTilingFeatures&vk.FormatFeatureFlags(vk.FormatFeatureSampledImageBit) != 0) &&
+		!s.useStagingBuffers {
+		// -> device can texture using linear textures
+
+		tex = s.prepareTextureImage(img, vk.ImageTilingLinear, vk.ImageUsageSampledBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+
+		// Nothing in the pipeline needs to be complete to start, and don't allow fragment
+		// shader to run until layout transition completes
+		s.setImageLayout(cmd, tex.image, vk.ImageAspectColorBit,
+			vk.ImageLayoutPreinitialized, tex.imageLayout,
+			vk.AccessHostWriteBit,
+			vk.PipelineStageTopOfPipeBit, vk.PipelineStageFragmentShaderBit)
+
+	} else if props.OptimalTilingFeatures&vk.FormatFeatureFlags(vk.FormatFeatureSampledImageBit) != 0 {
+		//  Must use staging buffer to copy linear texture to optimized
+		log.Println("vulkan warn: using staging buffers")
+
+		staging = s.prepareTextureImage(img, vk.ImageTilingLinear, vk.ImageUsageTransferSrcBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		tex = s.prepareTextureImage(img, vk.ImageTilingOptimal,
+			vk.ImageUsageTransferDstBit|vk.ImageUsageSampledBit, vk.MemoryPropertyDeviceLocalBit)
+
+		s.setImageLayout(cmd, staging.image, vk.ImageAspectColorBit,
+			vk.ImageLayoutPreinitialized, vk.ImageLayoutTransferSrcOptimal,
+			vk.AccessHostWriteBit,
+			vk.PipelineStageTopOfPipeBit, vk.PipelineStageTransferBit)
+
+		s.setImageLayout(cmd, tex.image, vk.ImageAspectColorBit,
+			vk.ImageLayoutPreinitialized, vk.ImageLayoutTransferDstOptimal,
+			vk.AccessHostWriteBit,
+			vk.PipelineStageTopOfPipeBit, vk.PipelineStageTransferBit)
+
+		vk.CmdCopyImage(cmd, staging.image, vk.ImageLayoutTransferSrcOptimal,
+			tex.image, vk.ImageLayoutTransferDstOptimal,
+			1, []vk.ImageCopy{{
+				SrcSubresource: vk.ImageSubresourceLayers{
+					AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+					LayerCount: 1,
+				},
+				SrcOffset: vk.Offset3D{
+					X: 0, Y: 0, Z: 0,
+				},
+				DstSubresource: vk.ImageSubresourceLayers{
+					AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+					LayerCount: 1,
+				},
+				DstOffset: vk.Offset3D{
+					X: 0, Y: 0, Z: 0,
+				},
+				Extent: vk.Extent3D{
+					Width:  uint32(staging.texWidth),
+					Height: uint32(staging.texHeight),
+					Depth:  1,
+				},
+			}})
+		s.setImageLayout(cmd, tex.image, vk.ImageAspectColorBit,
+			vk.ImageLayoutTransferDstOptimal, tex.imageLayout,
+			vk.AccessTransferWriteBit,
+			vk.PipelineStageTransferBit, vk.PipelineStageFragmentShaderBit)
+	}
 
-		if ret != vk.Success {
-			logger.Log.Error("Failed to create sampler")
-		}
+	// Wait for the upload so the staging image can go right away
+	submitErr := s.endOneTimeCommands(cmd)
+	if staging != nil {
+		staging.DestroyImage(dev)
+	}
+	if tex == nil {
+		logger.Log.Error("No support for R8g8b8a8Unorm as texture image format")
+		return nil
+	}
+	if submitErr != nil {
+		logger.Log.Error("Failed to upload texture", zap.Error(submitErr))
+		tex.DestroyImage(dev)
+		return nil
+	}
 
-		logger.Log.Info("Creating sampler")
-		var view vk.ImageView
-		ret = vk.CreateImageView(dev, &vk.ImageViewCreateInfo{
-			SType:    vk.StructureTypeImageViewCreateInfo,
-			Image:    tex.image,
-			ViewType: vk.ImageViewType2d,
-			Format:   texFormat,
-			Components: vk.ComponentMapping{
-				R: vk.ComponentSwizzleR,
-				G: vk.ComponentSwizzleG,
-				B: vk.ComponentSwizzleB,
-				A: vk.ComponentSwizzleA,
-			},
-			SubresourceRange: vk.ImageSubresourceRange{
-				AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
-				LevelCount: 1,
-				LayerCount: 1,
-			},
-		}, nil, &view)
+	var sampler vk.Sampler
+	ret := vk.CreateSampler(dev, &vk.SamplerCreateInfo{
+		SType:                   vk.StructureTypeSamplerCreateInfo,
+		MagFilter:               vk.FilterNearest,
+		MinFilter:               vk.FilterNearest,
+		MipmapMode:              vk.SamplerMipmapModeNearest,
+		AddressModeU:            vk.SamplerAddressModeClampToEdge,
+		AddressModeV:            vk.SamplerAddressModeClampToEdge,
+		AddressModeW:            vk.SamplerAddressModeClampToEdge,
+		AnisotropyEnable:        vk.False,
+		MaxAnisotropy:           1,
+		CompareOp:               vk.CompareOpNever,
+		BorderColor:             vk.BorderColorFloatOpaqueWhite,
+		UnnormalizedCoordinates: vk.False,
+	}, nil, &sampler)
+	tex.sampler = sampler
 
-		if ret != vk.Success {
-			logger.Log.Error("Failed to create image view")
-		}
+	if ret != vk.Success {
+		logger.Log.Error("Failed to create sampler")
+	}
 
-		tex.view = view
+	logger.Log.Info("Creating sampler")
+	var view vk.ImageView
+	ret = vk.CreateImageView(dev, &vk.ImageViewCreateInfo{
+		SType:    vk.StructureTypeImageViewCreateInfo,
+		Image:    tex.image,
+		ViewType: vk.ImageViewType2d,
+		Format:   texFormat,
+		Components: vk.ComponentMapping{
+			R: vk.ComponentSwizzleR,
+			G: vk.ComponentSwizzleG,
+			B: vk.ComponentSwizzleB,
+			A: vk.ComponentSwizzleA,
+		},
+		SubresourceRange: vk.ImageSubresourceRange{
+			AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			LevelCount: 1,
+			LayerCount: 1,
+		},
+	}, nil, &view)
 
-		return tex
+	if ret != vk.Success {
+		logger.Log.Error("Failed to create image view")
 	}
 
-	s.textures = make([]*Texture, 0, len(texEnabled))
-	for _, texFile := range texEnabled {
-		s.textures = append(s.textures, prepareTex(texFile))
-	}
+	tex.view = view
+
+	return tex
 }
 
 func (s *Scene) drawBuildCommandBuffer(res *as.SwapchainImageResources, cmd vk.CommandBuffer) {
@@ -903,6 +984,10 @@ func (s *Scene) VulkanContextCleanup() error {
 		s.textures[i].Destroy(dev)
 	}
 	s.depth.Destroy(dev)
+	if s.uploadPool != vk.NullCommandPool {
+		vk.DestroyCommandPool(dev, s.uploadPool, nil)
+		s.uploadPool = vk.NullCommandPool
+	}
 	return nil
 }
 
@@ -981,19 +1066,22 @@ func (d *Depth) Destroy(dev vk.Device) {
 	vk.FreeMemory(dev, d.mem, nil)
 }
 
-func loadTextureData(name string, rowPitch int) ([]byte, int, int, error) {
-	data := MustAsset(name)
-	img, err := png.Decode(bytes.NewReader(data))
-	if err != nil {
-		return nil, 0, 0, err
-	}
-	newImg := image.NewRGBA(img.Bounds())
-	if rowPitch <= 4*img.Bounds().Dy() {
-		newImg.Stride = rowPitch
+// decodeAssetImage decodes an embedded PNG asset
+func decodeAssetImage(name string) (image.Image, error) {
+	return png.Decode(bytes.NewReader(MustAsset(name)))
+}
+
+// rgbaPixels converts img to tightly packed RGBA rows, padded to rowPitch bytes
+// when the driver's image layout asks for wider rows
+func rgbaPixels(img image.Image, rowPitch int) []byte {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	if rowPitch > rgba.Stride {
+		rgba.Pix = make([]byte, rowPitch*bounds.Dy())
+		rgba.Stride = rowPitch
 	}
-	draw.Draw(newImg, newImg.Bounds(), img, image.ZP, draw.Src)
-	size := newImg.Bounds().Size()
-	return []byte(newImg.Pix), size.X, size.Y, nil
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba.Pix
 }
 
 func (s *Scene) prepareVertexBuffer(vertices []float32) (vk.Buffer, vk.DeviceMemory, error) {
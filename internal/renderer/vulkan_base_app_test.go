@@ -0,0 +1,30 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestRGBAPixelsRowPitch(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(5, 5, 8, 7)) // 3x2, offset origin
+	img.Set(5, 5, color.NRGBA{R: 255, A: 255})
+	img.Set(7, 6, color.NRGBA{B: 255, A: 255})
+
+	packed := rgbaPixels(img, 0)
+	if len(packed) != 3*2*4 {
+		t.Fatalf("packed size = %d, want %d", len(packed), 3*2*4)
+	}
+
+	const pitch = 16
+	padded := rgbaPixels(img, pitch)
+	if len(padded) != pitch*2 {
+		t.Fatalf("padded size = %d, want %d", len(padded), pitch*2)
+	}
+	if padded[0] != 255 || padded[3] != 255 {
+		t.Errorf("first pixel = %v, want opaque red", padded[0:4])
+	}
+	if last := padded[pitch+2*4 : pitch+3*4]; last[2] != 255 || last[3] != 255 {
+		t.Errorf("last pixel of the second row = %v, want opaque blue", last)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"Gopher3D/internal/logger"
 	"fmt"
 	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
 	"runtime"
-	"unsafe"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
 	as "github.com/vulkan-go/asche"
@@ -13,6 +15,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// VulkanRenderer is an incomplete backend that draws through the demo pipeline
+// in vulkan_base_app.go. It loads textures and frees model buffers; materials,
+// lights, instancing, the skybox, MSAA and post effects exist only in OpenGLRenderer.
 type VulkanRenderer struct {
 	VulkanApp             *Application
 	platform              as.Platform
@@ -21,7 +26,8 @@ type VulkanRenderer struct {
 	Debug                 bool
 	Shader                Shader
 	Models                []*Model
-	textures              map[string]*Texture
+	textures              []*Texture        // Loaded textures; a texture's ID is its index + 1
+	texturePaths          map[string]uint32 // IDs of textures loaded from files
 }
 type Application struct {
 	*Scene
@@ -100,7 +106,7 @@ func (rend *VulkanRenderer) Init(width, height int32, window *glfw.Window) {
 	vk.SetGetInstanceProcAddr(glfw.GetVulkanGetInstanceProcAddress())
 	rend.VulkanApp = NewVulkanApp(window)
 	rend.VulkanApp.window = window
-	rend.textures = make(map[string]*Texture) // Initialize the texture map
+	rend.texturePaths = make(map[string]uint32)
 
 	logger.Log.Info("Initializing Vulkan Renderer")
 
@@ -174,36 +180,80 @@ func (rend *VulkanRenderer) AddModel(model *Model) {
 	rend.VulkanApp.Scene.models = append(rend.VulkanApp.Scene.models, model)
 }
 func (rend *VulkanRenderer) RemoveModel(model *Model) {
+	scene := rend.VulkanApp.Scene
+	for i, m := range scene.models {
+		if m == model {
+			scene.models = append(scene.models[:i], scene.models[i+1:]...)
+			break
+		}
+	}
+	rend.destroyModelBuffers(model)
+}
 
+// destroyModelBuffers frees a model's vertex and index buffers once the GPU is done with them
+func (rend *VulkanRenderer) destroyModelBuffers(model *Model) {
+	if model.vertexBuffer == vk.NullBuffer && model.indexBuffer == vk.NullBuffer {
+		return
+	}
+	dev := rend.VulkanApp.Context().Device()
+	vk.DeviceWaitIdle(dev)
+	vk.DestroyBuffer(dev, model.vertexBuffer, nil)
+	vk.FreeMemory(dev, model.vertexMemory, nil)
+	vk.DestroyBuffer(dev, model.indexBuffer, nil)
+	vk.FreeMemory(dev, model.indexMemory, nil)
+	model.vertexBuffer, model.indexBuffer = vk.NullBuffer, vk.NullBuffer
+	model.vertexMemory, model.indexMemory = vk.NullDeviceMemory, vk.NullDeviceMemory
 }
 
 func (rend *VulkanRenderer) LoadTexture(path string) (uint32, error) {
-	if texture, exists := rend.textures[path]; exists {
-		logger.Log.Info("Texture already loaded", zap.String("path", path))
-		return uint32(uintptr(unsafe.Pointer(texture.image))), nil // Return the existing texture ID
+	if id, exists := rend.texturePaths[path]; exists {
+		return id, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode texture %s: %w", path, err)
 	}
-	// Load texture using the Scene's prepareTextureImage method
 	logger.Log.Info("Loading texture", zap.String("path", path))
-	texture := rend.VulkanApp.Scene.prepareTextureImage(path, vk.ImageTilingOptimal, vk.ImageUsageTransferDstBit|vk.ImageUsageSampledBit, vk.MemoryPropertyDeviceLocalBit)
-	if texture == nil {
-		return 0, fmt.Errorf("failed to load texture from path: %s", path)
+	id, err := rend.CreateTextureFromImage(img)
+	if err != nil {
+		return 0, err
 	}
-	// Store the texture in the map
-	rend.textures[path] = texture
-	// Return the texture's image handle as the texture ID
-	return uint32(uintptr(unsafe.Pointer(texture.image))), nil
+	rend.texturePaths[path] = id
+	return id, nil
 }
 
 func (rend *VulkanRenderer) CreateTextureFromImage(img image.Image) (uint32, error) {
-	return 0, nil
+	texture := rend.VulkanApp.Scene.createTexture(img)
+	if texture == nil {
+		return 0, fmt.Errorf("failed to create %dx%d texture", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	rend.textures = append(rend.textures, texture)
+	return uint32(len(rend.textures)), nil
 }
 
 func (rend *VulkanRenderer) SetSkybox(skybox *Skybox) {
 	// TODO: Implement Vulkan skybox support
+	logger.Log.Warn("Skyboxes are not supported by the Vulkan renderer")
 }
 
 func (rend *VulkanRenderer) Cleanup() {
-
+	if rend.VulkanApp == nil || rend.VulkanApp.Context() == nil {
+		return
+	}
+	for _, model := range rend.VulkanApp.Scene.models {
+		rend.destroyModelBuffers(model)
+	}
+	dev := rend.VulkanApp.Context().Device()
+	for _, texture := range rend.textures {
+		texture.Destroy(dev)
+	}
+	rend.textures = nil
+	rend.texturePaths = make(map[string]uint32)
 }
 
 func (rend *VulkanRenderer) UpdateViewport(width, height int32) {
@@ -0,0 +1,267 @@
+package ui
+
+import "Gopher3D/internal/renderer"
+
+// Sizes of widgets that have no content to measure
+const (
+	defaultImageSize    = 64
+	defaultSliderWidth  = 160
+	defaultSliderHeight = 20
+)
+
+// Arrange places every widget of the layout in a window of the given size
+func (l *Layout) Arrange(width, height float32) {
+	if l.Root == nil {
+		return
+	}
+	l.Root.arrange(l.Root.place(renderer.UIRect{W: width, H: height}))
+}
+
+// measure returns the size a widget asks for before rows and columns stretch it
+func (w *Widget) measure() (float32, float32) {
+	width, height := w.Size[0], w.Size[1]
+	if width > 0 && height > 0 {
+		return width, height
+	}
+
+	var contentW, contentH float32
+	switch w.Type {
+	case WidgetRow, WidgetColumn:
+		count := 0
+		for _, child := range w.Children {
+			if child.Hidden {
+				continue
+			}
+			cw, ch := child.measure()
+			if w.Type == WidgetRow {
+				contentW += cw
+				contentH = max(contentH, ch)
+			} else {
+				contentW = max(contentW, cw)
+				contentH += ch
+			}
+			count++
+		}
+		if count > 1 {
+			if w.Type == WidgetRow {
+				contentW += w.Spacing * float32(count-1)
+			} else {
+				contentH += w.Spacing * float32(count-1)
+			}
+		}
+	case WidgetPanel:
+		for _, child := range w.Children {
+			if child.Hidden || child.Anchor == AnchorStretch {
+				continue
+			}
+			cw, ch := child.measure()
+			contentW = max(contentW, cw+abs(child.Offset[0]))
+			contentH = max(contentH, ch+abs(child.Offset[1]))
+		}
+	case WidgetText, WidgetButton:
+		if w.Text != "" {
+			maxWidth := float32(0)
+			if width > 0 {
+				maxWidth = max(width-2*w.Padding, 1)
+			}
+			_, contentW, contentH = w.fontFace().Layout(w.Text, w.textLayout(maxWidth))
+		}
+	case WidgetImage:
+		contentW, contentH = defaultImageSize, defaultImageSize
+	case WidgetSlider, WidgetBar:
+		contentW, contentH = defaultSliderWidth, defaultSliderHeight
+	}
+
+	if width <= 0 {
+		width = contentW + 2*w.Padding
+	}
+	if height <= 0 {
+		height = contentH + 2*w.Padding
+	}
+	return width, height
+}
+
+// place returns where a widget goes inside a panel's content area
+func (w *Widget) place(area renderer.UIRect) renderer.UIRect {
+	if w.Anchor == AnchorStretch {
+		return area
+	}
+	width, height := w.measure()
+	fx, fy := anchorFactors(w.Anchor)
+	return renderer.UIRect{
+		X: area.X + (area.W-width)*fx + w.Offset[0],
+		Y: area.Y + (area.H-height)*fy + w.Offset[1],
+		W: width,
+		H: height,
+	}
+}
+
+// arrange stores the widget's rectangle and places its children inside it
+func (w *Widget) arrange(rect renderer.UIRect) {
+	w.rect = rect
+	inner := renderer.UIRect{
+		X: rect.X + w.Padding,
+		Y: rect.Y + w.Padding,
+		W: max(rect.W-2*w.Padding, 0),
+		H: max(rect.H-2*w.Padding, 0),
+	}
+	switch w.Type {
+	case WidgetRow, WidgetColumn:
+		w.arrangeLine(inner)
+	default:
+		for _, child := range w.Children {
+			if !child.Hidden {
+				child.arrange(child.place(inner))
+			}
+		}
+	}
+	if w.hasText() {
+		w.arrangeLabel(inner)
+	}
+}
+
+// arrangeLine lays a row or column's children end to end, sharing any spare
+// length between the ones that grow and stretching across unsized ones
+func (w *Widget) arrangeLine(inner renderer.UIRect) {
+	row := w.Type == WidgetRow
+	children := make([]*Widget, 0, len(w.Children))
+	lengths := make([]float32, 0, len(w.Children))
+	var used float32
+	growers := 0
+	for _, child := range w.Children {
+		if child.Hidden {
+			continue
+		}
+		cw, ch := child.measure()
+		length := ch
+		if row {
+			length = cw
+		}
+		children = append(children, child)
+		lengths = append(lengths, length)
+		used += length
+		if child.Grow {
+			growers++
+		}
+	}
+	if len(children) == 0 {
+		return
+	}
+	used += w.Spacing * float32(len(children)-1)
+
+	available := inner.H
+	if row {
+		available = inner.W
+	}
+	share := float32(0)
+	if growers > 0 && available > used {
+		share = (available - used) / float32(growers)
+	}
+
+	pos := inner.X
+	if !row {
+		pos = inner.Y
+	}
+	for i, child := range children {
+		length := lengths[i]
+		if child.Grow {
+			length += share
+		}
+		var rect renderer.UIRect
+		if row {
+			height := child.Size[1]
+			if height <= 0 {
+				height = inner.H
+			}
+			rect = renderer.UIRect{X: pos, Y: inner.Y + crossOffset(child.Anchor, inner.H-height, false), W: length, H: height}
+		} else {
+			width := child.Size[0]
+			if width <= 0 {
+				width = inner.W
+			}
+			rect = renderer.UIRect{X: inner.X + crossOffset(child.Anchor, inner.W-width, true), Y: pos, W: width, H: length}
+		}
+		child.arrange(rect)
+		pos += length + w.Spacing
+	}
+}
+
+// arrangeLabel positions the widget's text inside its content area, centered vertically
+func (w *Widget) arrangeLabel(inner renderer.UIRect) {
+	if w.label == nil {
+		w.label = renderer.NewText("")
+	}
+	t := w.label
+	t.Content = w.Text
+	t.Font = w.font
+	t.Size = w.fontSize()
+	t.Align = w.textAlign()
+	t.MaxWidth = 0
+	if w.Size[0] > 0 {
+		t.MaxWidth = inner.W
+	}
+	t.Color = orColor(w.TextColor, defaultTextColor)
+	if w.Disabled {
+		t.Color[3] *= 0.5
+	}
+
+	_, _, height := w.fontFace().Layout(w.Text, w.textLayout(t.MaxWidth))
+	x := inner.X
+	switch t.Align {
+	case renderer.AlignCenter:
+		x += inner.W / 2
+	case renderer.AlignRight:
+		x += inner.W
+	}
+	t.Position = [3]float32{x, inner.Y + (inner.H-height)/2, 0}
+}
+
+func (w *Widget) fontFace() *renderer.Font {
+	if w.font != nil {
+		return w.font
+	}
+	return renderer.DefaultFont()
+}
+
+func (w *Widget) textLayout(maxWidth float32) renderer.TextLayout {
+	return renderer.TextLayout{Size: w.fontSize(), Align: w.textAlign(), MaxWidth: maxWidth}
+}
+
+// anchorFactors returns where along its free space an anchor puts a widget, 0 to 1 on each axis
+func anchorFactors(a Anchor) (float32, float32) {
+	switch a {
+	case AnchorTop:
+		return 0.5, 0
+	case AnchorTopRight:
+		return 1, 0
+	case AnchorLeft:
+		return 0, 0.5
+	case AnchorCenter:
+		return 0.5, 0.5
+	case AnchorRight:
+		return 1, 0.5
+	case AnchorBottomLeft:
+		return 0, 1
+	case AnchorBottom:
+		return 0.5, 1
+	case AnchorBottomRight:
+		return 1, 1
+	}
+	return 0, 0
+}
+
+// crossOffset places a sized child across a row or column by its anchor
+func crossOffset(a Anchor, free float32, horizontal bool) float32 {
+	fx, fy := anchorFactors(a)
+	if horizontal {
+		return free * fx
+	}
+	return free * fy
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"Gopher3D/internal/renderer"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LayoutExtension is the file extension of UI layout assets
+const LayoutExtension = ".gui"
+
+// Layout is a tree of widgets loaded from a .gui JSON file. Its root is placed
+// against the whole window by its anchor.
+type Layout struct {
+	Name   string  `json:"name"`
+	Root   *Widget `json:"root"`
+	Hidden bool    `json:"hidden,omitempty"`
+
+	Path string `json:"-"` // File the layout was loaded from
+}
+
+// NewLayout creates a layout holding an empty full-window panel
+func NewLayout(name string) *Layout {
+	l := &Layout{Name: name, Root: &Widget{Type: WidgetPanel, Anchor: AnchorStretch}}
+	l.link()
+	return l
+}
+
+// LoadLayout reads a .gui file and loads the fonts its widgets use
+func LoadLayout(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := ParseLayout(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	l.Path = path
+	if l.Name == "" {
+		l.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := l.LoadFonts(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return l, nil
+}
+
+// ParseLayout decodes and validates .gui JSON
+func ParseLayout(data []byte) (*Layout, error) {
+	var l Layout
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, err
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Save writes the layout back to its file
+func (l *Layout) Save() error {
+	if l.Path == "" {
+		return fmt.Errorf("layout %q has no file", l.Name)
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.Path, data, 0644)
+}
+
+// ResolvePath returns a path from the layout file as one usable from the working directory
+func (l *Layout) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || l.Path == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(l.Path), filepath.FromSlash(path))
+}
+
+// Find returns the first widget with the given ID, or nil
+func (l *Layout) Find(id string) *Widget {
+	if l.Root == nil || id == "" {
+		return nil
+	}
+	return l.Root.Find(id)
+}
+
+// Validate links the widget tree and rejects unknown types, anchors and repeated IDs
+func (l *Layout) Validate() error {
+	if l.Root == nil {
+		return fmt.Errorf("layout has no root widget")
+	}
+	ids := make(map[string]bool)
+	var check func(w *Widget) error
+	check = func(w *Widget) error {
+		if !validType(w.Type) {
+			return fmt.Errorf("widget %q has unknown type %q", w.ID, w.Type)
+		}
+		if w.Anchor != "" && !validAnchor(w.Anchor) {
+			return fmt.Errorf("widget %q has unknown anchor %q", w.ID, w.Anchor)
+		}
+		switch w.TextAlign {
+		case "", renderer.AlignLeft, renderer.AlignCenter, renderer.AlignRight:
+		default:
+			return fmt.Errorf("widget %q has unknown text alignment %q", w.ID, w.TextAlign)
+		}
+		if w.ID != "" {
+			if ids[w.ID] {
+				return fmt.Errorf("duplicate widget id %q", w.ID)
+			}
+			ids[w.ID] = true
+		}
+		for _, child := range w.Children {
+			if child == nil {
+				return fmt.Errorf("widget %q has an empty child", w.ID)
+			}
+			if err := check(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(l.Root); err != nil {
+		return err
+	}
+	l.link()
+	return nil
+}
+
+// LoadFonts loads the font files widgets name; widgets without one use the built-in font
+func (l *Layout) LoadFonts() error {
+	var err error
+	l.Root.Walk(func(w *Widget) {
+		w.font = nil
+		if w.Font == "" || err != nil {
+			return
+		}
+		w.font, err = renderer.LoadFont(l.ResolvePath(w.Font))
+	})
+	return err
+}
+
+// link points every widget at its parent and the layout
+func (l *Layout) link() {
+	var link func(w, parent *Widget)
+	link = func(w, parent *Widget) {
+		w.parent = parent
+		w.layout = l
+		for _, child := range w.Children {
+			link(child, w)
+		}
+	}
+	link(l.Root, nil)
+}
+
+func validType(t WidgetType) bool {
+	for _, known := range WidgetTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func validAnchor(a Anchor) bool {
+	for _, known := range Anchors {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package ui
+
+import "testing"
+
+const hudLayout = `{
+  "name": "hud",
+  "root": {
+    "type": "panel",
+    "anchor": "stretch",
+    "children": [
+      {"id": "health", "type": "bar", "anchor": "bottom-left", "offset": [10, -10], "size": [200, 20], "value": 75, "max": 100},
+      {"id": "menu", "type": "column", "anchor": "center", "padding": 10, "spacing": 5, "children": [
+        {"id": "play", "type": "button", "text": "Play", "size": [120, 30]},
+        {"id": "volume", "type": "slider", "size": [120, 20], "value": 0.5, "max": 1},
+        {"id": "quit", "type": "button", "text": "Quit", "size": [0, 30]}
+      ]},
+      {"type": "row", "anchor": "top-right", "size": [300, 40], "spacing": 10, "children": [
+        {"id": "icon", "type": "image", "size": [40, 40]},
+        {"id": "score", "type": "text", "text": "Score", "grow": true}
+      ]}
+    ]
+  }
+}`
+
+func TestParseLayout(t *testing.T) {
+	l, err := ParseLayout([]byte(hudLayout))
+	if err != nil {
+		t.Fatalf("ParseLayout: %v", err)
+	}
+	play := l.Find("play")
+	if play == nil || play.Parent() != l.Find("menu") {
+		t.Fatal("widgets should be linked to their parents")
+	}
+
+	for _, bad := range []string{
+		`{"root": {"type": "window"}}`,
+		`{"root": {"type": "panel", "anchor": "middle"}}`,
+		`{"root": {"type": "panel", "children": [{"id": "a", "type": "text"}, {"id": "a", "type": "text"}]}}`,
+		`{"name": "empty"}`,
+	} {
+		if _, err := ParseLayout([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
+
+func TestArrangeAnchorsAndLines(t *testing.T) {
+	l, err := ParseLayout([]byte(hudLayout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Arrange(800, 600)
+
+	if r := l.Find("health").Rect(); r.X != 10 || r.Y != 600-20-10 || r.W != 200 {
+		t.Errorf("bottom-left bar at %+v", r)
+	}
+
+	menu := l.Find("menu").Rect()
+	// Three children of 30, 20 and 30 with two gaps of 5 and 10 padding each side
+	if menu.H != 30+20+30+2*5+2*10 || menu.W != 120+2*10 {
+		t.Errorf("column measured %vx%v", menu.W, menu.H)
+	}
+	if menu.X != (800-menu.W)/2 || menu.Y != (600-menu.H)/2 {
+		t.Errorf("centered column at %v,%v", menu.X, menu.Y)
+	}
+	volume, quit := l.Find("volume").Rect(), l.Find("quit").Rect()
+	if volume.Y != menu.Y+10+30+5 || quit.Y != volume.Y+20+5 {
+		t.Errorf("column children at y %v and %v", volume.Y, quit.Y)
+	}
+	if quit.W != 120 {
+		t.Errorf("an unsized child should fill the column's width, got %v", quit.W)
+	}
+
+	icon, score := l.Find("icon").Rect(), l.Find("score").Rect()
+	if icon.X != 800-300 || score.X != icon.X+40+10 {
+		t.Errorf("row children at x %v and %v", icon.X, score.X)
+	}
+	if score.W != 300-40-10 || score.H != 40 {
+		t.Errorf("growing text should take the rest of the row, got %vx%v", score.W, score.H)
+	}
+}
+
+func TestHiddenWidgetsTakeNoSpace(t *testing.T) {
+	l, err := ParseLayout([]byte(hudLayout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Arrange(800, 600)
+	before := l.Find("menu").Rect().H
+
+	l.Find("volume").Hidden = true
+	l.Arrange(800, 600)
+	if got := l.Find("menu").Rect().H; got != before-20-5 {
+		t.Errorf("column is %v tall with a hidden child, want %v", got, before-25)
+	}
+	if l.Find("quit").Rect().Y != l.Find("play").Rect().Y+30+5 {
+		t.Error("the next child should move up into the hidden one's place")
+	}
+}
@@ -0,0 +1,406 @@
+package ui
+
+import "Gopher3D/internal/renderer"
+
+// EventType names what happened to a widget
+type EventType string
+
+const (
+	EventClick  EventType = "click"  // A button was pressed and released, or submitted with the keyboard
+	EventChange EventType = "change" // A slider's value was changed by the player
+	EventFocus  EventType = "focus"
+	EventBlur   EventType = "blur"
+)
+
+// Event is delivered to the handlers subscribed to a widget
+type Event struct {
+	Type   EventType
+	Widget *Widget
+	Value  float32 // Slider value for EventChange
+}
+
+// Input is the state of the mouse and the navigation keys this frame. Keys
+// act when they go down, so callers pass whether each is held.
+type Input struct {
+	MouseX, MouseY float32 // Pixels from the window's top-left
+	MouseDown      bool
+
+	Next, Previous     bool // Move focus, e.g. Tab and Shift+Tab
+	Increase, Decrease bool // Step the focused slider
+	Submit             bool // Click the focused button
+}
+
+type subscription struct {
+	id      string
+	event   EventType
+	handler func(Event)
+}
+
+// Manager holds the layouts on screen, routes input to their widgets and
+// delivers widget events to subscribers
+type Manager struct {
+	layouts       []*Layout
+	subscriptions []subscription
+	focus         *Widget
+	hover         *Widget
+	pressed       *Widget
+	last          Input
+}
+
+// GlobalManager is the UI the engine updates and draws every frame
+var GlobalManager = NewManager()
+
+// NewManager creates a manager with no layouts
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Add puts a layout on screen above the ones already there
+func (m *Manager) Add(l *Layout) {
+	m.layouts = append(m.layouts, l)
+}
+
+// Remove takes a layout off screen
+func (m *Manager) Remove(l *Layout) {
+	for i, existing := range m.layouts {
+		if existing == l {
+			m.layouts = append(m.layouts[:i], m.layouts[i+1:]...)
+			break
+		}
+	}
+	m.forget(func(w *Widget) bool { return w.layout == l })
+}
+
+// Clear removes every layout and subscription
+func (m *Manager) Clear() {
+	m.layouts = nil
+	m.subscriptions = nil
+	m.focus, m.hover, m.pressed = nil, nil, nil
+}
+
+// Layouts returns the layouts on screen, bottom first
+func (m *Manager) Layouts() []*Layout {
+	return m.layouts
+}
+
+// FindLayout returns the layout with the given name, or nil
+func (m *Manager) FindLayout(name string) *Layout {
+	for _, l := range m.layouts {
+		if l.Name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// Find returns the first widget with the given ID in any layout, or nil
+func (m *Manager) Find(id string) *Widget {
+	for _, l := range m.layouts {
+		if w := l.Find(id); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// On calls handler whenever the widget with the given ID fires the event. An
+// empty ID subscribes to every widget.
+func (m *Manager) On(id string, event EventType, handler func(Event)) {
+	m.subscriptions = append(m.subscriptions, subscription{id: id, event: event, handler: handler})
+}
+
+// Off drops every handler subscribed to a widget ID
+func (m *Manager) Off(id string) {
+	kept := m.subscriptions[:0]
+	for _, s := range m.subscriptions {
+		if s.id != id {
+			kept = append(kept, s)
+		}
+	}
+	m.subscriptions = kept
+}
+
+// Focused returns the widget receiving keyboard input, or nil
+func (m *Manager) Focused() *Widget {
+	return m.focus
+}
+
+// Focus gives a widget keyboard input; nil clears the focus
+func (m *Manager) Focus(w *Widget) {
+	if w == m.focus {
+		return
+	}
+	if previous := m.focus; previous != nil {
+		previous.focused = false
+		m.focus = nil
+		m.emit(Event{Type: EventBlur, Widget: previous})
+	}
+	if w != nil && w.interactive() {
+		w.focused = true
+		m.focus = w
+		m.emit(Event{Type: EventFocus, Widget: w})
+	}
+}
+
+// WantsMouse reports whether the mouse is over or dragging a widget, so the
+// game can ignore clicks meant for the UI
+func (m *Manager) WantsMouse() bool {
+	return m.hover != nil || m.pressed != nil
+}
+
+// Update lays out the layouts for the window size and applies the frame's input
+func (m *Manager) Update(in Input, width, height float32) {
+	for _, l := range m.layouts {
+		if !l.Hidden {
+			l.Arrange(width, height)
+		}
+	}
+	// Widgets that were hidden, disabled or removed let go of the input
+	m.forget(func(w *Widget) bool { return !w.interactive() || !m.contains(w) })
+
+	m.updateMouse(in)
+	m.updateKeys(in)
+	m.last = in
+}
+
+func (m *Manager) updateMouse(in Input) {
+	hover := m.widgetAt(in.MouseX, in.MouseY)
+	if hover != m.hover {
+		if m.hover != nil {
+			m.hover.hovered = false
+		}
+		if hover != nil {
+			hover.hovered = true
+		}
+		m.hover = hover
+	}
+
+	switch {
+	case in.MouseDown && !m.last.MouseDown:
+		if hover == nil {
+			m.Focus(nil)
+			return
+		}
+		m.pressed = hover
+		hover.pressed = true
+		m.Focus(hover)
+		if hover.Type == WidgetSlider {
+			m.drag(hover, in.MouseX)
+		}
+	case in.MouseDown && m.pressed != nil:
+		if m.pressed.Type == WidgetSlider {
+			m.drag(m.pressed, in.MouseX)
+		}
+	case !in.MouseDown && m.pressed != nil:
+		released := m.pressed
+		released.pressed = false
+		m.pressed = nil
+		if released.Type == WidgetButton && released == hover {
+			m.emit(Event{Type: EventClick, Widget: released})
+		}
+	}
+}
+
+func (m *Manager) updateKeys(in Input) {
+	switch {
+	case in.Next && !m.last.Next:
+		m.moveFocus(1)
+	case in.Previous && !m.last.Previous:
+		m.moveFocus(-1)
+	}
+	w := m.focus
+	if w == nil {
+		return
+	}
+	switch w.Type {
+	case WidgetButton:
+		if in.Submit && !m.last.Submit {
+			m.emit(Event{Type: EventClick, Widget: w})
+		}
+	case WidgetSlider:
+		if in.Increase && !m.last.Increase {
+			m.setValue(w, w.Value+w.step())
+		}
+		if in.Decrease && !m.last.Decrease {
+			m.setValue(w, w.Value-w.step())
+		}
+	}
+}
+
+// moveFocus steps the focus through the focusable widgets in draw order, wrapping around
+func (m *Manager) moveFocus(direction int) {
+	focusable := m.focusable()
+	if len(focusable) == 0 {
+		return
+	}
+	next := 0
+	if direction < 0 {
+		next = len(focusable) - 1
+	}
+	for i, w := range focusable {
+		if w == m.focus {
+			next = (i + direction + len(focusable)) % len(focusable)
+			break
+		}
+	}
+	m.Focus(focusable[next])
+}
+
+func (m *Manager) focusable() []*Widget {
+	var widgets []*Widget
+	for _, l := range m.layouts {
+		if l.Hidden || l.Root == nil {
+			continue
+		}
+		l.Root.Walk(func(w *Widget) {
+			if w.interactive() {
+				widgets = append(widgets, w)
+			}
+		})
+	}
+	return widgets
+}
+
+// widgetAt returns the topmost interactive widget under a point
+func (m *Manager) widgetAt(x, y float32) *Widget {
+	for i := len(m.layouts) - 1; i >= 0; i-- {
+		l := m.layouts[i]
+		if l.Hidden || l.Root == nil {
+			continue
+		}
+		var hit *Widget
+		l.Root.Walk(func(w *Widget) {
+			if w.interactive() && w.rect.Contains(x, y) {
+				hit = w
+			}
+		})
+		if hit != nil {
+			return hit
+		}
+	}
+	return nil
+}
+
+// drag moves a slider to the mouse
+func (m *Manager) drag(w *Widget, mouseX float32) {
+	if w.rect.W <= 0 {
+		return
+	}
+	fraction := min(max((mouseX-w.rect.X)/w.rect.W, 0), 1)
+	m.setValue(w, w.Min+fraction*(w.Max-w.Min))
+}
+
+func (m *Manager) setValue(w *Widget, value float32) {
+	value = w.clamp(value)
+	if value == w.Value {
+		return
+	}
+	w.Value = value
+	m.emit(Event{Type: EventChange, Widget: w, Value: value})
+}
+
+func (m *Manager) emit(e Event) {
+	for _, s := range m.subscriptions {
+		if s.event == e.Type && (s.id == "" || s.id == e.Widget.ID) {
+			s.handler(e)
+		}
+	}
+}
+
+// forget drops hover, press and focus from widgets matching drop
+func (m *Manager) forget(drop func(*Widget) bool) {
+	if m.hover != nil && drop(m.hover) {
+		m.hover.hovered = false
+		m.hover = nil
+	}
+	if m.pressed != nil && drop(m.pressed) {
+		m.pressed.pressed = false
+		m.pressed = nil
+	}
+	if m.focus != nil && drop(m.focus) {
+		m.Focus(nil)
+	}
+}
+
+// contains reports whether a widget still belongs to a layout on screen
+func (m *Manager) contains(w *Widget) bool {
+	root := w
+	for root.parent != nil {
+		root = root.parent
+	}
+	for _, l := range m.layouts {
+		if l == w.layout && l.Root == root {
+			return true
+		}
+	}
+	return false
+}
+
+// DrawList returns what the renderer should draw for the visible layouts, back to front
+func (m *Manager) DrawList() []renderer.UIDrawItem {
+	var items []renderer.UIDrawItem
+	for _, l := range m.layouts {
+		if !l.Hidden && l.Root != nil {
+			items = l.Root.appendDrawItems(items)
+		}
+	}
+	return items
+}
+
+// appendDrawItems adds the widget and its children to a draw list
+func (w *Widget) appendDrawItems(items []renderer.UIDrawItem) []renderer.UIDrawItem {
+	if w.Hidden {
+		return items
+	}
+	if w.focused {
+		outline := w.rect
+		outline.X, outline.Y, outline.W, outline.H = outline.X-2, outline.Y-2, outline.W+4, outline.H+4
+		items = append(items, renderer.UIDrawItem{Rect: outline, Color: focusColor})
+	}
+
+	switch w.Type {
+	case WidgetButton:
+		color := shade(orColor(w.Color, defaultButtonColor), w)
+		items = append(items, renderer.UIDrawItem{Rect: w.rect, Color: color, Image: w.layoutPath(w.Image)})
+	case WidgetSlider, WidgetBar:
+		items = append(items, renderer.UIDrawItem{Rect: w.rect, Color: shade(orColor(w.Color, defaultTrackColor), w)})
+		fill := w.rect
+		fill.W *= w.Fraction()
+		items = append(items, renderer.UIDrawItem{Rect: fill, Color: shade(orColor(w.FillColor, defaultFillColor), w)})
+	case WidgetImage:
+		color := orColor(w.Color, [4]float32{1, 1, 1, 1})
+		items = append(items, renderer.UIDrawItem{Rect: w.rect, Color: color, Image: w.layoutPath(w.Image)})
+	default:
+		if w.Color[3] > 0 {
+			items = append(items, renderer.UIDrawItem{Rect: w.rect, Color: w.Color, Image: w.layoutPath(w.Image)})
+		}
+	}
+	if w.hasText() && w.label != nil {
+		items = append(items, renderer.UIDrawItem{Text: w.label})
+	}
+	for _, child := range w.Children {
+		items = child.appendDrawItems(items)
+	}
+	return items
+}
+
+// shade brightens hovered widgets, darkens pressed ones and dims disabled ones
+func shade(c [4]float32, w *Widget) [4]float32 {
+	scale := float32(1)
+	switch {
+	case w.Disabled:
+		c[3] *= 0.5
+	case w.pressed:
+		scale = 0.8
+	case w.hovered:
+		scale = 1.25
+	}
+	return [4]float32{min(c[0]*scale, 1), min(c[1]*scale, 1), min(c[2]*scale, 1), c[3]}
+}
+
+func (w *Widget) layoutPath(path string) string {
+	if w.layout == nil {
+		return path
+	}
+	return w.layout.ResolvePath(path)
+}
@@ -0,0 +1,148 @@
+package ui
+
+import "testing"
+
+func newTestManager(t *testing.T) (*Manager, *Layout) {
+	t.Helper()
+	l, err := ParseLayout([]byte(hudLayout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager()
+	m.Add(l)
+	m.Update(Input{}, 800, 600)
+	return m, l
+}
+
+// center returns the middle of a widget, for pointing the mouse at it
+func center(w *Widget) (float32, float32) {
+	r := w.Rect()
+	return r.X + r.W/2, r.Y + r.H/2
+}
+
+func TestButtonClick(t *testing.T) {
+	m, l := newTestManager(t)
+	clicks := 0
+	m.On("play", EventClick, func(e Event) {
+		if e.Widget.ID != "play" {
+			t.Errorf("click delivered for %q", e.Widget.ID)
+		}
+		clicks++
+	})
+
+	x, y := center(l.Find("play"))
+	m.Update(Input{MouseX: x, MouseY: y, MouseDown: true}, 800, 600)
+	if clicks != 0 || m.Focused() != l.Find("play") || !m.WantsMouse() {
+		t.Fatal("pressing a button should focus it without clicking yet")
+	}
+	m.Update(Input{MouseX: x, MouseY: y}, 800, 600)
+	if clicks != 1 {
+		t.Fatalf("release over the button gave %d clicks", clicks)
+	}
+
+	// Dragging off before releasing cancels the click
+	m.Update(Input{MouseX: x, MouseY: y, MouseDown: true}, 800, 600)
+	m.Update(Input{MouseX: 1, MouseY: 1, MouseDown: true}, 800, 600)
+	m.Update(Input{MouseX: 1, MouseY: 1}, 800, 600)
+	if clicks != 1 {
+		t.Error("releasing away from the button should not click it")
+	}
+
+	l.Find("play").Disabled = true
+	m.Update(Input{MouseX: x, MouseY: y, MouseDown: true}, 800, 600)
+	m.Update(Input{MouseX: x, MouseY: y}, 800, 600)
+	if clicks != 1 {
+		t.Error("disabled buttons should not click")
+	}
+}
+
+func TestSliderDrag(t *testing.T) {
+	m, l := newTestManager(t)
+	volume := l.Find("volume")
+	var changed []float32
+	m.On("volume", EventChange, func(e Event) { changed = append(changed, e.Value) })
+
+	r := volume.Rect()
+	m.Update(Input{MouseX: r.X + r.W/4, MouseY: r.Y + 1, MouseDown: true}, 800, 600)
+	m.Update(Input{MouseX: r.X + r.W*2, MouseY: r.Y + 100, MouseDown: true}, 800, 600)
+	m.Update(Input{}, 800, 600)
+
+	if len(changed) != 2 || changed[0] != 0.25 || changed[1] != 1 {
+		t.Errorf("drag changes = %v, want [0.25 1] clamped to the range", changed)
+	}
+	if l.Find("health").Fraction() != 0.75 {
+		t.Error("bars report their fill from Value, Min and Max")
+	}
+}
+
+func TestKeyboardNavigation(t *testing.T) {
+	m, l := newTestManager(t)
+	var order []string
+	m.On("", EventFocus, func(e Event) { order = append(order, e.Widget.ID) })
+	clicked := ""
+	m.On("", EventClick, func(e Event) { clicked = e.Widget.ID })
+
+	press := func(in Input) {
+		m.Update(in, 800, 600)
+		m.Update(Input{}, 800, 600)
+	}
+	for range 4 {
+		press(Input{Next: true})
+	}
+	if want := []string{"play", "volume", "quit", "play"}; len(order) != len(want) || order[0] != want[0] || order[1] != want[1] || order[2] != want[2] || order[3] != want[3] {
+		t.Errorf("focus order %v, want %v", order, want)
+	}
+	press(Input{Previous: true})
+	if m.Focused() != l.Find("quit") {
+		t.Errorf("Previous from the first widget should wrap to the last, got %v", m.Focused())
+	}
+
+	press(Input{Submit: true})
+	if clicked != "quit" {
+		t.Errorf("Submit clicked %q, want quit", clicked)
+	}
+
+	press(Input{Previous: true})
+	volume := l.Find("volume")
+	press(Input{Increase: true})
+	if volume.Value != 0.55 {
+		t.Errorf("Increase moved the slider to %v, want a twentieth of the range", volume.Value)
+	}
+
+	// Holding a key acts once
+	m.Update(Input{Decrease: true}, 800, 600)
+	m.Update(Input{Decrease: true}, 800, 600)
+	if volume.Value != 0.5 {
+		t.Errorf("held Decrease left the slider at %v", volume.Value)
+	}
+
+	volume.Hidden = true
+	m.Update(Input{}, 800, 600)
+	if m.Focused() != nil {
+		t.Error("hiding the focused widget should clear the focus")
+	}
+}
+
+func TestDrawList(t *testing.T) {
+	m, l := newTestManager(t)
+	items := m.DrawList()
+	texts := 0
+	for _, item := range items {
+		if item.Text != nil {
+			texts++
+		}
+	}
+	// Play, Quit and Score
+	if texts != 3 {
+		t.Errorf("got %d text items, want 3", texts)
+	}
+
+	l.Hidden = true
+	if len(m.DrawList()) != 0 {
+		t.Error("hidden layouts should draw nothing")
+	}
+	m.Remove(l)
+	if m.Find("play") != nil {
+		t.Error("removed layouts should not be searched")
+	}
+}
@@ -0,0 +1,238 @@
+package ui
+
+import "Gopher3D/internal/renderer"
+
+// WidgetType selects what a widget draws and how it lays out its children
+type WidgetType string
+
+const (
+	WidgetPanel  WidgetType = "panel"  // Places children by their anchors
+	WidgetRow    WidgetType = "row"    // Lays children out left to right
+	WidgetColumn WidgetType = "column" // Lays children out top to bottom
+	WidgetImage  WidgetType = "image"
+	WidgetText   WidgetType = "text"
+	WidgetButton WidgetType = "button"
+	WidgetSlider WidgetType = "slider" // Dragged or stepped with the keyboard
+	WidgetBar    WidgetType = "bar"    // A slider the player cannot change, for health and progress
+)
+
+// WidgetTypes lists the widget types, in display order
+var WidgetTypes = []WidgetType{WidgetPanel, WidgetRow, WidgetColumn, WidgetImage, WidgetText, WidgetButton, WidgetSlider, WidgetBar}
+
+// Anchor is the point of its panel a widget is placed against
+type Anchor string
+
+const (
+	AnchorTopLeft     Anchor = "top-left"
+	AnchorTop         Anchor = "top"
+	AnchorTopRight    Anchor = "top-right"
+	AnchorLeft        Anchor = "left"
+	AnchorCenter      Anchor = "center"
+	AnchorRight       Anchor = "right"
+	AnchorBottomLeft  Anchor = "bottom-left"
+	AnchorBottom      Anchor = "bottom"
+	AnchorBottomRight Anchor = "bottom-right"
+	AnchorStretch     Anchor = "stretch" // Fills the panel, ignoring Size and Offset
+)
+
+// Anchors lists the anchors, in display order
+var Anchors = []Anchor{
+	AnchorTopLeft, AnchorTop, AnchorTopRight,
+	AnchorLeft, AnchorCenter, AnchorRight,
+	AnchorBottomLeft, AnchorBottom, AnchorBottomRight,
+	AnchorStretch,
+}
+
+// Widget is a node of a UI layout
+type Widget struct {
+	ID   string     `json:"id,omitempty"` // Name scripts find the widget and subscribe to it by
+	Type WidgetType `json:"type"`
+
+	Anchor  Anchor     `json:"anchor,omitempty"`  // Placement inside a panel; unset is top-left
+	Offset  [2]float32 `json:"offset,omitzero"`   // Pixels added to the anchored position, y down
+	Size    [2]float32 `json:"size,omitzero"`     // Pixels; 0 fits the content, or fills across a row or column
+	Grow    bool       `json:"grow,omitempty"`    // Takes a share of a row or column's spare length
+	Padding float32    `json:"padding,omitempty"` // Space inside the edges
+	Spacing float32    `json:"spacing,omitempty"` // Gap between a row or column's children
+
+	Color     [4]float32 `json:"color,omitzero"`     // Background, image tint or slider track
+	FillColor [4]float32 `json:"fillColor,omitzero"` // Filled part of a slider or bar
+	Image     string     `json:"image,omitempty"`    // Relative to the layout file unless absolute
+
+	Text      string             `json:"text,omitempty"`
+	Font      string             `json:"font,omitempty"` // TTF or OTF file; empty uses the built-in font
+	FontSize  float32            `json:"fontSize,omitempty"`
+	TextColor [4]float32         `json:"textColor,omitzero"`
+	TextAlign renderer.TextAlign `json:"textAlign,omitempty"` // Unset centers buttons and left-aligns the rest
+
+	Value float32 `json:"value,omitempty"` // Slider and bar position between Min and Max
+	Min   float32 `json:"min,omitempty"`
+	Max   float32 `json:"max,omitempty"`
+	Step  float32 `json:"step,omitempty"` // Keyboard step; 0 moves a twentieth of the range
+
+	Hidden   bool `json:"hidden,omitempty"`
+	Disabled bool `json:"disabled,omitempty"` // Drawn dimmed and skipped by focus
+
+	Children []*Widget `json:"children,omitempty"`
+
+	parent  *Widget
+	layout  *Layout
+	rect    renderer.UIRect
+	font    *renderer.Font
+	label   *renderer.Text
+	hovered bool
+	pressed bool
+	focused bool
+}
+
+// Default colors of widgets that leave them unset
+var (
+	defaultButtonColor = [4]float32{0.18, 0.2, 0.25, 0.9}
+	defaultTrackColor  = [4]float32{0.1, 0.1, 0.12, 0.8}
+	defaultFillColor   = [4]float32{0.3, 0.6, 1, 1}
+	defaultTextColor   = [4]float32{1, 1, 1, 1}
+	focusColor         = [4]float32{1, 0.8, 0.3, 1}
+)
+
+// defaultFontSize is the text size of widgets that leave FontSize unset
+const defaultFontSize = 20
+
+// Rect returns where the widget was placed by the last layout pass
+func (w *Widget) Rect() renderer.UIRect {
+	return w.rect
+}
+
+// Parent returns the widget containing this one, or nil for a layout's root
+func (w *Widget) Parent() *Widget {
+	return w.parent
+}
+
+// Layout returns the layout the widget belongs to
+func (w *Widget) Layout() *Layout {
+	return w.layout
+}
+
+// Focused reports whether keyboard input goes to the widget
+func (w *Widget) Focused() bool {
+	return w.focused
+}
+
+// Hovered reports whether the mouse is over the widget
+func (w *Widget) Hovered() bool {
+	return w.hovered
+}
+
+// SetValue moves a slider or bar, clamped to its range, without firing events
+func (w *Widget) SetValue(value float32) {
+	w.Value = w.clamp(value)
+}
+
+// Fraction returns how far Value is between Min and Max
+func (w *Widget) Fraction() float32 {
+	if w.Max <= w.Min {
+		return 0
+	}
+	return (w.clamp(w.Value) - w.Min) / (w.Max - w.Min)
+}
+
+func (w *Widget) clamp(value float32) float32 {
+	if w.Max <= w.Min {
+		return value
+	}
+	return min(max(value, w.Min), w.Max)
+}
+
+// step returns how far one key press moves a slider
+func (w *Widget) step() float32 {
+	if w.Step > 0 {
+		return w.Step
+	}
+	return (w.Max - w.Min) / 20
+}
+
+// Find returns the first widget in this subtree with the given ID, or nil
+func (w *Widget) Find(id string) *Widget {
+	if w.ID == id {
+		return w
+	}
+	for _, child := range w.Children {
+		if found := child.Find(id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for the widget and its descendants in draw order
+func (w *Widget) Walk(fn func(*Widget)) {
+	fn(w)
+	for _, child := range w.Children {
+		child.Walk(fn)
+	}
+}
+
+// AddChild appends a widget to this one
+func (w *Widget) AddChild(child *Widget) {
+	child.parent = w
+	child.setLayout(w.layout)
+	w.Children = append(w.Children, child)
+}
+
+// RemoveChild detaches a widget from this one
+func (w *Widget) RemoveChild(child *Widget) {
+	for i, existing := range w.Children {
+		if existing == child {
+			w.Children = append(w.Children[:i], w.Children[i+1:]...)
+			child.parent = nil
+			return
+		}
+	}
+}
+
+func (w *Widget) setLayout(l *Layout) {
+	w.Walk(func(widget *Widget) { widget.layout = l })
+}
+
+// visible reports whether the widget and all its ancestors are shown
+func (w *Widget) visible() bool {
+	for widget := w; widget != nil; widget = widget.parent {
+		if widget.Hidden {
+			return false
+		}
+	}
+	return w.layout == nil || !w.layout.Hidden
+}
+
+// interactive reports whether the widget takes clicks and focus
+func (w *Widget) interactive() bool {
+	return (w.Type == WidgetButton || w.Type == WidgetSlider) && !w.Disabled && w.visible()
+}
+
+func (w *Widget) hasText() bool {
+	return w.Text != "" && (w.Type == WidgetText || w.Type == WidgetButton)
+}
+
+func (w *Widget) fontSize() float32 {
+	if w.FontSize > 0 {
+		return w.FontSize
+	}
+	return defaultFontSize
+}
+
+func (w *Widget) textAlign() renderer.TextAlign {
+	if w.TextAlign != "" {
+		return w.TextAlign
+	}
+	if w.Type == WidgetButton {
+		return renderer.AlignCenter
+	}
+	return renderer.AlignLeft
+}
+
+// orColor returns c, or fallback when c is unset
+func orColor(c, fallback [4]float32) [4]float32 {
+	if c == ([4]float32{}) {
+		return fallback
+	}
+	return c
+}
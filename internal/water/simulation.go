@@ -64,61 +64,68 @@ type Simulation struct {
 	DistortionStrength float32
 	NormalStrength     float32
 
+	// Planar reflection size as a fraction of the view; 0 reflects only the sky color
+	ReflectionResolution float32
+
 	// Texture
 	TexturePath string
+
+	reflection *renderer.PlanarReflection
 }
 
 // Config is an exportable config for saving/loading water settings
 type Config struct {
-	OceanSize           float32    `json:"ocean_size"`
-	BaseAmplitude       float32    `json:"base_amplitude"`
-	WaterColor          [3]float32 `json:"water_color"`
-	Transparency        float32    `json:"transparency"`
-	WaveSpeedMultiplier float32    `json:"wave_speed_multiplier"`
-	WaveHeight          float32    `json:"wave_height"`
-	WaveRandomness      float32    `json:"wave_randomness"`
-	FoamEnabled         bool       `json:"foam_enabled"`
-	FoamIntensity       float32    `json:"foam_intensity"`
-	CausticsEnabled     bool       `json:"caustics_enabled"`
-	CausticsIntensity   float32    `json:"caustics_intensity"`
-	CausticsScale       float32    `json:"caustics_scale"`
-	SpecularIntensity   float32    `json:"specular_intensity"`
-	ShadowStrength      float32    `json:"shadow_strength"`
-	DistortionStrength  float32    `json:"distortion_strength"`
-	NormalStrength      float32    `json:"normal_strength"`
-	TexturePath         string     `json:"texture_path"`
+	OceanSize            float32    `json:"ocean_size"`
+	BaseAmplitude        float32    `json:"base_amplitude"`
+	WaterColor           [3]float32 `json:"water_color"`
+	Transparency         float32    `json:"transparency"`
+	WaveSpeedMultiplier  float32    `json:"wave_speed_multiplier"`
+	WaveHeight           float32    `json:"wave_height"`
+	WaveRandomness       float32    `json:"wave_randomness"`
+	FoamEnabled          bool       `json:"foam_enabled"`
+	FoamIntensity        float32    `json:"foam_intensity"`
+	CausticsEnabled      bool       `json:"caustics_enabled"`
+	CausticsIntensity    float32    `json:"caustics_intensity"`
+	CausticsScale        float32    `json:"caustics_scale"`
+	SpecularIntensity    float32    `json:"specular_intensity"`
+	ShadowStrength       float32    `json:"shadow_strength"`
+	DistortionStrength   float32    `json:"distortion_strength"`
+	NormalStrength       float32    `json:"normal_strength"`
+	ReflectionResolution float32    `json:"reflection_resolution,omitempty"`
+	TexturePath          string     `json:"texture_path"`
 }
 
 // NewSimulation creates a new water simulation with the given size and amplitude
 func NewSimulation(eng *engine.Gopher, size float32, amplitude float32) *Simulation {
 	ws := &Simulation{
-		Engine:              eng,
-		Shader:              renderer.InitWaterShader(),
-		StartTime:           time.Now(),
-		WaveCount:           MaxWaves,
-		WaveDirections:      make([]mgl32.Vec3, MaxWaves),
-		WaveAmplitudes:      make([]float32, MaxWaves),
-		WaveFrequencies:     make([]float32, MaxWaves),
-		WaveSpeeds:          make([]float32, MaxWaves),
-		WavePhases:          make([]float32, MaxWaves),
-		WaveSteepness:       make([]float32, MaxWaves),
-		OceanSize:           size,
-		BaseAmplitude:       amplitude,
-		WaterColor:          mgl32.Vec3{0.06, 0.22, 0.45}, // Natural ocean blue
-		Transparency:        0.85,
-		WaveSpeedMultiplier: 1.0,
-		WaveHeight:          1.0,
-		WaveRandomness:      0.0,
-		FoamEnabled:         true,
-		FoamIntensity:       0.5,
-		CausticsEnabled:     false,
-		CausticsIntensity:   0.3,
-		CausticsScale:       0.003,
-		SpecularIntensity:   1.0,
-		ShadowStrength:      0.5,
-		DistortionStrength:  0.2,
-		NormalStrength:      1.0,
-		LastSkyColor:        mgl32.Vec3{0.5, 0.7, 1.0}, // Default sky blue
+		Engine:               eng,
+		Shader:               renderer.InitWaterShader(),
+		StartTime:            renderer.Clock.Now(),
+		WaveCount:            MaxWaves,
+		WaveDirections:       make([]mgl32.Vec3, MaxWaves),
+		WaveAmplitudes:       make([]float32, MaxWaves),
+		WaveFrequencies:      make([]float32, MaxWaves),
+		WaveSpeeds:           make([]float32, MaxWaves),
+		WavePhases:           make([]float32, MaxWaves),
+		WaveSteepness:        make([]float32, MaxWaves),
+		OceanSize:            size,
+		BaseAmplitude:        amplitude,
+		WaterColor:           mgl32.Vec3{0.06, 0.22, 0.45}, // Natural ocean blue
+		Transparency:         0.85,
+		WaveSpeedMultiplier:  1.0,
+		WaveHeight:           1.0,
+		WaveRandomness:       0.0,
+		FoamEnabled:          true,
+		FoamIntensity:        0.5,
+		CausticsEnabled:      false,
+		CausticsIntensity:    0.3,
+		CausticsScale:        0.003,
+		SpecularIntensity:    1.0,
+		ShadowStrength:       0.5,
+		DistortionStrength:   0.2,
+		NormalStrength:       1.0,
+		ReflectionResolution: renderer.DefaultReflectionResolution,
+		LastSkyColor:         mgl32.Vec3{0.5, 0.7, 1.0}, // Default sky blue
 	}
 
 	// Initialize wave parameters
@@ -231,7 +238,7 @@ func (ws *Simulation) Start() {
 
 // Update implements the Behaviour interface - called every frame
 func (ws *Simulation) Update() {
-	ws.CurrentTime = float32(time.Since(ws.StartTime).Seconds())
+	ws.CurrentTime = float32(renderer.Clock.Since(ws.StartTime).Seconds())
 
 	if ws.Model == nil || ws.Model.CustomUniforms == nil {
 		return
@@ -262,6 +269,7 @@ func (ws *Simulation) Update() {
 	ws.Model.CustomUniforms["shadowIntensity"] = ws.ShadowStrength
 	ws.Model.CustomUniforms["waterDistortionIntensity"] = ws.DistortionStrength
 	ws.Model.CustomUniforms["waterNormalIntensity"] = ws.NormalStrength
+	ws.updateReflection()
 
 	// Get the active light
 	var activeLight *renderer.Light
@@ -311,6 +319,36 @@ func (ws *Simulation) Update() {
 	}
 }
 
+// updateReflection keeps a planar reflection on the water surface while
+// ReflectionResolution is above zero
+func (ws *Simulation) updateReflection() {
+	rend, ok := ws.Engine.GetRenderer().(*renderer.OpenGLRenderer)
+	if !ok {
+		return
+	}
+	if ws.ReflectionResolution <= 0 {
+		if ws.reflection != nil {
+			rend.RemovePlanarReflection(ws.reflection)
+			ws.reflection = nil
+		}
+		delete(ws.Model.CustomUniforms, "reflectionTexture")
+		ws.Model.CustomUniforms["enablePlanarReflection"] = false
+		return
+	}
+
+	// The renderer drops the reflection if the model is removed
+	if ws.reflection == nil || ws.reflection.Texture == nil {
+		ws.reflection = renderer.NewPlanarReflection(fmt.Sprintf("water_%p", ws), ws.Model)
+		rend.AddPlanarReflection(ws.reflection)
+	}
+	ws.reflection.Surface = ws.Model
+	ws.reflection.Resolution = ws.ReflectionResolution
+	// Keep the wave troughs in the reflection
+	ws.reflection.ClipOffset = ws.BaseAmplitude * ws.WaveHeight
+	ws.Model.CustomUniforms["reflectionTexture"] = ws.reflection.Texture
+	ws.Model.CustomUniforms["enablePlanarReflection"] = true
+}
+
 // UpdateFixed implements the Behaviour interface
 func (ws *Simulation) UpdateFixed() {}
 
@@ -418,23 +456,24 @@ func (ws *Simulation) ApplyChanges() {
 // GetConfig returns the current configuration for saving
 func (ws *Simulation) GetConfig() Config {
 	return Config{
-		OceanSize:           ws.OceanSize,
-		BaseAmplitude:       ws.BaseAmplitude,
-		WaterColor:          [3]float32{ws.WaterColor.X(), ws.WaterColor.Y(), ws.WaterColor.Z()},
-		Transparency:        ws.Transparency,
-		WaveSpeedMultiplier: ws.WaveSpeedMultiplier,
-		WaveHeight:          ws.WaveHeight,
-		WaveRandomness:      ws.WaveRandomness,
-		FoamEnabled:         ws.FoamEnabled,
-		FoamIntensity:       ws.FoamIntensity,
-		CausticsEnabled:     ws.CausticsEnabled,
-		CausticsIntensity:   ws.CausticsIntensity,
-		CausticsScale:       ws.CausticsScale,
-		SpecularIntensity:   ws.SpecularIntensity,
-		ShadowStrength:      ws.ShadowStrength,
-		DistortionStrength:  ws.DistortionStrength,
-		NormalStrength:      ws.NormalStrength,
-		TexturePath:         ws.TexturePath,
+		OceanSize:            ws.OceanSize,
+		BaseAmplitude:        ws.BaseAmplitude,
+		WaterColor:           [3]float32{ws.WaterColor.X(), ws.WaterColor.Y(), ws.WaterColor.Z()},
+		Transparency:         ws.Transparency,
+		WaveSpeedMultiplier:  ws.WaveSpeedMultiplier,
+		WaveHeight:           ws.WaveHeight,
+		WaveRandomness:       ws.WaveRandomness,
+		FoamEnabled:          ws.FoamEnabled,
+		FoamIntensity:        ws.FoamIntensity,
+		CausticsEnabled:      ws.CausticsEnabled,
+		CausticsIntensity:    ws.CausticsIntensity,
+		CausticsScale:        ws.CausticsScale,
+		SpecularIntensity:    ws.SpecularIntensity,
+		ShadowStrength:       ws.ShadowStrength,
+		DistortionStrength:   ws.DistortionStrength,
+		NormalStrength:       ws.NormalStrength,
+		ReflectionResolution: ws.ReflectionResolution,
+		TexturePath:          ws.TexturePath,
 	}
 }
 
@@ -456,5 +495,6 @@ func (ws *Simulation) ApplyConfig(config Config) {
 	ws.ShadowStrength = config.ShadowStrength
 	ws.DistortionStrength = config.DistortionStrength
 	ws.NormalStrength = config.NormalStrength
+	ws.ReflectionResolution = config.ReflectionResolution
 	ws.TexturePath = config.TexturePath
 }
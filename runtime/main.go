@@ -4,6 +4,7 @@ import (
 	"Gopher3D/internal/engine"
 	"Gopher3D/internal/loader"
 	"Gopher3D/internal/renderer"
+	"Gopher3D/internal/ui"
 	"Gopher3D/internal/water"
 	"encoding/json"
 	"fmt"
@@ -35,6 +36,11 @@ func main() {
 		if sceneReady {
 			// Update water animation
 			updateWater(deltaTime)
+
+			// Advance the day/night cycle
+			if timeOfDay != nil {
+				timeOfDay.Advance(float32(deltaTime))
+			}
 		}
 	})
 
@@ -66,7 +72,22 @@ func loadGame() {
 		return
 	}
 
+	// Project shaders used by custom materials ship next to the scene
+	shaderDir := filepath.Join(filepath.Dir(scenePath), "shaders")
+	if info, err := os.Stat(shaderDir); err == nil && info.IsDir() {
+		renderer.SetShaderDirectory(shaderDir)
+	}
+
+	// Stream textures in while the first frames render
+	if r, ok := gameEngine.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		r.AsyncTextureLoading = true
+	}
 	loadSceneData(&scene, filepath.Dir(scenePath))
+
+	// Build every shader variant the scene uses up front to avoid first-frame hitches
+	if r, ok := gameEngine.GetRenderer().(*renderer.OpenGLRenderer); ok {
+		r.PrewarmShaderVariants()
+	}
 	fmt.Println("Game loaded!")
 }
 
@@ -94,6 +115,9 @@ func setupDefaultScene() {
 }
 
 func loadSceneData(scene *SceneData, assetsDir string) {
+	// Unversioned scenes sampled their textures without sRGB decoding
+	legacyTextures := scene.FormatVersion < 1
+
 	r, ok := gameEngine.GetRenderer().(*renderer.OpenGLRenderer)
 	if !ok {
 		fmt.Println("Could not get renderer")
@@ -160,20 +184,79 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 			}
 		}
 
+		// Custom material (.gmat)
+		if m.MaterialAsset != "" {
+			material, err := renderer.LoadMaterialAsset(filepath.Join(assetsDir, m.MaterialAsset))
+			if err != nil {
+				fmt.Printf("Failed to load material for %s: %v\n", m.Name, err)
+			} else {
+				model.CustomMaterial = material
+			}
+		}
+
+		// Texture file, or a render texture drawn by a scene camera
+		if m.TexturePath != "" && model.Material != nil {
+			texturePath := m.TexturePath
+			if _, ok := renderer.RenderTextureName(texturePath); !ok {
+				texturePath = resolveAssetPath(texturePath, assetsDir)
+			}
+			sampler := renderer.SamplerSettings{}
+			if m.TextureSampler != nil {
+				sampler = *m.TextureSampler
+			}
+			if legacyTextures {
+				sampler.ColorSpace = renderer.ColorSpaceLinear
+			}
+			if texturePath != "" {
+				model.Material.TexturePath = texturePath
+				model.Material.Sampler = sampler
+				for i := range model.MaterialGroups {
+					if model.MaterialGroups[i].Material != nil {
+						model.MaterialGroups[i].Material.TexturePath = texturePath
+						model.MaterialGroups[i].Material.Sampler = sampler
+					}
+				}
+			}
+		}
+
 		r.AddModel(model)
+		if m.Reflection != nil {
+			setupReflection(model, m.Reflection, r)
+		}
 		fmt.Printf("Loaded: %s\n", m.Name)
 	}
 
 	// Load lights
 	for i, l := range scene.Lights {
 		var light *renderer.Light
-		if l.Mode == "point" {
+		if l.Mode == "point" || l.Mode == "spot" {
 			light = renderer.CreatePointLight(
 				mgl.Vec3{l.Position[0], l.Position[1], l.Position[2]},
 				mgl.Vec3{l.Color[0], l.Color[1], l.Color[2]},
 				l.Intensity,
 				100.0, // Default range
 			)
+			light.Mode = l.Mode
+			if dir := (mgl.Vec3{l.Direction[0], l.Direction[1], l.Direction[2]}); dir.Len() > 0 {
+				light.Direction = dir
+			}
+			if l.IESProfile != "" {
+				if profile, err := renderer.LoadIESProfile(filepath.Join(assetsDir, filepath.FromSlash(l.IESProfile))); err != nil {
+					fmt.Printf("Warning: Could not load IES profile for %s: %v\n", l.Name, err)
+				} else {
+					light.IES = profile
+				}
+			}
+		} else if l.Mode == "rect" || l.Mode == "disk" {
+			light = renderer.CreateAreaLight(
+				l.Mode,
+				mgl.Vec3{l.Position[0], l.Position[1], l.Position[2]},
+				mgl.Vec3{l.Direction[0], l.Direction[1], l.Direction[2]},
+				mgl.Vec3{l.Color[0], l.Color[1], l.Color[2]},
+				l.Intensity,
+				l.AreaWidth,
+				l.AreaHeight,
+			)
 		} else {
 			light = renderer.CreateDirectionalLight(
 				mgl.Vec3{l.Direction[0], l.Direction[1], l.Direction[2]}.Normalize(),
@@ -222,12 +305,17 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 		if activeCamera.Far > 0 {
 			gameEngine.Camera.Far = activeCamera.Far
 		}
-		gameEngine.Camera.UpdateProjection()
+		setupCameraMode(gameEngine.Camera, activeCamera, r)
 	} else {
 		gameEngine.Camera.Position = mgl.Vec3{0, 50, 150}
 		gameEngine.Camera.Speed = 100
 		gameEngine.Camera.InvertMouse = false
 	}
+	setupMainCameraComponent(scene.GameObjects, r)
+	setupTextComponents(scene.GameObjects, r, assetsDir)
+	setupUILayouts(scene.UILayouts, assetsDir)
+	setupCameraViews(scene.Cameras, activeCamera)
+	setupRenderTextures(scene.RenderTextures, scene.Cameras, r)
 
 	// If no lights in scene, add a minimal fallback light
 	if len(scene.Lights) == 0 {
@@ -257,6 +345,8 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 			r.ClearColorG = scene.Skybox.Color[1]
 			r.ClearColorB = scene.Skybox.Color[2]
 			fmt.Printf("Skybox color set to: %.2f, %.2f, %.2f\n", scene.Skybox.Color[0], scene.Skybox.Color[1], scene.Skybox.Color[2])
+		} else if scene.Skybox.Type == "procedural" {
+			loadProceduralSky(scene.Skybox.Procedural, r)
 		} else if scene.Skybox.ImagePath != "" {
 			skyboxPath := resolveAssetPath(scene.Skybox.ImagePath, assetsDir)
 			if skyboxPath != "" {
@@ -279,6 +369,23 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 		}
 		renderer.FaceCullingEnabled = scene.Rendering.FaceCulling
 		renderer.Debug = scene.Rendering.Wireframe
+		r.RenderPath, _ = renderer.ParseRenderPath(scene.Rendering.RenderPath)
+		if scene.Rendering.SSR != nil {
+			r.SSR = *scene.Rendering.SSR
+		}
+		if fog := scene.Rendering.Fog; fog != nil {
+			r.Fog = renderer.FogSettings{
+				Mode:             renderer.FogMode(fog.Mode),
+				Color:            mgl.Vec3{fog.Color[0], fog.Color[1], fog.Color[2]},
+				Density:          fog.Density,
+				HeightFogEnabled: fog.HeightFog,
+				HeightDensity:    fog.HeightDensity,
+				HeightBase:       fog.HeightBase,
+				HeightFalloff:    fog.HeightFalloff,
+				MaxOpacity:       fog.MaxOpacity,
+				SkyBlend:         fog.SkyBlend,
+			}
+		}
 	}
 
 	// Load water if present - use full water simulation with shaders
@@ -287,6 +394,259 @@ func loadSceneData(scene *SceneData, assetsDir string) {
 	}
 }
 
+// setupCameraViews draws every scene camera with a viewport alongside the active
+// camera, which keeps the whole window unless it has a viewport of its own
+func setupCameraViews(cameras []SceneCamera, active *SceneCamera) {
+	var views []*renderer.Camera
+	for i := range cameras {
+		data := &cameras[i]
+		rect := renderer.ViewportRect{X: data.Viewport[0], Y: data.Viewport[1], Width: data.Viewport[2], Height: data.Viewport[3]}
+		if data == active {
+			gameEngine.Camera.Viewport = rect
+			gameEngine.Camera.Depth = data.Depth
+			continue
+		}
+		if rect.IsFull() {
+			continue
+		}
+		cam := newSceneCamera(data)
+		cam.Viewport = rect
+		cam.Depth = data.Depth
+		views = append(views, cam)
+	}
+	if len(views) > 0 {
+		gameEngine.Views = append([]*renderer.Camera{gameEngine.Camera}, views...)
+	}
+}
+
+// setupRenderTextures points each render texture at the scene camera that draws it
+func setupRenderTextures(textures []SceneRenderTexture, cameras []SceneCamera, r *renderer.OpenGLRenderer) {
+	for _, entry := range textures {
+		rt := r.RenderTexture(entry.Name)
+		rt.Width, rt.Height = entry.Width, entry.Height
+		rt.UpdateRate = entry.UpdateRate
+		for i := range cameras {
+			if cameras[i].Name == entry.Camera {
+				rt.Camera = newSceneCamera(&cameras[i])
+				break
+			}
+		}
+		if rt.Camera == nil {
+			fmt.Printf("Render texture %s: camera %q not found\n", entry.Name, entry.Camera)
+		}
+	}
+}
+
+// setupReflection makes a model a planar mirror, with the mirror material
+// unless it has its own
+func setupReflection(model *renderer.Model, data *SceneReflection, r *renderer.OpenGLRenderer) {
+	p := renderer.NewPlanarReflection("mirror:"+model.Name, model)
+	if data.Normal != ([3]float32{}) {
+		p.Normal = mgl.Vec3{data.Normal[0], data.Normal[1], data.Normal[2]}
+	}
+	if data.Resolution > 0 {
+		p.Resolution = data.Resolution
+	}
+	p.ClipOffset = data.ClipOffset
+	r.AddPlanarReflection(p)
+	if model.CustomMaterial == nil {
+		model.CustomMaterial = renderer.NewMirrorMaterial(p)
+	}
+}
+
+// setupCameraMode applies a scene camera's projection and controller
+func setupCameraMode(cam *renderer.Camera, data *SceneCamera, r *renderer.OpenGLRenderer) {
+	cam.ProjectionMode = renderer.ParseProjectionMode(data.Projection)
+	if data.OrthoSize > 0 {
+		cam.OrthoSize = data.OrthoSize
+	}
+	if data.Controller != nil {
+		cam.Controller = renderer.NewCameraController(*data.Controller, r.FindModel(data.Controller.Follow))
+	}
+	cam.UpdateProjection()
+}
+
+// setupUILayouts puts the scene's UI layouts on screen for scripts to find by ID
+func setupUILayouts(paths []string, assetsDir string) {
+	for _, path := range paths {
+		layout, err := ui.LoadLayout(filepath.Join(assetsDir, filepath.FromSlash(path)))
+		if err != nil {
+			fmt.Printf("Warning: Could not load UI layout %s: %v\n", path, err)
+			continue
+		}
+		ui.GlobalManager.Add(layout)
+	}
+}
+
+// setupTextComponents draws the screen labels and world text of game objects
+// with a TextComponent
+func setupTextComponents(objects []SceneGameObject, r *renderer.OpenGLRenderer, assetsDir string) {
+	for _, obj := range objects {
+		for _, comp := range obj.Components {
+			p := comp.Properties
+			if comp.Category != "Text" {
+				continue
+			}
+			num := func(key string) float32 {
+				v, _ := p[key].(float64)
+				return float32(v)
+			}
+			str := func(key string) string {
+				v, _ := p[key].(string)
+				return v
+			}
+			vec := func(key string, out []float32) {
+				if v, ok := p[key].([]interface{}); ok && len(v) == len(out) {
+					for i := range out {
+						f, _ := v[i].(float64)
+						out[i] = float32(f)
+					}
+				}
+			}
+
+			text := renderer.NewText(str("text"))
+			text.Name = obj.Name
+			if fontPath := str("font_path"); fontPath != "" {
+				font, err := renderer.LoadFont(resolveAssetPath(fontPath, assetsDir))
+				if err != nil {
+					fmt.Printf("Warning: Could not load font %s, using the default: %v\n", filepath.Base(fontPath), err)
+				} else {
+					text.Font = font
+				}
+			}
+			if size := num("size"); size > 0 {
+				text.Size = size
+			}
+			vec("color", text.Color[:3])
+			if _, ok := p["alpha"]; ok {
+				text.Color[3] = num("alpha")
+			}
+			if align := str("align"); align != "" {
+				text.Align = renderer.TextAlign(align)
+			}
+			text.LineSpacing = num("line_spacing")
+			text.MaxWidth = num("max_width")
+			text.Billboard, _ = p["billboard"].(bool)
+			text.Hidden = !obj.Active
+
+			if str("space") == string(renderer.TextWorld) {
+				text.Space = renderer.TextWorld
+				text.Position = mgl.Vec3(obj.Position)
+				text.Rotation = mgl.AnglesToQuat(
+					mgl.DegToRad(obj.Rotation[2]), mgl.DegToRad(obj.Rotation[1]), mgl.DegToRad(obj.Rotation[0]), mgl.ZYX)
+			} else {
+				var screen [2]float32
+				vec("screen_position", screen[:])
+				text.Position = mgl.Vec3{screen[0], screen[1], 0}
+			}
+			r.AddText(text)
+		}
+	}
+}
+
+// setupMainCameraComponent lets a game object with a main CameraComponent place
+// the camera and choose its projection and controller
+func setupMainCameraComponent(objects []SceneGameObject, r *renderer.OpenGLRenderer) {
+	for _, obj := range objects {
+		for _, comp := range obj.Components {
+			p := comp.Properties
+			if main, _ := p["is_main"].(bool); comp.Category != "Camera" || !main {
+				continue
+			}
+			num := func(key string) float32 {
+				v, _ := p[key].(float64)
+				return float32(v)
+			}
+			str := func(key string) string {
+				v, _ := p[key].(string)
+				return v
+			}
+			var target [3]float32
+			if v, ok := p["target"].([]interface{}); ok && len(v) == 3 {
+				for i := range target {
+					f, _ := v[i].(float64)
+					target[i] = float32(f)
+				}
+			}
+
+			data := &SceneCamera{
+				Name:       obj.Name,
+				Position:   obj.Position,
+				FOV:        num("fov"),
+				Near:       num("near"),
+				Far:        num("far"),
+				Projection: str("projection"),
+				OrthoSize:  num("ortho_size"),
+				Controller: &renderer.CameraControllerSettings{
+					Mode:      renderer.CameraMode(str("controller")),
+					Target:    target,
+					Follow:    str("follow"),
+					Distance:  num("distance"),
+					Yaw:       num("yaw"),
+					Pitch:     num("pitch"),
+					Stiffness: num("stiffness"),
+				},
+			}
+			cam := gameEngine.Camera
+			cam.Position = mgl.Vec3(data.Position)
+			if data.FOV > 0 {
+				cam.Fov = data.FOV
+			}
+			if data.Near > 0 {
+				cam.Near = data.Near
+			}
+			if data.Far > 0 {
+				cam.Far = data.Far
+			}
+			// Lens effects keep the camera's defaults for anything the scene leaves out
+			if v, ok := p["depth_of_field"].(bool); ok {
+				cam.DepthOfField.Enabled = v
+			}
+			if v := num("focus_distance"); v > 0 {
+				cam.DepthOfField.FocusDistance = v
+			}
+			if v := num("f_stop"); v > 0 {
+				cam.DepthOfField.FStop = v
+			}
+			if _, ok := p["bokeh_blades"].(float64); ok {
+				cam.DepthOfField.BokehBlades = int(num("bokeh_blades"))
+			}
+			if v, ok := p["motion_blur"].(bool); ok {
+				cam.MotionBlur.Enabled = v
+			}
+			if v := num("shutter"); v > 0 {
+				cam.MotionBlur.Shutter = v
+			}
+			cam.Controller = nil
+			setupCameraMode(cam, data, r)
+			return
+		}
+	}
+}
+
+// newSceneCamera creates a camera from its scene data
+func newSceneCamera(data *SceneCamera) *renderer.Camera {
+	cam := renderer.NewDefaultCamera(gameEngine.Width, gameEngine.Height)
+	cam.Name = data.Name
+	cam.Position = mgl.Vec3{data.Position[0], data.Position[1], data.Position[2]}
+	if data.FOV > 0 {
+		cam.Fov = data.FOV
+	}
+	if data.Near > 0 {
+		cam.Near = data.Near
+	}
+	if data.Far > 0 {
+		cam.Far = data.Far
+	}
+	cam.ProjectionMode = renderer.ParseProjectionMode(data.Projection)
+	if data.OrthoSize > 0 {
+		cam.OrthoSize = data.OrthoSize
+	}
+	cam.UpdateProjection()
+	cam.SetRotation(data.Rotation[0], data.Rotation[1])
+	return cam
+}
+
 func findAsset(name string) string {
 	exePath, _ := os.Executable()
 	exeDir := filepath.Dir(exePath)
@@ -335,14 +695,17 @@ func resolveAssetPath(path, assetsDir string) string {
 
 // Scene data structures (must match editor format)
 type SceneData struct {
-	GameObjects []SceneGameObject     `json:"game_objects,omitempty"`
-	Models      []SceneModel          `json:"models,omitempty"`
-	Lights      []SceneLight          `json:"lights,omitempty"`
-	Camera      *SceneCamera          `json:"camera,omitempty"`
-	Cameras     []SceneCamera         `json:"cameras,omitempty"`
-	Water       *SceneWater           `json:"water,omitempty"`
-	Skybox      *SceneSkybox          `json:"skybox,omitempty"`
-	Rendering   *SceneRenderingConfig `json:"rendering,omitempty"`
+	FormatVersion  int                   `json:"format_version,omitempty"`
+	GameObjects    []SceneGameObject     `json:"game_objects,omitempty"`
+	Models         []SceneModel          `json:"models,omitempty"`
+	Lights         []SceneLight          `json:"lights,omitempty"`
+	Camera         *SceneCamera          `json:"camera,omitempty"`
+	Cameras        []SceneCamera         `json:"cameras,omitempty"`
+	RenderTextures []SceneRenderTexture  `json:"render_textures,omitempty"`
+	Water          *SceneWater           `json:"water,omitempty"`
+	Skybox         *SceneSkybox          `json:"skybox,omitempty"`
+	Rendering      *SceneRenderingConfig `json:"rendering,omitempty"`
+	UILayouts      []string              `json:"ui_layouts,omitempty"`
 }
 
 type SceneGameObject struct {
@@ -365,6 +728,20 @@ type SceneCamera struct {
 	Far         float32    `json:"far,omitempty"`
 	InvertMouse bool       `json:"invert_mouse"`
 	IsActive    bool       `json:"is_active"`
+	Viewport    [4]float32 `json:"viewport,omitzero"` // X, Y, width, height as window fractions from the top-left
+	Depth       int        `json:"depth,omitempty"`   // Higher depths draw on top
+
+	Projection string                             `json:"projection,omitempty"` // "perspective" or "orthographic"
+	OrthoSize  float32                            `json:"ortho_size,omitempty"` // Half the visible height when orthographic
+	Controller *renderer.CameraControllerSettings `json:"controller,omitempty"` // Fly controls when nil
+}
+
+type SceneRenderTexture struct {
+	Name       string  `json:"name"`
+	Width      int32   `json:"width"`
+	Height     int32   `json:"height"`
+	UpdateRate float32 `json:"update_rate,omitempty"` // Updates per second; 0 is every frame
+	Camera     string  `json:"camera"`
 }
 
 type SceneModel struct {
@@ -380,7 +757,18 @@ type SceneModel struct {
 	Metallic      float32          `json:"metallic"`
 	Roughness     float32          `json:"roughness"`
 	Alpha         float32          `json:"alpha"`
-	Components    []SceneComponent `json:"components,omitempty"`
+	TexturePath   string           `json:"texture_path,omitempty"` // File, or rendertexture:<name>
+	MaterialAsset string           `json:"material_asset,omitempty"`
+	Reflection    *SceneReflection `json:"reflection,omitempty"`
+
+	TextureSampler *renderer.SamplerSettings `json:"texture_sampler,omitempty"`
+	Components     []SceneComponent          `json:"components,omitempty"`
+}
+
+type SceneReflection struct {
+	Normal     [3]float32 `json:"normal"` // In the model's space
+	Resolution float32    `json:"resolution"`
+	ClipOffset float32    `json:"clip_offset,omitempty"`
 }
 
 type SceneComponent struct {
@@ -397,33 +785,55 @@ type SceneLight struct {
 	Color           [3]float32 `json:"color"`
 	Intensity       float32    `json:"intensity"`
 	AmbientStrength float32    `json:"ambient_strength"`
+	AreaWidth       float32    `json:"area_width,omitempty"`
+	AreaHeight      float32    `json:"area_height,omitempty"`
+	IESProfile      string     `json:"ies_profile,omitempty"`
 }
 
 type SceneWater struct {
-	OceanSize           float32    `json:"ocean_size"`
-	BaseAmplitude       float32    `json:"base_amplitude"`
-	WaterColor          [3]float32 `json:"water_color"`
-	Transparency        float32    `json:"transparency"`
-	WaveSpeedMultiplier float32    `json:"wave_speed_multiplier"`
-	WaveHeight          float32    `json:"wave_height"`
-	WaveRandomness      float32    `json:"wave_randomness"`
-	Position            [3]float32 `json:"position"`
-	FoamEnabled         bool       `json:"foam_enabled"`
-	FoamIntensity       float32    `json:"foam_intensity"`
-	CausticsEnabled     bool       `json:"caustics_enabled"`
-	CausticsIntensity   float32    `json:"caustics_intensity"`
-	CausticsScale       float32    `json:"caustics_scale"`
-	SpecularIntensity   float32    `json:"specular_intensity"`
-	NormalStrength      float32    `json:"normal_strength"`
-	DistortionStrength  float32    `json:"distortion_strength"`
-	ShadowStrength      float32    `json:"shadow_strength"`
-	MeshDataFile        string     `json:"mesh_data_file,omitempty"`
+	OceanSize            float32    `json:"ocean_size"`
+	BaseAmplitude        float32    `json:"base_amplitude"`
+	WaterColor           [3]float32 `json:"water_color"`
+	Transparency         float32    `json:"transparency"`
+	WaveSpeedMultiplier  float32    `json:"wave_speed_multiplier"`
+	WaveHeight           float32    `json:"wave_height"`
+	WaveRandomness       float32    `json:"wave_randomness"`
+	Position             [3]float32 `json:"position"`
+	FoamEnabled          bool       `json:"foam_enabled"`
+	FoamIntensity        float32    `json:"foam_intensity"`
+	CausticsEnabled      bool       `json:"caustics_enabled"`
+	CausticsIntensity    float32    `json:"caustics_intensity"`
+	CausticsScale        float32    `json:"caustics_scale"`
+	SpecularIntensity    float32    `json:"specular_intensity"`
+	NormalStrength       float32    `json:"normal_strength"`
+	DistortionStrength   float32    `json:"distortion_strength"`
+	ReflectionResolution float32    `json:"reflection_resolution,omitempty"`
+	ShadowStrength       float32    `json:"shadow_strength"`
+	MeshDataFile         string     `json:"mesh_data_file,omitempty"`
 }
 
 type SceneSkybox struct {
-	Type      string     `json:"type"`
-	ImagePath string     `json:"image_path"`
-	Color     [3]float32 `json:"color"`
+	Type       string              `json:"type"`
+	ImagePath  string              `json:"image_path"`
+	Color      [3]float32          `json:"color"`
+	Procedural *SceneProceduralSky `json:"procedural,omitempty"`
+}
+
+type SceneProceduralSky struct {
+	TimeOfDay         float32    `json:"time_of_day"`
+	DayLength         float32    `json:"day_length"`
+	Paused            bool       `json:"paused"`
+	SunAzimuth        float32    `json:"sun_azimuth"`
+	SunTilt           float32    `json:"sun_tilt"`
+	DayIntensity      float32    `json:"day_intensity"`
+	NightIntensity    float32    `json:"night_intensity"`
+	DayAmbient        float32    `json:"day_ambient"`
+	NightAmbient      float32    `json:"night_ambient"`
+	SunIntensity      float32    `json:"sun_intensity"`
+	Rayleigh          [3]float32 `json:"rayleigh"`
+	Mie               float32    `json:"mie"`
+	MieDirectionality float32    `json:"mie_directionality"`
+	Exposure          float32    `json:"exposure"`
 }
 
 type SceneRenderingConfig struct {
@@ -433,6 +843,66 @@ type SceneRenderingConfig struct {
 	FaceCulling bool       `json:"face_culling"`
 	Wireframe   bool       `json:"wireframe"`
 	SkyboxColor [3]float32 `json:"skybox_color"`
+	Fog         *SceneFog  `json:"fog,omitempty"`
+	RenderPath  string     `json:"render_path,omitempty"`
+
+	SSR *renderer.SSRSettings `json:"ssr,omitempty"`
+}
+
+type SceneFog struct {
+	Mode          string     `json:"mode"`
+	Color         [3]float32 `json:"color"`
+	Density       float32    `json:"density"`
+	HeightFog     bool       `json:"height_fog"`
+	HeightDensity float32    `json:"height_density"`
+	HeightBase    float32    `json:"height_base"`
+	HeightFalloff float32    `json:"height_falloff"`
+	MaxOpacity    float32    `json:"max_opacity"`
+	SkyBlend      float32    `json:"sky_blend"`
+}
+
+// Day/night cycle driving the sun light, present when the scene uses a procedural sky
+var timeOfDay *renderer.TimeOfDay
+
+// loadProceduralSky creates the atmospheric sky and its time-of-day controller
+func loadProceduralSky(p *SceneProceduralSky, r *renderer.OpenGLRenderer) {
+	timeOfDay = renderer.NewTimeOfDay(gameEngine.Light, 10.0)
+	if p != nil {
+		timeOfDay.Hour = p.TimeOfDay
+		timeOfDay.DayLength = p.DayLength
+		timeOfDay.Paused = p.Paused
+		timeOfDay.SunAzimuth = p.SunAzimuth
+		timeOfDay.SunTilt = p.SunTilt
+		timeOfDay.DayIntensity = p.DayIntensity
+		timeOfDay.NightIntensity = p.NightIntensity
+		timeOfDay.DayAmbient = p.DayAmbient
+		timeOfDay.NightAmbient = p.NightAmbient
+		if p.SunIntensity > 0 {
+			timeOfDay.Atmosphere.SunIntensity = p.SunIntensity
+		}
+		if p.Rayleigh != [3]float32{} {
+			timeOfDay.Atmosphere.RayleighCoefficient = mgl.Vec3{p.Rayleigh[0], p.Rayleigh[1], p.Rayleigh[2]}
+		}
+		if p.Mie > 0 {
+			timeOfDay.Atmosphere.MieCoefficient = p.Mie
+		}
+		if p.MieDirectionality > 0 {
+			timeOfDay.Atmosphere.MieDirectionality = p.MieDirectionality
+		}
+		if p.Exposure > 0 {
+			timeOfDay.Atmosphere.Exposure = p.Exposure
+		}
+	}
+
+	sky, err := renderer.CreateProceduralSkybox(timeOfDay.Atmosphere)
+	if err != nil {
+		fmt.Printf("Failed to create procedural sky: %v\n", err)
+		timeOfDay = nil
+		return
+	}
+	r.SetSkybox(sky)
+	timeOfDay.Apply()
+	fmt.Printf("Procedural sky loaded at %.1fh\n", timeOfDay.Hour)
 }
 
 // Water simulation instance
@@ -457,10 +927,14 @@ func loadWater(w *SceneWater, r *renderer.OpenGLRenderer) {
 	waterSim.SpecularIntensity = w.SpecularIntensity
 	waterSim.NormalStrength = w.NormalStrength
 	waterSim.DistortionStrength = w.DistortionStrength
+	waterSim.ReflectionResolution = w.ReflectionResolution
 	waterSim.ShadowStrength = w.ShadowStrength
 
 	// Set sky color for reflections
 	waterSim.SetSkyColor(mgl.Vec3{r.ClearColorR, r.ClearColorG, r.ClearColorB})
+	if timeOfDay != nil {
+		timeOfDay.AddSkyColorTarget(waterSim)
+	}
 
 	// Initialize mesh and add to scene
 	model := waterSim.InitializeMesh()
@@ -0,0 +1,36 @@
+package scripts
+
+import (
+	"Gopher3D/internal/behaviour"
+	"Gopher3D/internal/ui"
+)
+
+// MenuScript hides the menu when its "play" button is clicked and shows it
+// again from the "pause" button
+type MenuScript struct {
+	behaviour.BaseComponent
+	menu *ui.Layout
+}
+
+func init() {
+	behaviour.RegisterScript("MenuScript", func() behaviour.Component {
+		return &MenuScript{}
+	})
+	println("Registered MenuScript")
+}
+
+func (m *MenuScript) Start() {
+	ui.GlobalManager.On("play", ui.EventClick, func(e ui.Event) {
+		m.menu = e.Widget.Layout()
+		m.menu.Hidden = true
+	})
+	ui.GlobalManager.On("pause", ui.EventClick, func(ui.Event) {
+		if m.menu != nil {
+			m.menu.Hidden = false
+		}
+	})
+}
+
+func (m *MenuScript) Update() {}
+
+func (m *MenuScript) FixedUpdate() {}